@@ -1,6 +1,12 @@
 package main
 
-import "fmt"
+import (
+	"fmt"
+
+	"go-practice/analytics"
+	"go-practice/collections"
+	"go-practice/seq"
+)
 
 func main() {
 	fmt.Println("🐹 Go Arrays and Slices - Chapter 5 🐹")
@@ -182,84 +188,67 @@ func section4_FunctionsWithCollections(scores []int) {
 	data := []int{10, 20, 30, 40, 50}
 	fmt.Printf("Original data: %v\n", data)
 	
-	// Call function to double all numbers
-	doubleSlice(data)
+	// Call function to double all numbers in place
+	double := func(n int) int { return n * 2 }
+	collections.MapInPlace(data, double)
 	fmt.Printf("After doubling: %v\n", data)
 	
 	// Function that returns a new slice
 	original := []int{1, 2, 3, 4, 5}
-	doubled := doubleSliceReturn(original)
+	doubled := collections.Map(original, double)
 	fmt.Printf("Original: %v\n", original)
 	fmt.Printf("Doubled: %v\n", doubled)
 	
-	// Function that finds maximum value
-	maxValue := findMax(scores)
+	// Function that finds maximum value; ok is false for an empty slice
+	maxValue, ok := collections.MaxBy(scores, collections.Identity[int])
 	fmt.Printf("Scores: %v\n", scores)
-	fmt.Printf("Maximum score: %d\n", maxValue)
+	fmt.Printf("Maximum score: %d (found: %t)\n", maxValue, ok)
+	_, ok = collections.MaxBy([]int{}, collections.Identity[int])
+	fmt.Printf("Maximum of no scores found: %t\n", ok)
+
+	// The analytics package goes further, with errors for empty input
+	if _, err := analytics.Max([]int{}); err != nil {
+		fmt.Printf("Maximum of no scores: %v\n", err)
+	}
+	if summary, err := analytics.Summarize(scores); err == nil {
+		fmt.Printf("Mean: %.2f, median: %.1f, std dev: %.2f\n", summary.Mean, summary.Median, summary.StdDev)
+	}
+	if letters, err := analytics.LetterGrades(scores, analytics.LetterScale, nil); err != nil {
+		fmt.Printf("Letter grades: %v\n", err)
+	} else {
+		fmt.Printf("Letter grades: %v\n", letters)
+	}
 	
 	// Function that filters slice
-	highScores := filterHighScores(scores, 85)
+	highScores := collections.Filter(scores, func(score int) bool { return score >= 85 })
 	fmt.Printf("All scores: %v\n", scores)
 	fmt.Printf("High scores (85+): %v\n", highScores)
 	
 	// Function that combines slices
 	slice1 := []int{1, 2, 3}
 	slice2 := []int{4, 5, 6}
-	combined := combineSlices(slice1, slice2)
+	combined := collections.Flatten([][]int{slice1, slice2})
 	fmt.Printf("Slice 1: %v\n", slice1)
 	fmt.Printf("Slice 2: %v\n", slice2)
 	fmt.Printf("Combined: %v\n", combined)
-}
-
-// ============================================================================
-// HELPER FUNCTIONS
-// ============================================================================
-
-// doubleSlice doubles all numbers in a slice (modifies original)
-func doubleSlice(numbers []int) {
-	for i := range numbers {
-		numbers[i] = numbers[i] * 2
-	}
-}
 
-// doubleSliceReturn returns a new slice with doubled values
-func doubleSliceReturn(numbers []int) []int {
-	result := make([]int, len(numbers))
-	for i, num := range numbers {
-		result[i] = num * 2
+	// Chunks are views into the original slice: writes show through, but
+	// each chunk's capacity is capped so append can't spill into the next
+	fmt.Println("\nChunks share memory with the original:")
+	chunks := collections.Chunk(combined, 2)
+	chunks[0][0] = 100
+	chunks[0] = append(chunks[0], 999)
+	fmt.Printf("Chunks: %v\n", chunks)
+	fmt.Printf("Combined after writing through a chunk: %v\n", combined)
+	fmt.Printf("First chunk len %d, cap %d (reallocated by append)\n", len(chunks[0]), cap(chunks[0]))
+	fmt.Printf("Second chunk len %d, cap %d\n", len(chunks[1]), cap(chunks[1]))
+
+	// Lazy pipelines process one element at a time instead of building a
+	// new slice at every step, and stop as soon as they have enough
+	fmt.Println("\nLazy pipeline:")
+	passed := seq.Filter(seq.Slice(scores), func(score int) bool { return score >= 90 })
+	firstTwo := seq.Take(seq.Map(passed, func(score int) string { return fmt.Sprintf("%d%%", score) }), 2)
+	for label := range firstTwo {
+		fmt.Printf("Top score: %s\n", label)
 	}
-	return result
 }
-
-// findMax finds the maximum value in a slice
-func findMax(numbers []int) int {
-	if len(numbers) == 0 {
-		return 0
-	}
-	max := numbers[0]
-	for _, num := range numbers {
-		if num > max {
-			max = num
-		}
-	}
-	return max
-}
-
-// filterHighScores returns scores above a threshold
-func filterHighScores(scores []int, threshold int) []int {
-	var result []int
-	for _, score := range scores {
-		if score >= threshold {
-			result = append(result, score)
-		}
-	}
-	return result
-}
-
-// combineSlices combines two slices into one
-func combineSlices(slice1, slice2 []int) []int {
-	result := make([]int, 0, len(slice1)+len(slice2))
-	result = append(result, slice1...)
-	result = append(result, slice2...)
-	return result
-} 
\ No newline at end of file
@@ -1,6 +1,15 @@
 package main
 
-import "fmt"
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"go-practice/analytics"
+	"go-practice/errs"
+	"go-practice/roster"
+	"go-practice/sorting"
+)
 
 func main() {
 	fmt.Println("🐹 Go Maps - Chapter 6 🐹")
@@ -265,26 +274,72 @@ func section4_FunctionsWithMaps() {
 	printStudentInfo(students, "Bob")
 	printStudentInfo(students, "Frank") // Doesn't exist
 
-	// Function that returns a new map
-	fmt.Println("\nFunction that returns a new map:")
-	activeStudents := getActiveStudents(students)
-	fmt.Printf("Active students: %v\n", activeStudents)
-
 	// Function that modifies a map
 	fmt.Println("\nFunction that modifies a map:")
 	fmt.Printf("Before modification: %v\n", students)
 	addStudent(students, "Diana", 21, "B", "Berlin", true)
 	fmt.Printf("After adding Diana: %v\n", students)
 
-	// Function that finds students by criteria
-	fmt.Println("\nFunction that finds students by criteria:")
-	gradeAStudents := findStudentsByGrade(students, "A")
-	fmt.Printf("Students with grade A: %v\n", gradeAStudents)
+	// Every lookup above needs a type assertion such as info["grade"].(string),
+	// and a typo in a key or a wrong type silently gives you the zero value.
+	// The go-practice/roster package loads the same data into typed Student
+	// structs with indexes for the common lookups.
+	fmt.Println("\nFrom nested maps to a typed store:")
+	store, err := roster.FromLegacy(students)
+	if err != nil {
+		fmt.Printf("Load error: %v\n", err)
+	}
+	fmt.Printf("Loaded %d students: %v\n", store.Len(), store.Names())
+
+	// Queries are built from filters and always return results in the same order
+	fmt.Println("\nQuerying the store:")
+	active := store.Query().Filter(roster.IsActive(true))
+	fmt.Printf("Active students: %v\n", active.Names())
+	fmt.Printf("Students with grade A: %v\n", store.Query().Filter(roster.HasGrade("A")).Names())
+	fmt.Printf("Students by city: %v\n", store.Query().CountBy(roster.ByCity))
+
+	byAge := sorting.Descending(func(s roster.Student) int { return s.Age })
+	fmt.Printf("Oldest active student: %v\n", active.OrderBy(byAge).Limit(1).Students())
+	for _, group := range store.Query().GroupBy(roster.ByGrade) {
+		fmt.Printf("  Grade %s: %d student(s)\n", group.Key, len(group.Students))
+	}
 
-	// Function that counts students by city
-	fmt.Println("\nFunction that counts students by city:")
-	cityCounts := countStudentsByCity(students)
-	fmt.Printf("Students by city: %v\n", cityCounts)
+	// Bad data is reported instead of being skipped
+	_, err = roster.FromLegacy(map[string]map[string]interface{}{
+		"Eve": {"age": "twenty", "grade": "A"},
+	})
+	fmt.Printf("Loading bad data: %v\n", err)
+
+	// Instead of calling addStudent once per student, load a whole roster
+	// exported from a spreadsheet. Rows that don't fit are reported by line.
+	fmt.Println("\nImporting a CSV roster:")
+	csvRoster := `Student,Age,Grade,City,Active
+Frank,23,C,Madrid,yes
+Grace,twenty,A,Rome,yes
+"Hopper, Grace",24,A,"New York",no
+`
+	added, err := store.ImportCSV(strings.NewReader(csvRoster), &roster.CSVOptions{
+		Aliases: map[string]string{"Student": "name"},
+	})
+	fmt.Printf("Imported %d students\n", added)
+	for _, rowErr := range errs.ValidationErrors(err) {
+		fmt.Printf("  Skipped: %v\n", rowErr)
+	}
+
+	// Test scores keyed by name join up with the store for per-group reports
+	fmt.Println("\nTest scores by grade:")
+	testScores := map[string]int{"Alice": 95, "Bob": 78, "Charlie": 88, "Diana": 91, "Frank": 84}
+	byGrade, err := analytics.Breakdown(store.Query(), testScores, roster.ByGrade)
+	if err != nil {
+		fmt.Printf("Breakdown error: %v\n", err)
+	}
+	report := analytics.Report{GroupLabel: "Grade", Groups: byGrade}
+	report.WriteText(os.Stdout)
+
+	fmt.Println("\nExporting grade A students as CSV:")
+	if err := store.Query().Filter(roster.HasGrade("A")).ExportCSV(os.Stdout, nil); err != nil {
+		fmt.Printf("Export error: %v\n", err)
+	}
 
 	// Function that returns success/failure with data (building on Chapter 4)
 	fmt.Println("\nFunction with success/failure pattern:")
@@ -317,17 +372,6 @@ func printStudentInfo(students map[string]map[string]interface{}, name string) {
 	}
 }
 
-// getActiveStudents returns a map of only active students
-func getActiveStudents(students map[string]map[string]interface{}) map[string]map[string]interface{} {
-	active := make(map[string]map[string]interface{})
-	for name, info := range students {
-		if activeStatus, ok := info["active"].(bool); ok && activeStatus {
-			active[name] = info
-		}
-	}
-	return active
-}
-
 // addStudent adds a new student to the map
 func addStudent(students map[string]map[string]interface{}, name string, age int, grade string, city string, active bool) {
 	students[name] = map[string]interface{}{
@@ -338,28 +382,6 @@ func addStudent(students map[string]map[string]interface{}, name string, age int
 	}
 }
 
-// findStudentsByGrade returns students with a specific grade
-func findStudentsByGrade(students map[string]map[string]interface{}, grade string) []string {
-	var result []string
-	for name, info := range students {
-		if studentGrade, ok := info["grade"].(string); ok && studentGrade == grade {
-			result = append(result, name)
-		}
-	}
-	return result
-}
-
-// countStudentsByCity returns a map counting students by city
-func countStudentsByCity(students map[string]map[string]interface{}) map[string]int {
-	cityCounts := make(map[string]int)
-	for _, info := range students {
-		if city, ok := info["city"].(string); ok {
-			cityCounts[city]++
-		}
-	}
-	return cityCounts
-}
-
 // getStudentDetails returns success status and student data (success/failure pattern)
 func getStudentDetails(students map[string]map[string]interface{}, name string) (bool, map[string]interface{}) {
 	if student, exists := students[name]; exists {
@@ -2,9 +2,11 @@ package main
 
 import (
 	"fmt"
-	"math"
 	"strings"
-	"time"
+
+	"go-practice/hardware"
+	"go-practice/inventory"
+	"go-practice/shapes"
 )
 
 func main() {
@@ -85,7 +87,7 @@ func section1_CreatingStructs() {
 	// Structs with collections
 	fmt.Println("\nStructs with collections:")
 	team := Team{
-		Members: []Person{person1, person2, person3},
+		Members: []Person{person1, person2, *person3},
 		Info: map[string]string{
 			"department": "Engineering",
 			"location":   "New York",
@@ -136,12 +138,15 @@ func section2_Methods() {
 
 	// Method chaining
 	fmt.Println("\nMethod chaining:")
-	computer := NewComputerBuilder().
+	computer, err := hardware.NewComputerBuilder().
 		SetCPU("Intel i9").
 		SetRAM(32).
 		SetStorage("1TB NVMe").
 		SetGPU("RTX 4080").
 		Build()
+	if err != nil {
+		fmt.Println("Error:", err)
+	}
 	fmt.Printf("Built computer: %+v\n", computer)
 }
 
@@ -154,23 +159,55 @@ func section3_AdvancedStructPatterns() {
 
 	// Builder pattern
 	fmt.Println("Builder pattern:")
-	car := NewCarBuilder().
+	car, err := inventory.NewCarBuilder().
 		SetBrand("Tesla").
 		SetModel("Model 3").
 		SetYear(2024).
 		SetColor("Red").
 		Build()
+	if err != nil {
+		fmt.Println("Error:", err)
+	}
 	fmt.Printf("Built car: %+v\n", car)
 
+	// Build checks every field and reports all the problems at once
+	_, err = inventory.NewCarBuilder().SetModel("Model T").SetYear(1850).Build()
+	fmt.Println("Invalid car:", err)
+
+	// Clone a configured builder to make variants of it
+	base := inventory.NewCarBuilder().SetBrand("Tesla").SetModel("Model Y").SetYear(2025)
+	for _, color := range []string{"Blue", "Black"} {
+		variant, err := base.Clone().SetColor(color).Build()
+		if err != nil {
+			fmt.Println("Error:", err)
+			continue
+		}
+		fmt.Printf("Variant: %+v\n", variant)
+	}
+	if defaultColor, err := base.Build(); err != nil {
+		fmt.Println("Error:", err)
+	} else {
+		fmt.Printf("Base (default color): %+v\n", defaultColor)
+	}
+
 	// Factory pattern
 	fmt.Println("\nFactory pattern:")
-	circle := NewShape("circle", 5.0)
-	square := NewShape("square", 4.0)
-	triangle := NewShape("triangle", 3.0)
+	for _, s := range []struct {
+		label, kind string
+		size        float64
+	}{{"Circle", "circle", 5.0}, {"Square", "square", 4.0}, {"Triangle", "triangle", 3.0}} {
+		shape, err := shapes.New(s.kind, s.size)
+		if err != nil {
+			fmt.Println("Error:", err)
+			continue
+		}
+		fmt.Printf("%s area: %.2f\n", s.label, shape.Area())
+	}
 
-	fmt.Printf("Circle area: %.2f\n", circle.Area())
-	fmt.Printf("Square area: %.2f\n", square.Area())
-	fmt.Printf("Triangle area: %.2f\n", triangle.Area())
+	// The factory reports bad input instead of returning an "unknown" shape
+	if _, err := shapes.New("hexagon", 2.0); err != nil {
+		fmt.Printf("Factory error: %v\n", err)
+	}
 
 	// Validation pattern
 	fmt.Println("\nValidation pattern:")
@@ -239,6 +276,11 @@ func section4_StructsWithCollections() {
 		}
 		fmt.Printf("%d. %s - %s (%s)\n", i+1, member.Name, member.Email, status)
 	}
+
+	// A Team without Info can still take members
+	var newTeam Team
+	newTeam.AddMember(newMember)
+	fmt.Printf("\nNew team with no Info: %d member\n", len(newTeam.Members))
 }
 
 // ============================================================================
@@ -282,43 +324,19 @@ type User struct {
 	Email string
 }
 
-// Shape represents different geometric shapes
-type Shape struct {
-	Type   string
-	Size   float64
-	Radius float64
-	Side   float64
-	Base   float64
-	Height float64
-}
-
-// Computer represents a computer system
-type Computer struct {
-	CPU     string
-	RAM     int
-	Storage string
-	GPU     string
-}
-
-// Car represents a car
-type Car struct {
-	Brand string
-	Model string
-	Year  int
-	Color string
-}
-
 // Person methods
 func (p Person) Introduce() {
 	fmt.Printf("Hi, I'm %s and I'm %d years old.\n", p.Name, p.Age)
 }
 
-func (p Person) HaveBirthday() {
+// HaveBirthday and UpdateEmail change the person, so they need pointer
+// receivers; with value receivers they would change a copy.
+func (p *Person) HaveBirthday() {
 	p.Age++
 	fmt.Printf("Happy birthday! %s is now %d years old.\n", p.Name, p.Age)
 }
 
-func (p Person) UpdateEmail(newEmail string) {
+func (p *Person) UpdateEmail(newEmail string) {
 	p.Email = newEmail
 	fmt.Printf("Email updated to: %s\n", p.Email)
 }
@@ -336,9 +354,12 @@ func (e Employee) DisplayInfo() {
 }
 
 // Team methods
+
+// AddMember appends person to the team. It doesn't touch Info, which may
+// be nil: the count is len(t.Members), so storing a copy of it would only
+// let the two disagree.
 func (t *Team) AddMember(person Person) {
 	t.Members = append(t.Members, person)
-	t.Info["count"] = fmt.Sprintf("%d", len(t.Members))
 }
 
 // User methods
@@ -354,97 +375,3 @@ func (u User) Validate() error {
 	}
 	return nil
 }
-
-// Shape methods
-func (s Shape) Area() float64 {
-	switch s.Type {
-	case "circle":
-		return math.Pi * s.Radius * s.Radius
-	case "square":
-		return s.Side * s.Side
-	case "triangle":
-		return 0.5 * s.Base * s.Height
-	default:
-		return 0
-	}
-}
-
-// Computer builder
-type ComputerBuilder struct {
-	computer Computer
-}
-
-func NewComputerBuilder() *ComputerBuilder {
-	return &ComputerBuilder{}
-}
-
-func (cb *ComputerBuilder) SetCPU(cpu string) *ComputerBuilder {
-	cb.computer.CPU = cpu
-	return cb
-}
-
-func (cb *ComputerBuilder) SetRAM(ram int) *ComputerBuilder {
-	cb.computer.RAM = ram
-	return cb
-}
-
-func (cb *ComputerBuilder) SetStorage(storage string) *ComputerBuilder {
-	cb.computer.Storage = storage
-	return cb
-}
-
-func (cb *ComputerBuilder) SetGPU(gpu string) *ComputerBuilder {
-	cb.computer.GPU = gpu
-	return cb
-}
-
-func (cb *ComputerBuilder) Build() Computer {
-	return cb.computer
-}
-
-// Car builder
-type CarBuilder struct {
-	car Car
-}
-
-func NewCarBuilder() *CarBuilder {
-	return &CarBuilder{}
-}
-
-func (cb *CarBuilder) SetBrand(brand string) *CarBuilder {
-	cb.car.Brand = brand
-	return cb
-}
-
-func (cb *CarBuilder) SetModel(model string) *CarBuilder {
-	cb.car.Model = model
-	return cb
-}
-
-func (cb *CarBuilder) SetYear(year int) *CarBuilder {
-	cb.car.Year = year
-	return cb
-}
-
-func (cb *CarBuilder) SetColor(color string) *CarBuilder {
-	cb.car.Color = color
-	return cb
-}
-
-func (cb *CarBuilder) Build() Car {
-	return cb.car
-}
-
-// Shape factory
-func NewShape(shapeType string, size float64) Shape {
-	switch shapeType {
-	case "circle":
-		return Shape{Type: "circle", Radius: size}
-	case "square":
-		return Shape{Type: "square", Side: size}
-	case "triangle":
-		return Shape{Type: "triangle", Base: size, Height: size}
-	default:
-		return Shape{Type: "unknown"}
-	}
-} 
\ No newline at end of file
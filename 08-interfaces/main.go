@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"math"
 	"strings"
+
+	"go-practice/codec"
+	"go-practice/sorting"
 )
 
 func main() {
@@ -101,7 +104,7 @@ func section2_CreatingAndUsingInterfaces() {
 
 	// Interface with multiple methods
 	fmt.Println("\nInterface with multiple methods:")
-	fmt.Println("type Animal interface { Speak() string; Move() string }")
+	fmt.Println("type Animal interface { Speak() string; Move() string; GetName() string }")
 	
 	dog := Dog{Name: "Buddy", Breed: "Golden Retriever"}
 	cat := Cat{Name: "Whiskers", Color: "Orange"}
@@ -111,7 +114,7 @@ func section2_CreatingAndUsingInterfaces() {
 	fmt.Println("Animals speaking and moving:")
 	for _, animal := range animals {
 		fmt.Printf("%s: %s, %s\n", 
-			getAnimalName(animal), animal.Speak(), animal.Move())
+			animal.GetName(), animal.Speak(), animal.Move())
 	}
 
 	// Function that works with any type implementing an interface
@@ -265,6 +268,39 @@ func section4_EmptyInterfaceAndTypeAssertions() {
 			fmt.Printf("Unknown type: %T\n", v)
 		}
 	}
+
+	// Type registries
+	fmt.Println("\nType registries (saving and loading interface slices):")
+	fmt.Println("Register each concrete type once, then encode with a \"type\" field")
+
+	registry := codec.NewRegistry()
+	registry.MustRegister("dog", Dog{})
+	registry.MustRegister("cat", Cat{})
+
+	pets := []Animal{
+		Dog{Name: "Buddy", Breed: "Golden Retriever"},
+		Cat{Name: "Whiskers", Color: "Orange"},
+	}
+
+	data, err := codec.Marshal(registry, codec.JSON, pets)
+	if err != nil {
+		fmt.Printf("Encode error: %v\n", err)
+		return
+	}
+	fmt.Printf("Encoded: %s\n", data)
+
+	decoded, err := codec.Unmarshal[Animal](registry, codec.JSON, data)
+	if err != nil {
+		fmt.Printf("Decode error: %v\n", err)
+		return
+	}
+	for _, animal := range decoded {
+		fmt.Printf("Decoded %T: %s\n", animal, animal.Speak())
+	}
+
+	// Unknown types produce a clear error instead of falling into "default"
+	_, err = codec.Unmarshal[Animal](registry, codec.JSON, []byte(`[{"type": "hamster"}]`))
+	fmt.Printf("Unknown type: %v\n", err)
 }
 
 // ============================================================================
@@ -326,12 +362,17 @@ func section5_RealWorldExamples() {
 	names := []string{"Charlie", "Alice", "Bob", "David"}
 	
 	fmt.Printf("Original numbers: %v\n", numbers)
-	sortInts(numbers)
+	sorting.Sort(numbers)
 	fmt.Printf("Sorted numbers: %v\n", numbers)
 	
 	fmt.Printf("Original names: %v\n", names)
-	sortStrings(names)
+	sorting.Sort(names)
 	fmt.Printf("Sorted names: %v\n", names)
+
+	// Sorting by a custom rule with a Less function
+	byLength := func(a, b string) bool { return len(a) < len(b) }
+	sorting.StableFunc(names, byLength)
+	fmt.Printf("Names by length (stable): %v\n", names)
 }
 
 // ============================================================================
@@ -351,6 +392,7 @@ type AdvancedShape interface {
 type Animal interface {
 	Speak() string
 	Move() string
+	GetName() string
 }
 
 // ReadWriteCloser interface (like io.ReadWriteCloser)
@@ -487,6 +529,10 @@ func (d Dog) Move() string {
 	return "Running on four legs"
 }
 
+func (d Dog) GetName() string {
+	return d.Name
+}
+
 func (c Cat) Speak() string {
 	return "Meow!"
 }
@@ -495,6 +541,10 @@ func (c Cat) Move() string {
 	return "Walking gracefully"
 }
 
+func (c Cat) GetName() string {
+	return c.Name
+}
+
 // File methods (implementing ReadWriteCloser)
 func (f *File) Read(p []byte) (n int, err error) {
 	if !f.IsOpen {
@@ -582,17 +632,6 @@ func (p *ProductHandler) GetEndpoint() string {
 }
 
 // Helper functions
-func getAnimalName(animal Animal) string {
-	switch a := animal.(type) {
-	case Dog:
-		return a.Name
-	case Cat:
-		return a.Name
-	default:
-		return "Unknown"
-	}
-}
-
 func calculateTotalArea(shapes []Shape) float64 {
 	total := 0.0
 	for _, shape := range shapes {
@@ -607,26 +646,4 @@ func makeAllAnimalsSpeak(animals []Animal) string {
 		responses = append(responses, animal.Speak())
 	}
 	return strings.Join(responses, ", ")
-}
-
-func sortInts(nums []int) {
-	// Simple bubble sort for demonstration
-	for i := 0; i < len(nums)-1; i++ {
-		for j := 0; j < len(nums)-i-1; j++ {
-			if nums[j] > nums[j+1] {
-				nums[j], nums[j+1] = nums[j+1], nums[j]
-			}
-		}
-	}
-}
-
-func sortStrings(strs []string) {
-	// Simple bubble sort for demonstration
-	for i := 0; i < len(strs)-1; i++ {
-		for j := 0; j < len(strs)-i-1; j++ {
-			if strs[j] > strs[j+1] {
-				strs[j], strs[j+1] = strs[j+1], strs[j]
-			}
-		}
-	}
-} 
\ No newline at end of file
+}
\ No newline at end of file
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// diagnostic is one line of compiler output, such as
+//
+//	./receivers_test.go:31:7: p does not escape
+type diagnostic struct {
+	File      string
+	Line, Col int
+	Message   string
+}
+
+func (d diagnostic) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s", d.File, d.Line, d.Col, d.Message)
+}
+
+// methodEscapes is what the compiler said about one Sum method.
+type methodEscapes struct {
+	Method   string // e.g. "(*ptr16).Sum"
+	Line     int
+	Messages []string
+}
+
+// TestReceiverEscapes compiles the test binary with -gcflags=-m and checks
+// the compiler's escape analysis for receivers_test.go: pointer receivers
+// don't make their struct escape, and converting a value receiver to an
+// interface moves a copy to the heap, which is the allocation
+// BenchmarkReceiverBox counts.
+//
+//	go test ./09-pointers -run ReceiverEscapes -v
+func TestReceiverEscapes(t *testing.T) {
+	if testing.Short() {
+		t.Skip("rebuilds the package")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("needs the go command")
+	}
+	const file = "receivers_test.go"
+	methods, err := sumMethods(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command("go", "test", "-c", "-gcflags=-m", "-o", nullDevice(), ".")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("go test -c -gcflags=-m: %v\n%s", err, out)
+	}
+	diags, err := parseDiagnostics(out, file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var conversions []string
+	seen := make(map[diagnostic]bool) // generic code is reported per instantiation
+	for _, d := range diags {
+		if seen[d] || !interesting(d) {
+			continue
+		}
+		seen[d] = true
+		if strings.HasPrefix(d.Message, "summer(") {
+			conversions = append(conversions, d.Message)
+			t.Logf("box workload, line %d: %s", d.Line, d.Message)
+			continue
+		}
+		for i := range methods {
+			if d.Line == methods[i].Line {
+				methods[i].Messages = append(methods[i].Messages, d.Message)
+			}
+		}
+	}
+
+	for _, m := range methods {
+		msg := "no escape diagnostics"
+		if len(m.Messages) > 0 {
+			msg = strings.Join(m.Messages, "; ")
+		}
+		t.Logf("%-18s %s", m.Method, msg)
+		if strings.HasPrefix(m.Method, "(*") && !slices.Contains(m.Messages, "p does not escape") {
+			t.Errorf("%s: want \"p does not escape\", got %q", m.Method, m.Messages)
+		}
+	}
+	if !slices.Contains(conversions, "summer(v) escapes to heap") {
+		t.Errorf("boxing a value receiver should escape, got %q", conversions)
+	}
+	if slices.Contains(conversions, "summer(p) escapes to heap") {
+		t.Error("boxing a pointer receiver shouldn't allocate")
+	}
+}
+
+// sumMethods finds the Sum method declarations in a source file. The
+// compiler reports receiver escapes at the receiver's position, which is
+// on the func line.
+func sumMethods(path string) ([]methodEscapes, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, path, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	var methods []methodEscapes
+	for _, decl := range f.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv == nil || fn.Name.Name != "Sum" {
+			continue
+		}
+		recv := types.ExprString(fn.Recv.List[0].Type)
+		if strings.HasPrefix(recv, "*") {
+			recv = "(" + recv + ")"
+		}
+		methods = append(methods, methodEscapes{
+			Method: recv + ".Sum",
+			Line:   fset.Position(fn.Pos()).Line,
+		})
+	}
+	return methods, nil
+}
+
+func nullDevice() string {
+	if runtime.GOOS == "windows" {
+		return "NUL"
+	}
+	return "/dev/null"
+}
+
+// parseDiagnostics extracts the diagnostics for file from compiler output,
+// skipping the "# package" headers and lines for other files.
+func parseDiagnostics(out []byte, file string) ([]diagnostic, error) {
+	var diags []diagnostic
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	for sc.Scan() {
+		line := sc.Text()
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		d, ok := parseDiagnostic(line)
+		if !ok {
+			continue
+		}
+		if filepath.Base(d.File) == file {
+			diags = append(diags, d)
+		}
+	}
+	return diags, sc.Err()
+}
+
+// parseDiagnostic splits "file:line:col: message".
+func parseDiagnostic(line string) (diagnostic, bool) {
+	parts := strings.SplitN(line, ":", 4)
+	if len(parts) != 4 {
+		return diagnostic{}, false
+	}
+	ln, err1 := strconv.Atoi(parts[1])
+	col, err2 := strconv.Atoi(parts[2])
+	if err1 != nil || err2 != nil {
+		return diagnostic{}, false
+	}
+	return diagnostic{
+		File:    parts[0],
+		Line:    ln,
+		Col:     col,
+		Message: strings.TrimSpace(parts[3]),
+	}, true
+}
+
+// interesting reports whether a diagnostic says something about where
+// receivers live: escapes and heap moves, not inlining of helpers.
+func interesting(d diagnostic) bool {
+	for _, s := range []string{"escape", "moved to heap", "leaking param"} {
+		if strings.Contains(d.Message, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -1,9 +1,27 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
-	"math"
+	"iter"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
+
+	"go-practice/config"
+	"go-practice/errs"
+	"go-practice/hardware"
+	"go-practice/lists"
+	"go-practice/persistent"
+	"go-practice/reflectutil"
+	"go-practice/seq"
+	"go-practice/shapes"
 )
 
 func main() {
@@ -273,7 +291,12 @@ func section4_LargeStructsAndPerformance() {
 		Metadata:     map[string]interface{}{"source": "web", "campaign": "winter2024"},
 	}
 	
-	fmt.Printf("Large struct size: %d bytes\n", estimateStructSize(largeStruct))
+	// SizeOf reports the struct's own bytes (including alignment padding)
+	// and the heap memory its strings, slice and maps keep alive
+	size := reflectutil.SizeOf(largeStruct)
+	fmt.Printf("Large struct size: %d bytes copied per value, %d bytes in total\n",
+		size.Shallow, size.Total())
+	size.WriteText(os.Stdout)
 	
 	// Performance comparison: value vs pointer
 	fmt.Println("\nPerformance comparison: value vs pointer:")
@@ -302,6 +325,9 @@ func section4_LargeStructsAndPerformance() {
 		improvement := float64(pointerTime-valueTime) / float64(pointerTime) * 100
 		fmt.Printf("Value receiver is %.1f%% faster\n", improvement)
 	}
+	// One timing loop is noisy and dominated by Sprintf; the benchmarks in
+	// receivers_test.go isolate the receiver copy across struct sizes
+	fmt.Println("For a careful comparison: go test ./09-pointers -run '^$' -bench Receiver -benchmem")
 	
 	// When to use pointers for performance
 	fmt.Println("\nWhen to use pointers for performance:")
@@ -387,26 +413,46 @@ func section6_CommonPointerPatterns() {
 	// Builder pattern with pointers
 	fmt.Println("Builder pattern with pointers:")
 	
-	computer := NewComputerBuilder().
+	// Each setter returns the same *ComputerBuilder, which is what makes
+	// the chain possible
+	base := hardware.NewComputerBuilder().
 		SetCPU("Intel i9").
-		SetRAM(32).
+		SetRAM(32)
+	
+	computer, err := base.Clone().
 		SetStorage("1TB NVMe").
 		SetGPU("RTX 4080").
 		Build()
-	
+	if err != nil {
+		fmt.Println("Error:", err)
+	}
 	fmt.Printf("Built computer: %+v\n", computer)
 	
+	// Clone copies the builder, so the variants don't affect each other
+	if office, err := base.Clone().Build(); err != nil {
+		fmt.Println("Error:", err)
+	} else {
+		fmt.Printf("Office variant (defaults): %+v\n", office)
+	}
+	
+	_, err = hardware.NewComputerBuilder().SetRAM(0).Build()
+	fmt.Printf("Invalid computer: %v\n", err)
+	
 	// Factory pattern with pointers
 	fmt.Println("\nFactory pattern with pointers:")
 	
-	// Create different types of shapes
-	circle := NewShape("circle", 5.0)
-	square := NewShape("square", 4.0)
-	triangle := NewShape("triangle", 3.0)
-	
-	fmt.Printf("Circle: %+v, Area: %.2f\n", circle, circle.Area())
-	fmt.Printf("Square: %+v, Area: %.2f\n", square, square.Area())
-	fmt.Printf("Triangle: %+v, Area: %.2f\n", triangle, triangle.Area())
+	// Create different types of shapes (the factory returns (Shape, error))
+	for _, s := range []struct {
+		label, kind string
+		size        float64
+	}{{"Circle", "circle", 5.0}, {"Square", "square", 4.0}, {"Triangle", "triangle", 3.0}} {
+		shape, err := shapes.New(s.kind, s.size)
+		if err != nil {
+			fmt.Println("Error:", err)
+			continue
+		}
+		fmt.Printf("%s: %+v, Area: %.2f\n", s.label, shape, shape.Area())
+	}
 	
 	// Singleton pattern (using pointers)
 	fmt.Println("\nSingleton pattern (using pointers):")
@@ -418,10 +464,53 @@ func section6_CommonPointerPatterns() {
 	fmt.Printf("config2 address: %p\n", config2)
 	fmt.Printf("Are they the same instance? %t\n", config1 == config2)
 	
-	// Modify config through one reference
-	config1.SetTheme("dark")
-	fmt.Printf("config1 theme: %s\n", config1.GetTheme())
-	fmt.Printf("config2 theme: %s\n", config2.GetTheme())  // Same instance!
+	// Changes publish a new snapshot; anyone holding the old one keeps
+	// a consistent view of it
+	before := config1.Get()
+	if err := config1.Update(func(c *Config) { c.Theme = "dark" }); err != nil {
+		fmt.Println("Update failed:", err)
+	}
+	fmt.Printf("config1 theme: %s\n", config1.Get().Theme)
+	fmt.Printf("config2 theme: %s\n", config2.Get().Theme)  // Same instance!
+	fmt.Printf("Earlier snapshot theme: %s\n", before.Theme) // Unchanged!
+	
+	// Invalid changes are rejected and the old configuration stays
+	err = config1.Update(func(c *Config) { c.Timezone = "Mars/Olympus_Mons" })
+	fmt.Printf("Invalid update: %v\n", err)
+	fmt.Printf("Timezone is still: %s\n", config1.Get().Timezone)
+	
+	// Readers never block and never see a half-written config, even
+	// while another goroutine keeps updating it; config/store_test.go
+	// checks that under the race detector
+	var wg sync.WaitGroup
+	var darkReads atomic.Int64
+	for range 4 {
+		wg.Go(func() {
+			for range 1000 {
+				if config1.Get().Theme == "dark" {
+					darkReads.Add(1)
+				}
+			}
+		})
+	}
+	for i := range 100 {
+		theme := []string{"light", "dark"}[i%2]
+		if err := config1.Update(func(c *Config) { c.Theme = theme }); err != nil {
+			fmt.Println("Update failed:", err)
+			break
+		}
+	}
+	wg.Wait()
+	fmt.Printf("4 readers made 4000 reads alongside 100 updates; %d saw the dark theme\n", darkReads.Load())
+	fmt.Println("go test -race ./config checks that no read sees a half-written config")
+	
+	// Layered loading: defaults, then a file, then environment
+	// variables, then flags, each overriding the last
+	demonstrateLayeredConfig()
+	
+	// Hot reload: a watcher polls the file, and subscribers hear about
+	// every change instead of holding stale values
+	demonstrateConfigReload()
 	
 	// Linked list pattern
 	fmt.Println("\nLinked list pattern:")
@@ -442,13 +531,34 @@ func section6_CommonPointerPatterns() {
 	newNode := &LinkedList{Value: "fourth"}
 	list.Next.Next.Next = newNode
 	
-	// Print again
-	current = list
-	for current != nil {
-		fmt.Printf("Node: %s -> ", current.Value)
-		current = current.Next
+	// Print again, this time with a range-over-func iterator
+	for value := range list.All() {
+		fmt.Printf("Node: %s -> ", value)
 	}
 	fmt.Println("nil")
+
+	// Iterators compose lazily: only the nodes that are needed are visited
+	upper := seq.Map(list.All(), strings.ToUpper)
+	fmt.Printf("First two, upper-cased: %v\n", slices.Collect(seq.Take(upper, 2)))
+
+	// A node that points back to an earlier one makes the loops above run
+	// forever. FindCycle spots that with two pointers moving at different speeds.
+	newNode.Next = list.Next
+	next := func(n *LinkedList) *LinkedList { return n.Next }
+	if start, length := lists.FindCycle(list, next); start != nil {
+		fmt.Printf("Cycle found: %d nodes starting at %q\n", length, start.Value)
+	}
+	newNode.Next = nil
+
+	// lists.List keeps pointers in both directions and tracks both ends,
+	// so adding at either end no longer means walking list.Next.Next.Next
+	fmt.Println("\nDoubly linked list:")
+	doubly := lists.New("first", "second", "third")
+	doubly.PushBack("fourth")
+	doubly.PushFront("zeroth")
+	doubly.InsertAfter("second-and-a-half", doubly.Front().Next().Next())
+	fmt.Printf("Forward: %v\n", doubly.Values())
+	fmt.Printf("Backward: %v\n", slices.Collect(doubly.Backward()))
 }
 
 // ============================================================================
@@ -495,6 +605,54 @@ func section7_CopyingVsSharingState() {
 	fmt.Printf("After modifying deep copy:\n")
 	fmt.Printf("  Original: %+v\n", originalPerson)   // Unchanged!
 	fmt.Printf("  Deep:     %+v\n", deepCopy)
+
+	// Diff walks both values and says exactly where they disagree
+	fmt.Println("Differences between original and deep copy:")
+	for _, d := range reflectutil.Diff(originalPerson, deepCopy) {
+		fmt.Printf("  %s\n", d)
+	}
+
+	// DeepCopy follows interface values too, so nested data inside
+	// map[string]interface{} is copied rather than shared
+	fmt.Println("\nDeep copying a LargeStruct:")
+	account := LargeStruct{
+		ID:          "user123",
+		Name:        "Alice Johnson",
+		Preferences: map[string]string{"theme": "dark"},
+		Tags:        []string{"premium", "verified"},
+		Metadata: map[string]interface{}{
+			"source":   "web",
+			"devices":  []string{"laptop", "phone"},
+			"referrer": map[string]interface{}{"campaign": "winter2024"},
+		},
+	}
+	accountCopy := reflectutil.DeepCopy(account)
+	fmt.Printf("Equal after copy? %t\n", reflectutil.DeepEqual(account, accountCopy))
+
+	accountCopy.Tags = append(accountCopy.Tags, "beta")
+	accountCopy.Metadata["devices"].([]string)[1] = "tablet"
+	accountCopy.Metadata["referrer"].(map[string]interface{})["campaign"] = "spring2025"
+	fmt.Printf("Original devices: %v\n", account.Metadata["devices"])   // Unchanged!
+	fmt.Println("Differences after modifying the copy:")
+	for _, d := range reflectutil.Diff(account, accountCopy) {
+		fmt.Printf("  %s\n", d)
+	}
+
+	// Persistent collections never change in place, so a plain assignment
+	// is already a safe copy and nothing has to be copied up front
+	fmt.Println("\nPersistent collections:")
+	sharedPerson := SharedPerson{
+		Name:     "Alice",
+		Hobbies:  persistent.VectorOf("reading", "swimming"),
+		Metadata: persistent.MapOf(map[string]string{"city": "New York", "country": "USA"}),
+	}
+	cheapCopy := sharedPerson
+	cheapCopy.Hobbies = cheapCopy.Hobbies.Set(0, "coding")
+	cheapCopy.Metadata = cheapCopy.Metadata.Set("city", "Boston")
+
+	fmt.Printf("After modifying the copy:\n")
+	fmt.Printf("  Original: %+v\n", sharedPerson)   // Unchanged!
+	fmt.Printf("  Copy:     %+v\n", cheapCopy)
 	
 	// Pointer sharing
 	fmt.Println("\nPointer sharing:")
@@ -585,6 +743,14 @@ type Person struct {
 	Metadata map[string]string
 }
 
+// SharedPerson is Person with persistent collections instead of a slice
+// and a map, so copies never need a DeepCopy
+type SharedPerson struct {
+	Name     string
+	Hobbies  persistent.Vector[string]
+	Metadata persistent.Map[string, string]
+}
+
 // LargeStruct represents a large data structure
 type LargeStruct struct {
 	ID          string
@@ -607,24 +773,6 @@ type LargeStruct struct {
 	Metadata    map[string]interface{}
 }
 
-// Shape represents different geometric shapes
-type Shape struct {
-	Type   string
-	Size   float64
-	Radius float64
-	Side   float64
-	Base   float64
-	Height float64
-}
-
-// Computer represents a computer system
-type Computer struct {
-	CPU     string
-	RAM     int
-	Storage string
-	GPU     string
-}
-
 // Config represents application configuration
 type Config struct {
 	Theme     string
@@ -639,6 +787,14 @@ type LinkedList struct {
 	Next  *LinkedList
 }
 
+// All returns an iterator over the values from this node to the end of
+// the list
+func (l *LinkedList) All() iter.Seq[string] {
+	return seq.Linked(l,
+		func(n *LinkedList) *LinkedList { return n.Next },
+		func(n *LinkedList) string { return n.Value })
+}
+
 // Person methods
 func (p Person) Introduce() {
 	fmt.Printf("Hi, I'm %s and I'm %d years old.\n", p.Name, p.Age)
@@ -658,24 +814,9 @@ func (p *Person) UpdateEmail(newEmail string) {
 	fmt.Printf("Email updated to: %s\n", p.Email)
 }
 
+// DeepCopy returns a copy of p that shares no slices or maps with it
 func (p Person) DeepCopy() Person {
-	// Deep copy the slices and maps
-	hobbiesCopy := make([]string, len(p.Hobbies))
-	copy(hobbiesCopy, p.Hobbies)
-	
-	metadataCopy := make(map[string]string)
-	for k, v := range p.Metadata {
-		metadataCopy[k] = v
-	}
-	
-	return Person{
-		Name:     p.Name,
-		Age:      p.Age,
-		Email:    p.Email,
-		IsActive: p.IsActive,
-		Hobbies:  hobbiesCopy,
-		Metadata: metadataCopy,
-	}
+	return reflectutil.DeepCopy(p)
 }
 
 // LargeStruct methods
@@ -687,100 +828,214 @@ func (ls *LargeStruct) GetInfoPointer() string {
 	return fmt.Sprintf("User %s (%s) is %d years old", ls.Name, ls.Email, ls.Age)
 }
 
-// Shape methods
-func (s Shape) Area() float64 {
-	switch s.Type {
-	case "circle":
-		return math.Pi * s.Radius * s.Radius
-	case "square":
-		return s.Side * s.Side
-	case "triangle":
-		return 0.5 * s.Base * s.Height
-	default:
-		return 0
-	}
-}
-
-// Computer builder
-type ComputerBuilder struct {
-	computer Computer
-}
-
-func NewComputerBuilder() *ComputerBuilder {
-	return &ComputerBuilder{}
-}
-
-func (cb *ComputerBuilder) SetCPU(cpu string) *ComputerBuilder {
-	cb.computer.CPU = cpu
-	return cb
-}
-
-func (cb *ComputerBuilder) SetRAM(ram int) *ComputerBuilder {
-	cb.computer.RAM = ram
-	return cb
-}
-
-func (cb *ComputerBuilder) SetStorage(storage string) *ComputerBuilder {
-	cb.computer.Storage = storage
-	return cb
-}
-
-func (cb *ComputerBuilder) SetGPU(gpu string) *ComputerBuilder {
-	cb.computer.GPU = gpu
-	return cb
+// defaultConfig is the configuration before any file, environment
+// variable or flag changes it
+var defaultConfig = Config{
+	Theme:     "light",
+	Language:  "en",
+	Timezone:  "UTC",
+	DebugMode: false,
 }
 
-func (cb *ComputerBuilder) Build() Computer {
-	return cb.computer
-}
+// GetConfig returns the shared configuration, loading it on first use.
+// sync.OnceValue makes that safe when several goroutines call it at
+// once, which a plain nil check is not
+var GetConfig = sync.OnceValue(func() *config.Store[Config] {
+	store, err := config.Load(&config.Loader[Config]{
+		Defaults:  defaultConfig,
+		EnvPrefix: "GOPRACTICE_",
+	})
+	if err != nil {
+		fmt.Println("Ignoring invalid configuration:", err)
+		fallback := defaultConfig
+		return config.NewStore(&fallback)
+	}
+	return store
+})
 
-// Shape factory
-func NewShape(shapeType string, size float64) *Shape {
-	switch shapeType {
-	case "circle":
-		return &Shape{Type: "circle", Radius: size}
-	case "square":
-		return &Shape{Type: "square", Side: size}
-	case "triangle":
-		return &Shape{Type: "triangle", Base: size, Height: size}
-	default:
-		return &Shape{Type: "unknown"}
+// Validate checks the settings; the config package calls it on every
+// load and update
+func (c Config) Validate() error {
+	var problems []error
+	if c.Theme != "light" && c.Theme != "dark" {
+		problems = append(problems, &errs.ValidationError{
+			Field: "theme", Message: "must be light or dark", Value: c.Theme,
+		})
 	}
+	if len(c.Language) != 2 {
+		problems = append(problems, &errs.ValidationError{
+			Field: "language", Message: "must be a two-letter code", Value: c.Language,
+		})
+	}
+	if _, err := time.LoadLocation(c.Timezone); err != nil {
+		problems = append(problems, &errs.ValidationError{
+			Field: "timezone", Message: "unknown time zone", Value: c.Timezone,
+		})
+	}
+	return errors.Join(problems...)
 }
 
-// Config singleton
-var configInstance *Config
-
-func GetConfig() *Config {
-	if configInstance == nil {
-		configInstance = &Config{
-			Theme:     "light",
-			Language:  "en",
-			Timezone:  "UTC",
-			DebugMode: false,
+func demonstrateLayeredConfig() {
+	fmt.Println("\nLayered configuration:")
+	
+	dir, err := os.MkdirTemp("", "ch9-config")
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer os.RemoveAll(dir)
+	
+	file := filepath.Join(dir, "app.toml")
+	if err := os.WriteFile(file, []byte("theme = \"dark\"\nlanguage = \"fr\"\n"), 0o644); err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	env := map[string]string{"APP_TIMEZONE": "Europe/Paris"}
+	
+	loader := &config.Loader[Config]{
+		Defaults:  defaultConfig,
+		File:      file,
+		EnvPrefix: "APP_",
+		LookupEnv: func(key string) (string, bool) {
+			v, ok := env[key]
+			return v, ok
+		},
+	}
+	flags := flag.NewFlagSet("app", flag.ContinueOnError)
+	if err := loader.RegisterFlags(flags); err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	if err := flags.Parse([]string{"-language", "de", "-debug-mode"}); err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	
+	cfg, err := loader.Load()
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	fmt.Printf("defaults:      %+v\n", defaultConfig)
+	fmt.Printf("after layers:  %+v\n", *cfg)
+	fmt.Println("(theme from the file, timezone from APP_TIMEZONE, language and debug mode from flags)")
+	
+	// Every setting that fails to parse is reported, each naming where
+	// it came from; Validate only runs once they all parse
+	env["APP_DEBUG_MODE"] = "maybe"
+	if err := os.WriteFile(file, []byte("theme = \"neon\"\ncolour = \"red\"\n"), 0o644); err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	// no flags this time
+	if err := loader.RegisterFlags(flag.NewFlagSet("app", flag.ContinueOnError)); err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	if _, err := loader.Load(); err != nil {
+		for _, ve := range errs.ValidationErrors(err) {
+			fmt.Printf("  %s\n", ve)
 		}
 	}
-	return configInstance
 }
 
-func (c *Config) SetTheme(theme string) {
-	c.Theme = theme
+func demonstrateConfigReload() {
+	fmt.Println("\nHot reload and change events:")
+	
+	dir, err := os.MkdirTemp("", "ch9-reload")
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	defer os.RemoveAll(dir)
+	
+	file := filepath.Join(dir, "app.yaml")
+	if err := os.WriteFile(file, []byte("theme: light\n"), 0o644); err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	
+	loader := &config.Loader[Config]{Defaults: defaultConfig, File: file}
+	store, err := config.Load(loader)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	
+	// The callbacks give up once the demo is over, so a late event can't
+	// leave the watcher or the store blocked on a send nobody receives
+	ctx, stop := context.WithCancel(context.Background())
+	events := make(chan config.Event[Config])
+	unsubscribe := store.Subscribe(func(ev config.Event[Config]) {
+		select {
+		case events <- ev:
+		case <-ctx.Done():
+		}
+	})
+	defer unsubscribe()
+	
+	reloadErrors := make(chan error)
+	watcher := &config.Watcher[Config]{
+		Store:    store,
+		Loader:   loader,
+		Interval: 10 * time.Millisecond,
+		OnError: func(err error) {
+			select {
+			case reloadErrors <- err:
+			case <-ctx.Done():
+			}
+		},
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		watcher.Run(ctx)
+	}()
+	defer func() {
+		stop()
+		<-done
+	}()
+	
+	// Someone edits the file. Editors save by renaming a finished file
+	// into place, so the watcher never sees it half written
+	if err := replaceFile(file, []byte("theme: dark\nlanguage: es\n")); err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	ev := <-events
+	for _, change := range ev.Changes {
+		fmt.Printf("  %s changed: %v -> %v\n", change.Field, change.Old, change.New)
+	}
+	
+	// ...and then breaks it. The reload fails and the old config stays
+	if err := replaceFile(file, []byte("theme: neon\nlanguage: es\n")); err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	fmt.Printf("  reload failed: %v\n", <-reloadErrors)
+	fmt.Printf("  theme is still: %s\n", store.Get().Theme)
 }
 
-func (c *Config) GetTheme() string {
-	return c.Theme
+// replaceFile writes data to a temporary file next to path and renames it
+// over path, so readers see either the old contents or the new, never a
+// truncated file.
+func replaceFile(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
 }
-
-// Helper functions
-func estimateStructSize(s LargeStruct) int {
-	// Rough estimation of struct size
-	size := 0
-	size += len(s.ID) + len(s.Name) + len(s.Email) + len(s.Phone)
-	size += len(s.Address) + len(s.City) + len(s.State) + len(s.ZipCode)
-	size += len(s.Country) + len(s.DateOfBirth) + len(s.JoinDate) + len(s.LastLogin)
-	size += len(s.Preferences) * 20  // Rough estimate for map
-	size += len(s.Tags) * 10         // Rough estimate for slice
-	size += len(s.Metadata) * 30     // Rough estimate for interface{} map
-	size += 50  // Fixed fields (int, bool, etc.)
-	return size
-} 
\ No newline at end of file
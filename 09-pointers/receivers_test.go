@@ -0,0 +1,249 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+	"unsafe"
+)
+
+// The benchmarks measure what section 4 only claims: how much a value
+// receiver costs compared with a pointer receiver as the struct grows.
+//
+//	go test ./09-pointers -run '^$' -bench Receiver -benchmem
+//	go run ./cmd/receiverbench                 # value and pointer side by side
+//	go run ./cmd/receiverbench -format benchstat -count 10 > new.txt
+//
+// Each size comes as a pair of identical types, one with a value receiver
+// and one with a pointer receiver. The methods are kept out of line so the
+// benchmark measures the call, and with it the receiver copy, rather than
+// whatever the inliner leaves behind. TestReceiverEscapes checks what the
+// compiler's escape analysis decides for this file.
+
+type summer interface{ Sum() int }
+
+type val16 struct{ data [16]byte }
+type ptr16 struct{ data [16]byte }
+
+//go:noinline
+func (v val16) Sum() int { return int(v.data[0]) + len(v.data) }
+
+//go:noinline
+func (p *ptr16) Sum() int { return int(p.data[0]) + len(p.data) }
+
+type val64 struct{ data [64]byte }
+type ptr64 struct{ data [64]byte }
+
+//go:noinline
+func (v val64) Sum() int { return int(v.data[0]) + len(v.data) }
+
+//go:noinline
+func (p *ptr64) Sum() int { return int(p.data[0]) + len(p.data) }
+
+type val256 struct{ data [256]byte }
+type ptr256 struct{ data [256]byte }
+
+//go:noinline
+func (v val256) Sum() int { return int(v.data[0]) + len(v.data) }
+
+//go:noinline
+func (p *ptr256) Sum() int { return int(p.data[0]) + len(p.data) }
+
+type val1024 struct{ data [1024]byte }
+type ptr1024 struct{ data [1024]byte }
+
+//go:noinline
+func (v val1024) Sum() int { return int(v.data[0]) + len(v.data) }
+
+//go:noinline
+func (p *ptr1024) Sum() int { return int(p.data[0]) + len(p.data) }
+
+type val4096 struct{ data [4096]byte }
+type ptr4096 struct{ data [4096]byte }
+
+//go:noinline
+func (v val4096) Sum() int { return int(v.data[0]) + len(v.data) }
+
+//go:noinline
+func (p *ptr4096) Sum() int { return int(p.data[0]) + len(p.data) }
+
+type valLarge struct{ LargeStruct }
+type ptrLarge struct{ LargeStruct }
+
+//go:noinline
+func (v valLarge) Sum() int { return len(v.Name) + v.Age }
+
+//go:noinline
+func (p *ptrLarge) Sum() int { return len(p.Name) + p.Age }
+
+var sink int
+
+var receiverNames = [2]string{"value", "pointer"}
+
+// receiverCase is one struct size. call and box run the same loop with a
+// value and with a pointer receiver; index 0 is the value receiver.
+type receiverCase struct {
+	name string
+	size uintptr
+
+	// call invokes Sum directly on a local variable.
+	call [2]func(b *testing.B)
+
+	// iface is the receiver already stored in an interface, so only the
+	// dynamic dispatch is measured.
+	iface [2]summer
+
+	// box converts the receiver to an interface on every call, which is
+	// where value receivers start to allocate.
+	box [2]func(b *testing.B)
+}
+
+func newCase[V, P summer](name string, v V, p P, call [2]func(b *testing.B)) receiverCase {
+	c := receiverCase{
+		name:  name,
+		size:  unsafe.Sizeof(v),
+		call:  call,
+		iface: [2]summer{v, p},
+	}
+	c.box = [2]func(b *testing.B){
+		func(b *testing.B) {
+			for b.Loop() {
+				sink += summer(v).Sum()
+			}
+		},
+		func(b *testing.B) {
+			for b.Loop() {
+				sink += summer(p).Sum()
+			}
+		},
+	}
+	return c
+}
+
+func receiverCases() []receiverCase {
+	var (
+		v16, p16     = val16{}, &ptr16{}
+		v64, p64     = val64{}, &ptr64{}
+		v256, p256   = val256{}, &ptr256{}
+		v1024, p1024 = val1024{}, &ptr1024{}
+		v4096, p4096 = val4096{}, &ptr4096{}
+		vl, pl       = valLarge{}, &ptrLarge{}
+	)
+	vl.Name, pl.Name = "Alice Johnson", "Alice Johnson"
+
+	return []receiverCase{
+		newCase("16B", v16, p16, [2]func(b *testing.B){
+			func(b *testing.B) {
+				for b.Loop() {
+					sink += v16.Sum()
+				}
+			},
+			func(b *testing.B) {
+				for b.Loop() {
+					sink += p16.Sum()
+				}
+			},
+		}),
+		newCase("64B", v64, p64, [2]func(b *testing.B){
+			func(b *testing.B) {
+				for b.Loop() {
+					sink += v64.Sum()
+				}
+			},
+			func(b *testing.B) {
+				for b.Loop() {
+					sink += p64.Sum()
+				}
+			},
+		}),
+		newCase("256B", v256, p256, [2]func(b *testing.B){
+			func(b *testing.B) {
+				for b.Loop() {
+					sink += v256.Sum()
+				}
+			},
+			func(b *testing.B) {
+				for b.Loop() {
+					sink += p256.Sum()
+				}
+			},
+		}),
+		newCase("1KiB", v1024, p1024, [2]func(b *testing.B){
+			func(b *testing.B) {
+				for b.Loop() {
+					sink += v1024.Sum()
+				}
+			},
+			func(b *testing.B) {
+				for b.Loop() {
+					sink += p1024.Sum()
+				}
+			},
+		}),
+		newCase("4KiB", v4096, p4096, [2]func(b *testing.B){
+			func(b *testing.B) {
+				for b.Loop() {
+					sink += v4096.Sum()
+				}
+			},
+			func(b *testing.B) {
+				for b.Loop() {
+					sink += p4096.Sum()
+				}
+			},
+		}),
+		newCase("LargeStruct", vl, pl, [2]func(b *testing.B){
+			func(b *testing.B) {
+				for b.Loop() {
+					sink += vl.Sum()
+				}
+			},
+			func(b *testing.B) {
+				for b.Loop() {
+					sink += pl.Sum()
+				}
+			},
+		}),
+	}
+}
+
+// benchReceivers runs one sub-benchmark per size and receiver kind, named
+// so that benchstat can put value and pointer side by side.
+func benchReceivers(b *testing.B, workload func(c receiverCase, i int) func(b *testing.B)) {
+	for _, c := range receiverCases() {
+		for i, recv := range receiverNames {
+			b.Run(fmt.Sprintf("size=%s/receiver=%s", c.name, recv), func(b *testing.B) {
+				b.ReportAllocs()
+				workload(c, i)(b)
+			})
+		}
+	}
+}
+
+// BenchmarkReceiverCall calls Sum directly, where a value receiver costs
+// one copy of the struct.
+func BenchmarkReceiverCall(b *testing.B) {
+	benchReceivers(b, func(c receiverCase, i int) func(b *testing.B) {
+		return c.call[i]
+	})
+}
+
+// BenchmarkReceiverInterface calls Sum through an interface that already
+// holds the receiver.
+func BenchmarkReceiverInterface(b *testing.B) {
+	benchReceivers(b, func(c receiverCase, i int) func(b *testing.B) {
+		s := c.iface[i]
+		return func(b *testing.B) {
+			for b.Loop() {
+				sink += s.Sum()
+			}
+		}
+	})
+}
+
+// BenchmarkReceiverBox converts the receiver to an interface on every
+// call, which allocates for value receivers.
+func BenchmarkReceiverBox(b *testing.B) {
+	benchReceivers(b, func(c receiverCase, i int) func(b *testing.B) {
+		return c.box[i]
+	})
+}
@@ -1,13 +1,22 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"os"
 	"strings"
 	"time"
+
+	"go-practice/logging"
 )
 
+// logger writes structured logs to stderr, apart from the program's
+// output. Errors logged with logging.Err show their fields as separate
+// attributes, and email addresses are redacted.
+var logger = logging.New(os.Stderr, logging.Options{})
+
 // User represents a user in the system
 type User struct {
 	ID   string
@@ -62,6 +71,18 @@ func (e *DatabaseError) IsRetryable() bool {
 	return e.Code == 1001 || e.Code == 1002
 }
 
+func (e *DatabaseError) GetOperation() string {
+	return e.Operation
+}
+
+func (e *DatabaseError) GetTable() string {
+	return e.Table
+}
+
+func (e *DatabaseError) GetCode() int {
+	return e.Code
+}
+
 // NetworkError represents network operation failures
 type NetworkError struct {
 	URL     string        // Which URL failed
@@ -78,6 +99,14 @@ func (e *NetworkError) IsNetworkError() bool {
 	return true
 }
 
+func (e *NetworkError) GetURL() string {
+	return e.URL
+}
+
+func (e *NetworkError) GetTimeout() time.Duration {
+	return e.Timeout
+}
+
 // AggregatedError collects multiple errors
 type AggregatedError struct {
 	Errors []error
@@ -601,11 +630,28 @@ func section6_ErrorHandlingBestPractices() {
 		fmt.Printf("Processing failed: %v\n", err)
 	}
 	
+	// An adult gets past validation to the database, whose failure is
+	// logged in full but reported to the user in general terms
+	adult := User{Name: "Bob", Age: 30, Email: "bob@example.com"}
+	if err := processUserWithErrorTypes(adult); err != nil {
+		fmt.Printf("Processing failed: %v\n", err)
+	}
+	
 	// 2. Logging vs Returning Errors - Don't Mix Responsibilities
 	fmt.Println("\n2. Separating Logging from Error Handling:")
 	
 	handleUserSubmission(user)
 	
+	// A test can swap in a logger that records, and check what was logged
+	saved := logger
+	var recorder *logging.Recorder
+	logger, recorder = logging.NewTest(logging.Options{})
+	handleUserSubmission(user)
+	logger = saved
+	if entry, ok := recorder.Find("user submission failed"); ok {
+		fmt.Println("Logged:", entry)
+	}
+	
 	// 3. Retry Patterns - Don't Give Up Too Easily
 	fmt.Println("\n3. Retry Patterns:")
 	
@@ -644,8 +690,8 @@ func processUserWithErrorTypes(user User) error {
 	
 	// Unexpected error - log it and return generic message
 	if err := connectToDatabase(); err != nil {
-		// Log the full error for debugging
-		log.Printf("Database connection failed: %v", err)
+		// Log the full error, with its fields, for debugging
+		logger.Error("database connection failed", logging.Err(err))
 		// Return generic message to user
 		return errors.New("service temporarily unavailable")
 	}
@@ -677,8 +723,13 @@ func connectToDatabase() error {
 // Separating Logging from Error Handling
 func handleUserSubmission(user User) {
 	if err := processUser(user); err != nil {
-		// Log the error for debugging
-		log.Printf("User submission failed: %v", err)
+		// Log the error for debugging; the user's mistakes are only warnings
+		level := slog.LevelError
+		if IsValidationError(err) {
+			level = slog.LevelWarn
+		}
+		logger.Log(context.Background(), level, "user submission failed",
+			"name", user.Name, "email", user.Email, logging.Err(err))
 		
 		// Show appropriate message to user
 		if IsValidationError(err) {
@@ -0,0 +1,221 @@
+// Package address validates, normalizes, parses and formats postal
+// addresses, following the chapter 7 Address type. Each country's rules
+// (postal code patterns, state and province codes, street abbreviations
+// and label layouts) come from a table built into the package, so
+// nothing needs a network connection.
+package address
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"go-practice/errs"
+)
+
+// ErrInvalidPostalCode is wrapped by every postal code failure.
+var ErrInvalidPostalCode = errors.New("invalid postal code")
+
+// ErrUnknownRegion is wrapped when State isn't one of the country's
+// states or provinces.
+var ErrUnknownRegion = errors.New("unknown region")
+
+// Address is the chapter 7 Address with a country, since the rules for
+// the other fields depend on it. State holds whatever the country calls
+// its regions, and PostalCode holds a ZIP code or postcode.
+type Address struct {
+	Street     string
+	City       string
+	State      string
+	PostalCode string
+	Country    string // a code, name or alias; Normalize sets the code
+}
+
+// Normalize checks a against the rules of its country and returns it in
+// standard form: spacing tidied, words capitalized, street words
+// abbreviated, the region as a code, the postal code upper case and
+// spaced, and the country as a code. Every problem is reported at once
+// in an *errs.AggregatedError of *errs.ValidationError values.
+func (r *Rules) Normalize(a Address) (Address, error) {
+	c, err := r.Lookup(a.Country)
+	if err != nil {
+		field := &errs.ValidationError{Field: "Country", Value: a.Country, Err: err}
+		if clean(a.Country) == "" {
+			field = &errs.ValidationError{Field: "Country", Message: "is required"}
+		}
+		return a, errs.Aggregate("address", field)
+	}
+
+	n := Address{
+		Street:     c.recase(clean(a.Street), true),
+		City:       c.recase(clean(a.City), false),
+		PostalCode: strings.ToUpper(strings.Join(strings.Fields(a.PostalCode), "")),
+		Country:    c.Code,
+	}
+	var problems []error
+	if n.Street == "" {
+		problems = append(problems, &errs.ValidationError{Field: "Street", Message: "is required"})
+	}
+	if n.City == "" {
+		problems = append(problems, &errs.ValidationError{Field: "City", Message: "is required"})
+	}
+
+	state := clean(a.State)
+	switch code, ok := c.Region(state); {
+	case state == "" && c.RegionRequired:
+		problems = append(problems, &errs.ValidationError{Field: "State", Message: "is required"})
+	case state == "":
+	case ok:
+		n.State = code
+	case len(c.Regions) > 0:
+		problems = append(problems, &errs.ValidationError{
+			Field:   "State",
+			Message: fmt.Sprintf("isn't a %s of %s", strings.ToLower(c.RegionLabel), c.Name),
+			Value:   a.State,
+			Err:     ErrUnknownRegion,
+		})
+	default:
+		n.State = c.recase(state, false)
+	}
+
+	switch {
+	case n.PostalCode == "":
+		problems = append(problems, &errs.ValidationError{Field: "PostalCode", Message: "is required"})
+	case !c.postal.MatchString(n.PostalCode):
+		problems = append(problems, &errs.ValidationError{
+			Field:   "PostalCode",
+			Message: fmt.Sprintf("isn't a valid %s %s", c.Name, c.PostalLabel),
+			Value:   a.PostalCode,
+			Err:     ErrInvalidPostalCode,
+		})
+	default:
+		n.PostalCode = c.formatPostal(n.PostalCode)
+		if prefixes := c.PostalPrefixes[n.State]; len(prefixes) > 0 && !hasAnyPrefix(n.PostalCode, prefixes) {
+			problems = append(problems, &errs.ValidationError{
+				Field:   "PostalCode",
+				Message: fmt.Sprintf("isn't in %s", c.Regions[n.State]),
+				Value:   a.PostalCode,
+				Err:     ErrInvalidPostalCode,
+			})
+		}
+	}
+
+	if err := errs.Aggregate("address", problems...); err != nil {
+		return a, err
+	}
+	return n, nil
+}
+
+// Validate reports the problems Normalize would, without the result.
+func (r *Rules) Validate(a Address) error {
+	_, err := r.Normalize(a)
+	return err
+}
+
+// Normalize normalizes a with the built-in rules; see Rules.Normalize.
+func Normalize(a Address) (Address, error) { return DefaultRules().Normalize(a) }
+
+// clean collapses runs of white space and trims stray commas.
+func clean(s string) string {
+	return strings.Trim(strings.Join(strings.Fields(s), " "), ", ")
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// formatPostal respaces a postal code that already matches the pattern.
+func (c *Country) formatPostal(code string) string {
+	code = strings.NewReplacer(" ", "", "-", "").Replace(code)
+	split := func(n int) string { return code[:len(code)-n] + " " + code[len(code)-n:] }
+	switch {
+	case c.PostalFormat == "zip" && len(code) == 9:
+		return code[:5] + "-" + code[5:]
+	case c.PostalFormat == "split3" && len(code) > 3:
+		return split(3)
+	case c.PostalFormat == "split2" && len(code) > 2:
+		return split(2)
+	}
+	return code
+}
+
+// recase capitalizes each word of s. Words typed in mixed case, such as
+// "McDonald", are taken to be deliberate and kept. With street set,
+// words are also abbreviated by the country's table.
+func (c *Country) recase(s string, street bool) string {
+	words := strings.Fields(s)
+	for i, w := range words {
+		if street {
+			if short, ok := c.abbreviate(w); ok {
+				words[i] = short
+				continue
+			}
+		}
+		lower := strings.ToLower(w)
+		switch {
+		case w != lower && w != strings.ToUpper(w):
+			// mixed case: leave it
+		case startsWithDigit(w):
+			words[i] = numberCase(w)
+		case i > 0 && slices.Contains(c.Lowercase, lower):
+			words[i] = lower
+		default:
+			words[i] = capitalize(lower)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// abbreviate returns the standard form of a street word, such as "St"
+// for "street", "STREET" or "st.", and rewrites word endings such as
+// German "strasse".
+func (c *Country) abbreviate(w string) (string, bool) {
+	key := strings.ToLower(strings.TrimSuffix(w, "."))
+	if short, ok := c.Abbreviations[key]; ok {
+		return short, true
+	}
+	if short, ok := c.short[key]; ok {
+		return short, true
+	}
+	lower := strings.ToLower(w)
+	for ending, repl := range c.Endings {
+		if len(lower) > len(ending) && strings.HasSuffix(lower, ending) {
+			return capitalize(lower[:len(lower)-len(ending)] + repl), true
+		}
+	}
+	return "", false
+}
+
+func startsWithDigit(w string) bool { return w[0] >= '0' && w[0] <= '9' }
+
+// numberCase writes house numbers such as "12B" in upper case and
+// ordinals such as "5th" in lower case.
+func numberCase(w string) string {
+	lower := strings.ToLower(w)
+	for _, suffix := range []string{"st", "nd", "rd", "th"} {
+		digits := strings.TrimSuffix(lower, suffix)
+		if digits != lower && digits != "" && strings.Trim(digits, "0123456789") == "" {
+			return lower
+		}
+	}
+	return strings.ToUpper(w)
+}
+
+// capitalize upper-cases the first letter of w and of each part after a
+// hyphen, and after an apostrophe in second place, as in "O'Brien".
+func capitalize(w string) string {
+	runes := []rune(w)
+	for i, r := range runes {
+		if i == 0 || runes[i-1] == '-' || (i == 2 && runes[1] == '\'') {
+			runes[i] = unicode.ToUpper(r)
+		}
+	}
+	return string(runes)
+}
@@ -0,0 +1,188 @@
+package address
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"go-practice/errs"
+)
+
+// ErrUnknownCountry is returned for a country that isn't in the rules.
+var ErrUnknownCountry = errors.New("unknown country")
+
+// Country is one country's address format, as read from the rules file.
+type Country struct {
+	Code    string   `json:"code"` // ISO 3166 alpha-2, e.g. "US"
+	Name    string   `json:"name"`
+	Aliases []string `json:"aliases"` // other names Lookup accepts
+
+	PostalLabel   string `json:"postal_label"`   // what the postal code is called, e.g. "ZIP code"
+	PostalPattern string `json:"postal_pattern"` // a regular expression for the upper-cased code
+	// PostalFormat respaces a valid code: "zip" puts a dash before the
+	// last four digits of nine, "split3" and "split2" put a space before
+	// the last three or two characters, and "" leaves it alone.
+	PostalFormat string `json:"postal_format"`
+
+	RegionLabel    string            `json:"region_label"` // what the State field holds, e.g. "Province"
+	RegionRequired bool              `json:"region_required"`
+	Regions        map[string]string `json:"regions"` // code to name; empty means any text
+	// PostalPrefixes lists, per region code, how that region's postal
+	// codes start, to catch a postal code from the wrong region.
+	PostalPrefixes map[string][]string `json:"postal_prefixes"`
+
+	// Label is the layout of an address label, one string per line, with
+	// {street}, {city}, {state} and {postal} placeholders. {CITY} is the
+	// city in upper case.
+	Label []string `json:"label"`
+
+	Abbreviations map[string]string `json:"abbreviations"` // lower-case street word to its standard form
+	Endings       map[string]string `json:"endings"`       // lower-case word endings to replace, e.g. "strasse"
+	Lowercase     []string          `json:"lowercase"`     // particles such as "van" that stay lower case
+
+	postal  *regexp.Regexp
+	strict  *regexp.Regexp // parses a whole address, with a known region
+	loose   *regexp.Regexp // parses a whole address, with any region
+	regions map[string]string
+	short   map[string]string
+}
+
+// Rules holds the address formats of a set of countries. It is read-only
+// once loaded, so it is safe for concurrent use.
+type Rules struct {
+	byCode map[string]*Country
+	byName map[string]*Country // lower-case names, aliases and codes
+}
+
+//go:embed countries.json
+var defaultRules []byte
+
+var loadDefault = sync.OnceValue(func() *Rules {
+	r, err := ParseRules(defaultRules)
+	if err != nil {
+		panic("address: built-in rules: " + err.Error())
+	}
+	return r
+})
+
+// DefaultRules returns the rules built into the package, which cover the
+// United States, Canada, the United Kingdom, Australia, Germany and the
+// Netherlands.
+func DefaultRules() *Rules { return loadDefault() }
+
+// LoadRules reads a rules file; see ParseRules.
+func LoadRules(path string) (*Rules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("address: %w", err)
+	}
+	return ParseRules(data)
+}
+
+// ParseRules decodes a JSON list of countries in the format of the
+// built-in countries.json. Missing codes or names, repeated codes, bad
+// postal patterns and label layouts without a {street} line are all
+// reported together as *errs.ValidationError values.
+func ParseRules(data []byte) (*Rules, error) {
+	var countries []*Country
+	if err := json.Unmarshal(data, &countries); err != nil {
+		return nil, fmt.Errorf("address: rules: %w", err)
+	}
+
+	r := &Rules{byCode: make(map[string]*Country), byName: make(map[string]*Country)}
+	var problems []error
+	for i, c := range countries {
+		field := func(name string) string { return fmt.Sprintf("countries[%d].%s", i, name) }
+		c.Code = strings.ToUpper(strings.TrimSpace(c.Code))
+		switch {
+		case c.Code == "":
+			problems = append(problems, &errs.ValidationError{Field: field("code"), Message: "is required"})
+			continue
+		case r.byCode[c.Code] != nil:
+			problems = append(problems, &errs.ValidationError{Field: field("code"), Message: "is a duplicate", Value: c.Code})
+			continue
+		}
+		if strings.TrimSpace(c.Name) == "" {
+			problems = append(problems, &errs.ValidationError{Field: field("name"), Message: "is required"})
+		}
+		if !slices.Contains(c.Label, "{street}") {
+			problems = append(problems, &errs.ValidationError{Field: field("label"), Message: "needs a {street} line", Value: c.Label})
+		}
+		if err := c.compile(); err != nil {
+			problems = append(problems, &errs.ValidationError{Field: field("postal_pattern"), Value: c.PostalPattern, Err: err})
+			continue
+		}
+		r.byCode[c.Code] = c
+		for _, name := range append([]string{c.Code, c.Name}, c.Aliases...) {
+			r.byName[strings.ToLower(name)] = c
+		}
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("address: rules: %w", errors.Join(problems...))
+	}
+	return r, nil
+}
+
+// Lookup finds a country by code, name or alias, ignoring case.
+func (r *Rules) Lookup(country string) (*Country, error) {
+	c, ok := r.byName[strings.ToLower(clean(country))]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownCountry, country)
+	}
+	return c, nil
+}
+
+// Countries returns every country, ordered by code.
+func (r *Rules) Countries() []*Country {
+	all := make([]*Country, 0, len(r.byCode))
+	for _, c := range r.byCode {
+		all = append(all, c)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Code < all[j].Code })
+	return all
+}
+
+// Region returns the code of the region with the given code or name,
+// ignoring case.
+func (c *Country) Region(s string) (code string, ok bool) {
+	code, ok = c.regions[strings.ToLower(clean(s))]
+	return code, ok
+}
+
+// compile builds the lookup tables and regular expressions, once, when
+// the rules are parsed.
+func (c *Country) compile() error {
+	var err error
+	if c.postal, err = regexp.Compile(c.PostalPattern); err != nil {
+		return err
+	}
+	c.regions = make(map[string]string, 2*len(c.Regions))
+	names := make([]string, 0, 2*len(c.Regions))
+	for code, name := range c.Regions {
+		c.regions[strings.ToLower(code)] = code
+		c.regions[strings.ToLower(name)] = code
+		names = append(names, regexp.QuoteMeta(code), regexp.QuoteMeta(name))
+	}
+	c.short = make(map[string]string, len(c.Abbreviations))
+	for _, short := range c.Abbreviations {
+		c.short[strings.ToLower(short)] = short
+	}
+
+	// try longer names first, so "West Virginia" beats "Virginia"
+	sort.Slice(names, func(i, j int) bool { return len(names[i]) > len(names[j]) })
+	if len(names) > 0 {
+		if c.strict, err = c.layout(`[^,\n]+?`, strings.Join(names, "|")); err != nil {
+			return err
+		}
+	}
+	// with any region allowed, a greedy city keeps it to the last word
+	c.loose, err = c.layout(`[^,\n]+`, `[^,\n]+?`)
+	return err
+}
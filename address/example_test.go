@@ -0,0 +1,50 @@
+package address_test
+
+import (
+	"fmt"
+
+	"go-practice/address"
+	"go-practice/errs"
+)
+
+func ExampleNormalize() {
+	a, err := address.Normalize(address.Address{
+		Street: "123  main street", City: "NEW YORK", State: "new york",
+		PostalCode: "10001", Country: "USA",
+	})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Printf("%+v\n", a)
+
+	// Every problem comes back as its own ValidationError
+	_, err = address.Normalize(address.Address{
+		Street: "1 Main St", City: "Boston", State: "MA", PostalCode: "90210", Country: "US",
+	})
+	for _, problem := range errs.ValidationErrors(err) {
+		fmt.Println(problem)
+	}
+	// Output:
+	// {Street:123 Main St City:New York State:NY PostalCode:10001 Country:US}
+	// validation failed for PostalCode: isn't in Massachusetts (value: 90210)
+}
+
+func ExampleParse() {
+	a, err := address.Parse("24 sussex drive, ottawa ON k1m1m4, Canada", "")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	// Posting from the US adds the country's name to the label
+	label, err := address.Label(a, "US")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(label)
+	// Output:
+	// 24 Sussex Dr
+	// Ottawa ON K1M 1M4
+	// CANADA
+}
@@ -0,0 +1,41 @@
+package address
+
+import (
+	"strings"
+)
+
+// Label normalizes a and lays it out as a mailing label, one line per
+// line of the country's layout. Mail sent from another country gets the
+// destination country's name in capitals as a last line; from may be a
+// code, name or alias, and "" means the same country as a.
+func (r *Rules) Label(a Address, from string) (string, error) {
+	n, err := r.Normalize(a)
+	if err != nil {
+		return "", err
+	}
+	c, _ := r.Lookup(n.Country)
+	fields := strings.NewReplacer(
+		"{street}", n.Street,
+		"{city}", n.City,
+		"{CITY}", strings.ToUpper(n.City),
+		"{state}", n.State,
+		"{postal}", n.PostalCode,
+	)
+
+	var lines []string
+	for _, layout := range c.Label {
+		// a line whose fields are all empty is left out
+		if line := clean(fields.Replace(layout)); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if from != "" {
+		if home, err := r.Lookup(from); err != nil || home != c {
+			lines = append(lines, strings.ToUpper(c.Name))
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// Label formats a with the built-in rules; see Rules.Label.
+func Label(a Address, from string) (string, error) { return DefaultRules().Label(a, from) }
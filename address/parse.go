@@ -0,0 +1,159 @@
+package address
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"go-practice/errs"
+)
+
+// layout turns the country's label layout into a regular expression
+// that reads an address back, with city and region as the patterns for
+// those fields. Lines may be split by new lines or commas, and the
+// street may take several, so "Apt 4, 12 Main St, Springfield, IL
+// 62701" reads the same as the label.
+func (c *Country) layout(city, region string) (*regexp.Regexp, error) {
+	placeholders := regexp.MustCompile(`\{(street|city|CITY|state|postal)\}`)
+	postal := strings.TrimSuffix(strings.TrimPrefix(c.PostalPattern, "^"), "$")
+
+	var lines []string
+	for _, line := range c.Label {
+		var b strings.Builder
+		last := 0
+		for _, m := range placeholders.FindAllStringSubmatchIndex(line, -1) {
+			if literal := line[last:m[0]]; literal != "" {
+				// whatever separates two fields on a label, people write
+				// a space, a comma or both
+				b.WriteString(`(?:\s*,\s*|\s+)`)
+			}
+			switch line[m[2]:m[3]] {
+			case "street":
+				b.WriteString(`(?P<street>(?s:.+?))`) // the shortest that fits
+			case "city", "CITY":
+				b.WriteString(`(?P<city>` + city + `)`)
+			case "state":
+				b.WriteString(`(?P<state>` + region + `)`)
+			case "postal":
+				b.WriteString(`(?P<postal>` + postal + `)`)
+			}
+			last = m[1]
+		}
+		lines = append(lines, b.String())
+	}
+	return regexp.Compile(`(?i)^\s*` + strings.Join(lines, `\s*[,\n]\s*`) + `\s*$`)
+}
+
+// Parse reads a whole address written out as a label, on several lines
+// or on one line with commas, such as "123 Main Street, New York, NY
+// 10001". A last line or part naming a country picks that country's
+// rules; otherwise country is used. The result is normalized as by
+// Normalize. Problems are *errs.ValidationError values, with Line set to
+// the line they were found on when text has several lines.
+func (r *Rules) Parse(text, country string) (Address, error) {
+	text = strings.TrimSpace(text)
+	if i := strings.LastIndexAny(text, ",\n"); i >= 0 {
+		if _, err := r.Lookup(text[i+1:]); err == nil {
+			text, country = strings.TrimSpace(text[:i]), text[i+1:]
+		}
+	}
+	c, err := r.Lookup(country)
+	if err != nil {
+		field := &errs.ValidationError{Field: "Country", Value: country, Err: err}
+		if clean(country) == "" {
+			field = &errs.ValidationError{Field: "Country", Message: "is required"}
+		}
+		return Address{}, errs.Aggregate("parse address", field)
+	}
+
+	m, re := c.match(text)
+	if m == nil {
+		return Address{}, errs.Aggregate("parse address", c.diagnose(text))
+	}
+	group := func(name string) string {
+		i := re.SubexpIndex(name)
+		if i < 0 || m[2*i] < 0 {
+			return ""
+		}
+		return text[m[2*i]:m[2*i+1]]
+	}
+	// a street over several lines is one street
+	street := strings.ReplaceAll(group("street"), "\n", ", ")
+
+	a, err := r.Normalize(Address{
+		Street:     street,
+		City:       group("city"),
+		State:      group("state"),
+		PostalCode: group("postal"),
+		Country:    c.Code,
+	})
+	if err != nil && strings.Contains(text, "\n") {
+		fields := map[string]string{"Street": "street", "City": "city", "State": "state", "PostalCode": "postal"}
+		for _, v := range errs.ValidationErrors(err) {
+			if i := re.SubexpIndex(fields[v.Field]); i > 0 && m[2*i] >= 0 {
+				v.Line = strings.Count(text[:m[2*i]], "\n") + 1
+			}
+		}
+	}
+	return a, err
+}
+
+// Parse parses text with the built-in rules; see Rules.Parse.
+func Parse(text, country string) (Address, error) { return DefaultRules().Parse(text, country) }
+
+// match tries the layout with the country's known regions, which finds
+// the right split of "New York New York 10001", and with any region so
+// that Normalize can say what is wrong with it. The street is the
+// shortest run of lines the rest fits after, and a known region only
+// wins when it doesn't push part of the city into the street, as
+// "Virginia" would in "Charleston, Wes Virginia".
+func (c *Country) match(text string) ([]int, *regexp.Regexp) {
+	var strict []int
+	if c.strict != nil {
+		strict = c.strict.FindStringSubmatchIndex(text)
+	}
+	loose := c.loose.FindStringSubmatchIndex(text)
+	switch {
+	case loose == nil && strict == nil:
+		return nil, nil
+	case loose == nil:
+		return strict, c.strict
+	case strict == nil || streetEnd(c.strict, strict) > streetEnd(c.loose, loose):
+		return loose, c.loose
+	}
+	return strict, c.strict
+}
+
+// streetEnd returns where the street ends in a match of re.
+func streetEnd(re *regexp.Regexp, m []int) int {
+	return m[2*re.SubexpIndex("street")+1]
+}
+
+// diagnose explains why text doesn't fit the country's layout.
+func (c *Country) diagnose(text string) error {
+	lines := strings.Split(text, "\n")
+	last := 0
+	if len(lines) > 1 {
+		last = len(lines)
+	}
+	postal := regexp.MustCompile(`(?i)(^|[\s,])` +
+		strings.TrimSuffix(strings.TrimPrefix(c.PostalPattern, "^"), "$") + `($|[\s,])`)
+	switch {
+	case text == "":
+		return &errs.ValidationError{Message: "address is empty"}
+	case !postal.MatchString(text):
+		return &errs.ValidationError{
+			Line:    last,
+			Field:   "PostalCode",
+			Message: fmt.Sprintf("no %s %s found", c.Name, c.PostalLabel),
+			Err:     ErrInvalidPostalCode,
+		}
+	case !strings.ContainsAny(text, ",\n"):
+		return &errs.ValidationError{Field: "Street", Message: "must be followed by a comma or a new line"}
+	}
+	return &errs.ValidationError{
+		Line:    last,
+		Message: fmt.Sprintf("doesn't match the %s layout %q", c.Name, strings.Join(c.Label, " / ")),
+		Value:   text,
+	}
+}
@@ -0,0 +1,100 @@
+package address_test
+
+import (
+	"slices"
+	"testing"
+
+	"go-practice/address"
+	"go-practice/errs"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name, text, country string
+		want                address.Address
+		problems            []string // Field of each problem, when parsing fails
+		line                int      // Line of the first problem
+	}{
+		{
+			name: "multi-word state after a comma",
+			text: "1 Main St, Charleston, West Virginia 25301", country: "US",
+			want: address.Address{Street: "1 Main St", City: "Charleston", State: "WV", PostalCode: "25301", Country: "US"},
+		},
+		{
+			name: "multi-word state after the city",
+			text: "1 Main St, Charleston West Virginia 25301", country: "US",
+			want: address.Address{Street: "1 Main St", City: "Charleston", State: "WV", PostalCode: "25301", Country: "US"},
+		},
+		{
+			name: "multi-word city and state",
+			text: "350 5th Ave, New York New York 10118", country: "US",
+			want: address.Address{Street: "350 5th Ave", City: "New York", State: "NY", PostalCode: "10118", Country: "US"},
+		},
+		{
+			name: "street over several parts",
+			text: "Apt 4, 12 Main St, Springfield, IL 62701", country: "US",
+			want: address.Address{Street: "Apt 4, 12 Main St", City: "Springfield", State: "IL", PostalCode: "62701", Country: "US"},
+		},
+		{
+			name: "street over several lines",
+			text: "Suite 100\n200 Elm St\nRaleigh, North Carolina 27601", country: "US",
+			want: address.Address{Street: "Ste 100, 200 Elm St", City: "Raleigh", State: "NC", PostalCode: "27601", Country: "US"},
+		},
+		{
+			name: "multi-word region in Australia",
+			text: "1 George St, Sydney New South Wales 2000", country: "AU",
+			want: address.Address{Street: "1 George St", City: "Sydney", State: "NSW", PostalCode: "2000", Country: "AU"},
+		},
+		{
+			name: "country on the last line",
+			text: "Unter den Linden 1\n10117 Berlin\nGermany", country: "US",
+			want: address.Address{Street: "Unter Den Linden 1", City: "Berlin", PostalCode: "10117", Country: "DE"},
+		},
+		{
+			name: "misspelled region stays out of the street",
+			text: "1 Main St\nCharleston, Wes Virginia 25301", country: "US",
+			problems: []string{"State"}, line: 2,
+		},
+		{
+			name: "postal code of another state",
+			text: "1 Main St, Charleston, West Virginia 99999", country: "US",
+			problems: []string{"PostalCode"},
+		},
+		{
+			name: "no postal code",
+			text: "1 Main St\nCharleston, West Virginia", country: "US",
+			problems: []string{"PostalCode"}, line: 2,
+		},
+		{
+			name: "unknown country",
+			text: "1 Main St, Charleston, West Virginia 25301", country: "Atlantis",
+			problems: []string{"Country"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := address.Parse(tt.text, tt.country)
+			if tt.problems == nil {
+				if err != nil {
+					t.Fatalf("Parse: %v", err)
+				}
+				if got != tt.want {
+					t.Errorf("Parse = %+v, want %+v", got, tt.want)
+				}
+				return
+			}
+
+			problems := errs.ValidationErrors(err)
+			var fields []string
+			for _, p := range problems {
+				fields = append(fields, p.Field)
+			}
+			if !slices.Equal(fields, tt.problems) {
+				t.Fatalf("Parse error = %v, want problems with %v", err, tt.problems)
+			}
+			if problems[0].Line != tt.line {
+				t.Errorf("Line = %d, want %d", problems[0].Line, tt.line)
+			}
+		})
+	}
+}
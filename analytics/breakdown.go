@@ -0,0 +1,39 @@
+package analytics
+
+import (
+	"go-practice/roster"
+)
+
+// Group is the summary for one key of a breakdown.
+type Group struct {
+	Key     string
+	Summary Summary
+}
+
+// Breakdown joins scores, keyed by student name, with the students
+// selected by q and summarizes each group produced by key, such as
+// roster.ByCity or roster.ByGrade. Only students with a score are counted,
+// groups without any scored student are left out, and groups come back
+// sorted by key. Scores for names q doesn't select are ignored, so the
+// query's filters narrow the breakdown. ErrEmpty means no selected student
+// had a score.
+func Breakdown[T Number](q roster.Query, scores map[string]T, key func(roster.Student) string) ([]Group, error) {
+	var groups []Group
+	for _, g := range q.GroupBy(key) {
+		var xs []T
+		for _, s := range g.Students {
+			if score, ok := scores[s.Name]; ok {
+				xs = append(xs, score)
+			}
+		}
+		if len(xs) == 0 {
+			continue
+		}
+		summary, _ := Summarize(xs)
+		groups = append(groups, Group{Key: g.Key, Summary: summary})
+	}
+	if len(groups) == 0 {
+		return nil, ErrEmpty
+	}
+	return groups, nil
+}
@@ -0,0 +1,96 @@
+package analytics
+
+import (
+	"fmt"
+	"math"
+	"slices"
+)
+
+// Curve adjusts a class's raw scores before letter grades are assigned.
+// Adjust must return one score per input, in the same order.
+type Curve interface {
+	Adjust(scores []float64) []float64
+}
+
+// NoCurve grades the raw scores.
+type NoCurve struct{}
+
+func (NoCurve) Adjust(scores []float64) []float64 {
+	return scores
+}
+
+// ShiftCurve adds the same number of points to every score so that the
+// class mean lands on Target; a class already at or above Target gets no
+// shift. When Cap is non-zero every score ends up at most Cap, so a raw
+// score above Cap is lowered to it.
+type ShiftCurve struct {
+	Target float64
+	Cap    float64
+}
+
+func (c ShiftCurve) Adjust(scores []float64) []float64 {
+	mean, err := Mean(scores)
+	if err != nil {
+		return scores
+	}
+	shift := max(c.Target-mean, 0)
+	adjusted := make([]float64, len(scores))
+	for i, s := range scores {
+		adjusted[i] = s + shift
+		if c.Cap != 0 {
+			adjusted[i] = math.Min(adjusted[i], c.Cap)
+		}
+	}
+	return adjusted
+}
+
+// RankCurve replaces each score with its percentile rank in the class, so
+// grades depend on position rather than raw marks: with LetterScale the
+// top 10% of the class get an A, the next 10% a B, and so on.
+type RankCurve struct{}
+
+func (RankCurve) Adjust(scores []float64) []float64 {
+	sorted := slices.Clone(scores)
+	slices.Sort(sorted)
+	n := float64(len(sorted))
+	ranks := make([]float64, len(scores))
+	for i, s := range scores {
+		below, _ := slices.BinarySearch(sorted, s)
+		upTo, _ := slices.BinarySearch(sorted, math.Nextafter(s, math.Inf(1)))
+		ranks[i] = 100 * (float64(below) + float64(upTo-below)/2) / n
+	}
+	return ranks
+}
+
+// LetterGrades curves the scores and assigns each one the label of its
+// band in scale, returning grades in input order. A nil curve means
+// NoCurve. A curve that doesn't return one score per input is an error.
+func LetterGrades[T Number](xs []T, scale Bands, curve Curve) ([]string, error) {
+	if len(xs) == 0 {
+		return nil, ErrEmpty
+	}
+	if err := scale.Validate(); err != nil {
+		return nil, err
+	}
+	if curve == nil {
+		curve = NoCurve{}
+	}
+
+	raw := make([]float64, len(xs))
+	for i, x := range xs {
+		raw[i] = float64(x)
+	}
+	adjusted := curve.Adjust(raw)
+	if len(adjusted) != len(xs) {
+		return nil, fmt.Errorf("analytics: %T returned %d scores for %d", curve, len(adjusted), len(xs))
+	}
+	grades := make([]string, len(xs))
+	for i, s := range adjusted {
+		band, ok := scale.Find(s)
+		if !ok {
+			continue // below the scale: no grade
+		}
+		grades[i] = band.Label
+	}
+	return grades, nil
+}
@@ -0,0 +1,78 @@
+package analytics
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestCurves(t *testing.T) {
+	tests := []struct {
+		name   string
+		curve  Curve
+		scores []float64
+		want   []float64
+	}{
+		{"none", NoCurve{}, []float64{60, 70, 80}, []float64{60, 70, 80}},
+		{"shift to the target", ShiftCurve{Target: 75}, []float64{60, 70, 80}, []float64{65, 75, 85}},
+		{"shift capped", ShiftCurve{Target: 75, Cap: 80}, []float64{60, 70, 80}, []float64{65, 75, 80}},
+		{"already above target", ShiftCurve{Target: 60}, []float64{60, 70, 80}, []float64{60, 70, 80}},
+		{"cap lowers raw scores", ShiftCurve{Cap: 90}, []float64{95, 50}, []float64{90, 50}},
+		{"shift of nothing", ShiftCurve{Target: 75}, []float64{}, []float64{}},
+		// ranks: 50 has none below and one equal of 4, 60 one below and
+		// two equal, 90 three below and one equal
+		{"rank", RankCurve{}, []float64{60, 90, 50, 60}, []float64{50, 87.5, 12.5, 50}},
+		{"rank of one", RankCurve{}, []float64{42}, []float64{50}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := slices.Clone(tt.scores)
+			got := tt.curve.Adjust(in)
+			if !slices.EqualFunc(got, tt.want, near) {
+				t.Errorf("Adjust = %v, want %v", got, tt.want)
+			}
+			if !slices.Equal(in, tt.scores) {
+				t.Errorf("Adjust changed its input to %v", in)
+			}
+		})
+	}
+}
+
+// sizedCurve returns n scores whatever it is given.
+type sizedCurve int
+
+func (n sizedCurve) Adjust([]float64) []float64 { return make([]float64, n) }
+
+func TestLetterGrades(t *testing.T) {
+	tens := []int{10, 20, 30, 40, 50, 60, 70, 80, 90, 100} // ranks 5, 15, ..., 95
+	tests := []struct {
+		name  string
+		xs    []int
+		scale Bands
+		curve Curve
+		want  []string
+	}{
+		{"raw", []int{95, 85, 75, 65, 55}, LetterScale, nil, []string{"A", "B", "C", "D", "F"}},
+		{"shifted", []int{85, 75, 65, 55}, LetterScale, ShiftCurve{Target: 80}, []string{"A", "B", "C", "D"}},
+		{"ranked", tens, LetterScale, RankCurve{}, []string{"F", "F", "F", "F", "F", "F", "D", "C", "B", "A"}},
+		{"below the scale", []int{95, 50}, Bands{{"Pass", 60}}, nil, []string{"Pass", ""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LetterGrades(tt.xs, tt.scale, tt.curve)
+			if err != nil || !slices.Equal(got, tt.want) {
+				t.Errorf("LetterGrades = %v, %v; want %v", got, err, tt.want)
+			}
+		})
+	}
+	if _, err := LetterGrades(tens, Bands{}, nil); err == nil {
+		t.Error("grading on no bands succeeded")
+	}
+	for _, n := range []int{9, 11, 0} {
+		got, err := LetterGrades(tens, LetterScale, sizedCurve(n))
+		want := fmt.Sprintf("analytics: analytics.sizedCurve returned %d scores for 10", n)
+		if err == nil || err.Error() != want {
+			t.Errorf("curve returning %d scores: LetterGrades = %v, %v; want error %q", n, got, err, want)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package analytics
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// Band is one histogram bucket or letter grade: every value from Min up to
+// the next band's Min belongs to it.
+type Band struct {
+	Label string
+	Min   float64
+}
+
+// Bands is a set of buckets, usually written from highest to lowest the
+// way chapter 3 checks scores:
+//
+//	analytics.Bands{
+//		{Label: "Excellent", Min: 90},
+//		{Label: "Good", Min: 80},
+//		{Label: "Needs improvement", Min: math.Inf(-1)},
+//	}
+//
+// Order doesn't matter; a value belongs to the band with the largest Min
+// not above it. Values below every band belong to none.
+type Bands []Band
+
+// LetterScale is the usual 90/80/70/60 letter-grade scale.
+var LetterScale = Bands{
+	{Label: "A", Min: 90},
+	{Label: "B", Min: 80},
+	{Label: "C", Min: 70},
+	{Label: "D", Min: 60},
+	{Label: "F", Min: math.Inf(-1)},
+}
+
+// EqualWidth splits [lo, hi) into n buckets of the same width, labelled
+// "lo-hi". Values at or above hi fall into the last bucket.
+func EqualWidth(lo, hi float64, n int) (Bands, error) {
+	if n <= 0 || !(hi > lo) {
+		return nil, fmt.Errorf("analytics: can't split [%v, %v) into %d buckets", lo, hi, n)
+	}
+	width := (hi - lo) / float64(n)
+	bands := make(Bands, n)
+	for i := range bands {
+		from := lo + float64(i)*width
+		bands[i] = Band{Label: fmt.Sprintf("%g-%g", from, from+width), Min: from}
+	}
+	return bands, nil
+}
+
+// Validate checks that the bands have distinct minimums.
+func (b Bands) Validate() error {
+	if len(b) == 0 {
+		return errors.New("analytics: no bands")
+	}
+	seen := make(map[float64]string)
+	for _, band := range b {
+		if math.IsNaN(band.Min) {
+			return fmt.Errorf("analytics: band %q has no minimum", band.Label)
+		}
+		if other, dup := seen[band.Min]; dup {
+			return fmt.Errorf("analytics: bands %q and %q share minimum %v", other, band.Label, band.Min)
+		}
+		seen[band.Min] = band.Label
+	}
+	return nil
+}
+
+// Find returns the band x belongs to.
+func (b Bands) Find(x float64) (Band, bool) {
+	var found Band
+	ok := false
+	for _, band := range b {
+		if band.Min <= x && (!ok || band.Min > found.Min) {
+			found, ok = band, true
+		}
+	}
+	return found, ok
+}
+
+// ascending returns a copy sorted by Min.
+func (b Bands) ascending() Bands {
+	sorted := slices.Clone(b)
+	slices.SortFunc(sorted, func(x, y Band) int {
+		switch {
+		case x.Min < y.Min:
+			return -1
+		case x.Min > y.Min:
+			return 1
+		}
+		return 0
+	})
+	return sorted
+}
+
+// Bucket is one row of a histogram.
+type Bucket struct {
+	Band
+	Count int
+}
+
+// Histogram counts the values in each band. Buckets come back in
+// ascending order of Min, including empty ones. Values below every band,
+// and NaNs, are reported in the error along with the counts for the rest.
+func Histogram[T Number](xs []T, bands Bands) ([]Bucket, error) {
+	if len(xs) == 0 {
+		return nil, ErrEmpty
+	}
+	if err := bands.Validate(); err != nil {
+		return nil, err
+	}
+
+	sorted := bands.ascending()
+	buckets := make([]Bucket, len(sorted))
+	for i, band := range sorted {
+		buckets[i].Band = band
+	}
+
+	outside := 0
+	for _, x := range xs {
+		v := float64(x)
+		if math.IsNaN(v) {
+			outside++
+			continue
+		}
+		// the last band whose Min is <= v
+		i, found := slices.BinarySearchFunc(sorted, v, func(b Band, v float64) int {
+			switch {
+			case b.Min < v:
+				return -1
+			case b.Min > v:
+				return 1
+			}
+			return 0
+		})
+		if !found {
+			i--
+		}
+		if i < 0 {
+			outside++
+			continue
+		}
+		buckets[i].Count++
+	}
+	if outside > 0 {
+		return buckets, fmt.Errorf("analytics: %d value(s) fall outside every band (lowest is %q)", outside, sorted[0].Label)
+	}
+	return buckets, nil
+}
@@ -0,0 +1,123 @@
+package analytics
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestFind(t *testing.T) {
+	noF := Bands{{Label: "A", Min: 90}, {Label: "B", Min: 80}}
+	tests := []struct {
+		bands Bands
+		x     float64
+		want  string // "" for no band
+	}{
+		{LetterScale, 100, "A"},
+		{LetterScale, 90, "A"}, // a band includes its Min
+		{LetterScale, 89.99, "B"},
+		{LetterScale, 60, "D"},
+		{LetterScale, 59.99, "F"},
+		{LetterScale, -1e9, "F"},
+		{noF, 80, "B"},
+		{noF, 79.99, ""},
+		{slices.Clone(LetterScale[2:]), 95, "C"}, // order doesn't matter, only the largest Min
+	}
+	for _, tt := range tests {
+		band, ok := tt.bands.Find(tt.x)
+		if ok != (tt.want != "") || band.Label != tt.want {
+			t.Errorf("Find(%v) = %q, %v; want %q", tt.x, band.Label, ok, tt.want)
+		}
+	}
+}
+
+func TestEqualWidth(t *testing.T) {
+	got, err := EqualWidth(0, 100, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Bands{{"0-25", 0}, {"25-50", 25}, {"50-75", 50}, {"75-100", 75}}
+	if !slices.Equal(got, want) {
+		t.Errorf("EqualWidth = %v, want %v", got, want)
+	}
+
+	for _, bad := range []struct {
+		lo, hi float64
+		n      int
+	}{{0, 100, 0}, {0, 100, -1}, {100, 100, 2}, {100, 0, 2}, {0, math.NaN(), 2}} {
+		if _, err := EqualWidth(bad.lo, bad.hi, bad.n); err == nil {
+			t.Errorf("EqualWidth(%v, %v, %d) succeeded", bad.lo, bad.hi, bad.n)
+		}
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	quarters, _ := EqualWidth(0, 100, 4)
+	tests := []struct {
+		name    string
+		xs      []float64
+		bands   Bands
+		counts  []int // per band, ascending
+		outside bool
+	}{
+		{
+			name:  "edges go up",
+			xs:    []float64{0, 24.99, 25, 50, 74.99, 75},
+			bands: quarters, counts: []int{2, 1, 2, 1},
+		},
+		{
+			name:  "at or above the top",
+			xs:    []float64{100, 150, 99.99},
+			bands: quarters, counts: []int{0, 0, 0, 3},
+		},
+		{
+			name:  "below every band and NaN",
+			xs:    []float64{-0.01, math.NaN(), 10},
+			bands: quarters, counts: []int{1, 0, 0, 0}, outside: true,
+		},
+		{
+			name:  "letter scale, listed high to low",
+			xs:    []float64{90, 89.5, 80, 79, 60, 59, 0, -5},
+			bands: LetterScale, counts: []int{3, 1, 1, 2, 1}, // F D C B A
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buckets, err := Histogram(tt.xs, tt.bands)
+			if (err != nil) != tt.outside {
+				t.Errorf("error = %v, want one: %v", err, tt.outside)
+			}
+			var counts []int
+			for i, b := range buckets {
+				counts = append(counts, b.Count)
+				if i > 0 && b.Min <= buckets[i-1].Min {
+					t.Errorf("bucket %q comes after %q", b.Label, buckets[i-1].Label)
+				}
+			}
+			if !slices.Equal(counts, tt.counts) {
+				t.Errorf("counts = %v, want %v", counts, tt.counts)
+			}
+		})
+	}
+}
+
+func TestBandsValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		bands Bands
+		ok    bool
+	}{
+		{"letter scale", LetterScale, true},
+		{"none", Bands{}, false},
+		{"shared minimum", Bands{{"A", 90}, {"A+", 90}}, false},
+		{"NaN minimum", Bands{{"A", math.NaN()}}, false},
+	}
+	for _, tt := range tests {
+		if err := tt.bands.Validate(); (err == nil) != tt.ok {
+			t.Errorf("%s: Validate = %v", tt.name, err)
+		}
+		if _, err := Histogram([]int{1}, tt.bands); (err == nil) != tt.ok {
+			t.Errorf("%s: Histogram = %v", tt.name, err)
+		}
+	}
+}
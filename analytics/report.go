@@ -0,0 +1,135 @@
+package analytics
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// Report bundles the pieces of a grade report. Any part may be left
+// empty: Overall is skipped when its Count is 0, and Histogram and Groups
+// when they are nil.
+type Report struct {
+	Title      string
+	Overall    Summary
+	Histogram  []Bucket
+	GroupLabel string // heading for the Groups key column, e.g. "City"
+	Groups     []Group
+}
+
+// NewReport summarizes xs and buckets it into bands (skipped when bands is
+// nil).
+func NewReport[T Number](title string, xs []T, bands Bands) (Report, error) {
+	overall, err := Summarize(xs)
+	if err != nil {
+		return Report{}, err
+	}
+	r := Report{Title: title, Overall: overall}
+	if bands != nil {
+		if r.Histogram, err = Histogram(xs, bands); err != nil {
+			return Report{}, err
+		}
+	}
+	return r, nil
+}
+
+var summaryColumns = []string{"count", "min", "max", "mean", "median", "stddev", "p25", "p75", "p90"}
+
+func (s Summary) values() []float64 {
+	return []float64{float64(s.Count), s.Min, s.Max, s.Mean, s.Median, s.StdDev, s.P25, s.P75, s.P90}
+}
+
+// WriteText renders the report as aligned plain text, with a bar of '#'
+// characters per histogram bucket.
+func (r Report) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	wrote := false
+	section := func() {
+		if wrote {
+			fmt.Fprintln(tw)
+		}
+		wrote = true
+	}
+
+	if r.Title != "" {
+		section()
+		fmt.Fprintf(tw, "%s\n%s\n", r.Title, strings.Repeat("=", len(r.Title)))
+	}
+
+	if r.Overall.Count > 0 {
+		section()
+		for i, name := range summaryColumns {
+			fmt.Fprintf(tw, "%s:\t%s\t\n", name, formatStat(r.Overall.values()[i]))
+		}
+	}
+
+	if len(r.Histogram) > 0 {
+		section()
+		// highest band first, the way grades are usually listed
+		for i := len(r.Histogram) - 1; i >= 0; i-- {
+			b := r.Histogram[i]
+			fmt.Fprintf(tw, "%s:\t%d\t %s\n", b.Label, b.Count, strings.Repeat("#", b.Count))
+		}
+	}
+
+	if len(r.Groups) > 0 {
+		section()
+		label := r.GroupLabel
+		if label == "" {
+			label = "group"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t\n", label, strings.Join(summaryColumns, "\t"))
+		for _, g := range r.Groups {
+			fmt.Fprintf(tw, "%s", g.Key)
+			for _, v := range g.Summary.values() {
+				fmt.Fprintf(tw, "\t%s", formatStat(v))
+			}
+			fmt.Fprintln(tw, "\t")
+		}
+	}
+	return tw.Flush()
+}
+
+// WriteCSV renders the report as one CSV table with a "section" column:
+// an "overall" row, one "histogram" row per bucket (label and count) and
+// one "group" row per group. Numbers are written at full precision.
+func (r Report) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	header := append([]string{"section", "key"}, summaryColumns...)
+	cw.Write(header)
+
+	row := func(section, key string, s Summary) {
+		record := []string{section, key}
+		for _, v := range s.values() {
+			record = append(record, strconv.FormatFloat(v, 'g', -1, 64))
+		}
+		cw.Write(record)
+	}
+
+	if r.Overall.Count > 0 {
+		row("overall", r.Title, r.Overall)
+	}
+	for _, b := range r.Histogram {
+		record := make([]string, len(header))
+		record[0], record[1], record[2] = "histogram", b.Label, strconv.Itoa(b.Count)
+		cw.Write(record)
+	}
+	for _, g := range r.Groups {
+		row("group", g.Key, g.Summary)
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// formatStat prints whole numbers without decimals and the rest to two
+// places.
+func formatStat(v float64) string {
+	if v == float64(int64(v)) {
+		return strconv.FormatInt(int64(v), 10)
+	}
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
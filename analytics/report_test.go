@@ -0,0 +1,102 @@
+package analytics
+
+import (
+	"strings"
+	"testing"
+)
+
+func testReport(t *testing.T) Report {
+	t.Helper()
+	r, err := NewReport("Quiz", scores, Bands{{Label: "pass", Min: 5}, {Label: "fail", Min: 0}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.GroupLabel = "City"
+	r.Groups = []Group{
+		{Key: "Austin", Summary: Summary{Count: 2, Min: 4, Max: 5, Mean: 4.5, Median: 4.5, StdDev: 0.5, P25: 4.25, P75: 4.75, P90: 4.9}},
+		{Key: "Boston", Summary: Summary{Count: 1, Min: 9, Max: 9, Mean: 9, Median: 9, P25: 9, P75: 9, P90: 9}},
+	}
+	return r
+}
+
+func TestReportText(t *testing.T) {
+	tests := []struct {
+		name   string
+		report func(Report) Report
+		want   string
+	}{
+		{
+			name:   "every section",
+			report: func(r Report) Report { return r },
+			want: `Quiz
+====
+
+   count:     8
+     min:     2
+     max:     9
+    mean:     5
+  median:  4.50
+  stddev:     2
+     p25:     4
+     p75:  5.50
+     p90:  7.60
+
+  pass:  4 ####
+  fail:  4 ####
+
+    City  count  min  max  mean  median  stddev   p25   p75   p90
+  Austin      2    4    5  4.50    4.50    0.50  4.25  4.75  4.90
+  Boston      1    9    9     9       9       0     9     9     9
+`,
+		},
+		{
+			name: "histogram only",
+			report: func(r Report) Report {
+				return Report{Histogram: r.Histogram}
+			},
+			want: "  pass:  4 ####\n  fail:  4 ####\n",
+		},
+		{
+			name: "groups without a label",
+			report: func(r Report) Report {
+				return Report{Title: "By city", Groups: r.Groups[1:]}
+			},
+			want: "By city\n=======\n\n   group  count  min  max  mean  median  stddev  p25  p75  p90\n" +
+				"  Boston      1    9    9     9       9       0    9    9    9\n",
+		},
+		{name: "empty", report: func(Report) Report { return Report{} }, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			if err := tt.report(testReport(t)).WriteText(&b); err != nil {
+				t.Fatal(err)
+			}
+			if b.String() != tt.want {
+				t.Errorf("WriteText =\n%s\nwant\n%s", b.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestReportCSV(t *testing.T) {
+	var b strings.Builder
+	if err := testReport(t).WriteCSV(&b); err != nil {
+		t.Fatal(err)
+	}
+	want := `section,key,count,min,max,mean,median,stddev,p25,p75,p90
+overall,Quiz,8,2,9,5,4.5,2,4,5.5,7.6
+histogram,fail,4,,,,,,,,
+histogram,pass,4,,,,,,,,
+group,Austin,2,4,5,4.5,4.5,0.5,4.25,4.75,4.9
+group,Boston,1,9,9,9,9,0,9,9,9
+`
+	if b.String() != want {
+		t.Errorf("WriteCSV =\n%s\nwant\n%s", b.String(), want)
+	}
+
+	b.Reset()
+	if err := (Report{}).WriteCSV(&b); err != nil || b.String() != "section,key,count,min,max,mean,median,stddev,p25,p75,p90\n" {
+		t.Errorf("empty report = %q, %v; want only the header", b.String(), err)
+	}
+}
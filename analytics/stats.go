@@ -0,0 +1,180 @@
+// Package analytics computes descriptive statistics over score datasets:
+// mean, median, mode, standard deviation, percentiles, histograms and
+// letter grades, plus per-group breakdowns joined with a roster.Store and
+// reports rendered as text or CSV.
+//
+// Every function reports an empty dataset as ErrEmpty rather than
+// returning 0 the way chapter 5's findMax does, so "no scores" can't be
+// mistaken for "everyone scored zero".
+package analytics
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// ErrEmpty is returned when a statistic is requested for no values.
+var ErrEmpty = errors.New("analytics: empty dataset")
+
+// Number is any integer or floating-point type a score can be stored as.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 |
+		~float32 | ~float64
+}
+
+// Min returns the smallest value.
+func Min[T Number](xs []T) (T, error) {
+	if len(xs) == 0 {
+		var zero T
+		return zero, ErrEmpty
+	}
+	return slices.Min(xs), nil
+}
+
+// Max returns the largest value.
+func Max[T Number](xs []T) (T, error) {
+	if len(xs) == 0 {
+		var zero T
+		return zero, ErrEmpty
+	}
+	return slices.Max(xs), nil
+}
+
+// Mean returns the arithmetic mean.
+func Mean[T Number](xs []T) (float64, error) {
+	if len(xs) == 0 {
+		return 0, ErrEmpty
+	}
+	sum := 0.0
+	for _, x := range xs {
+		sum += float64(x)
+	}
+	return sum / float64(len(xs)), nil
+}
+
+// Median returns the middle value, or the mean of the two middle values
+// for an even count. xs is not modified.
+func Median[T Number](xs []T) (float64, error) {
+	return Percentile(xs, 50)
+}
+
+// Mode returns the most frequent values in ascending order; a dataset
+// with several equally common values has several modes.
+func Mode[T Number](xs []T) ([]T, error) {
+	if len(xs) == 0 {
+		return nil, ErrEmpty
+	}
+	counts := make(map[T]int)
+	best := 0
+	for _, x := range xs {
+		counts[x]++
+		best = max(best, counts[x])
+	}
+	var modes []T
+	for x, n := range counts {
+		if n == best {
+			modes = append(modes, x)
+		}
+	}
+	slices.Sort(modes)
+	return modes, nil
+}
+
+// StdDev returns the population standard deviation, treating xs as the
+// whole class rather than a sample of it.
+func StdDev[T Number](xs []T) (float64, error) {
+	mean, err := Mean(xs)
+	if err != nil {
+		return 0, err
+	}
+	sum := 0.0
+	for _, x := range xs {
+		d := float64(x) - mean
+		sum += d * d
+	}
+	return math.Sqrt(sum / float64(len(xs))), nil
+}
+
+// Percentile returns the p-th percentile (0 to 100), interpolating
+// linearly between the closest ranks. Percentile(xs, 50) is the median.
+// xs is not modified.
+func Percentile[T Number](xs []T, p float64) (float64, error) {
+	if len(xs) == 0 {
+		return 0, ErrEmpty
+	}
+	if math.IsNaN(p) || p < 0 || p > 100 {
+		return 0, fmt.Errorf("analytics: percentile %v is outside 0-100", p)
+	}
+	return percentileSorted(sortedFloats(xs), p), nil
+}
+
+// PercentileRank returns the percentage of values strictly below x plus
+// half of those equal to it, so the middle of a dataset ranks 50.
+func PercentileRank[T Number](xs []T, x T) (float64, error) {
+	if len(xs) == 0 {
+		return 0, ErrEmpty
+	}
+	below, equal := 0, 0
+	for _, v := range xs {
+		switch {
+		case v < x:
+			below++
+		case v == x:
+			equal++
+		}
+	}
+	return 100 * (float64(below) + float64(equal)/2) / float64(len(xs)), nil
+}
+
+func sortedFloats[T Number](xs []T) []float64 {
+	fs := make([]float64, len(xs))
+	for i, x := range xs {
+		fs[i] = float64(x)
+	}
+	slices.Sort(fs)
+	return fs
+}
+
+func percentileSorted(sorted []float64, p float64) float64 {
+	rank := p / 100 * float64(len(sorted)-1)
+	lo := int(math.Floor(rank))
+	hi := int(math.Ceil(rank))
+	return sorted[lo] + (sorted[hi]-sorted[lo])*(rank-float64(lo))
+}
+
+// Summary collects the common statistics for one dataset.
+type Summary struct {
+	Count  int
+	Min    float64
+	Max    float64
+	Mean   float64
+	Median float64
+	StdDev float64
+	P25    float64
+	P75    float64
+	P90    float64
+}
+
+// Summarize computes a Summary from a single sorted copy of xs.
+func Summarize[T Number](xs []T) (Summary, error) {
+	if len(xs) == 0 {
+		return Summary{}, ErrEmpty
+	}
+	sorted := sortedFloats(xs)
+	mean, _ := Mean(sorted)
+	stddev, _ := StdDev(sorted)
+	return Summary{
+		Count:  len(sorted),
+		Min:    sorted[0],
+		Max:    sorted[len(sorted)-1],
+		Mean:   mean,
+		Median: percentileSorted(sorted, 50),
+		StdDev: stddev,
+		P25:    percentileSorted(sorted, 25),
+		P75:    percentileSorted(sorted, 75),
+		P90:    percentileSorted(sorted, 90),
+	}, nil
+}
@@ -0,0 +1,157 @@
+package analytics
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"testing"
+)
+
+// near reports whether got is within rounding error of want.
+func near(got, want float64) bool {
+	return math.Abs(got-want) < 1e-9
+}
+
+// scores is the usual textbook dataset: mean 5, population standard
+// deviation exactly 2.
+var scores = []int{9, 2, 5, 4, 4, 7, 4, 5}
+
+func TestSummarize(t *testing.T) {
+	got, err := Summarize(scores)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// sorted: 2 4 4 4 5 5 7 9, ranks 0-7
+	want := Summary{
+		Count:  8,
+		Min:    2,
+		Max:    9,
+		Mean:   5,
+		Median: 4.5, // rank 3.5, halfway from 4 to 5
+		StdDev: 2,   // sqrt((9+1+1+1+0+0+4+16)/8)
+		P25:    4,   // rank 1.75, between two 4s
+		P75:    5.5, // rank 5.25, a quarter of the way from 5 to 7
+		P90:    7.6, // rank 6.3, 30% of the way from 7 to 9
+	}
+	for _, f := range []struct {
+		name      string
+		got, want float64
+	}{
+		{"Count", float64(got.Count), float64(want.Count)},
+		{"Min", got.Min, want.Min},
+		{"Max", got.Max, want.Max},
+		{"Mean", got.Mean, want.Mean},
+		{"Median", got.Median, want.Median},
+		{"StdDev", got.StdDev, want.StdDev},
+		{"P25", got.P25, want.P25},
+		{"P75", got.P75, want.P75},
+		{"P90", got.P90, want.P90},
+	} {
+		if !near(f.got, f.want) {
+			t.Errorf("%s = %v, want %v", f.name, f.got, f.want)
+		}
+	}
+	if !slices.Equal(scores, []int{9, 2, 5, 4, 4, 7, 4, 5}) {
+		t.Errorf("Summarize sorted its input: %v", scores)
+	}
+}
+
+func TestStats(t *testing.T) {
+	tests := []struct {
+		name string
+		stat func([]float64) (float64, error)
+		xs   []float64
+		want float64
+	}{
+		{"mean", Mean[float64], []float64{1, 2, 3, 4}, 2.5},
+		{"mean of one", Mean[float64], []float64{-3}, -3},
+		{"median odd", Median[float64], []float64{3, 1, 2}, 2},
+		{"median even", Median[float64], []float64{4, 1, 3, 2}, 2.5},
+		{"stddev of equal values", StdDev[float64], []float64{7, 7, 7}, 0},
+		{"stddev", StdDev[float64], []float64{1, 3}, 1},
+		{"min", Min[float64], []float64{3, -1, 2}, -1},
+		{"max", Max[float64], []float64{3, -1, 2}, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.stat(tt.xs)
+			if err != nil || !near(got, tt.want) {
+				t.Errorf("= %v, %v; want %v", got, err, tt.want)
+			}
+			if _, err := tt.stat(nil); !errors.Is(err, ErrEmpty) {
+				t.Errorf("of nothing: %v, want ErrEmpty", err)
+			}
+		})
+	}
+}
+
+func TestMode(t *testing.T) {
+	tests := []struct {
+		xs, want []int
+	}{
+		{[]int{3, 1, 3, 2}, []int{3}},
+		{[]int{2, 1, 2, 1, 3}, []int{1, 2}},
+		{[]int{5}, []int{5}},
+		{[]int{3, 2, 1}, []int{1, 2, 3}},
+	}
+	for _, tt := range tests {
+		if got, err := Mode(tt.xs); err != nil || !slices.Equal(got, tt.want) {
+			t.Errorf("Mode(%v) = %v, %v; want %v", tt.xs, got, err, tt.want)
+		}
+	}
+	if _, err := Mode([]int{}); !errors.Is(err, ErrEmpty) {
+		t.Errorf("Mode of nothing: %v, want ErrEmpty", err)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	tens := []int{40, 10, 30, 20} // ranks 0-3
+	tests := []struct {
+		xs   []int
+		p    float64
+		want float64
+	}{
+		{tens, 0, 10},
+		{tens, 100, 40},
+		{tens, 50, 25},        // rank 1.5
+		{tens, 10, 13},        // rank 0.3
+		{tens, 100.0 / 3, 20}, // rank 1, exactly
+		{tens, 90, 37},        // rank 2.7
+		{[]int{7}, 0, 7},
+		{[]int{7}, 73, 7},
+		{[]int{1, 2}, 25, 1.25},
+	}
+	for _, tt := range tests {
+		if got, err := Percentile(tt.xs, tt.p); err != nil || !near(got, tt.want) {
+			t.Errorf("Percentile(%v, %v) = %v, %v; want %v", tt.xs, tt.p, got, err, tt.want)
+		}
+	}
+
+	for _, p := range []float64{-1, 100.5, math.NaN()} {
+		if _, err := Percentile(tens, p); err == nil {
+			t.Errorf("Percentile(%v) succeeded", p)
+		}
+	}
+	if _, err := Percentile([]int{}, 50); !errors.Is(err, ErrEmpty) {
+		t.Errorf("Percentile of nothing: %v, want ErrEmpty", err)
+	}
+}
+
+func TestPercentileRank(t *testing.T) {
+	xs := []int{1, 2, 2, 3}
+	tests := []struct {
+		x    int
+		want float64
+	}{
+		{0, 0},
+		{1, 12.5}, // half of one of four
+		{2, 50},   // one below, two equal
+		{3, 87.5},
+		{4, 100},
+	}
+	for _, tt := range tests {
+		if got, err := PercentileRank(xs, tt.x); err != nil || got != tt.want {
+			t.Errorf("PercentileRank(%d) = %v, %v; want %v", tt.x, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+// Package builder implements the builder pattern once for any struct
+// type. Chapters 7 and 9 each wrote a ComputerBuilder and a CarBuilder by
+// hand: a setter per field and a Build that returned whatever had been
+// set, including a Computer with 0 GB of RAM. Here a Spec lists a type's
+// fields once, with which are required, their defaults and validators,
+// and Build reports every problem together in an *errs.AggregatedError.
+//
+//	var spec = builder.NewSpec[Car]("car")
+//	var (
+//		brand = builder.Required(spec, "Brand", func(c *Car) *string { return &c.Brand }, builder.NotEmpty)
+//		color = builder.Optional(spec, "Color", func(c *Car) *string { return &c.Color }, "White")
+//	)
+//
+//	car, err := spec.New().Set(brand.To("Tesla")).Build()
+package builder
+
+import (
+	"fmt"
+
+	"go-practice/errs"
+	"go-practice/reflectutil"
+)
+
+// Spec describes how to build a T. Declare its fields with Required and
+// Optional before creating builders, typically in package-level vars.
+type Spec[T any] struct {
+	name   string
+	fields []fieldInfo[T]
+	checks []func(T) error
+}
+
+// fieldInfo is a field with its value type erased.
+type fieldInfo[T any] struct {
+	name       string
+	required   bool
+	setDefault func(*T)                       // nil when there is no default
+	validate   func(*T) *errs.ValidationError // nil when there are no validators
+}
+
+// NewSpec returns an empty Spec; name appears in errors, as in
+// "build car: 2 problems: ...".
+func NewSpec[T any](name string) *Spec[T] {
+	return &Spec[T]{name: name}
+}
+
+// Check adds a validator for the finished value, for rules that involve
+// several fields. It runs only when every field is valid. Errors joined
+// with errors.Join are reported as separate problems.
+func (s *Spec[T]) Check(check func(T) error) {
+	s.checks = append(s.checks, check)
+}
+
+// Validator checks one field's value and describes what is wrong with it.
+type Validator[V any] func(V) error
+
+// Field is a typed handle for one field of a Spec.
+type Field[T, V any] struct {
+	spec  *Spec[T]
+	index int
+	ptr   func(*T) *V
+}
+
+// Required declares a field that must be set before Build. ptr returns
+// the field's address in a T, and validators run on the set value in
+// order, stopping at the first failure.
+func Required[T, V any](s *Spec[T], name string, ptr func(*T) *V, validators ...Validator[V]) Field[T, V] {
+	return addField(s, fieldInfo[T]{name: name, required: true}, ptr, validators)
+}
+
+// Optional declares a field that takes def when it isn't set. Each built
+// value gets its own deep copy of def, so a slice or map default is never
+// shared. Validators run on whichever value the field ends up with.
+func Optional[T, V any](s *Spec[T], name string, ptr func(*T) *V, def V, validators ...Validator[V]) Field[T, V] {
+	info := fieldInfo[T]{name: name, setDefault: func(t *T) { *ptr(t) = reflectutil.DeepCopy(def) }}
+	return addField(s, info, ptr, validators)
+}
+
+func addField[T, V any](s *Spec[T], info fieldInfo[T], ptr func(*T) *V, validators []Validator[V]) Field[T, V] {
+	if len(validators) > 0 {
+		info.validate = func(t *T) *errs.ValidationError {
+			v := *ptr(t)
+			for _, check := range validators {
+				if err := check(v); err != nil {
+					return &errs.ValidationError{Field: info.name, Message: err.Error(), Value: v}
+				}
+			}
+			return nil
+		}
+	}
+	s.fields = append(s.fields, info)
+	return Field[T, V]{spec: s, index: len(s.fields) - 1, ptr: ptr}
+}
+
+// Name returns the field's name.
+func (f Field[T, V]) Name() string {
+	return f.spec.fields[f.index].name
+}
+
+// To returns a Setter that sets the field to v.
+func (f Field[T, V]) To(v V) Setter[T] {
+	return Setter[T]{spec: f.spec, index: f.index, apply: func(t *T) { *f.ptr(t) = v }}
+}
+
+// Setter is one pending assignment, made by Field.To.
+type Setter[T any] struct {
+	spec  *Spec[T]
+	index int
+	apply func(*T)
+}
+
+// Builder accumulates field values for one T. It is not safe for
+// concurrent use; Clone it to hand variants to other goroutines.
+type Builder[T any] struct {
+	spec  *Spec[T]
+	value T
+	set   []bool // by field index
+}
+
+// New returns an empty builder.
+func (s *Spec[T]) New() *Builder[T] {
+	return &Builder[T]{spec: s}
+}
+
+// Set applies setters in order, so a later value for the same field wins,
+// and returns b for chaining. It panics if a setter belongs to a
+// different Spec, which is a programming error.
+func (b *Builder[T]) Set(setters ...Setter[T]) *Builder[T] {
+	for _, s := range setters {
+		if s.spec != b.spec {
+			panic(fmt.Sprintf("builder: setter for a different %s spec", b.spec.name))
+		}
+		s.apply(&b.value)
+		for len(b.set) <= s.index {
+			b.set = append(b.set, false)
+		}
+		b.set[s.index] = true
+	}
+	return b
+}
+
+// Clone returns an independent copy of b, for building variants of a
+// common base. Slices and maps already set are deep-copied, so changing
+// a clone never affects the original.
+func (b *Builder[T]) Clone() *Builder[T] {
+	return &Builder[T]{
+		spec:  b.spec,
+		value: reflectutil.DeepCopy(b.value),
+		set:   append([]bool(nil), b.set...),
+	}
+}
+
+func (b *Builder[T]) isSet(i int) bool {
+	return i < len(b.set) && b.set[i]
+}
+
+// Build applies defaults, checks required fields and runs the validators.
+// If anything is wrong it returns the zero T and an *errs.AggregatedError
+// holding one *errs.ValidationError per field, in declaration order.
+// The builder is unchanged and can be corrected and built again; the
+// result shares no slices or maps with it.
+func (b *Builder[T]) Build() (T, error) {
+	v := reflectutil.DeepCopy(b.value)
+	var problems []error
+	for i, f := range b.spec.fields {
+		switch {
+		case b.isSet(i):
+		case f.required:
+			problems = append(problems, &errs.ValidationError{Field: f.name, Message: "is required"})
+			continue
+		case f.setDefault != nil:
+			f.setDefault(&v)
+		}
+		if f.validate != nil {
+			if err := f.validate(&v); err != nil {
+				problems = append(problems, err)
+			}
+		}
+	}
+	if len(problems) == 0 {
+		for _, check := range b.spec.checks {
+			switch err := check(v).(type) {
+			case nil:
+			case interface{ Unwrap() []error }: // errors.Join: one entry each
+				problems = append(problems, err.Unwrap()...)
+			default:
+				problems = append(problems, err)
+			}
+		}
+	}
+
+	if err := errs.Aggregate("build "+b.spec.name, problems...); err != nil {
+		var zero T
+		return zero, err
+	}
+	return v, nil
+}
+
+// MustBuild is Build for values known to be valid, such as fixtures; it
+// panics on error.
+func (b *Builder[T]) MustBuild() T {
+	v, err := b.Build()
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
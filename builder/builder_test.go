@@ -0,0 +1,179 @@
+package builder_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"go-practice/builder"
+	"go-practice/errs"
+)
+
+type server struct {
+	Host  string
+	Port  int
+	Cores int
+	Tags  []string
+}
+
+var (
+	spec  = builder.NewSpec[server]("server")
+	host  = builder.Required(spec, "Host", func(s *server) *string { return &s.Host }, builder.NotEmpty)
+	port  = builder.Optional(spec, "Port", func(s *server) *int { return &s.Port }, 8080, builder.Between(1, 65535))
+	cores = builder.Optional(spec, "Cores", func(s *server) *int { return &s.Cores }, 0, builder.Min(1))
+	tags  = builder.Optional(spec, "Tags", func(s *server) *[]string { return &s.Tags }, nil)
+)
+
+func init() {
+	spec.Check(func(s server) error {
+		var problems []error
+		if s.Port < 1024 && s.Host != "localhost" {
+			problems = append(problems, &errs.ValidationError{Field: "Port", Message: "is privileged", Value: s.Port})
+		}
+		if s.Cores > 64 {
+			problems = append(problems, errors.New("too many cores for one host"))
+		}
+		return errors.Join(problems...)
+	})
+}
+
+func TestBuild(t *testing.T) {
+	tests := []struct {
+		name     string
+		set      []builder.Setter[server]
+		want     server
+		problems []string // each entry of the *errs.AggregatedError
+	}{
+		{
+			name: "defaults",
+			set:  []builder.Setter[server]{host.To("db"), cores.To(4)},
+			want: server{Host: "db", Port: 8080, Cores: 4},
+		},
+		{
+			name:     "required field never set",
+			set:      []builder.Setter[server]{cores.To(4)},
+			problems: []string{"validation failed for Host: is required"},
+		},
+		{
+			name:     "default is validated",
+			set:      []builder.Setter[server]{host.To("db")},
+			problems: []string{"validation failed for Cores: must be at least 1 (value: 0)"},
+		},
+		{
+			name: "later set wins",
+			set:  []builder.Setter[server]{host.To(""), port.To(0), host.To("db"), port.To(9000), cores.To(2)},
+			want: server{Host: "db", Port: 9000, Cores: 2},
+		},
+		{
+			name: "every field problem together",
+			set:  []builder.Setter[server]{host.To(" "), port.To(70000)},
+			problems: []string{
+				"validation failed for Host: must not be empty (value:  )",
+				"validation failed for Port: must be between 1 and 65535 (value: 70000)",
+				"validation failed for Cores: must be at least 1 (value: 0)",
+			},
+		},
+		{
+			name: "joined check errors are separate",
+			set:  []builder.Setter[server]{host.To("db"), port.To(80), cores.To(128)},
+			problems: []string{
+				"validation failed for Port: is privileged (value: 80)",
+				"too many cores for one host",
+			},
+		},
+		{
+			name: "checks wait for valid fields",
+			set:  []builder.Setter[server]{port.To(80), cores.To(128)},
+			problems: []string{
+				"validation failed for Host: is required",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := spec.New().Set(tt.set...).Build()
+			if tt.problems == nil {
+				if err != nil {
+					t.Fatalf("Build: %v", err)
+				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("Build = %+v, want %+v", got, tt.want)
+				}
+				return
+			}
+
+			var agg *errs.AggregatedError
+			if !errors.As(err, &agg) {
+				t.Fatalf("Build error = %v, want an *errs.AggregatedError", err)
+			}
+			if agg.Op != "build server" {
+				t.Errorf("Op = %q, want %q", agg.Op, "build server")
+			}
+			var msgs []string
+			for _, e := range agg.Errors {
+				msgs = append(msgs, e.Error())
+			}
+			if !reflect.DeepEqual(msgs, tt.problems) {
+				t.Errorf("problems = %q, want %q", msgs, tt.problems)
+			}
+			if !reflect.DeepEqual(got, server{}) {
+				t.Errorf("Build returned %+v with an error, want the zero value", got)
+			}
+		})
+	}
+}
+
+func TestCloneIsolatesSlices(t *testing.T) {
+	shared := []string{"a", "b"}
+	base := spec.New().Set(host.To("db"), cores.To(2), tags.To(shared))
+	clone := base.Clone()
+
+	shared[0] = "changed" // reaches base, which holds shared itself
+	clone.Set(host.To("cache"))
+	built := clone.MustBuild()
+	built.Tags[1] = "built"
+
+	if got := clone.MustBuild(); got.Host != "cache" || !reflect.DeepEqual(got.Tags, []string{"a", "b"}) {
+		t.Errorf("clone = %+v, want Host cache and Tags [a b]", got)
+	}
+	if got := base.MustBuild(); got.Host != "db" || !reflect.DeepEqual(got.Tags, []string{"changed", "b"}) {
+		t.Errorf("base = %+v, want Host db and Tags [changed b]", got)
+	}
+}
+
+func TestDefaultsAreNotShared(t *testing.T) {
+	type labelled struct {
+		Tags []string
+		Meta map[string]string
+	}
+	spec := builder.NewSpec[labelled]("labelled")
+	builder.Optional(spec, "Tags", func(l *labelled) *[]string { return &l.Tags }, []string{"a"})
+	builder.Optional(spec, "Meta", func(l *labelled) *map[string]string { return &l.Meta }, map[string]string{"a": "b"})
+	b := spec.New()
+
+	first := b.MustBuild()
+	first.Tags[0] = "MUTATED"
+	first.Meta["a"] = "MUTATED"
+
+	want := labelled{Tags: []string{"a"}, Meta: map[string]string{"a": "b"}}
+	if got := b.MustBuild(); !reflect.DeepEqual(got, want) {
+		t.Errorf("second Build = %+v, want %+v", got, want)
+	}
+}
+
+func TestSetPanicsForAnotherSpec(t *testing.T) {
+	other := builder.NewSpec[server]("other server")
+	otherHost := builder.Required(other, "Host", func(s *server) *string { return &s.Host })
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatal("Set with another spec's setter did not panic")
+		}
+		if want := "builder: setter for a different server spec"; fmt.Sprint(r) != want {
+			t.Errorf("panic = %q, want %q", r, want)
+		}
+	}()
+	spec.New().Set(otherHost.To("db"))
+}
@@ -0,0 +1,57 @@
+package builder
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// NotEmpty rejects strings that are empty or only whitespace.
+func NotEmpty(s string) error {
+	if strings.TrimSpace(s) == "" {
+		return errors.New("must not be empty")
+	}
+	return nil
+}
+
+// Min rejects values below min.
+func Min[V cmp.Ordered](min V) Validator[V] {
+	return func(v V) error {
+		if v < min {
+			return fmt.Errorf("must be at least %v", min)
+		}
+		return nil
+	}
+}
+
+// Max rejects values above max.
+func Max[V cmp.Ordered](max V) Validator[V] {
+	return func(v V) error {
+		if v > max {
+			return fmt.Errorf("must be at most %v", max)
+		}
+		return nil
+	}
+}
+
+// Between rejects values outside [min, max].
+func Between[V cmp.Ordered](min, max V) Validator[V] {
+	return func(v V) error {
+		if v < min || v > max {
+			return fmt.Errorf("must be between %v and %v", min, max)
+		}
+		return nil
+	}
+}
+
+// OneOf rejects values that aren't in allowed.
+func OneOf[V comparable](allowed ...V) Validator[V] {
+	return func(v V) error {
+		if !slices.Contains(allowed, v) {
+			return fmt.Errorf("must be one of %v", allowed)
+		}
+		return nil
+	}
+}
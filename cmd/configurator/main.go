@@ -0,0 +1,97 @@
+// Command configurator checks a PC build against a parts catalogue: it
+// prints the parts, price and score of a compatible build, or every
+// problem with an incompatible one.
+//
+//	go run ./cmd/configurator -list
+//	go run ./cmd/configurator -cpu ryzen-5-7600 -board b650-plus \
+//		-memory ddr5-32-6000 -storage nvme-1tb,hdd-4tb -gpu rtx-4060 -psu psu-550
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"go-practice/errs"
+	"go-practice/hardware"
+)
+
+var presets = map[string]hardware.Weights{
+	"gaming":      hardware.Gaming,
+	"office":      hardware.Office,
+	"workstation": hardware.Workstation,
+}
+
+func list(cat *hardware.Catalogue) {
+	out := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(out, "id\tname\tprice\t")
+	for _, id := range cat.IDs() {
+		c, _ := cat.Get(id)
+		p := hardware.Info(c)
+		fmt.Fprintf(out, "%s\t%s\t%v\t\n", p.ID, p.Name, p.Price)
+	}
+	out.Flush()
+}
+
+func main() {
+	path := flag.String("catalogue", "", "catalogue JSON file (default: the built-in one)")
+	listParts := flag.Bool("list", false, "list the catalogue and exit")
+	cpu := flag.String("cpu", "", "CPU id")
+	board := flag.String("board", "", "motherboard id")
+	memory := flag.String("memory", "", "memory kit id")
+	storage := flag.String("storage", "", "comma-separated drive ids")
+	gpu := flag.String("gpu", "", "graphics card id (default: integrated graphics)")
+	psu := flag.String("psu", "", "power supply id")
+	weights := flag.String("weights", "gaming", "scoring preset: gaming, office or workstation")
+	flag.Parse()
+
+	w, ok := presets[*weights]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "configurator: unknown -weights %q\n", *weights)
+		os.Exit(2)
+	}
+
+	cat := hardware.DefaultCatalogue()
+	if *path != "" {
+		var err error
+		if cat, err = hardware.LoadCatalogue(*path); err != nil {
+			fmt.Fprintln(os.Stderr, "configurator:", err)
+			os.Exit(1)
+		}
+	}
+	if *listParts {
+		list(cat)
+		return
+	}
+
+	pb := cat.NewPCBuilder().CPU(*cpu).Motherboard(*board).Memory(*memory).PSU(*psu)
+	if *gpu != "" {
+		pb.GPU(*gpu)
+	}
+	if *storage != "" {
+		for _, id := range strings.Split(*storage, ",") {
+			pb.AddStorage(strings.TrimSpace(id))
+		}
+	}
+
+	pc, err := pb.Build()
+	if err != nil {
+		var agg *errs.AggregatedError
+		if !errors.As(err, &agg) {
+			fmt.Fprintln(os.Stderr, "configurator:", err)
+			os.Exit(1)
+		}
+		fmt.Fprintln(os.Stderr, "configurator: the build doesn't work:")
+		for _, problem := range agg.Errors {
+			fmt.Fprintln(os.Stderr, "  -", problem)
+		}
+		os.Exit(1)
+	}
+
+	fmt.Println(pc)
+	fmt.Printf("\npower: %d W needed, %d W supplied\n", pc.RequiredWatts(), pc.PSU.Wattage)
+	fmt.Printf("%s score: %v\n", *weights, pc.Score(w))
+}
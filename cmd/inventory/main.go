@@ -0,0 +1,190 @@
+// Command inventory manages a vehicle stock saved in a JSON file, or
+// serves it over HTTP.
+//
+//	go run ./cmd/inventory add 1HGCM82633A004352 Honda Accord 2003 Silver 4500
+//	go run ./cmd/inventory search -make honda -available
+//	go run ./cmd/inventory price 1HGCM82633A004352 3999
+//	go run ./cmd/inventory reserve -for 24h 1HGCM82633A004352 "Ada Lovelace"
+//	go run ./cmd/inventory serve -addr :8080
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"go-practice/inventory"
+)
+
+const usage = `usage: inventory [-db file] <command> [arguments]
+
+commands:
+  add <vin> <brand> <model> <year> <color> <price>
+  get <vin>                      show a vehicle and its price history
+  search [-make m] [-model m] [-color c] [-years 2018-2022] [-available]
+  price <vin> <price>            change the asking price
+  reserve [-for 48h] <vin> <customer>
+  release <vin> <customer>
+  remove <vin> [customer]        take a sold vehicle out of stock
+  serve [-addr :8080]            serve the stock as JSON under /vehicles
+`
+
+func main() {
+	dbPath := flag.String("db", "inventory.json", "file the stock is saved in")
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	inv, err := inventory.Open(&inventory.FileDatabase{Path: *dbPath})
+	if err != nil {
+		fail(err)
+	}
+	defer inv.Close()
+
+	cmd, args := flag.Arg(0), flag.Args()[1:]
+	switch cmd {
+	case "add":
+		need(args, 6)
+		year, price := number(args[3]), number(args[5])
+		car := inventory.Car{Brand: args[1], Model: args[2], Year: year, Color: args[4]}
+		v, err := inv.Add(args[0], car, price)
+		check(err)
+		show(v)
+	case "get":
+		need(args, 1)
+		v, err := inv.Get(args[0])
+		check(err)
+		show(v)
+	case "search":
+		search(inv, args)
+	case "price":
+		need(args, 2)
+		v, err := inv.SetPrice(args[0], number(args[1]))
+		check(err)
+		show(v)
+	case "reserve":
+		fs := flag.NewFlagSet("reserve", flag.ExitOnError)
+		d := fs.Duration("for", inventory.DefaultHold, "how long to hold the vehicle")
+		fs.Parse(args)
+		need(fs.Args(), 2)
+		hold, err := inv.Reserve(fs.Arg(0), fs.Arg(1), *d)
+		check(err)
+		fmt.Printf("held for %s until %s\n", hold.Customer, hold.Expires.Format(time.RFC1123))
+	case "release":
+		need(args, 2)
+		check(inv.Release(args[0], args[1]))
+	case "remove":
+		need(args, 1)
+		customer := ""
+		if len(args) > 1 {
+			customer = args[1]
+		}
+		check(inv.Remove(args[0], customer))
+	case "serve":
+		fs := flag.NewFlagSet("serve", flag.ExitOnError)
+		addr := fs.String("addr", ":8080", "address to listen on")
+		fs.Parse(args)
+		h := inventory.NewHandler(inv, "/vehicles")
+		mux := http.NewServeMux()
+		mux.Handle(h.GetEndpoint(), h)
+		mux.Handle(h.GetEndpoint()+"/", h)
+		fmt.Fprintf(os.Stderr, "serving %s on %s\n", h.GetEndpoint(), *addr)
+		check(http.ListenAndServe(*addr, mux))
+	default:
+		fmt.Fprintf(os.Stderr, "inventory: unknown command %q\n\n", cmd)
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+func search(inv *inventory.Inventory, args []string) {
+	fs := flag.NewFlagSet("search", flag.ExitOnError)
+	brand := fs.String("make", "", "brand")
+	model := fs.String("model", "", "model")
+	color := fs.String("color", "", "color")
+	years := fs.String("years", "", "model years, such as 2018-2022")
+	available := fs.Bool("available", false, "only vehicles nobody holds")
+	fs.Parse(args)
+
+	var filters []inventory.Filter
+	if *brand != "" {
+		filters = append(filters, inventory.Make(*brand))
+	}
+	if *model != "" {
+		filters = append(filters, inventory.Model(*model))
+	}
+	if *color != "" {
+		filters = append(filters, inventory.Color(*color))
+	}
+	if *years != "" {
+		var min, max int
+		if _, err := fmt.Sscanf(*years, "%d-%d", &min, &max); err != nil {
+			fail(fmt.Errorf("-years %q: want a range such as 2018-2022", *years))
+		}
+		filters = append(filters, inventory.YearBetween(min, max))
+	}
+	if *available {
+		filters = append(filters, inventory.Available())
+	}
+
+	now := time.Now()
+	out := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(out, "VIN\tyear\tbrand\tmodel\tcolor\tprice\tstatus")
+	for _, v := range inv.Search(filters...) {
+		status := "available"
+		if v.OnHold(now) {
+			status = "held by " + v.Hold.Customer
+		}
+		fmt.Fprintf(out, "%s\t%d\t%s\t%s\t%s\t$%d\t%s\n",
+			v.VIN, v.Car.Year, v.Car.Brand, v.Car.Model, v.Car.Color, v.Price, status)
+	}
+	out.Flush()
+}
+
+func show(v inventory.Vehicle) {
+	raw, _ := json.MarshalIndent(v, "", "  ")
+	fmt.Println(string(raw))
+}
+
+func need(args []string, n int) {
+	if len(args) < n {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+}
+
+func number(s string) int {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		fail(fmt.Errorf("%q is not a whole number", s))
+	}
+	return n
+}
+
+func check(err error) {
+	if err != nil {
+		fail(err)
+	}
+}
+
+func fail(err error) {
+	var agg interface{ Unwrap() []error }
+	if errors.As(err, &agg) {
+		fmt.Fprintln(os.Stderr, "inventory:")
+		for _, e := range agg.Unwrap() {
+			fmt.Fprintln(os.Stderr, "  -", e)
+		}
+	} else {
+		fmt.Fprintln(os.Stderr, "inventory:", err)
+	}
+	os.Exit(1)
+}
@@ -0,0 +1,97 @@
+// Command payroll runs one pay period for a small sample staff and prints
+// the payslips as text or JSON. The rules are illustrative, not any real
+// country's tax code.
+//
+//	go run ./cmd/payroll -period 2025-03-01
+//	go run ./cmd/payroll -period 2025-03-01 -frequency biweekly -json
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"go-practice/payroll"
+)
+
+func usd(s string) payroll.Money { return payroll.MustParse(s, "USD") }
+
+var staff = []payroll.Employee{
+	{ID: "E001", Name: "Alice", Salary: usd("75000"), Start: payroll.Date(2021, 4, 1)},
+	{ID: "E002", Name: "Bob", Salary: usd("92000"), Start: payroll.Date(2019, 9, 16)},
+	{ID: "E003", Name: "Charlie", Salary: usd("58000"), Start: payroll.Date(2023, 1, 9), End: payroll.Date(2025, 3, 14)},
+	{ID: "E004", Name: "Diana", Salary: usd("64000"), Start: payroll.Date(2025, 3, 17)},
+}
+
+var rules = []payroll.Rule{
+	payroll.PercentDeduction{Label: "Pension", Rate: payroll.Percent(5), When: payroll.PreTax},
+	payroll.FixedDeduction{Label: "Health plan", Amount: usd("180"), When: payroll.PreTax, ProRated: true},
+	payroll.BracketTax{Label: "Income tax", Brackets: []payroll.Bracket{
+		{UpTo: usd("12000"), Rate: 0},
+		{UpTo: usd("50000"), Rate: payroll.Percent(20)},
+		{Rate: payroll.Percent(40)},
+	}},
+	payroll.FlatTax{Label: "Social insurance", Rate: payroll.Percent(7.65)},
+	payroll.FixedDeduction{Label: "Union dues", Amount: usd("25"), When: payroll.PostTax},
+}
+
+var frequencies = map[string]payroll.Frequency{
+	"weekly":      payroll.Weekly,
+	"biweekly":    payroll.Biweekly,
+	"semimonthly": payroll.Semimonthly,
+	"monthly":     payroll.Monthly,
+}
+
+func main() {
+	day := flag.String("period", time.Now().Format(time.DateOnly), "any day in the pay period")
+	freq := flag.String("frequency", "monthly", "weekly, biweekly, semimonthly or monthly")
+	asJSON := flag.Bool("json", false, "print JSON instead of text")
+	calendar := flag.Bool("calendar", false, "pro-rate by calendar days instead of working days")
+	flag.Parse()
+
+	f, ok := frequencies[*freq]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "payroll: unknown -frequency %q\n", *freq)
+		os.Exit(2)
+	}
+	t, err := time.Parse(time.DateOnly, *day)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "payroll: -period:", err)
+		os.Exit(2)
+	}
+
+	en := &payroll.Engine{
+		// biweekly and weekly periods start on Mondays from this one
+		Schedule: payroll.Schedule{Frequency: f, Anchor: payroll.Date(2025, 1, 6)},
+		Rules:    rules,
+	}
+	if *calendar {
+		en.ProRate = payroll.CalendarDays
+	}
+	if err := en.Validate(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	slips, err := en.RunAll(staff, en.Schedule.PeriodFor(t))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(slips)
+		return
+	}
+	var gross, net payroll.Money
+	for _, s := range slips {
+		s.WriteText(os.Stdout)
+		fmt.Println()
+		gross, net = gross.Add(s.Gross), net.Add(s.Net)
+	}
+	fmt.Printf("%d payslips, %v gross, %v net\n", len(slips), gross, net)
+}
@@ -0,0 +1,213 @@
+// Command receiverbench runs chapter 9's receiver benchmarks and escape
+// test and prints value and pointer receivers side by side: time and
+// allocations per call for every struct size and workload, then the
+// compiler's escape-analysis verdict for each method. It runs
+// `go test` on ./09-pointers, so the benchmarks live in one place.
+//
+//	go run ./cmd/receiverbench
+//	go run ./cmd/receiverbench -count 5 -benchtime 200ms
+//	go run ./cmd/receiverbench -format benchstat -count 10 > new.txt
+//
+// The benchstat format is what `go test -bench` prints, so two runs can be
+// compared with golang.org/x/perf/cmd/benchstat, e.g.
+// `benchstat -col /receiver new.txt`.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+const pkg = "go-practice/09-pointers"
+
+var receiverNames = [2]string{"value", "pointer"}
+
+// result is one benchmark line, such as
+//
+//	BenchmarkReceiverBox/size=16B/receiver=value-8  48903842  26.23 ns/op  16 B/op  1 allocs/op
+type result struct {
+	workload, size, receiver string
+	nsPerOp                  float64
+	allocsPerOp              int64
+}
+
+// benchLine matches a result line; group 1 is the name without the
+// GOMAXPROCS suffix.
+var benchLine = regexp.MustCompile(`^(Benchmark\S+?)(-\d+)?\s+\d+\s+.*ns/op`)
+
+// escapeLog matches what TestReceiverEscapes logs with -v.
+var escapeLog = regexp.MustCompile(`^\s+escape_test\.go:\d+: (.*)$`)
+
+func main() {
+	format := flag.String("format", "table", "output format: table or benchstat")
+	count := flag.Int("count", 1, "run each benchmark this many times")
+	benchtime := flag.String("benchtime", "", "passed to go test -benchtime, e.g. 100ms or 1000x")
+	escape := flag.Bool("escape", true, "show escape analysis from TestReceiverEscapes (table format only)")
+	flag.Parse()
+
+	if *count <= 0 || (*format != "table" && *format != "benchstat") || flag.NArg() > 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	run := "^$"
+	if *escape && *format == "table" {
+		run = "^TestReceiverEscapes$"
+	}
+	args := []string{"test", pkg, "-v", "-run", run, "-bench", "^BenchmarkReceiver", "-benchmem",
+		"-count", strconv.Itoa(*count)}
+	if *benchtime != "" {
+		args = append(args, "-benchtime", *benchtime)
+	}
+	out, err := exec.Command("go", args...).CombinedOutput()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "receiverbench: go %s: %v\n%s", strings.Join(args, " "), err, out)
+		os.Exit(1)
+	}
+
+	switch *format {
+	case "table":
+		results, escapes := parse(out)
+		if len(results) == 0 {
+			fmt.Fprintf(os.Stderr, "receiverbench: no benchmark results in output:\n%s", out)
+			os.Exit(1)
+		}
+		writeTable(results)
+		if *escape {
+			writeEscapes(escapes)
+		}
+	case "benchstat":
+		writeBenchstat(out)
+	}
+}
+
+// parse splits go test -v output into benchmark results and the lines
+// TestReceiverEscapes logged. -count runs the test more than once, so
+// repeated lines are dropped.
+func parse(out []byte) ([]result, []string) {
+	var results []result
+	var escapes []string
+	seen := make(map[string]bool)
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	for sc.Scan() {
+		line := sc.Text()
+		if m := escapeLog.FindStringSubmatch(line); m != nil {
+			if !seen[m[1]] {
+				seen[m[1]] = true
+				escapes = append(escapes, m[1])
+			}
+			continue
+		}
+		if r, ok := parseResult(line); ok {
+			results = append(results, r)
+		}
+	}
+	return results, escapes
+}
+
+func parseResult(line string) (result, bool) {
+	m := benchLine.FindStringSubmatch(line)
+	if m == nil {
+		return result{}, false
+	}
+	parts := strings.Split(m[1], "/")
+	if len(parts) != 3 {
+		return result{}, false
+	}
+	r := result{
+		workload: strings.ToLower(strings.TrimPrefix(parts[0], "BenchmarkReceiver")),
+		size:     strings.TrimPrefix(parts[1], "size="),
+		receiver: strings.TrimPrefix(parts[2], "receiver="),
+	}
+
+	// After the name and iteration count come value and unit pairs.
+	fields := strings.Fields(line)[2:]
+	for i := 0; i+1 < len(fields); i += 2 {
+		switch fields[i+1] {
+		case "ns/op":
+			r.nsPerOp, _ = strconv.ParseFloat(fields[i], 64)
+		case "allocs/op":
+			r.allocsPerOp, _ = strconv.ParseInt(fields[i], 10, 64)
+		}
+	}
+	return r, true
+}
+
+// writeTable prints one row per workload and size, with value and pointer
+// receivers side by side. With -count above 1 the median run is shown.
+func writeTable(results []result) {
+	type key struct{ workload, size string }
+	var order []key
+	runs := make(map[key]*[2][]result)
+	for _, r := range results {
+		k := key{r.workload, r.size}
+		if runs[k] == nil {
+			runs[k] = new([2][]result)
+			order = append(order, k)
+		}
+		i := slices.Index(receiverNames[:], r.receiver)
+		if i < 0 {
+			continue
+		}
+		runs[k][i] = append(runs[k][i], r)
+	}
+
+	out := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(out, "workload\tsize\tvalue ns/op\tpointer ns/op\tvalue/pointer\tvalue allocs/op\tpointer allocs/op\t")
+	for _, k := range order {
+		pair := runs[k]
+		if len(pair[0]) == 0 || len(pair[1]) == 0 {
+			continue
+		}
+		v, p := median(pair[0]), median(pair[1])
+		fmt.Fprintf(out, "%s\t%s\t%.2f\t%.2f\t%.2fx\t%d\t%d\t\n",
+			k.workload, k.size, v.nsPerOp, p.nsPerOp, v.nsPerOp/p.nsPerOp, v.allocsPerOp, p.allocsPerOp)
+	}
+	out.Flush()
+}
+
+// median returns the run with the median time.
+func median(runs []result) result {
+	sorted := slices.Clone(runs)
+	slices.SortFunc(sorted, func(a, b result) int { return cmp.Compare(a.nsPerOp, b.nsPerOp) })
+	return sorted[len(sorted)/2]
+}
+
+func writeEscapes(escapes []string) {
+	fmt.Println("\nEscape analysis (TestReceiverEscapes, go build -gcflags=-m):")
+	if len(escapes) == 0 {
+		fmt.Println("  no verdicts; TestReceiverEscapes was skipped")
+		return
+	}
+	for _, e := range escapes {
+		fmt.Println(" ", e)
+	}
+}
+
+// writeBenchstat prints the header and result lines of the go test
+// output, dropping the -v noise so the file holds only what benchstat
+// reads.
+func writeBenchstat(out []byte) {
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	for sc.Scan() {
+		line := sc.Text()
+		for _, prefix := range []string{"goos:", "goarch:", "pkg:", "cpu:"} {
+			if strings.HasPrefix(line, prefix) {
+				fmt.Println(line)
+			}
+		}
+		if benchLine.MatchString(line) {
+			fmt.Println(line)
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// Format selects the wire format used by Marshal and Unmarshal.
+type Format int
+
+const (
+	JSON Format = iota
+	YAML
+)
+
+func (f Format) String() string {
+	switch f {
+	case JSON:
+		return "json"
+	case YAML:
+		return "yaml"
+	default:
+		return fmt.Sprintf("Format(%d)", int(f))
+	}
+}
+
+// Marshal encodes items as a list of objects, each carrying a "type"
+// field with the name registered for the element's concrete type.
+func Marshal[T any](r *Registry, format Format, items []T) ([]byte, error) {
+	list := make([]any, len(items))
+	for i, item := range items {
+		obj, err := r.encodeItem(item)
+		if err != nil {
+			return nil, &ItemError{Index: i, Err: err}
+		}
+		list[i] = obj
+	}
+
+	switch format {
+	case JSON:
+		return json.MarshalIndent(list, "", "  ")
+	case YAML:
+		return EncodeYAML(list)
+	default:
+		return nil, fmt.Errorf("codec: unsupported format %v", format)
+	}
+}
+
+// Unmarshal decodes a list produced by Marshal. Every element is rebuilt
+// as its registered concrete type and must satisfy T, possibly after one
+// or more Upgrade steps. Errors for individual elements are collected and
+// returned together as *ItemError values.
+func Unmarshal[T any](r *Registry, format Format, data []byte) ([]T, error) {
+	var tree any
+	switch format {
+	case JSON:
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		if err := decoder.Decode(&tree); err != nil {
+			return nil, fmt.Errorf("codec: %w", err)
+		}
+		if err := decoder.Decode(new(json.RawMessage)); err != io.EOF {
+			return nil, errors.New("codec: unexpected data after the top-level list")
+		}
+	case YAML:
+		var err error
+		if tree, err = DecodeYAML(data); err != nil {
+			return nil, fmt.Errorf("codec: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("codec: unsupported format %v", format)
+	}
+
+	list, ok := tree.([]any)
+	if !ok && tree != nil {
+		return nil, fmt.Errorf("codec: expected a list at the top level, got %T", tree)
+	}
+
+	items := make([]T, 0, len(list))
+	var errs []error
+	for i, raw := range list {
+		item, err := decodeAs[T](r, raw)
+		if err != nil {
+			errs = append(errs, &ItemError{Index: i, Err: err})
+			continue
+		}
+		items = append(items, item)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return items, nil
+}
+
+// MarshalValue encodes a single value as a JSON object carrying its
+// "type" field, the form one element takes in Marshal's output.
+func (r *Registry) MarshalValue(v any) ([]byte, error) {
+	obj, err := r.encodeItem(v)
+	if err != nil {
+		return nil, fmt.Errorf("codec: %w", err)
+	}
+	return json.Marshal(obj)
+}
+
+// UnmarshalValue decodes a single JSON object produced by MarshalValue,
+// the way Unmarshal decodes each element of a list.
+func UnmarshalValue[T any](r *Registry, data []byte) (T, error) {
+	var zero T
+	var raw any
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&raw); err != nil {
+		return zero, fmt.Errorf("codec: %w", err)
+	}
+	if err := decoder.Decode(new(json.RawMessage)); err != io.EOF {
+		return zero, errors.New("codec: unexpected data after the value")
+	}
+	v, err := decodeAs[T](r, raw)
+	if err != nil {
+		return zero, fmt.Errorf("codec: %w", err)
+	}
+	return v, nil
+}
+
+func decodeAs[T any](r *Registry, raw any) (T, error) {
+	var zero T
+
+	obj, ok := raw.(map[string]any)
+	if !ok {
+		return zero, fmt.Errorf("expected an object, got %T", raw)
+	}
+	v, err := r.decodeItem(obj)
+	if err != nil {
+		return zero, err
+	}
+
+	// Follow Upgrade steps until the value fits the element type. The
+	// limit guards against upgrade cycles.
+	for range 16 {
+		if item, ok := v.(T); ok {
+			return item, nil
+		}
+		upgrader, ok := v.(Upgrader)
+		if !ok {
+			break
+		}
+		if v, err = upgrader.Upgrade(); err != nil {
+			return zero, fmt.Errorf("upgrading %T: %w", upgrader, err)
+		}
+	}
+	return zero, fmt.Errorf("%T does not implement %v", v, reflect.TypeFor[T]())
+}
+
+func (r *Registry) encodeItem(item any) (map[string]any, error) {
+	name, err := r.NameOf(item)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(item)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var payload any
+	if err := decoder.Decode(&payload); err != nil {
+		return nil, err
+	}
+
+	if !objectLike(reflect.TypeOf(item)) {
+		return map[string]any{TypeKey: name, ValueKey: payload}, nil
+	}
+
+	obj, ok := payload.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%s encoded as %s, expected an object", name, data)
+	}
+	if _, clash := obj[TypeKey]; clash {
+		return nil, fmt.Errorf("%s has a field named %q, which is reserved", name, TypeKey)
+	}
+	obj[TypeKey] = name
+	return obj, nil
+}
+
+func (r *Registry) decodeItem(obj map[string]any) (any, error) {
+	name, ok := obj[TypeKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("missing or non-string %q field", TypeKey)
+	}
+	e, err := r.lookup(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var payload any
+	if objectLike(e.typ) {
+		fields := make(map[string]any, len(obj)-1)
+		for k, v := range obj {
+			if k != TypeKey {
+				fields[k] = v
+			}
+		}
+		payload = fields
+	} else {
+		for k := range obj {
+			if k != TypeKey && k != ValueKey {
+				return nil, fmt.Errorf("%s: unexpected field %q", name, k)
+			}
+		}
+		payload = obj[ValueKey]
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	target := e.typ
+	if target.Kind() == reflect.Pointer {
+		target = target.Elem()
+	}
+	ptr := reflect.New(target)
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(ptr.Interface()); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	if e.typ.Kind() == reflect.Pointer {
+		return ptr.Interface(), nil
+	}
+	return ptr.Elem().Interface(), nil
+}
+
+// objectLike reports whether values of t encode as JSON objects, in which
+// case the discriminator is merged into the object instead of wrapping it.
+func objectLike(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if reflect.PointerTo(t).Implements(reflect.TypeFor[json.Marshaler]()) {
+		return false
+	}
+	return t.Kind() == reflect.Struct || (t.Kind() == reflect.Map && t.Key().Kind() == reflect.String)
+}
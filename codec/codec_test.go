@@ -0,0 +1,251 @@
+package codec
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type animal interface {
+	Sound() string
+}
+
+// dog is the current version of "dog".
+type dog struct {
+	Name string `json:"name"`
+}
+
+func (dog) Sound() string { return "woof" }
+
+// oldDog is "dog@v1". It upgrades through puppy, which is not an animal,
+// so decoding it takes two Upgrade steps.
+type oldDog struct {
+	Nick string `json:"nick"`
+}
+
+func (d oldDog) Upgrade() (any, error) { return puppy{name: d.Nick}, nil }
+
+type puppy struct{ name string }
+
+func (p puppy) Upgrade() (any, error) { return dog{Name: p.name}, nil }
+
+// cricket encodes as a number, so it is wrapped in a "value" field.
+type cricket int
+
+func (c cricket) Sound() string { return strings.Repeat("chirp", int(c)) }
+
+type broken struct{}
+
+func (broken) Upgrade() (any, error) { return nil, errors.New("no longer supported") }
+
+type rock struct{}
+
+type tagged struct {
+	Type string `json:"type"`
+}
+
+func (tagged) Sound() string { return "" }
+
+func newTestRegistry() *Registry {
+	r := NewRegistry()
+	r.MustRegister("dog@v1", oldDog{})
+	r.MustRegister("dog@v2", dog{})
+	r.MustRegister("cricket", cricket(0))
+	r.MustRegister("broken", broken{})
+	r.MustRegister("rock", rock{})
+	r.MustRegister("tagged", tagged{})
+	return r
+}
+
+func TestMarshalJSON(t *testing.T) {
+	r := newTestRegistry()
+	pets := []animal{dog{Name: "Rex"}, cricket(3)}
+
+	data, err := Marshal(r, JSON, pets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[
+  {
+    "name": "Rex",
+    "type": "dog@v2"
+  },
+  {
+    "type": "cricket",
+    "value": 3
+  }
+]`
+	if string(data) != want {
+		t.Errorf("Marshal gave\n%s\nwant\n%s", data, want)
+	}
+
+	got, err := Unmarshal[animal](r, JSON, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, pets) {
+		t.Errorf("round trip gave %#v, want %#v", got, pets)
+	}
+}
+
+func TestMarshalErrors(t *testing.T) {
+	r := newTestRegistry()
+	tests := []struct {
+		name  string
+		items []any
+		want  string
+	}{
+		{"unregistered type", []any{dog{}, 1.5}, "codec: item 1: type float64 is not registered"},
+		{"nil element", []any{nil}, "codec: item 0: nil value has no type"},
+		{"reserved field", []any{tagged{Type: "x"}}, `codec: item 0: tagged has a field named "type", which is reserved`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Marshal(r, JSON, tc.items)
+			if err == nil || err.Error() != tc.want {
+				t.Fatalf("got error %v, want %q", err, tc.want)
+			}
+			var itemErr *ItemError
+			if !errors.As(err, &itemErr) {
+				t.Errorf("error %v is not an *ItemError", err)
+			}
+		})
+	}
+
+	if _, err := Marshal(r, Format(7), []any{dog{}}); err == nil || err.Error() != "codec: unsupported format Format(7)" {
+		t.Errorf("unknown format: got error %v", err)
+	}
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want []animal
+	}{
+		{"exact version", `[{"type": "dog@v2", "name": "Rex"}]`, []animal{dog{Name: "Rex"}}},
+		{"bare name is the latest version", `[{"type": "dog", "name": "Rex"}]`, []animal{dog{Name: "Rex"}}},
+		{"upgrade chain", `[{"type": "dog@v1", "nick": "Rex"}]`, []animal{dog{Name: "Rex"}}},
+		{"wrapped value", `[{"type": "cricket", "value": 2}]`, []animal{cricket(2)}},
+		{"mixed", `[{"type": "cricket", "value": 1}, {"type": "dog@v1", "nick": "Ace"}]`, []animal{cricket(1), dog{Name: "Ace"}}},
+		{"empty list", `[]`, []animal{}},
+		{"null", `null`, []animal{}},
+		{"trailing whitespace", "[]\n\t ", []animal{}},
+	}
+	r := newTestRegistry()
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Unmarshal[animal](r, JSON, []byte(tc.in))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %#v, want %#v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    string
+		indexes []int // of the *ItemError values, nil when the whole input is rejected
+	}{
+		{"trailing garbage", `[] garbage`, "codec: unexpected data after the top-level list", nil},
+		{"second value", `[] []`, "codec: unexpected data after the top-level list", nil},
+		{"not a list", `{"type": "dog"}`, "codec: expected a list at the top level, got map[string]interface {}", nil},
+		{"malformed", `[{"type": `, "codec: unexpected EOF", nil},
+		{"not an object", `[1]`, "codec: item 0: expected an object, got json.Number", []int{0}},
+		{"missing type", `[{"name": "Rex"}]`, `codec: item 0: missing or non-string "type" field`, []int{0}},
+		{"unknown field", `[{"type": "dog", "name": "Rex", "age": 3}]`, `codec: item 0: dog: json: unknown field "age"`, []int{0}},
+		{"field beside value", `[{"type": "cricket", "value": 1, "legs": 6}]`, `codec: item 0: cricket: unexpected field "legs"`, []int{0}},
+		{"failed upgrade", `[{"type": "broken"}]`, "codec: item 0: upgrading codec.broken: no longer supported", []int{0}},
+		{"not the element type", `[{"type": "rock"}]`, "codec: item 0: codec.rock does not implement codec.animal", []int{0}},
+		{
+			"every bad item",
+			`[{"type": "rock"}, {"type": "dog", "name": "Rex"}, 7]`,
+			"codec: item 0: codec.rock does not implement codec.animal\ncodec: item 2: expected an object, got json.Number",
+			[]int{0, 2},
+		},
+	}
+	r := newTestRegistry()
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Unmarshal[animal](r, JSON, []byte(tc.in))
+			if err == nil || err.Error() != tc.want {
+				t.Fatalf("got error %v, want %q", err, tc.want)
+			}
+			if got != nil {
+				t.Errorf("got %#v with an error, want nil", got)
+			}
+
+			var indexes []int
+			if joined, ok := err.(interface{ Unwrap() []error }); ok {
+				for _, e := range joined.Unwrap() {
+					var itemErr *ItemError
+					if !errors.As(e, &itemErr) {
+						t.Fatalf("%v is not an *ItemError", e)
+					}
+					indexes = append(indexes, itemErr.Index)
+				}
+			}
+			if !reflect.DeepEqual(indexes, tc.indexes) {
+				t.Errorf("item indexes = %v, want %v", indexes, tc.indexes)
+			}
+		})
+	}
+}
+
+func TestUnknownTypeError(t *testing.T) {
+	r := newTestRegistry()
+	_, err := Unmarshal[animal](r, JSON, []byte(`[{"type": "dog"}, {"type": "hamster"}]`))
+
+	var itemErr *ItemError
+	if !errors.As(err, &itemErr) || itemErr.Index != 1 {
+		t.Fatalf("got error %v, want an *ItemError for item 1", err)
+	}
+	var unknown *UnknownTypeError
+	if !errors.As(err, &unknown) {
+		t.Fatalf("got error %v, want an *UnknownTypeError", err)
+	}
+	if unknown.Name != "hamster" {
+		t.Errorf("Name = %q, want hamster", unknown.Name)
+	}
+	if want := r.Names(); !reflect.DeepEqual(unknown.Known, want) {
+		t.Errorf("Known = %q, want %q", unknown.Known, want)
+	}
+	want := `codec: item 1: unknown type "hamster" (known: broken, cricket, dog@v1, dog@v2, rock, tagged)`
+	if err.Error() != want {
+		t.Errorf("error = %q, want %q", err, want)
+	}
+}
+
+func TestMarshalValue(t *testing.T) {
+	r := newTestRegistry()
+	for _, pet := range []animal{dog{Name: "Rex"}, cricket(2)} {
+		data, err := r.MarshalValue(pet)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := UnmarshalValue[animal](r, data)
+		if err != nil {
+			t.Fatalf("%s: %v", data, err)
+		}
+		if got != pet {
+			t.Errorf("%s: round trip gave %#v, want %#v", data, got, pet)
+		}
+	}
+
+	if got, err := UnmarshalValue[animal](r, []byte(`{"type": "dog@v1", "nick": "Ace"}`)); err != nil || got != (dog{Name: "Ace"}) {
+		t.Errorf("upgrading a single value gave %#v, %v", got, err)
+	}
+	if _, err := UnmarshalValue[animal](r, []byte(`{"type": "dog"} {}`)); err == nil {
+		t.Error("trailing data: expected an error")
+	}
+	if _, err := r.MarshalValue(1.5); err == nil || err.Error() != "codec: type float64 is not registered" {
+		t.Errorf("unregistered type: got error %v", err)
+	}
+}
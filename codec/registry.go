@@ -0,0 +1,203 @@
+// Package codec serializes slices of interface values, such as []Animal,
+// []shapes.Shape or []HTTPHandler, by tagging every element with a "type"
+// discriminator. It replaces the chapter 8 approach of storing values in
+// []interface{} and recovering them with type switches: concrete types are
+// registered once under a name, and the decoder uses that name to rebuild
+// the right Go type.
+//
+// Type names may carry a version suffix ("dog@v2"). Decoding a bare name
+// resolves to the newest registered version, and values decoded from an
+// older version can migrate themselves forward by implementing Upgrader.
+package codec
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// TypeKey is the field that carries the registered type name.
+const TypeKey = "type"
+
+// ValueKey holds the payload for registered types that don't encode as an
+// object, e.g. a named string or int type.
+const ValueKey = "value"
+
+// Upgrader is implemented by types registered under an older version of a
+// name. The decoder calls Upgrade until the value satisfies the slice's
+// element type.
+type Upgrader interface {
+	Upgrade() (any, error)
+}
+
+// UnknownTypeError reports a discriminator with no registered type.
+type UnknownTypeError struct {
+	Name  string   // the name found in the input
+	Known []string // every registered name, sorted
+}
+
+func (e *UnknownTypeError) Error() string {
+	return fmt.Sprintf("unknown type %q (known: %s)", e.Name, strings.Join(e.Known, ", "))
+}
+
+// ItemError wraps a failure to encode or decode a single slice element.
+type ItemError struct {
+	Index int
+	Err   error
+}
+
+func (e *ItemError) Error() string {
+	return fmt.Sprintf("codec: item %d: %v", e.Index, e.Err)
+}
+
+func (e *ItemError) Unwrap() error {
+	return e.Err
+}
+
+// entry describes one registered name.
+type entry struct {
+	name    string // full name including any version suffix
+	kind    string // name without the version suffix
+	version int    // 0 when unversioned
+	typ     reflect.Type
+}
+
+// Registry maps type names to concrete Go types and back. The zero value
+// is not usable; create one with NewRegistry.
+type Registry struct {
+	mu     sync.RWMutex
+	byName map[string]entry
+	byType map[reflect.Type]entry
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		byName: make(map[string]entry),
+		byType: make(map[reflect.Type]entry),
+	}
+}
+
+// Register associates name with the concrete type of prototype. Register
+// pointer prototypes (&UserHandler{}) for types whose methods have pointer
+// receivers. Both the name and the type must be unique.
+func (r *Registry) Register(name string, prototype any) error {
+	kind, version, err := parseName(name)
+	if err != nil {
+		return err
+	}
+	if prototype == nil {
+		return fmt.Errorf("codec: %q needs a non-nil prototype", name)
+	}
+
+	e := entry{name: name, kind: kind, version: version, typ: reflect.TypeOf(prototype)}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.byName[name]; exists {
+		return fmt.Errorf("codec: %q is already registered", name)
+	}
+	if other, exists := r.byType[e.typ]; exists {
+		return fmt.Errorf("codec: type %v is already registered as %q", e.typ, other.name)
+	}
+
+	r.byName[name] = e
+	r.byType[e.typ] = e
+	return nil
+}
+
+// MustRegister is like Register but panics on error.
+func (r *Registry) MustRegister(name string, prototype any) {
+	if err := r.Register(name, prototype); err != nil {
+		panic(err)
+	}
+}
+
+// Names returns every registered name in sorted order.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.namesLocked()
+}
+
+// NameOf returns the name registered for v's concrete type.
+func (r *Registry) NameOf(v any) (string, error) {
+	if v == nil {
+		return "", errors.New("nil value has no type")
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	e, ok := r.byType[reflect.TypeOf(v)]
+	if !ok {
+		return "", fmt.Errorf("type %T is not registered", v)
+	}
+	return e.name, nil
+}
+
+// lookup resolves a name from the input. An exact match wins; otherwise a
+// bare kind resolves to its highest registered version.
+func (r *Registry) lookup(name string) (entry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if e, ok := r.byName[name]; ok {
+		return e, nil
+	}
+
+	if kind, version, err := parseName(name); err == nil && version == 0 {
+		var latest entry
+		found := false
+		for _, e := range r.byName {
+			if e.kind == kind && (!found || e.version > latest.version) {
+				latest, found = e, true
+			}
+		}
+		if found {
+			return latest, nil
+		}
+	}
+
+	return entry{}, &UnknownTypeError{Name: name, Known: r.namesLocked()}
+}
+
+func (r *Registry) namesLocked() []string {
+	names := make([]string, 0, len(r.byName))
+	for name := range r.byName {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// parseName splits "dog@v2" into ("dog", 2). Names without a suffix have
+// version 0.
+func parseName(name string) (kind string, version int, err error) {
+	if strings.TrimSpace(name) != name || name == "" {
+		return "", 0, fmt.Errorf("codec: invalid type name %q", name)
+	}
+
+	kind, suffix, versioned := strings.Cut(name, "@")
+	if kind == "" {
+		return "", 0, fmt.Errorf("codec: invalid type name %q", name)
+	}
+	if !versioned {
+		return kind, 0, nil
+	}
+
+	digits, ok := strings.CutPrefix(suffix, "v")
+	if !ok {
+		return "", 0, fmt.Errorf("codec: version in %q must look like @v1", name)
+	}
+	version, err = strconv.Atoi(digits)
+	if err != nil || version < 1 {
+		return "", 0, fmt.Errorf("codec: version in %q must look like @v1", name)
+	}
+	return kind, version, nil
+}
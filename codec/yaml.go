@@ -0,0 +1,444 @@
+package codec
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// The YAML support here covers the subset needed for discriminated lists
+// and simple configuration files: block mappings and sequences, plain,
+// single- and double-quoted scalars, comments, and the empty flow
+// collections [] and {}. Anchors, tags, multi-line scalars and multiple
+// documents are not supported.
+
+// EncodeYAML renders a tree of map[string]any, []any and scalar values as
+// block-style YAML. Mapping keys are written in sorted order so output is
+// deterministic.
+func EncodeYAML(v any) ([]byte, error) {
+	var b strings.Builder
+	if err := writeYAML(&b, v, 0); err != nil {
+		return nil, err
+	}
+	return []byte(b.String()), nil
+}
+
+// DecodeYAML parses YAML into map[string]any, []any, string, bool, nil
+// and json.Number values, the same shapes encoding/json produces with
+// UseNumber.
+func DecodeYAML(data []byte) (any, error) {
+	p := &yamlParser{}
+	if err := p.scan(string(data)); err != nil {
+		return nil, err
+	}
+	if len(p.lines) == 0 {
+		return nil, nil
+	}
+
+	v, err := p.parseBlock(p.lines[0].indent)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.lines) {
+		l := p.lines[p.pos]
+		return nil, fmt.Errorf("yaml: line %d: unexpected indentation", l.number)
+	}
+	return v, nil
+}
+
+func writeYAML(b *strings.Builder, v any, indent int) error {
+	pad := strings.Repeat(" ", indent)
+
+	switch v := v.(type) {
+	case map[string]any:
+		if len(v) == 0 {
+			b.WriteString(pad + "{}\n")
+			return nil
+		}
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		for _, k := range keys {
+			b.WriteString(pad + yamlString(k) + ":")
+			if err := writeYAMLValue(b, v[k], indent); err != nil {
+				return err
+			}
+		}
+	case []any:
+		if len(v) == 0 {
+			b.WriteString(pad + "[]\n")
+			return nil
+		}
+		for _, item := range v {
+			if m, ok := item.(map[string]any); ok && len(m) > 0 {
+				// Start the mapping on the dash line, then indent the rest
+				// to line up with the first key.
+				var nested strings.Builder
+				if err := writeYAML(&nested, m, indent+2); err != nil {
+					return err
+				}
+				b.WriteString(pad + "- " + strings.TrimPrefix(nested.String(), pad+"  "))
+				continue
+			}
+			b.WriteString(pad + "-")
+			if err := writeYAMLValue(b, item, indent); err != nil {
+				return err
+			}
+		}
+	default:
+		s, err := yamlScalar(v)
+		if err != nil {
+			return err
+		}
+		b.WriteString(pad + s + "\n")
+	}
+	return nil
+}
+
+// writeYAMLValue writes the value that follows "key:" or "-".
+func writeYAMLValue(b *strings.Builder, v any, indent int) error {
+	switch c := v.(type) {
+	case map[string]any:
+		if len(c) == 0 {
+			b.WriteString(" {}\n")
+			return nil
+		}
+		b.WriteString("\n")
+		return writeYAML(b, c, indent+2)
+	case []any:
+		if len(c) == 0 {
+			b.WriteString(" []\n")
+			return nil
+		}
+		b.WriteString("\n")
+		return writeYAML(b, c, indent+2)
+	default:
+		s, err := yamlScalar(v)
+		if err != nil {
+			return err
+		}
+		b.WriteString(" " + s + "\n")
+		return nil
+	}
+}
+
+func yamlScalar(v any) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "null", nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case string:
+		return yamlString(v), nil
+	case json.Number:
+		return v.String(), nil
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return "", fmt.Errorf("yaml: unsupported number %v", v)
+		}
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	default:
+		return "", fmt.Errorf("yaml: unsupported value of type %T", v)
+	}
+}
+
+var (
+	plainString = regexp.MustCompile(`^[A-Za-z_/.][A-Za-z0-9_ ./@()+-]*$`)
+	numberLike  = regexp.MustCompile(`^[-+]?(\d[\d_]*)?(\.\d*)?([eE][-+]?\d+)?$`)
+)
+
+// yamlString quotes s unless it can be written as a plain scalar that
+// reads back as the same string. Strings such as ".5" look like numbers
+// to parseScalar, so they are quoted too.
+func yamlString(s string) string {
+	if plainString.MatchString(s) && !strings.HasSuffix(s, " ") && !isReservedWord(s) && !numberLike.MatchString(s) {
+		return s
+	}
+	quoted, _ := json.Marshal(s)
+	return string(quoted)
+}
+
+func isReservedWord(s string) bool {
+	switch strings.ToLower(s) {
+	case "true", "false", "yes", "no", "on", "off", "null", "~", ".inf", ".nan":
+		return true
+	}
+	return false
+}
+
+type yamlLine struct {
+	number  int
+	indent  int
+	content string
+}
+
+type yamlParser struct {
+	lines []yamlLine
+	pos   int
+}
+
+// scan splits the input into significant lines, dropping blank lines,
+// comments and document markers.
+func (p *yamlParser) scan(src string) error {
+	for i, raw := range strings.Split(src, "\n") {
+		raw = strings.TrimRight(raw, " \t\r")
+		content := strings.TrimLeft(raw, " ")
+		if strings.HasPrefix(content, "\t") {
+			return fmt.Errorf("yaml: line %d: tabs are not allowed for indentation", i+1)
+		}
+		content = stripComment(content)
+		if content == "" || content == "---" {
+			continue
+		}
+		p.lines = append(p.lines, yamlLine{number: i + 1, indent: len(raw) - len(strings.TrimLeft(raw, " ")), content: content})
+	}
+	return nil
+}
+
+func (p *yamlParser) parseBlock(indent int) (any, error) {
+	l := p.lines[p.pos]
+	if l.indent != indent {
+		return nil, fmt.Errorf("yaml: line %d: unexpected indentation", l.number)
+	}
+	if isSequenceItem(l.content) {
+		return p.parseSequence(indent)
+	}
+	if _, _, ok := splitKey(l.content); ok {
+		return p.parseMapping(indent)
+	}
+	p.pos++
+	return parseScalar(l.content, l.number)
+}
+
+func (p *yamlParser) parseSequence(indent int) ([]any, error) {
+	list := []any{}
+	for p.pos < len(p.lines) {
+		l := p.lines[p.pos]
+		if l.indent != indent || !isSequenceItem(l.content) {
+			break
+		}
+
+		rest := strings.TrimLeft(strings.TrimPrefix(l.content, "-"), " ")
+		switch {
+		case rest == "":
+			p.pos++
+			v, err := p.parseNested(indent)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+		case isSequenceItem(rest) || isMappingStart(rest):
+			// "- key: value" or "- - item": reinterpret the remainder as a
+			// block that starts at the column after the dash.
+			p.lines[p.pos] = yamlLine{number: l.number, indent: indent + len(l.content) - len(rest), content: rest}
+			v, err := p.parseBlock(p.lines[p.pos].indent)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+		default:
+			p.pos++
+			v, err := parseScalar(rest, l.number)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+		}
+	}
+	return list, nil
+}
+
+func (p *yamlParser) parseMapping(indent int) (map[string]any, error) {
+	m := map[string]any{}
+	for p.pos < len(p.lines) {
+		l := p.lines[p.pos]
+		if l.indent != indent || isSequenceItem(l.content) {
+			break
+		}
+
+		key, value, ok := splitKey(l.content)
+		if !ok {
+			return nil, fmt.Errorf("yaml: line %d: expected \"key: value\"", l.number)
+		}
+		if _, dup := m[key]; dup {
+			return nil, fmt.Errorf("yaml: line %d: duplicate key %q", l.number, key)
+		}
+		p.pos++
+
+		if value != "" {
+			v, err := parseScalar(value, l.number)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = v
+			continue
+		}
+
+		// A sequence may sit at the same indentation as its key.
+		if p.pos < len(p.lines) && p.lines[p.pos].indent == indent && isSequenceItem(p.lines[p.pos].content) {
+			v, err := p.parseSequence(indent)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = v
+			continue
+		}
+
+		v, err := p.parseNested(indent)
+		if err != nil {
+			return nil, err
+		}
+		m[key] = v
+	}
+	return m, nil
+}
+
+// parseNested parses the block that follows a bare "key:" or "-", which
+// must be indented further than parent. A missing block means null.
+func (p *yamlParser) parseNested(parent int) (any, error) {
+	if p.pos >= len(p.lines) || p.lines[p.pos].indent <= parent {
+		return nil, nil
+	}
+	return p.parseBlock(p.lines[p.pos].indent)
+}
+
+func isSequenceItem(content string) bool {
+	return content == "-" || strings.HasPrefix(content, "- ")
+}
+
+func isMappingStart(content string) bool {
+	_, _, ok := splitKey(content)
+	return ok
+}
+
+// splitKey splits "key: value" or "key:". Quoted keys are unquoted.
+func splitKey(content string) (key, value string, ok bool) {
+	if strings.HasPrefix(content, `"`) || strings.HasPrefix(content, "'") {
+		end := closingQuote(content)
+		if end < 0 || end+1 >= len(content) || content[end+1] != ':' {
+			return "", "", false
+		}
+		k, err := parseScalar(content[:end+1], 0)
+		if err != nil {
+			return "", "", false
+		}
+		rest := content[end+2:]
+		if rest != "" && rest[0] != ' ' {
+			return "", "", false
+		}
+		return k.(string), strings.TrimSpace(rest), true
+	}
+
+	if strings.HasPrefix(content, "[") || strings.HasPrefix(content, "{") {
+		return "", "", false
+	}
+	if strings.HasSuffix(content, ":") {
+		return content[:len(content)-1], "", true
+	}
+	i := strings.Index(content, ": ")
+	if i <= 0 {
+		return "", "", false
+	}
+	return content[:i], strings.TrimSpace(content[i+2:]), true
+}
+
+func parseScalar(s string, line int) (any, error) {
+	switch {
+	case strings.HasPrefix(s, `"`):
+		if closingQuote(s) != len(s)-1 {
+			return nil, fmt.Errorf("yaml: line %d: unterminated string %s", line, s)
+		}
+		var out string
+		if err := json.Unmarshal([]byte(s), &out); err != nil {
+			return nil, fmt.Errorf("yaml: line %d: invalid string %s", line, s)
+		}
+		return out, nil
+	case strings.HasPrefix(s, "'"):
+		if closingQuote(s) != len(s)-1 {
+			return nil, fmt.Errorf("yaml: line %d: unterminated string %s", line, s)
+		}
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'"), nil
+	case s == "[]":
+		return []any{}, nil
+	case s == "{}":
+		return map[string]any{}, nil
+	case strings.HasPrefix(s, "[") || strings.HasPrefix(s, "{"):
+		return nil, fmt.Errorf("yaml: line %d: flow collections are not supported", line)
+	case s == "null" || s == "~":
+		return nil, nil
+	case s == "true" || s == "false":
+		return s == "true", nil
+	case numberLike.MatchString(s) && strings.ContainsAny(s, "0123456789"):
+		if n, ok := parseNumber(s); ok {
+			return n, nil
+		}
+		return s, nil
+	default:
+		return s, nil
+	}
+}
+
+// parseNumber converts a YAML number into a json.Number in canonical
+// JSON form, so "+1_000" becomes "1000" and ".5" becomes "0.5". An
+// integer keeps all its digits, however many; only a number with a
+// fraction or exponent goes through float64.
+func parseNumber(s string) (json.Number, bool) {
+	s = strings.ReplaceAll(s, "_", "")
+	if i, ok := new(big.Int).SetString(s, 10); ok {
+		return json.Number(i.String()), true
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsInf(f, 0) {
+		return "", false
+	}
+	return json.Number(strconv.FormatFloat(f, 'g', -1, 64)), true
+}
+
+// closingQuote returns the index of the quote that closes the string
+// starting at s[0], or -1.
+func closingQuote(s string) int {
+	quote := s[0]
+	for i := 1; i < len(s); i++ {
+		switch {
+		case quote == '"' && s[i] == '\\':
+			i++
+		case s[i] == quote && quote == '\'' && i+1 < len(s) && s[i+1] == '\'':
+			i++
+		case s[i] == quote:
+			return i
+		}
+	}
+	return -1
+}
+
+// stripComment removes a trailing "# comment" that isn't inside quotes.
+func stripComment(content string) string {
+	var quote byte
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case (c == '"' || c == '\'') && (i == 0 || content[i-1] == ' '):
+			quote = c
+		case c == '#' && (i == 0 || content[i-1] == ' '):
+			return strings.TrimRight(content[:i], " ")
+		}
+	}
+	return content
+}
@@ -0,0 +1,187 @@
+package codec
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// trickyStrings read back as something else, or not at all, if written
+// as plain scalars.
+var trickyStrings = []string{
+	"", " ", "plain", "two words", "trailing ", " leading",
+	".5", "5", "-1", "+1", "1e3", "1_000", ".", "..", "0x10",
+	"true", "False", "yes", "No", "on", "null", "Null", "~", ".inf", ".nan",
+	"a: b", "a:b", "key:", "# not a comment", "x #y", "x# y",
+	"- item", "-", "[]", "{}", "[a, b]", "{a: b}",
+	"'single'", `"double"`, `back\slash`, "it's",
+	"line\nbreak", "tab\there", "ünïcode", "a/b.c@d (e+f)",
+}
+
+func TestYAMLStringRoundTrip(t *testing.T) {
+	for _, s := range trickyStrings {
+		tree := map[string]any{"key": s, "list": []any{s}}
+		if s != "" {
+			tree[s] = "as a key"
+		}
+		data, err := EncodeYAML(tree)
+		if err != nil {
+			t.Fatalf("EncodeYAML(%q): %v", s, err)
+		}
+		got, err := DecodeYAML(data)
+		if err != nil {
+			t.Errorf("DecodeYAML of %q: %v\n%s", s, err, data)
+			continue
+		}
+		if !reflect.DeepEqual(got, tree) {
+			t.Errorf("%q: round trip gave %#v\n%s", s, got, data)
+		}
+	}
+}
+
+func TestYAMLTreeRoundTrip(t *testing.T) {
+	tree := map[string]any{
+		"name":    "fleet",
+		"count":   json.Number("3"),
+		"ratio":   json.Number("0.25"),
+		"big":     json.Number("-1.5e+300"),
+		"enabled": true,
+		"missing": nil,
+		"empty":   map[string]any{},
+		"none":    []any{},
+		"items": []any{
+			map[string]any{"type": "dog", "name": "Rex", "tags": []any{"good", "loud"}},
+			map[string]any{"type": "cat", "owner": map[string]any{"name": "Ann", "age": json.Number("30")}},
+			[]any{json.Number("1"), []any{}, map[string]any{}},
+			nil,
+			"last",
+		},
+	}
+	data, err := EncodeYAML(tree)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := DecodeYAML(data)
+	if err != nil {
+		t.Fatalf("%v\n%s", err, data)
+	}
+	if !reflect.DeepEqual(got, tree) {
+		t.Errorf("round trip gave %#v\n%s", got, data)
+	}
+	again, err := EncodeYAML(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(again) != string(data) {
+		t.Errorf("encoding is not stable:\n%s\nthen\n%s", data, again)
+	}
+}
+
+func TestDecodeYAML(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want any
+	}{
+		{"empty", "", nil},
+		{"only comments", "# nothing\n---\n", nil},
+		{"scalar", "hello world", "hello world"},
+		{"numbers", "a: +1_000\nb: .5\nc: -2e3\nd: 1.", map[string]any{
+			"a": json.Number("1000"), "b": json.Number("0.5"), "c": json.Number("-2000"), "d": json.Number("1"),
+		}},
+		{"integers past int64", "a: 9223372036854775808\nb: -123_456_789_012_345_678_901_234\nc: +0099999999999999999999", map[string]any{
+			"a": json.Number("9223372036854775808"), "b": json.Number("-123456789012345678901234"), "c": json.Number("99999999999999999999"),
+		}},
+		{"comments", "a: 1 # one\n# whole line\nb: 'x # y' # z\nc: x#y", map[string]any{
+			"a": json.Number("1"), "b": "x # y", "c": "x#y",
+		}},
+		{"quoted keys", `"a b": 1` + "\n'c:d': 2", map[string]any{"a b": json.Number("1"), "c:d": json.Number("2")}},
+		{"single quote escape", "a: 'it''s'", map[string]any{"a": "it's"}},
+		{"double quote escapes", `a: "tab\there \u00e9"`, map[string]any{"a": "tab\there é"}},
+		{"null forms", "a:\nb: null\nc: ~", map[string]any{"a": nil, "b": nil, "c": nil}},
+		{"sequence at key indentation", "list:\n- a\n- b\nnext: 1", map[string]any{
+			"list": []any{"a", "b"}, "next": json.Number("1"),
+		}},
+		{"mapping in sequence", "- a: 1\n  b: 2\n- c: 3", []any{
+			map[string]any{"a": json.Number("1"), "b": json.Number("2")},
+			map[string]any{"c": json.Number("3")},
+		}},
+		{"nested sequences", "- - a\n  - b\n- -\n    - c", []any{
+			[]any{"a", "b"},
+			[]any{[]any{"c"}},
+		}},
+		{"dash then block", "-\n  a: 1", []any{map[string]any{"a": json.Number("1")}}},
+		{"empty flow collections", "a: []\nb: {}", map[string]any{"a": []any{}, "b": map[string]any{}}},
+		{"not a number", "a: 1.2.3\nb: 1e", map[string]any{"a": "1.2.3", "b": "1e"}},
+		{"windows line endings", "a: 1\r\nb: 2\r\n", map[string]any{"a": json.Number("1"), "b": json.Number("2")}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := DecodeYAML([]byte(tc.in))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %#v, want %#v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDecodeYAMLErrors(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"tab indentation", "a:\n\tb: 1", "line 2: tabs"},
+		{"duplicate key", "a: 1\nb: 2\na: 3", `line 3: duplicate key "a"`},
+		{"unterminated double", `a: "open`, "line 1: unterminated string"},
+		{"unterminated single", "a: 'open", "line 1: unterminated string"},
+		{"flow sequence", "a: [1, 2]", "line 1: flow collections"},
+		{"flow mapping", "a: {b: 1}", "line 1: flow collections"},
+		{"dedent past start", "  a: 1\nb: 2", "line 2: unexpected indentation"},
+		{"scalar in mapping", "a: 1\njust text", `line 2: expected "key: value"`},
+		{"bad escape", `a: "\q"`, "line 1: invalid string"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := DecodeYAML([]byte(tc.in))
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("got error %v, want one containing %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestEncodeYAMLUnsupported(t *testing.T) {
+	for _, v := range []any{struct{}{}, []string{"a"}} {
+		if _, err := EncodeYAML(v); err == nil {
+			t.Errorf("EncodeYAML(%#v): expected an error", v)
+		}
+	}
+}
+
+type note struct {
+	Text string `json:"text"`
+}
+
+func TestMarshalYAMLRoundTrip(t *testing.T) {
+	r := NewRegistry()
+	r.MustRegister("note", note{})
+
+	var notes []note
+	for _, s := range trickyStrings {
+		notes = append(notes, note{Text: s})
+	}
+	data, err := Marshal(r, YAML, notes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := Unmarshal[note](r, YAML, data)
+	if err != nil {
+		t.Fatalf("%v\n%s", err, data)
+	}
+	if !reflect.DeepEqual(got, notes) {
+		t.Errorf("round trip gave %q\n%s", got, data)
+	}
+}
@@ -0,0 +1,38 @@
+package collections
+
+import "cmp"
+
+// MaxBy returns the element with the largest key, and false if s is
+// empty, rather than a 0 that could be a real value the way findMax does.
+// Ties go to the first such element.
+func MaxBy[T any, K cmp.Ordered](s []T, key func(T) K) (T, bool) {
+	return best(s, key, func(a, b K) bool { return a > b })
+}
+
+// MinBy returns the element with the smallest key, and false if s is
+// empty. Ties go to the first such element.
+func MinBy[T any, K cmp.Ordered](s []T, key func(T) K) (T, bool) {
+	return best(s, key, func(a, b K) bool { return a < b })
+}
+
+func best[T any, K cmp.Ordered](s []T, key func(T) K, better func(a, b K) bool) (T, bool) {
+	if len(s) == 0 {
+		var zero T
+		return zero, false
+	}
+	found, foundKey := s[0], key(s[0])
+	for _, v := range s[1:] {
+		if k := key(v); better(k, foundKey) {
+			found, foundKey = v, k
+		}
+	}
+	return found, true
+}
+
+// Identity is a key function for MaxBy and MinBy over values that are
+// their own key:
+//
+//	highest, ok := collections.MaxBy(scores, collections.Identity[int])
+func Identity[T any](v T) T {
+	return v
+}
@@ -0,0 +1,56 @@
+package collections
+
+import (
+	"slices"
+	"testing"
+)
+
+type student struct {
+	Name  string
+	Score int
+}
+
+func TestMaxByMinBy(t *testing.T) {
+	students := []student{{"Ann", 90}, {"Bob", 75}, {"Cy", 90}, {"Di", 75}}
+	score := func(s student) int { return s.Score }
+
+	if got, ok := MaxBy(students, score); !ok || got.Name != "Ann" {
+		t.Errorf("MaxBy = %v, %v; want Ann, the first of the tied highest", got, ok)
+	}
+	if got, ok := MinBy(students, score); !ok || got.Name != "Bob" {
+		t.Errorf("MinBy = %v, %v; want Bob, the first of the tied lowest", got, ok)
+	}
+
+	// An empty slice reports false instead of a zero that looks like a
+	// score, which is what findMax got wrong.
+	if got, ok := MaxBy([]int{}, Identity[int]); ok || got != 0 {
+		t.Errorf("MaxBy(empty) = %v, %v; want 0, false", got, ok)
+	}
+	if got, ok := MaxBy([]int{-5, -2, -9}, Identity[int]); !ok || got != -2 {
+		t.Errorf("MaxBy(negatives) = %v, %v; want -2, true", got, ok)
+	}
+}
+
+func TestZipUnzip(t *testing.T) {
+	names := []string{"Ann", "Bob", "Cy"}
+	scores := []int{90, 75}
+
+	pairs := Zip(names, scores)
+	want := []Pair[string, int]{{"Ann", 90}, {"Bob", 75}}
+	if !slices.Equal(pairs, want) {
+		t.Errorf("Zip = %v, want %v; extra elements are dropped", pairs, want)
+	}
+
+	gotNames, gotScores := Unzip(pairs)
+	if !slices.Equal(gotNames, names[:2]) || !slices.Equal(gotScores, scores) {
+		t.Errorf("Unzip = %v, %v", gotNames, gotScores)
+	}
+	gotNames[0] = "Zed"
+	if names[0] != "Ann" {
+		t.Error("Unzip result aliases the zipped input")
+	}
+
+	if empty := Zip(names, []int(nil)); empty == nil || len(empty) != 0 {
+		t.Errorf("Zip with nil gave %#v, want an empty, non-nil slice", empty)
+	}
+}
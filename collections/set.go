@@ -0,0 +1,27 @@
+package collections
+
+// Distinct returns a new slice with the first occurrence of every value in
+// s, in order. The result's capacity equals its length.
+func Distinct[T comparable](s []T) []T {
+	return Filter(s, seen[T]())
+}
+
+// DistinctInPlace removes repeated values from s, keeping first
+// occurrences in order, and returns the shortened slice. Like
+// FilterInPlace it reuses s's backing array, keeps its capacity and zeroes
+// the leftover tail.
+func DistinctInPlace[T comparable](s []T) []T {
+	return FilterInPlace(s, seen[T]())
+}
+
+// seen returns a filter that keeps each value the first time it is seen.
+func seen[T comparable]() func(T) bool {
+	set := make(map[T]struct{})
+	return func(v T) bool {
+		if _, dup := set[v]; dup {
+			return false
+		}
+		set[v] = struct{}{}
+		return true
+	}
+}
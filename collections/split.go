@@ -0,0 +1,107 @@
+package collections
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Chunk splits s into consecutive pieces of size elements; the last piece
+// may be shorter. The pieces are subslices of s, so writing to an element
+// changes s too. Each piece's capacity is capped at its length, so
+// appending to one reallocates instead of overwriting the start of the
+// next. Chunk panics if size is not positive.
+func Chunk[T any](s []T, size int) [][]T {
+	if size <= 0 {
+		panic(fmt.Sprintf("collections: chunk size %d must be positive", size))
+	}
+	chunks := make([][]T, 0, (len(s)+size-1)/size)
+	for i := 0; i < len(s); i += size {
+		end := min(i+size, len(s))
+		chunks = append(chunks, s[i:end:end])
+	}
+	return chunks
+}
+
+// ChunkCopy is like Chunk but copies the elements into a single new
+// backing array, so the pieces don't alias s. Pieces are still capped at
+// their length.
+func ChunkCopy[T any](s []T, size int) [][]T {
+	return Chunk(append([]T(nil), s...), size)
+}
+
+// Window returns every run of size consecutive elements of s, sliding one
+// element at a time: [1 2 3 4] with size 2 gives [1 2] [2 3] [3 4]. s
+// shorter than size gives no windows. Windows are capped subslices of s
+// and overlap, so writing to an element is visible in s and in every
+// window containing it. Window panics if size is not positive.
+func Window[T any](s []T, size int) [][]T {
+	if size <= 0 {
+		panic(fmt.Sprintf("collections: window size %d must be positive", size))
+	}
+	if len(s) < size {
+		return [][]T{}
+	}
+	windows := make([][]T, 0, len(s)-size+1)
+	for i := 0; i+size <= len(s); i++ {
+		windows = append(windows, s[i:i+size:i+size])
+	}
+	return windows
+}
+
+// WindowCopy is like Window but gives every window its own backing array,
+// so they can be modified independently.
+func WindowCopy[T any](s []T, size int) [][]T {
+	windows := Window(s, size)
+	for i, w := range windows {
+		windows[i] = append([]T(nil), w...)
+	}
+	return windows
+}
+
+// Partition splits s into two new slices, the elements for which pred
+// returns true and the rest, both in their original order. Like Filter,
+// it calls pred once per element in order. Neither result aliases s, and
+// each has capacity equal to its length.
+func Partition[T any](s []T, pred func(T) bool) (matched, rest []T) {
+	matched, rest = []T{}, []T{}
+	for _, v := range s {
+		if pred(v) {
+			matched = append(matched, v)
+		} else {
+			rest = append(rest, v)
+		}
+	}
+	return slices.Clip(matched), slices.Clip(rest)
+}
+
+// PartitionInPlace reorders s so that the elements for which pred returns
+// true come first, and returns both halves as subslices of s. It doesn't
+// allocate, but doesn't keep the original order either. The first half is
+// capped at its length, so appending to it can't overwrite the second.
+func PartitionInPlace[T any](s []T, pred func(T) bool) (matched, rest []T) {
+	i, j := 0, len(s)-1
+	for {
+		for i <= j && pred(s[i]) {
+			i++
+		}
+		for i <= j && !pred(s[j]) {
+			j--
+		}
+		if i >= j {
+			break
+		}
+		s[i], s[j] = s[j], s[i]
+	}
+	return s[:i:i], s[i:]
+}
+
+// GroupBy buckets the elements of s by key, keeping their order within
+// each group. The groups are new slices that don't alias s.
+func GroupBy[T any, K comparable](s []T, key func(T) K) map[K][]T {
+	groups := make(map[K][]T)
+	for _, v := range s {
+		k := key(v)
+		groups[k] = append(groups[k], v)
+	}
+	return groups
+}
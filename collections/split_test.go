@@ -0,0 +1,189 @@
+package collections
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestChunk(t *testing.T) {
+	tests := []struct {
+		n, size int
+		want    [][]int
+	}{
+		{0, 2, [][]int{}},
+		{1, 2, [][]int{{0}}},
+		{4, 2, [][]int{{0, 1}, {2, 3}}},
+		{5, 2, [][]int{{0, 1}, {2, 3}, {4}}},
+		{3, 5, [][]int{{0, 1, 2}}},
+	}
+	for _, tc := range tests {
+		s := make([]int, tc.n)
+		for i := range s {
+			s[i] = i
+		}
+		got := Chunk(s, tc.size)
+		if !slices.EqualFunc(got, tc.want, slices.Equal) {
+			t.Errorf("Chunk(%v, %d) = %v, want %v", s, tc.size, got, tc.want)
+		}
+		for i, c := range got {
+			if cap(c) != len(c) {
+				t.Errorf("chunk %d of %v has cap %d, want %d", i, s, cap(c), len(c))
+			}
+		}
+	}
+}
+
+func TestChunkAliasing(t *testing.T) {
+	s := []int{1, 2, 3, 4, 5}
+	chunks := Chunk(s, 2)
+
+	// Chunks are views of s.
+	chunks[1][0] = 30
+	if s[2] != 30 {
+		t.Errorf("writing to a chunk didn't change s: %v", s)
+	}
+
+	// Their capacity is capped, so appending to one reallocates rather
+	// than overwriting the start of the next.
+	chunks[0] = append(chunks[0], 99)
+	if s[2] != 30 || chunks[1][0] != 30 {
+		t.Errorf("append overwrote the next chunk: s = %v", s)
+	}
+
+	copied := ChunkCopy(s, 2)
+	copied[0][0] = 100
+	if s[0] != 1 {
+		t.Errorf("ChunkCopy aliases s: %v", s)
+	}
+	copied[0] = append(copied[0], 99)
+	if copied[1][0] != 30 {
+		t.Errorf("append to a copied chunk overwrote the next: %v", copied)
+	}
+}
+
+func TestWindow(t *testing.T) {
+	s := []int{1, 2, 3, 4}
+	got := Window(s, 2)
+	want := [][]int{{1, 2}, {2, 3}, {3, 4}}
+	if !slices.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	for i, w := range got {
+		if cap(w) != len(w) {
+			t.Errorf("window %d has cap %d, want %d", i, cap(w), len(w))
+		}
+	}
+
+	// Windows overlap: one write shows in s and both windows holding it.
+	got[0][1] = 20
+	if s[1] != 20 || got[1][0] != 20 {
+		t.Errorf("s = %v, windows = %v", s, got)
+	}
+
+	if short := Window(s, 5); short == nil || len(short) != 0 {
+		t.Errorf("window longer than s gave %#v, want an empty, non-nil slice", short)
+	}
+}
+
+func TestWindowCopy(t *testing.T) {
+	s := []int{1, 2, 3}
+	windows := WindowCopy(s, 2)
+	windows[0][1] = 20
+	if s[1] != 2 || windows[1][0] != 2 {
+		t.Errorf("copied windows share memory: s = %v, windows = %v", s, windows)
+	}
+}
+
+func TestPanicsOnBadSize(t *testing.T) {
+	for name, f := range map[string]func(){
+		"Chunk 0":   func() { Chunk([]int{1}, 0) },
+		"Chunk -1":  func() { Chunk([]int{1}, -1) },
+		"Window 0":  func() { Window([]int{1}, 0) },
+		"Window -1": func() { Window([]int{1}, -1) },
+	} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil || !strings.Contains(r.(string), "must be positive") {
+					t.Errorf("%s: recovered %v, want a size panic", name, r)
+				}
+			}()
+			f()
+		}()
+	}
+}
+
+func isEven(v int) bool { return v%2 == 0 }
+
+func TestPartition(t *testing.T) {
+	s := []int{1, 2, 3, 4, 5, 6}
+	even, odd := Partition(s, isEven)
+	if !slices.Equal(even, []int{2, 4, 6}) || !slices.Equal(odd, []int{1, 3, 5}) {
+		t.Errorf("got %v and %v", even, odd)
+	}
+	if cap(even) != len(even) || cap(odd) != len(odd) {
+		t.Errorf("caps %d and %d, want lengths", cap(even), cap(odd))
+	}
+	even[0] = 20
+	if s[1] != 2 {
+		t.Errorf("Partition result aliases s: %v", s)
+	}
+
+	var seen []int
+	Partition(s, func(v int) bool {
+		seen = append(seen, v)
+		return isEven(v)
+	})
+	if !slices.Equal(seen, s) {
+		t.Errorf("pred saw %v, want each of %v once in order", seen, s)
+	}
+}
+
+func TestPartitionInPlace(t *testing.T) {
+	s := []int{1, 2, 3, 4, 5, 6}
+	even, odd := PartitionInPlace(s, isEven)
+
+	got := [][]int{slices.Sorted(slices.Values(even)), slices.Sorted(slices.Values(odd))}
+	if !slices.EqualFunc(got, [][]int{{2, 4, 6}, {1, 3, 5}}, slices.Equal) {
+		t.Errorf("got %v and %v", even, odd)
+	}
+
+	// Both halves are views of s, one after the other.
+	if &even[0] != &s[0] || &odd[0] != &s[len(even)] {
+		t.Error("halves are not subslices of s")
+	}
+
+	// The first half is capped, so appending to it leaves the second alone.
+	first := odd[0]
+	_ = append(even, 8)
+	if odd[0] != first {
+		t.Errorf("append to the first half overwrote the second: %v", odd)
+	}
+
+	all, none := PartitionInPlace([]int{2, 4}, isEven)
+	if len(all) != 2 || len(none) != 0 {
+		t.Errorf("all matching gave %v and %v", all, none)
+	}
+	none, all = PartitionInPlace([]int{1, 3}, isEven)
+	if len(none) != 0 || len(all) != 2 {
+		t.Errorf("none matching gave %v and %v", none, all)
+	}
+}
+
+func TestGroupBy(t *testing.T) {
+	words := []string{"go", "is", "fun", "and", "fast"}
+	groups := GroupBy(words, func(w string) int { return len(w) })
+	want := map[int][]string{2: {"go", "is"}, 3: {"fun", "and"}, 4: {"fast"}}
+	if len(groups) != len(want) {
+		t.Fatalf("got %v, want %v", groups, want)
+	}
+	for k, w := range want {
+		if !slices.Equal(groups[k], w) {
+			t.Errorf("group %d = %v, want %v", k, groups[k], w)
+		}
+	}
+	groups[2][0] = "GO"
+	if words[0] != "go" {
+		t.Errorf("groups alias the input: %v", words)
+	}
+}
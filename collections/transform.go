@@ -0,0 +1,99 @@
+// Package collections is a generic version of the chapter 5 slice helpers
+// (doubleSlice, doubleSliceReturn, findMax, filterHighScores and
+// combineSlices), which only work on []int.
+//
+// Most operations come in two forms, following the doubleSlice /
+// doubleSliceReturn split:
+//
+//   - Copying functions (Map, Filter, Partition, ...) return results with their
+//     own backing arrays. Changing the result never changes the input, and
+//     the other way round.
+//   - InPlace functions (MapInPlace, FilterInPlace, ...) reuse the input's
+//     backing array. They don't allocate a result, but the input slice is
+//     overwritten and the result shares memory with it.
+//   - Chunk and Window return views: subslices of the input, with
+//     ChunkCopy and WindowCopy as their copying forms.
+//
+// Each function documents what its result aliases and what capacity it
+// has, since appending to a slice that shares a backing array can
+// overwrite elements another slice still sees.
+package collections
+
+import "slices"
+
+// Map returns a new slice holding f applied to every element of s. The
+// result has length and capacity len(s); it is nil only if s is nil.
+func Map[T, U any](s []T, f func(T) U) []U {
+	if s == nil {
+		return nil
+	}
+	result := make([]U, len(s))
+	for i, v := range s {
+		result[i] = f(v)
+	}
+	return result
+}
+
+// MapInPlace replaces every element of s with f applied to it. Every slice
+// sharing s's backing array sees the change.
+func MapInPlace[T any](s []T, f func(T) T) {
+	for i, v := range s {
+		s[i] = f(v)
+	}
+}
+
+// Filter returns a new slice of the elements of s for which keep returns
+// true, in order. keep is called exactly once per element, in order, so it
+// may carry state. The result's capacity equals its length, so appending
+// to it always reallocates.
+func Filter[T any](s []T, keep func(T) bool) []T {
+	result := []T{}
+	for _, v := range s {
+		if keep(v) {
+			result = append(result, v)
+		}
+	}
+	return slices.Clip(result)
+}
+
+// FilterInPlace moves the kept elements to the front of s, in order, and
+// returns s[:n]. Like Filter, it calls keep once per element in order.
+// The result shares s's backing array and keeps its full capacity. The
+// elements between n and len(s) are set to the zero value so they don't
+// keep pointers alive; s itself should not be used afterwards.
+func FilterInPlace[T any](s []T, keep func(T) bool) []T {
+	n := 0
+	for _, v := range s {
+		if keep(v) {
+			s[n] = v
+			n++
+		}
+	}
+	clear(s[n:])
+	return s[:n]
+}
+
+// Reduce folds s into a single value, starting from initial and applying
+// f to the running value and each element in order.
+func Reduce[T, A any](s []T, initial A, f func(A, T) A) A {
+	acc := initial
+	for _, v := range s {
+		acc = f(acc, v)
+	}
+	return acc
+}
+
+// Flatten concatenates the slices into one new slice with capacity equal
+// to the total length. It is the generalization of chapter 5's
+// combineSlices.
+func Flatten[T any](parts [][]T) []T {
+	total := 0
+	for _, s := range parts {
+		total += len(s)
+	}
+	result := make([]T, 0, total)
+	for _, s := range parts {
+		result = append(result, s...)
+	}
+	return result
+}
@@ -0,0 +1,167 @@
+package collections
+
+import (
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func TestMap(t *testing.T) {
+	scores := []int{1, 2, 3}
+	doubled := Map(scores, func(v int) int { return v * 2 })
+	if !slices.Equal(doubled, []int{2, 4, 6}) {
+		t.Errorf("got %v", doubled)
+	}
+	if len(doubled) != cap(doubled) {
+		t.Errorf("len %d, cap %d; want them equal", len(doubled), cap(doubled))
+	}
+
+	// The result has its own backing array, like doubleSliceReturn.
+	doubled[0] = 100
+	if scores[0] != 1 {
+		t.Errorf("writing to the result changed the input: %v", scores)
+	}
+
+	if got := Map(nil, strconv.Itoa); got != nil {
+		t.Errorf("Map(nil) = %#v, want nil", got)
+	}
+	if got := Map([]int{}, strconv.Itoa); got == nil || len(got) != 0 {
+		t.Errorf("Map([]int{}) = %#v, want an empty, non-nil slice", got)
+	}
+}
+
+func TestMapInPlace(t *testing.T) {
+	backing := []int{1, 2, 3, 4}
+	view := backing[1:3]
+	MapInPlace(view, func(v int) int { return v * 10 })
+
+	// Like doubleSlice, the change shows through every slice sharing the
+	// backing array, but only inside the range that was passed in.
+	if !slices.Equal(backing, []int{1, 20, 30, 4}) {
+		t.Errorf("backing = %v", backing)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	scores := []int{85, 92, 78, 96, 88}
+	high := Filter(scores, func(v int) bool { return v > 85 })
+	if !slices.Equal(high, []int{92, 96, 88}) {
+		t.Errorf("got %v", high)
+	}
+	if cap(high) != len(high) {
+		t.Errorf("cap %d, want %d", cap(high), len(high))
+	}
+
+	// A full result reallocates on append, so it never writes into
+	// memory another slice can see.
+	grown := append(high, 99)
+	grown[0] = 0
+	if high[0] != 92 {
+		t.Errorf("appending to the result changed it: %v", high)
+	}
+
+	none := Filter(scores, func(int) bool { return false })
+	if none == nil || len(none) != 0 {
+		t.Errorf("no matches gave %#v, want an empty, non-nil slice", none)
+	}
+}
+
+func TestFilterStateful(t *testing.T) {
+	var calls []int
+	everyOther := Filter([]int{1, 2, 3, 4, 5}, func(v int) bool {
+		calls = append(calls, v)
+		return len(calls)%2 == 1
+	})
+	if !slices.Equal(calls, []int{1, 2, 3, 4, 5}) {
+		t.Errorf("keep called with %v, want each element once, in order", calls)
+	}
+	if !slices.Equal(everyOther, []int{1, 3, 5}) {
+		t.Errorf("got %v", everyOther)
+	}
+}
+
+func TestFilterInPlace(t *testing.T) {
+	scores := []int{85, 92, 78, 96, 88}
+	high := FilterInPlace(scores, func(v int) bool { return v > 85 })
+	if !slices.Equal(high, []int{92, 96, 88}) {
+		t.Errorf("got %v", high)
+	}
+
+	// The result is a prefix of the input with the input's capacity, and
+	// the tail left behind is zeroed.
+	if &high[0] != &scores[0] {
+		t.Error("result doesn't share the input's backing array")
+	}
+	if cap(high) != cap(scores) {
+		t.Errorf("cap %d, want %d", cap(high), cap(scores))
+	}
+	if !slices.Equal(scores, []int{92, 96, 88, 0, 0}) {
+		t.Errorf("input afterwards = %v", scores)
+	}
+
+	// So appending to the result writes into the old input.
+	_ = append(high, 70)
+	if scores[3] != 70 {
+		t.Errorf("append didn't reuse the input's array: %v", scores)
+	}
+}
+
+func TestFilterInPlaceClearsPointers(t *testing.T) {
+	a, b := new(int), new(int)
+	s := []*int{a, nil, b}
+	kept := FilterInPlace(s, func(p *int) bool { return p != nil })
+	if len(kept) != 2 || s[2] != nil {
+		t.Errorf("got %v, input afterwards %v; the tail should be nil", kept, s)
+	}
+}
+
+func TestReduce(t *testing.T) {
+	sum := Reduce([]int{1, 2, 3, 4}, 0, func(acc, v int) int { return acc + v })
+	if sum != 10 {
+		t.Errorf("sum = %d", sum)
+	}
+	joined := Reduce([]int{1, 2, 3}, "", func(acc string, v int) string { return acc + strconv.Itoa(v) })
+	if joined != "123" {
+		t.Errorf("joined = %q", joined)
+	}
+	if got := Reduce(nil, 7, func(acc, v int) int { return acc + v }); got != 7 {
+		t.Errorf("empty input gave %d, want the initial value", got)
+	}
+}
+
+func TestFlatten(t *testing.T) {
+	first, second := []int{1, 2}, []int{3, 4, 5}
+	all := Flatten([][]int{first, nil, second, {}})
+	if !slices.Equal(all, []int{1, 2, 3, 4, 5}) {
+		t.Errorf("got %v", all)
+	}
+	if cap(all) != len(all) {
+		t.Errorf("cap %d, want %d", cap(all), len(all))
+	}
+	all[0] = 100
+	if first[0] != 1 {
+		t.Error("the result aliases its first part")
+	}
+}
+
+func TestDistinct(t *testing.T) {
+	words := []string{"go", "is", "go", "fun", "is"}
+	got := Distinct(words)
+	if !slices.Equal(got, []string{"go", "is", "fun"}) {
+		t.Errorf("got %v", got)
+	}
+	if cap(got) != len(got) {
+		t.Errorf("cap %d, want %d", cap(got), len(got))
+	}
+	if !slices.Equal(words, []string{"go", "is", "go", "fun", "is"}) {
+		t.Errorf("Distinct changed its input: %v", words)
+	}
+
+	inPlace := DistinctInPlace(words)
+	if !slices.Equal(inPlace, []string{"go", "is", "fun"}) {
+		t.Errorf("in place got %v", inPlace)
+	}
+	if cap(inPlace) != 5 || !slices.Equal(words, []string{"go", "is", "fun", "", ""}) {
+		t.Errorf("cap %d, input afterwards %q", cap(inPlace), words)
+	}
+}
@@ -0,0 +1,28 @@
+package collections
+
+// Pair holds one element from each of two zipped slices.
+type Pair[A, B any] struct {
+	First  A
+	Second B
+}
+
+// Zip pairs up the elements of a and b by index. The result is as long as
+// the shorter input; extra elements of the longer one are dropped.
+func Zip[A, B any](a []A, b []B) []Pair[A, B] {
+	n := min(len(a), len(b))
+	pairs := make([]Pair[A, B], n)
+	for i := range n {
+		pairs[i] = Pair[A, B]{First: a[i], Second: b[i]}
+	}
+	return pairs
+}
+
+// Unzip splits pairs back into two new slices.
+func Unzip[A, B any](pairs []Pair[A, B]) ([]A, []B) {
+	a := make([]A, len(pairs))
+	b := make([]B, len(pairs))
+	for i, p := range pairs {
+		a[i], b[i] = p.First, p.Second
+	}
+	return a, b
+}
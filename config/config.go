@@ -0,0 +1,242 @@
+// Package config loads a configuration struct from layered sources and
+// shares it safely between goroutines. It replaces chapter 9's GetConfig,
+// whose lazy nil check races when two goroutines call it at once and whose
+// SetTheme changes a struct other goroutines may be reading.
+//
+// A Loader builds a value in four layers, each overriding the last:
+//
+//  1. the Defaults value
+//  2. a JSON, YAML or TOML file
+//  3. environment variables, such as APP_THEME
+//  4. command-line flags that were set explicitly, such as -theme
+//
+// The result is validated, then kept in a Store. The Store hands out
+// immutable snapshots and replaces them atomically, so a reader sees
+// either the old configuration or the new one, never a mix. Subscribers
+// are told which settings changed, and a Watcher reloads the file when
+// it is edited.
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+
+	"go-practice/codec"
+	"go-practice/errs"
+)
+
+// Validator is implemented by configuration types that check themselves.
+// Validate should return *errs.ValidationError values, joined with
+// errors.Join when there are several.
+type Validator interface {
+	Validate() error
+}
+
+// Loader describes where the settings of a T come from. T must be a
+// struct of strings, bools, numbers, time.Durations, string slices and
+// nested structs of the same. A field's key is its `config` tag, or its
+// name in snake_case.
+type Loader[T any] struct {
+	// Defaults is the starting value.
+	Defaults T
+
+	// File is read when set. Its format follows the extension: .json,
+	// .yaml, .yml or .toml. Nested tables fill nested struct fields.
+	File string
+
+	// EnvPrefix enables environment variables when set: with the prefix
+	// "APP_", the key debug_mode is read from APP_DEBUG_MODE.
+	EnvPrefix string
+
+	// LookupEnv reads the environment; nil means os.LookupEnv.
+	LookupEnv func(key string) (string, bool)
+
+	flags map[string]*flagValue // by key, after RegisterFlags
+}
+
+// Load builds a T from the layers and validates it. Every problem found
+// is reported, not just the first: the error joins one
+// *errs.ValidationError per bad setting, whose Field names the file key,
+// environment variable or flag it came from.
+func (l *Loader[T]) Load() (*T, error) {
+	fields, err := fieldsOf(reflect.TypeFor[T]())
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := new(T)
+	*cfg = l.Defaults
+	v := reflect.ValueOf(cfg).Elem()
+	var problems []error
+
+	if l.File != "" {
+		values, err := ReadFile(l.File)
+		if err != nil {
+			return nil, err
+		}
+		byKey := make(map[string]field, len(fields))
+		for _, f := range fields {
+			byKey[f.key] = f
+		}
+		for _, key := range slices.Sorted(maps.Keys(values)) {
+			f, ok := byKey[key]
+			if !ok {
+				problems = append(problems, &errs.ValidationError{Field: key, Message: "unknown setting"})
+				continue
+			}
+			if err := set(v.FieldByIndex(f.index), values[key]); err != nil {
+				problems = append(problems, &errs.ValidationError{Field: key, Message: err.Error(), Value: values[key]})
+			}
+		}
+	}
+
+	if l.EnvPrefix != "" {
+		lookup := l.LookupEnv
+		if lookup == nil {
+			lookup = os.LookupEnv
+		}
+		for _, f := range fields {
+			name := f.envName(l.EnvPrefix)
+			raw, ok := lookup(name)
+			if !ok {
+				continue
+			}
+			if err := setString(v.FieldByIndex(f.index), raw); err != nil {
+				problems = append(problems, &errs.ValidationError{Field: name, Message: err.Error(), Value: raw})
+			}
+		}
+	}
+
+	for _, f := range fields {
+		fv := l.flags[f.key]
+		if fv == nil || !fv.set {
+			continue
+		}
+		if err := setString(v.FieldByIndex(f.index), fv.raw); err != nil {
+			problems = append(problems, &errs.ValidationError{Field: "-" + f.flagName(), Message: err.Error(), Value: fv.raw})
+		}
+	}
+
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("config: %w", errors.Join(problems...))
+	}
+	if err := validate(cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// validate runs T's Validate method, if it has one.
+func validate[T any](cfg *T) error {
+	var v any = cfg
+	if _, ok := v.(Validator); !ok {
+		v = *cfg
+	}
+	if val, ok := v.(Validator); ok {
+		if err := val.Validate(); err != nil {
+			return fmt.Errorf("config: %w", err)
+		}
+	}
+	return nil
+}
+
+// ReadFile decodes a configuration file into a map from dotted keys, such
+// as "server.port", to values, choosing the format by extension.
+func ReadFile(path string) (map[string]any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+
+	var tree any
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		err = dec.Decode(&tree)
+	case ".yaml", ".yml":
+		tree, err = codec.DecodeYAML(data)
+	case ".toml":
+		tree, err = DecodeTOML(data)
+	default:
+		return nil, fmt.Errorf("config: %s: unsupported file type %q", path, ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("config: %s: %w", path, err)
+	}
+
+	values := make(map[string]any)
+	switch root := tree.(type) {
+	case nil: // an empty file sets nothing
+	case map[string]any:
+		flatten("", root, values)
+	default:
+		return nil, fmt.Errorf("config: %s: top level must be a mapping, not %T", path, tree)
+	}
+	return values, nil
+}
+
+func flatten(prefix string, m map[string]any, out map[string]any) {
+	for k, v := range m {
+		if sub, ok := v.(map[string]any); ok {
+			flatten(prefix+k+".", sub, out)
+			continue
+		}
+		out[prefix+k] = v
+	}
+}
+
+// RegisterFlags defines a flag for every setting on fs, named after its
+// key with dashes (-debug-mode, -server-port). Lists are comma-separated.
+// Parse fs before calling Load; only flags given on the command line
+// override the other layers.
+func (l *Loader[T]) RegisterFlags(fs *flag.FlagSet) error {
+	fields, err := fieldsOf(reflect.TypeFor[T]())
+	if err != nil {
+		return err
+	}
+	defaults := reflect.ValueOf(&l.Defaults).Elem()
+	l.flags = make(map[string]*flagValue, len(fields))
+	for _, f := range fields {
+		def := defaults.FieldByIndex(f.index)
+		fv := &flagValue{raw: fmt.Sprint(def), isBool: f.typ.Kind() == reflect.Bool}
+		kind := f.typ.Kind().String()
+		switch {
+		case f.typ == durationType:
+			kind = "duration"
+		case f.typ.Kind() == reflect.Slice:
+			fv.raw = strings.Join(def.Convert(reflect.TypeFor[[]string]()).Interface().([]string), ",")
+			kind = "list"
+		}
+		l.flags[f.key] = fv
+		fs.Var(fv, f.flagName(), fmt.Sprintf("set %s to `%s`", f.key, kind))
+	}
+	return nil
+}
+
+// flagValue records a flag's raw text, which starts out as the default
+// for the usage message; Load parses it with the other layers so all
+// errors are reported the same way.
+type flagValue struct {
+	raw    string
+	set    bool
+	isBool bool
+}
+
+func (v *flagValue) String() string { return v.raw }
+
+func (v *flagValue) Set(s string) error {
+	v.raw, v.set = s, true
+	return nil
+}
+
+func (v *flagValue) IsBoolFlag() bool { return v.isBool }
@@ -0,0 +1,144 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"go-practice/errs"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// TestLoadFileFormats loads the same settings from each format. The YAML
+// file goes through codec.DecodeYAML, so it also checks that the codec's
+// output fits what set accepts.
+func TestLoadFileFormats(t *testing.T) {
+	want := settings{
+		Name:      "app",
+		DebugMode: true,
+		HTTPPort:  8080,
+		Ratio:     0.5,
+		Tags:      []string{"a", "b"},
+		Server:    server{Host: "example.com", Port: 443, Timeout: 90 * time.Second},
+	}
+	files := map[string]string{
+		"app.json": `{
+  "name": "app", "debug_mode": true, "http": 8080, "ratio": 0.5,
+  "tags": ["a", "b"],
+  "server": {"host": "example.com", "port": 443, "timeout": "1m30s"}
+}`,
+		"app.yaml": `name: app
+debug_mode: true
+http: 8080
+ratio: 0.5
+tags:
+  - a
+  - b
+server:
+  host: example.com
+  port: 443
+  timeout: 1m30s
+`,
+		"app.toml": `name = "app"
+debug_mode = true
+http = 8080
+ratio = 0.5
+tags = ["a", "b"]
+
+[server]
+host = "example.com"
+port = 443
+timeout = "1m30s"
+`,
+	}
+	for name, content := range files {
+		t.Run(name, func(t *testing.T) {
+			l := &Loader[settings]{File: writeFile(t, name, content)}
+			got, err := l.Load()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(*got, want) {
+				t.Errorf("got  %+v\nwant %+v", *got, want)
+			}
+		})
+	}
+}
+
+func TestLoadLayers(t *testing.T) {
+	l := &Loader[settings]{
+		Defaults:  settings{Name: "default", HTTPPort: 80, Tags: []string{"d"}},
+		File:      writeFile(t, "app.toml", "name = \"file\"\nhttp = 8080\n"),
+		EnvPrefix: "APP_",
+		LookupEnv: func(key string) (string, bool) {
+			v, ok := map[string]string{"APP_HTTP": "9090", "APP_TAGS": "x,y"}[key]
+			return v, ok
+		},
+	}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	if err := l.RegisterFlags(fs); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.Parse([]string{"-http", "7070", "-debug-mode"}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := l.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := settings{Name: "file", DebugMode: true, HTTPPort: 7070, Tags: []string{"x", "y"}}
+	if !reflect.DeepEqual(*got, want) {
+		t.Errorf("got  %+v\nwant %+v", *got, want)
+	}
+}
+
+func TestLoadReportsEveryProblem(t *testing.T) {
+	l := &Loader[settings]{
+		File:      writeFile(t, "app.yaml", "http: lots\ncolour: red\nratio: 0.5\n"),
+		EnvPrefix: "APP_",
+		LookupEnv: func(key string) (string, bool) {
+			if key == "APP_SERVER_PORT" {
+				return "-1", true
+			}
+			return "", false
+		},
+	}
+	_, err := l.Load()
+	var fields []string
+	for _, ve := range errs.ValidationErrors(err) {
+		fields = append(fields, ve.Field)
+	}
+	if got := strings.Join(fields, " "); got != "colour http APP_SERVER_PORT" {
+		t.Errorf("problems with %q, want colour, http and APP_SERVER_PORT; error: %v", got, err)
+	}
+}
+
+func TestReadFileErrors(t *testing.T) {
+	if _, err := ReadFile(filepath.Join(t.TempDir(), "missing.toml")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("missing file: %v", err)
+	}
+	if _, err := ReadFile(writeFile(t, "app.ini", "a=1")); err == nil || !strings.Contains(err.Error(), `unsupported file type ".ini"`) {
+		t.Errorf("unknown extension: %v", err)
+	}
+	if _, err := ReadFile(writeFile(t, "app.yaml", "- a\n- b\n")); err == nil || !strings.Contains(err.Error(), "top level must be a mapping") {
+		t.Errorf("list at the top: %v", err)
+	}
+	values, err := ReadFile(writeFile(t, "app.yaml", ""))
+	if err != nil || len(values) != 0 {
+		t.Errorf("empty file gave %v, %v", values, err)
+	}
+}
@@ -0,0 +1,213 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// field is one setting of a configuration struct. Nested structs are
+// flattened, so a Port field inside a Server field has the key
+// "server.port", the environment name PREFIX_SERVER_PORT and the flag
+// -server-port.
+type field struct {
+	key   string
+	index []int
+	typ   reflect.Type
+}
+
+func (f field) envName(prefix string) string {
+	return prefix + strings.ToUpper(strings.ReplaceAll(f.key, ".", "_"))
+}
+
+func (f field) flagName() string {
+	return strings.NewReplacer(".", "-", "_", "-").Replace(f.key)
+}
+
+var durationType = reflect.TypeFor[time.Duration]()
+
+// fieldsOf lists the settings of struct type t. A field's key is its
+// `config` tag, or its name in snake_case; the tag "-" skips the field.
+// Unexported fields are skipped too.
+func fieldsOf(t reflect.Type) ([]field, error) {
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("config: %s is not a struct", t)
+	}
+	var fields []field
+	var walk func(t reflect.Type, prefix string, index []int) error
+	walk = func(t reflect.Type, prefix string, index []int) error {
+		for i := range t.NumField() {
+			sf := t.Field(i)
+			tag := sf.Tag.Get("config")
+			if !sf.IsExported() || tag == "-" {
+				continue
+			}
+			key := tag
+			if key == "" {
+				key = snakeCase(sf.Name)
+			}
+			key = prefix + key
+			idx := append(extend(index), i)
+
+			if sf.Type.Kind() == reflect.Struct && sf.Type != durationType {
+				if err := walk(sf.Type, key+".", idx); err != nil {
+					return err
+				}
+				continue
+			}
+			if !supported(sf.Type) {
+				return fmt.Errorf("config: field %s: unsupported type %s", key, sf.Type)
+			}
+			fields = append(fields, field{key: key, index: idx, typ: sf.Type})
+		}
+		return nil
+	}
+	return fields, walk(t, "", nil)
+}
+
+// extend returns a copy of index with room for one more element, so
+// sibling fields don't share a backing array.
+func extend(index []int) []int {
+	return append(make([]int, 0, len(index)+1), index...)
+}
+
+func supported(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Slice:
+		return t.Elem().Kind() == reflect.String
+	}
+	return false
+}
+
+// snakeCase turns DebugMode into debug_mode and HTTPPort into http_port.
+func snakeCase(name string) string {
+	var b strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			prevLower := i > 0 && unicode.IsLower(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if i > 0 && (prevLower || nextLower && unicode.IsUpper(runes[i-1])) {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// set stores raw in dst. raw is a string from the environment or a flag,
+// or a value decoded from a file: string, bool, json.Number, int64,
+// float64 or []any.
+func set(dst reflect.Value, raw any) error {
+	if s, ok := raw.(string); ok && dst.Kind() != reflect.String {
+		return setString(dst, s)
+	}
+
+	switch dst.Kind() {
+	case reflect.String:
+		s, ok := raw.(string)
+		if !ok {
+			return fmt.Errorf("want a string, got %T", raw)
+		}
+		dst.SetString(s)
+
+	case reflect.Bool:
+		b, ok := raw.(bool)
+		if !ok {
+			return fmt.Errorf("want true or false, got %T", raw)
+		}
+		dst.SetBool(b)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		switch n := raw.(type) {
+		case json.Number:
+			return setString(dst, n.String())
+		case int64:
+			return setString(dst, strconv.FormatInt(n, 10))
+		case float64:
+			if dst.Kind() != reflect.Float32 && dst.Kind() != reflect.Float64 && n != math.Trunc(n) {
+				return fmt.Errorf("want a whole number, got %v", n)
+			}
+			return setString(dst, strconv.FormatFloat(n, 'f', -1, 64))
+		}
+		return fmt.Errorf("want a number, got %T", raw)
+
+	case reflect.Slice:
+		items, ok := raw.([]any)
+		if !ok {
+			return fmt.Errorf("want a list, got %T", raw)
+		}
+		list := make([]string, len(items))
+		for i, item := range items {
+			if list[i], ok = item.(string); !ok {
+				return fmt.Errorf("item %d: want a string, got %T", i, item)
+			}
+		}
+		dst.Set(reflect.ValueOf(list).Convert(dst.Type()))
+	}
+	return nil
+}
+
+// setString parses s into dst. Lists are comma-separated.
+func setString(dst reflect.Value, s string) error {
+	if dst.Type() == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return fmt.Errorf("want a duration like 30s or 5m")
+		}
+		dst.SetInt(int64(d))
+		return nil
+	}
+
+	switch dst.Kind() {
+	case reflect.String:
+		dst.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("want true or false")
+		}
+		dst.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 0, dst.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("want an integer of %d bits", dst.Type().Bits())
+		}
+		dst.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 0, dst.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("want a non-negative integer of %d bits", dst.Type().Bits())
+		}
+		dst.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, dst.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("want a number")
+		}
+		dst.SetFloat(f)
+	case reflect.Slice:
+		var list []string
+		for item := range strings.SplitSeq(s, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		dst.Set(reflect.ValueOf(list).Convert(dst.Type()))
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type server struct {
+	Host    string
+	Port    uint16
+	Timeout time.Duration
+}
+
+type settings struct {
+	Name      string
+	DebugMode bool
+	HTTPPort  int `config:"http"`
+	Ratio     float32
+	Tags      []string
+	Server    server
+	Secret    string `config:"-"`
+	internal  int
+}
+
+func TestFieldsOf(t *testing.T) {
+	fields, err := fieldsOf(reflect.TypeFor[settings]())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keys, envs, flags []string
+	for _, f := range fields {
+		keys = append(keys, f.key)
+		envs = append(envs, f.envName("APP_"))
+		flags = append(flags, f.flagName())
+	}
+	want := "name debug_mode http ratio tags server.host server.port server.timeout"
+	if got := strings.Join(keys, " "); got != want {
+		t.Errorf("keys = %s\nwant   %s", got, want)
+	}
+	if got := envs[1] + " " + envs[6]; got != "APP_DEBUG_MODE APP_SERVER_PORT" {
+		t.Errorf("env names %s", got)
+	}
+	if got := flags[1] + " " + flags[6]; got != "debug-mode server-port" {
+		t.Errorf("flag names %s", got)
+	}
+
+	if _, err := fieldsOf(reflect.TypeFor[struct{ M map[string]int }]()); err == nil {
+		t.Error("a map field was accepted")
+	}
+	if _, err := fieldsOf(reflect.TypeFor[int]()); err == nil {
+		t.Error("a non-struct was accepted")
+	}
+}
+
+func TestSnakeCase(t *testing.T) {
+	for in, want := range map[string]string{
+		"Name":      "name",
+		"DebugMode": "debug_mode",
+		"HTTPPort":  "http_port",
+		"UserID":    "user_id",
+		"APIKeyV2":  "api_key_v2",
+	} {
+		if got := snakeCase(in); got != want {
+			t.Errorf("snakeCase(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestSet(t *testing.T) {
+	tests := []struct {
+		name  string
+		field string
+		raw   any
+		want  any    // the field's value afterwards
+		err   string // a substring of the error, if one is expected
+	}{
+		{"string", "Name", "app", "app", ""},
+		{"string from number", "Name", int64(1), nil, "want a string, got int64"},
+		{"bool", "DebugMode", true, true, ""},
+		{"bool from env", "DebugMode", "true", true, ""},
+		{"bool from number", "DebugMode", int64(1), nil, "want true or false"},
+		{"int from TOML", "HTTPPort", int64(8080), 8080, ""},
+		{"int from JSON", "HTTPPort", json.Number("8080"), 8080, ""},
+		{"int from YAML float", "HTTPPort", float64(8080), 8080, ""},
+		{"int from fraction", "HTTPPort", 80.5, nil, "want a whole number"},
+		{"int from hex string", "HTTPPort", "0x50", 80, ""},
+		{"int from bad string", "HTTPPort", "eighty", nil, "want an integer of 64 bits"},
+		{"uint overflow", "Server.Port", int64(70000), nil, "want a non-negative integer of 16 bits"},
+		{"uint negative", "Server.Port", int64(-1), nil, "non-negative"},
+		{"float", "Ratio", 0.5, float32(0.5), ""},
+		{"float from int", "Ratio", int64(2), float32(2), ""},
+		{"duration", "Server.Timeout", "1m30s", 90 * time.Second, ""},
+		{"bad duration", "Server.Timeout", "soon", nil, "want a duration"},
+		{"duration without a unit", "Server.Timeout", int64(5), nil, "want a duration"},
+		{"list", "Tags", []any{"a", "b"}, []string{"a", "b"}, ""},
+		{"list from env", "Tags", "a, b,,c", []string{"a", "b", "c"}, ""},
+		{"list with a number", "Tags", []any{"a", int64(1)}, nil, "item 1: want a string, got int64"},
+		{"list from scalar", "Tags", true, nil, "want a list, got bool"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var s settings
+			dst := reflect.ValueOf(&s).Elem().FieldByName(tt.field)
+			if before, after, ok := strings.Cut(tt.field, "."); ok {
+				dst = reflect.ValueOf(&s).Elem().FieldByName(before).FieldByName(after)
+			}
+			err := set(dst, tt.raw)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("err = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := dst.Interface(); !reflect.DeepEqual(got, reflect.ValueOf(tt.want).Convert(dst.Type()).Interface()) {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package config
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+
+	"go-practice/reflectutil"
+)
+
+// Store holds the current configuration. Readers call Get, which is a
+// single atomic load, and never block; writers are serialized and
+// publish a whole new value, so a snapshot never changes after Get
+// returns it.
+type Store[T any] struct {
+	mu  sync.Mutex // serializes writers
+	cur atomic.Pointer[T]
+
+	subMu  sync.Mutex
+	subs   map[int]*subscriber[T]
+	nextID int
+}
+
+// NewStore returns a Store holding initial, which must not be modified
+// afterwards.
+func NewStore[T any](initial *T) *Store[T] {
+	s := &Store[T]{}
+	s.cur.Store(initial)
+	return s
+}
+
+// Load builds a configuration with l and returns a Store holding it.
+func Load[T any](l *Loader[T]) (*Store[T], error) {
+	cfg, err := l.Load()
+	if err != nil {
+		return nil, err
+	}
+	return NewStore(cfg), nil
+}
+
+// Get returns the current snapshot. It is shared with other readers and
+// must be treated as read-only; use Update to change it.
+func (s *Store[T]) Get() *T {
+	return s.cur.Load()
+}
+
+// Update applies fn to a deep copy of the current configuration,
+// validates the result and publishes it. If validation fails the current
+// configuration stays in place and the error is returned.
+func (s *Store[T]) Update(fn func(*T)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next := reflectutil.DeepCopy(s.cur.Load())
+	fn(next)
+	if err := validate(next); err != nil {
+		return err
+	}
+	s.publish(next)
+	return nil
+}
+
+// Replace validates next and, if it is valid, publishes it in place of
+// the current configuration. next must not be modified afterwards.
+func (s *Store[T]) Replace(next *T) error {
+	if err := validate(next); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.publish(next)
+	return nil
+}
+
+// publish swaps in next and tells subscribers what changed. s.mu must be
+// held, so events are queued in the order the snapshots were published.
+func (s *Store[T]) publish(next *T) {
+	old := s.cur.Swap(next)
+	changes := diff(old, next)
+	if len(changes) == 0 {
+		return
+	}
+	ev := Event[T]{Old: old, New: next, Changes: changes}
+
+	s.subMu.Lock()
+	defer s.subMu.Unlock()
+	for _, sub := range s.subs {
+		sub.queue(ev)
+	}
+}
+
+// Event describes one published change to a Store.
+type Event[T any] struct {
+	Old, New *T       // the snapshots before and after; both read-only
+	Changes  []Change // the settings that differ, in field order
+}
+
+// Change is one setting that changed. Field is its key, such as "theme"
+// or "server.port"; Old and New hold values of the field's type.
+type Change struct {
+	Field    string
+	Old, New any
+}
+
+// Values returns a change's old and new values as V, and false if the
+// field isn't a V.
+func Values[V any](c Change) (old, new V, ok bool) {
+	old, ok1 := c.Old.(V)
+	new, ok2 := c.New.(V)
+	return old, new, ok1 && ok2
+}
+
+// diff lists the settings that differ between two configurations. Types
+// the Loader can't describe yield no changes.
+func diff[T any](old, next *T) []Change {
+	fields, err := fieldsOf(reflect.TypeFor[T]())
+	if err != nil || old == nil || next == nil {
+		return nil
+	}
+	ov, nv := reflect.ValueOf(old).Elem(), reflect.ValueOf(next).Elem()
+	var changes []Change
+	for _, f := range fields {
+		a, b := ov.FieldByIndex(f.index).Interface(), nv.FieldByIndex(f.index).Interface()
+		if !reflectutil.DeepEqual(a, b) {
+			changes = append(changes, Change{Field: f.key, Old: a, New: b})
+		}
+	}
+	return changes
+}
+
+// Subscribe calls fn with an Event after every published change, until
+// the returned function is called. Each subscriber has its own goroutine
+// and an unbounded queue, so events arrive in order, a slow subscriber
+// doesn't hold up writers or other subscribers, and fn may itself call
+// Update.
+func (s *Store[T]) Subscribe(fn func(Event[T])) (unsubscribe func()) {
+	sub := &subscriber[T]{fn: fn, wake: make(chan struct{}, 1), done: make(chan struct{})}
+	go sub.run()
+
+	s.subMu.Lock()
+	if s.subs == nil {
+		s.subs = make(map[int]*subscriber[T])
+	}
+	id := s.nextID
+	s.nextID++
+	s.subs[id] = sub
+	s.subMu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			s.subMu.Lock()
+			delete(s.subs, id)
+			s.subMu.Unlock()
+			close(sub.done)
+		})
+	}
+}
+
+type subscriber[T any] struct {
+	fn   func(Event[T])
+	mu   sync.Mutex
+	q    []Event[T]
+	wake chan struct{} // signalled when q becomes non-empty
+	done chan struct{} // closed on unsubscribe
+}
+
+func (s *subscriber[T]) queue(ev Event[T]) {
+	s.mu.Lock()
+	s.q = append(s.q, ev)
+	s.mu.Unlock()
+	select {
+	case s.wake <- struct{}{}:
+	default: // already signalled
+	}
+}
+
+func (s *subscriber[T]) run() {
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-s.wake:
+		}
+		s.mu.Lock()
+		batch := s.q
+		s.q = nil
+		s.mu.Unlock()
+		for _, ev := range batch {
+			select {
+			case <-s.done:
+				return
+			default:
+				s.fn(ev)
+			}
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package config
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"go-practice/errs"
+)
+
+// pair is valid only while B is twice A, so a reader that sees one field
+// from one update and the other from the next notices.
+type pair struct {
+	A, B  int
+	Items []string
+}
+
+func (p pair) Validate() error {
+	if p.B != 2*p.A {
+		return &errs.ValidationError{Field: "b", Message: "must be twice a", Value: p.B}
+	}
+	return nil
+}
+
+// TestStoreConcurrentAccess runs readers against several writers. Run it
+// with go test -race to check that Get never races with Update.
+func TestStoreConcurrentAccess(t *testing.T) {
+	s := NewStore(&pair{A: 0, B: 0})
+	const writers, updates = 4, 200
+
+	var readers sync.WaitGroup
+	stop := make(chan struct{})
+	for range 4 {
+		readers.Go(func() {
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				p := s.Get()
+				a, b, n := p.A, p.B, len(p.Items)
+				if b != 2*a || n != a {
+					t.Errorf("torn read: A %d, B %d, %d items", a, b, n)
+					return
+				}
+				// A snapshot never changes after Get returns it.
+				time.Sleep(time.Microsecond)
+				if p.A != a || p.B != b || len(p.Items) != n {
+					t.Errorf("snapshot changed under a reader: %+v", p)
+					return
+				}
+			}
+		})
+	}
+
+	var wg sync.WaitGroup
+	for range writers {
+		wg.Go(func() {
+			for range updates {
+				err := s.Update(func(p *pair) {
+					p.A++
+					p.B = 2 * p.A
+					p.Items = append(p.Items, "x")
+				})
+				if err != nil {
+					t.Errorf("Update: %v", err)
+					return
+				}
+			}
+		})
+	}
+	wg.Wait()
+	close(stop)
+	readers.Wait()
+
+	if got := s.Get().A; got != writers*updates {
+		t.Errorf("A = %d after %d updates; some were lost", got, writers*updates)
+	}
+}
+
+func TestStoreRejectsInvalid(t *testing.T) {
+	s := NewStore(&pair{A: 1, B: 2})
+	before := s.Get()
+
+	err := s.Update(func(p *pair) { p.A = 5 })
+	var ve *errs.ValidationError
+	if !errors.As(err, &ve) || ve.Field != "b" {
+		t.Fatalf("Update = %v, want a validation error for b", err)
+	}
+	if s.Get() != before || before.A != 1 {
+		t.Errorf("a rejected update changed the store: %+v", s.Get())
+	}
+	if err := s.Replace(&pair{A: 3, B: 7}); err == nil || s.Get() != before {
+		t.Errorf("Replace accepted an invalid value: %v", err)
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	s := NewStore(&pair{})
+	events := make(chan Event[pair], 10)
+	unsubscribe := s.Subscribe(func(ev Event[pair]) { events <- ev })
+
+	for i := 1; i <= 3; i++ {
+		if err := s.Update(func(p *pair) { p.A, p.B = i, 2*i }); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// An update that changes nothing publishes no event.
+	if err := s.Update(func(*pair) {}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Update(func(p *pair) { p.Items = []string{"a"} }); err != nil {
+		t.Fatal(err)
+	}
+
+	want := [][]string{{"a", "b"}, {"a", "b"}, {"a", "b"}, {"items"}}
+	for i, fields := range want {
+		select {
+		case ev := <-events:
+			var got []string
+			for _, c := range ev.Changes {
+				got = append(got, c.Field)
+			}
+			if !slices.Equal(got, fields) {
+				t.Errorf("event %d changed %v, want %v", i, got, fields)
+			}
+			if i < 3 {
+				old, new, ok := Values[int](ev.Changes[0])
+				if !ok || old != i || new != i+1 {
+					t.Errorf("event %d: a went %d -> %d, %v", i, old, new, ok)
+				}
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("event %d never arrived", i)
+		}
+	}
+
+	unsubscribe()
+	unsubscribe() // a second call is harmless
+	if err := s.Update(func(p *pair) { p.A, p.B = 10, 20 }); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case ev := <-events:
+		t.Errorf("got an event after unsubscribing: %+v", ev.Changes)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
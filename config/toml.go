@@ -0,0 +1,244 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// The TOML support covers what flat and lightly nested configuration
+// files use: key = value pairs, [table] and [dotted.table] headers, bare
+// and quoted keys, basic and literal strings, integers, floats, booleans,
+// single-line arrays and comments. Multi-line strings, inline tables,
+// arrays of tables and dates are not supported.
+
+// DecodeTOML parses TOML into nested map[string]any values holding
+// string, int64, float64, bool and []any.
+func DecodeTOML(data []byte) (map[string]any, error) {
+	root := make(map[string]any)
+	table := root
+	for i, line := range strings.Split(string(data), "\n") {
+		n := i + 1
+		line = strings.TrimSpace(stripTOMLComment(line))
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") || strings.HasPrefix(line, "[[") {
+				return nil, fmt.Errorf("toml: line %d: invalid table header %q", n, line)
+			}
+			path, err := splitTOMLKey(strings.TrimSpace(line[1 : len(line)-1]))
+			if err != nil {
+				return nil, fmt.Errorf("toml: line %d: %w", n, err)
+			}
+			if table, err = descend(root, path); err != nil {
+				return nil, fmt.Errorf("toml: line %d: %w", n, err)
+			}
+			continue
+		}
+
+		eq := indexOutsideQuotes(line, '=')
+		if eq < 0 {
+			return nil, fmt.Errorf("toml: line %d: expected key = value", n)
+		}
+		path, err := splitTOMLKey(strings.TrimSpace(line[:eq]))
+		if err != nil {
+			return nil, fmt.Errorf("toml: line %d: %w", n, err)
+		}
+		v, err := parseTOMLValue(strings.TrimSpace(line[eq+1:]))
+		if err != nil {
+			return nil, fmt.Errorf("toml: line %d: %w", n, err)
+		}
+		parent, err := descend(table, path[:len(path)-1])
+		if err != nil {
+			return nil, fmt.Errorf("toml: line %d: %w", n, err)
+		}
+		key := path[len(path)-1]
+		if _, dup := parent[key]; dup {
+			return nil, fmt.Errorf("toml: line %d: duplicate key %q", n, key)
+		}
+		parent[key] = v
+	}
+	return root, nil
+}
+
+// descend returns the table at path below t, creating missing tables.
+func descend(t map[string]any, path []string) (map[string]any, error) {
+	for _, key := range path {
+		switch next := t[key].(type) {
+		case nil:
+			m := make(map[string]any)
+			t[key] = m
+			t = m
+		case map[string]any:
+			t = next
+		default:
+			return nil, fmt.Errorf("key %q is already a value, not a table", key)
+		}
+	}
+	return t, nil
+}
+
+// splitTOMLKey splits a dotted key such as server."host name".port.
+func splitTOMLKey(s string) ([]string, error) {
+	var path []string
+	for s != "" {
+		var part string
+		switch s[0] {
+		case '"', '\'':
+			end := closingQuote(s)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated quoted key")
+			}
+			var err error
+			if part, err = parseTOMLString(s[:end+1]); err != nil {
+				return nil, err
+			}
+			s = s[end+1:]
+		default:
+			end := strings.IndexByte(s, '.')
+			if end < 0 {
+				end = len(s)
+			}
+			part = strings.TrimSpace(s[:end])
+			if part == "" || strings.ContainsFunc(part, func(r rune) bool {
+				return !(r == '_' || r == '-' || 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9')
+			}) {
+				return nil, fmt.Errorf("invalid key %q", s[:end])
+			}
+			s = s[end:]
+		}
+		path = append(path, part)
+
+		s = strings.TrimSpace(s)
+		if s == "" {
+			break
+		}
+		if s[0] != '.' {
+			return nil, fmt.Errorf("expected '.' in key, found %q", s)
+		}
+		s = strings.TrimSpace(s[1:])
+		if s == "" {
+			return nil, fmt.Errorf("key ends with '.'")
+		}
+	}
+	if len(path) == 0 {
+		return nil, fmt.Errorf("empty key")
+	}
+	return path, nil
+}
+
+func parseTOMLValue(s string) (any, error) {
+	switch {
+	case s == "":
+		return nil, fmt.Errorf("missing value")
+	case s[0] == '"' || s[0] == '\'':
+		if closingQuote(s) != len(s)-1 {
+			return nil, fmt.Errorf("invalid string %s", s)
+		}
+		return parseTOMLString(s)
+	case s[0] == '[':
+		return parseTOMLArray(s)
+	case s == "true":
+		return true, nil
+	case s == "false":
+		return false, nil
+	}
+
+	digits := strings.ReplaceAll(s, "_", "")
+	if n, err := strconv.ParseInt(digits, 0, 64); err == nil {
+		return n, nil
+	}
+	if f, err := strconv.ParseFloat(digits, 64); err == nil {
+		return f, nil
+	}
+	switch s {
+	case "inf", "+inf", "-inf", "nan", "+nan", "-nan":
+		f, _ := strconv.ParseFloat(strings.Replace(s, "inf", "Inf", 1), 64)
+		return f, nil
+	}
+	return nil, fmt.Errorf("invalid value %s", s)
+}
+
+func parseTOMLArray(s string) ([]any, error) {
+	if !strings.HasSuffix(s, "]") {
+		return nil, fmt.Errorf("arrays must be on one line")
+	}
+	items := []any{}
+	rest := strings.TrimSpace(s[1 : len(s)-1])
+	for rest != "" {
+		end := indexOutsideQuotes(rest, ',')
+		if end < 0 {
+			end = len(rest)
+		}
+		item := strings.TrimSpace(rest[:end])
+		if strings.HasPrefix(item, "[") {
+			return nil, fmt.Errorf("nested arrays are not supported")
+		}
+		v, err := parseTOMLValue(item)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, v)
+		if end == len(rest) {
+			break
+		}
+		rest = strings.TrimSpace(rest[end+1:]) // a trailing comma is allowed
+	}
+	return items, nil
+}
+
+// parseTOMLString decodes a basic ("...") or literal ('...') string.
+// Basic strings use the same escapes as Go, except that TOML has no
+// \a, \v or octal escapes, which are rare enough not to matter here.
+func parseTOMLString(s string) (string, error) {
+	if s[0] == '\'' {
+		return s[1 : len(s)-1], nil
+	}
+	v, err := strconv.Unquote(s)
+	if err != nil {
+		return "", fmt.Errorf("invalid string %s", s)
+	}
+	return v, nil
+}
+
+// closingQuote returns the index of the quote that ends the string s
+// starts with, or -1.
+func closingQuote(s string) int {
+	q := s[0]
+	for i := 1; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && q == '"':
+			i++
+		case s[i] == q:
+			return i
+		}
+	}
+	return -1
+}
+
+// indexOutsideQuotes returns the index of the first c in s that is not
+// inside a string, or -1.
+func indexOutsideQuotes(s string, c byte) int {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"', '\'':
+			end := closingQuote(s[i:])
+			if end < 0 {
+				return -1
+			}
+			i += end
+		case c:
+			return i
+		}
+	}
+	return -1
+}
+
+func stripTOMLComment(line string) string {
+	if i := indexOutsideQuotes(line, '#'); i >= 0 {
+		return line[:i]
+	}
+	return line
+}
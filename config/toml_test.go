@@ -0,0 +1,146 @@
+package config
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecodeTOML(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want map[string]any
+	}{
+		{"empty", "", map[string]any{}},
+		{"scalars", `
+name = "app"
+port = 8080
+big = 1_000_000
+hex = 0xff
+ratio = 0.75
+debug = true
+quiet = false
+`, map[string]any{
+			"name": "app", "port": int64(8080), "big": int64(1000000), "hex": int64(255),
+			"ratio": 0.75, "debug": true, "quiet": false,
+		}},
+		{"bare keys", `a-b_c1 = 1`, map[string]any{"a-b_c1": int64(1)}},
+		{"quoted keys", `
+"host name" = "x"
+'lit key' = 1
+"a.b" = 2
+`, map[string]any{"host name": "x", "lit key": int64(1), "a.b": int64(2)}},
+		{"dotted keys", `server.port = 80
+server . "host name" = "web"`, map[string]any{
+			"server": map[string]any{"port": int64(80), "host name": "web"},
+		}},
+		{"strings", `
+basic = "tab\there \"quoted\" \u00e9"
+literal = 'C:\path\no escapes'
+hash = "not # a comment"
+empty = ""
+`, map[string]any{
+			"basic": "tab\there \"quoted\" é", "literal": `C:\path\no escapes`,
+			"hash": "not # a comment", "empty": "",
+		}},
+		{"arrays", `
+tags = ["a", 'b', "c,d"]
+nums = [1, 2.5, -3]
+trailing = [ "x", ]
+none = []
+`, map[string]any{
+			"tags": []any{"a", "b", "c,d"}, "nums": []any{int64(1), 2.5, int64(-3)},
+			"trailing": []any{"x"}, "none": []any{},
+		}},
+		{"comments", `
+# a whole line
+key = "value" # after a value
+  # indented
+`, map[string]any{"key": "value"}},
+		{"tables", `
+top = 1
+[server]
+port = 80
+[server.tls]
+cert = "c.pem"
+[database]
+url = "db"
+`, map[string]any{
+			"top": int64(1),
+			"server": map[string]any{
+				"port": int64(80),
+				"tls":  map[string]any{"cert": "c.pem"},
+			},
+			"database": map[string]any{"url": "db"},
+		}},
+		{"reopened table", `
+[a]
+x = 1
+[b]
+y = 2
+[a]
+z = 3
+`, map[string]any{"a": map[string]any{"x": int64(1), "z": int64(3)}, "b": map[string]any{"y": int64(2)}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeTOML([]byte(tt.in))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got  %#v\nwant %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeTOMLSpecialFloats(t *testing.T) {
+	got, err := DecodeTOML([]byte("a = inf\nb = -inf\nc = nan"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got["a"] != math.Inf(1) || got["b"] != math.Inf(-1) || !math.IsNaN(got["c"].(float64)) {
+		t.Errorf("got %v", got)
+	}
+}
+
+func TestDecodeTOMLErrors(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"duplicate key", "a = 1\na = 2", "line 2: duplicate key \"a\""},
+		{"duplicate in table", "[s]\nport = 1\n[s]\nport = 2", "line 4: duplicate key \"port\""},
+		{"duplicate dotted", "s.port = 1\n[s]\nport = 2", "line 3: duplicate key \"port\""},
+		{"value then table", "a = 1\n[a]", "line 2: key \"a\" is already a value, not a table"},
+		{"value then dotted", "a = 1\na.b = 2", "line 2: key \"a\" is already a value"},
+		{"no equals", "just words", "line 1: expected key = value"},
+		{"missing value", "a =", "line 1: missing value"},
+		{"bad value", "a = yes", "line 1: invalid value yes"},
+		{"unterminated string", `a = "open`, "line 1: invalid string"},
+		{"trailing text", `a = "x" y`, "line 1: invalid string"},
+		{"bad escape", `a = "\q"`, "line 1: invalid string"},
+		{"bad key", "a b = 1", "line 1: invalid key \"a b\""},
+		{"empty key", "= 1", "line 1: empty key"},
+		{"trailing dot", "a. = 1", "line 1: key ends with '.'"},
+		{"unterminated quoted key", `"a = 1`, "line 1: expected key = value"},
+		{"array of tables", "[[items]]", "line 1: invalid table header"},
+		{"open header", "[server", "line 1: invalid table header"},
+		{"multi-line array", "a = [\n1]", "line 1: arrays must be on one line"},
+		{"nested array", "a = [[1], [2]]", "line 1: nested arrays are not supported"},
+		{"later line", "a = 1\n\n# c\nb = ?", "line 4: invalid value ?"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeTOML([]byte(tt.in))
+			if err == nil {
+				t.Fatal("no error")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got %q, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"time"
+)
+
+// Watcher reloads a Store whenever its Loader's file changes. It polls
+// rather than relying on file-system notifications, so it works on every
+// platform and file system, including network mounts and editors that
+// save by renaming a new file into place.
+type Watcher[T any] struct {
+	Store  *Store[T]
+	Loader *Loader[T] // File must be set
+
+	// Interval is the time between checks; 0 means one second.
+	Interval time.Duration
+
+	// OnError is called with every failed reload; nil ignores them.
+	OnError func(error)
+
+	checked bool   // the file has been checked at least once
+	last    []byte // file contents when last checked
+	missing bool   // the file couldn't be read when last checked
+}
+
+// Reload loads the configuration again and publishes it. On failure the
+// current configuration is kept and the error is returned as Load or
+// Replace reported it: bad settings as *errs.ValidationError values, and
+// a file that couldn't be read or parsed as that error, so
+// errors.Is(err, fs.ErrNotExist) works.
+//
+// A reload replaces the whole configuration, including any changes made
+// with Update since the file was last read.
+func (w *Watcher[T]) Reload() error {
+	cfg, err := w.Loader.Load()
+	if err != nil {
+		return err
+	}
+	return w.Store.Replace(cfg)
+}
+
+// Run checks the file every Interval until ctx is done, reloading when
+// its contents change, and returns ctx's error. The first check always
+// reloads, so a change made between loading the Store and starting Run
+// isn't missed; if nothing changed, subscribers hear nothing.
+func (w *Watcher[T]) Run(ctx context.Context) error {
+	interval := w.Interval
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			w.poll()
+		}
+	}
+}
+
+// poll compares contents rather than modification times, which some file
+// systems only record to the second. A file that fails to load is only
+// reported once, until it changes again.
+func (w *Watcher[T]) poll() {
+	data, err := os.ReadFile(w.Loader.File)
+	switch {
+	case err != nil && w.missing:
+		return
+	case err != nil:
+		w.missing = true // perhaps mid-rename; Reload reports it
+	case w.checked && !w.missing && bytes.Equal(data, w.last):
+		return
+	default:
+		w.missing, w.last = false, data
+	}
+	w.checked = true
+	if err := w.Reload(); err != nil && w.OnError != nil {
+		w.OnError(err)
+	}
+}
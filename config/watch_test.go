@@ -0,0 +1,116 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"testing"
+	"time"
+
+	"go-practice/errs"
+)
+
+func newWatcher(t *testing.T, content string) *Watcher[settings] {
+	t.Helper()
+	return &Watcher[settings]{
+		Store:  NewStore(&settings{Name: "stale"}),
+		Loader: &Loader[settings]{Defaults: settings{Name: "default"}, File: writeFile(t, "app.toml", content)},
+	}
+}
+
+// TestWatcherFirstCheckReloads covers an empty file, whose contents equal
+// the nil a fresh Watcher starts with.
+func TestWatcherFirstCheckReloads(t *testing.T) {
+	w := newWatcher(t, "")
+	w.poll()
+	if got := w.Store.Get().Name; got != "default" {
+		t.Errorf("Name = %q after the first check, want the reloaded default", got)
+	}
+}
+
+func TestWatcherReloadsOnlyChanges(t *testing.T) {
+	w := newWatcher(t, `name = "one"`)
+	var failures []error
+	w.OnError = func(err error) { failures = append(failures, err) }
+	w.poll()
+	if got := w.Store.Get().Name; got != "one" {
+		t.Fatalf("Name = %q", got)
+	}
+
+	// Unchanged contents don't reload, so an Update survives.
+	if err := w.Store.Update(func(s *settings) { s.Name = "updated" }); err != nil {
+		t.Fatal(err)
+	}
+	w.poll()
+	if got := w.Store.Get().Name; got != "updated" {
+		t.Errorf("an unchanged file was reloaded: Name = %q", got)
+	}
+
+	if err := os.WriteFile(w.Loader.File, []byte(`name = "two"`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	w.poll()
+	if got := w.Store.Get().Name; got != "two" {
+		t.Errorf("Name = %q after the file changed", got)
+	}
+
+	// A broken file is reported once and the old configuration stays.
+	if err := os.WriteFile(w.Loader.File, []byte(`name = `), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	w.poll()
+	w.poll()
+	if len(failures) != 1 || w.Store.Get().Name != "two" {
+		t.Errorf("got %d errors and Name %q, want 1 error and the old config", len(failures), w.Store.Get().Name)
+	}
+}
+
+func TestWatcherReloadErrors(t *testing.T) {
+	w := newWatcher(t, `name = `)
+	err := w.Reload()
+	if err == nil || errs.IsValidationError(err) {
+		t.Errorf("a syntax error came back as %#v, want it unwrapped", err)
+	}
+
+	if err := os.WriteFile(w.Loader.File, []byte("colour = \"red\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	err = w.Reload()
+	if ves := errs.ValidationErrors(err); len(ves) != 1 || ves[0].Field != "colour" {
+		t.Errorf("an unknown key gave %v", err)
+	}
+
+	if err := os.Remove(w.Loader.File); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Reload(); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("a missing file gave %v", err)
+	}
+	if got := w.Store.Get().Name; got != "stale" {
+		t.Errorf("failed reloads changed the config to %q", got)
+	}
+}
+
+func TestWatcherRun(t *testing.T) {
+	w := newWatcher(t, `name = "run"`)
+	w.Interval = time.Millisecond
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	changed := make(chan struct{})
+	unsubscribe := w.Store.Subscribe(func(Event[settings]) { close(changed) })
+	defer unsubscribe()
+
+	done := make(chan error)
+	go func() { done <- w.Run(ctx) }()
+	select {
+	case <-changed:
+	case <-ctx.Done():
+		t.Fatal("Run never reloaded the file")
+	}
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Run returned %v", err)
+	}
+}
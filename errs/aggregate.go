@@ -0,0 +1,47 @@
+package errs
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AggregatedError collects every problem one operation found, so callers
+// can fix them all at once instead of one per attempt. Errors are usually
+// *ValidationError values, which ValidationErrors can extract.
+type AggregatedError struct {
+	Op     string // What was attempted, e.g. "build computer"
+	Errors []error
+}
+
+func (e *AggregatedError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	noun := "problems"
+	if len(e.Errors) == 1 {
+		noun = "problem"
+	}
+	return fmt.Sprintf("%s: %d %s: %s", e.Op, len(e.Errors), noun, strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the collected errors, so errors.Is and errors.As look
+// at each of them.
+func (e *AggregatedError) Unwrap() []error {
+	return e.Errors
+}
+
+// Aggregate returns an *AggregatedError for op holding the non-nil errors
+// in errors, or nil if there are none.
+func Aggregate(op string, errors ...error) error {
+	var found []error
+	for _, err := range errors {
+		if err != nil {
+			found = append(found, err)
+		}
+	}
+	if len(found) == 0 {
+		return nil
+	}
+	return &AggregatedError{Op: op, Errors: found}
+}
@@ -0,0 +1,63 @@
+package errs
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAggregate(t *testing.T) {
+	first := errors.New("first")
+	second := &ValidationError{Field: "Age", Message: "can't be negative", Value: -1}
+	tests := []struct {
+		name string
+		in   []error
+		want []error // nil when Aggregate should return nil
+		msg  string
+	}{
+		{name: "none"},
+		{name: "only nils", in: []error{nil, nil}},
+		{
+			name: "one",
+			in:   []error{nil, first},
+			want: []error{first},
+			msg:  "check: 1 problem: first",
+		},
+		{
+			name: "nils dropped",
+			in:   []error{first, nil, second, nil},
+			want: []error{first, second},
+			msg:  "check: 2 problems: first; validation failed for Age: can't be negative (value: -1)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Aggregate("check", tt.in...)
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("Aggregate = %v, want nil", err)
+				}
+				return
+			}
+			agg, ok := err.(*AggregatedError)
+			if !ok {
+				t.Fatalf("Aggregate = %T, want *AggregatedError", err)
+			}
+			if len(agg.Errors) != len(tt.want) {
+				t.Fatalf("Errors = %v, want %v", agg.Errors, tt.want)
+			}
+			for i := range tt.want {
+				if agg.Errors[i] != tt.want[i] {
+					t.Errorf("Errors[%d] = %v, want %v", i, agg.Errors[i], tt.want[i])
+				}
+			}
+			if err.Error() != tt.msg {
+				t.Errorf("Error() = %q, want %q", err.Error(), tt.msg)
+			}
+			for _, e := range tt.want {
+				if !errors.Is(err, e) {
+					t.Errorf("errors.Is(err, %v) = false", e)
+				}
+			}
+		})
+	}
+}
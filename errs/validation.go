@@ -0,0 +1,91 @@
+// Package errs holds the structured error types from chapter 10 in a form
+// other packages can import. Chapter 10 defines them in package main,
+// where they can only be used by that one program.
+package errs
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ValidationError reports one field that failed validation, following the
+// chapter 10 type of the same name. Line is set when the value came from a
+// file (1-based, 0 when not applicable), and Err optionally carries an
+// underlying cause for errors.Is and errors.As.
+type ValidationError struct {
+	Line    int         // Which input line had the problem
+	Field   string      // Which field had the problem
+	Message string      // What the problem was
+	Value   interface{} // What value caused the problem
+	Err     error       // Underlying cause, if any
+}
+
+func (e *ValidationError) Error() string {
+	msg := e.Message
+	if msg == "" && e.Err != nil {
+		msg = e.Err.Error()
+	}
+
+	var s string
+	if e.Field == "" {
+		s = fmt.Sprintf("validation failed: %s", msg)
+	} else {
+		s = fmt.Sprintf("validation failed for %s: %s", e.Field, msg)
+	}
+	if e.Value != nil {
+		s += fmt.Sprintf(" (value: %v)", e.Value)
+	}
+	if e.Line > 0 {
+		s = fmt.Sprintf("line %d: %s", e.Line, s)
+	}
+	return s
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// GetField returns the name of the invalid field.
+func (e *ValidationError) GetField() string {
+	return e.Field
+}
+
+// GetValue returns the rejected value.
+func (e *ValidationError) GetValue() interface{} {
+	return e.Value
+}
+
+// GetLine returns the input line, or 0 when not applicable.
+func (e *ValidationError) GetLine() int {
+	return e.Line
+}
+
+// IsValidationError reports whether any error in err's tree is a
+// *ValidationError.
+func IsValidationError(err error) bool {
+	var validationErr *ValidationError
+	return errors.As(err, &validationErr)
+}
+
+// ValidationErrors collects every *ValidationError in err's tree, including
+// those inside errors.Join results, in order.
+func ValidationErrors(err error) []*ValidationError {
+	var found []*ValidationError
+	var walk func(error)
+	walk = func(err error) {
+		switch e := err.(type) {
+		case nil:
+			return
+		case *ValidationError:
+			found = append(found, e)
+		case interface{ Unwrap() []error }:
+			for _, inner := range e.Unwrap() {
+				walk(inner)
+			}
+		case interface{ Unwrap() error }:
+			walk(e.Unwrap())
+		}
+	}
+	walk(err)
+	return found
+}
@@ -0,0 +1,136 @@
+package hardware
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"go-practice/codec"
+	"go-practice/errs"
+)
+
+// ErrUnknownPart is returned for an ID that isn't in the catalogue.
+var ErrUnknownPart = errors.New("unknown part")
+
+var registry = codec.NewRegistry()
+
+func init() {
+	registry.MustRegister("cpu", &CPU{})
+	registry.MustRegister("motherboard", &Motherboard{})
+	registry.MustRegister("memory", &Memory{})
+	registry.MustRegister("storage", &Storage{})
+	registry.MustRegister("gpu", &GPU{})
+	registry.MustRegister("psu", &PSU{})
+}
+
+// Catalogue is a set of components indexed by ID. It is read-only once
+// loaded, so it is safe for concurrent use.
+type Catalogue struct {
+	byID  map[string]Component
+	order []string // IDs in file order
+}
+
+//go:embed catalogue.json
+var defaultCatalogue []byte
+
+// DefaultCatalogue returns the catalogue built into the package, a small
+// selection of current desktop parts.
+func DefaultCatalogue() *Catalogue {
+	c, err := ParseCatalogue(defaultCatalogue)
+	if err != nil {
+		panic("hardware: built-in catalogue: " + err.Error())
+	}
+	return c
+}
+
+// LoadCatalogue reads a catalogue file; see ParseCatalogue.
+func LoadCatalogue(path string) (*Catalogue, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("hardware: %w", err)
+	}
+	return ParseCatalogue(data)
+}
+
+// ParseCatalogue decodes a JSON list of components, each with a "type"
+// of cpu, motherboard, memory, storage, gpu or psu. Entries that can't be
+// decoded, lack an ID or name, repeat an ID or have a negative price are
+// all reported together; the returned error holds a *codec.ItemError or
+// *errs.ValidationError for each.
+func ParseCatalogue(data []byte) (*Catalogue, error) {
+	parts, err := codec.Unmarshal[Component](registry, codec.JSON, data)
+	if err != nil {
+		return nil, fmt.Errorf("hardware: catalogue: %w", err)
+	}
+
+	c := &Catalogue{byID: make(map[string]Component, len(parts))}
+	var problems []error
+	for i, comp := range parts {
+		p := comp.part()
+		field := func(name string) string { return fmt.Sprintf("parts[%d].%s", i, name) }
+		switch {
+		case strings.TrimSpace(p.ID) == "":
+			problems = append(problems, &errs.ValidationError{Field: field("id"), Message: "is required"})
+			continue
+		case c.byID[p.ID] != nil:
+			problems = append(problems, &errs.ValidationError{Field: field("id"), Message: "is a duplicate", Value: p.ID})
+			continue
+		}
+		if strings.TrimSpace(p.Name) == "" {
+			problems = append(problems, &errs.ValidationError{Field: field("name"), Message: "is required"})
+		}
+		if p.Price < 0 {
+			problems = append(problems, &errs.ValidationError{Field: field("price"), Message: "must not be negative", Value: p.Price})
+		}
+		c.byID[p.ID] = comp
+		c.order = append(c.order, p.ID)
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("hardware: catalogue: %w", errors.Join(problems...))
+	}
+	return c, nil
+}
+
+// Get returns the component with the given ID, or ErrUnknownPart.
+func (c *Catalogue) Get(id string) (Component, error) {
+	comp, ok := c.byID[id]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownPart, id)
+	}
+	return comp, nil
+}
+
+// Lookup returns the component with the given ID as a P, such as *CPU,
+// failing if it is missing or of another kind.
+func Lookup[P Component](c *Catalogue, id string) (P, error) {
+	var zero P
+	comp, err := c.Get(id)
+	if err != nil {
+		return zero, err
+	}
+	p, ok := comp.(P)
+	if !ok {
+		return zero, fmt.Errorf("%q is a %s, not a %s", id, kindOf(comp), kindOf(zero))
+	}
+	return p, nil
+}
+
+// All returns every component of type P in file order, e.g.
+// All[*GPU](c).
+func All[P Component](c *Catalogue) []P {
+	var found []P
+	for _, id := range c.order {
+		if p, ok := c.byID[id].(P); ok {
+			found = append(found, p)
+		}
+	}
+	return found
+}
+
+// IDs returns every ID in file order.
+func (c *Catalogue) IDs() []string {
+	return slices.Clone(c.order)
+}
@@ -0,0 +1,129 @@
+package hardware
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+)
+
+// Power budget: the parts' TDPs plus BaseWatts for the motherboard,
+// drives and fans, with PowerHeadroom on top so the supply never runs
+// near its limit.
+const (
+	BaseWatts     = 75
+	PowerHeadroom = 1.25
+)
+
+// Compatibility rules, as reported in Incompatibility.Rule.
+const (
+	RuleSocket      = "socket"
+	RuleMemoryType  = "memory-type"
+	RuleMemorySlots = "memory-slots"
+	RuleMemorySize  = "memory-size"
+	RuleStorage     = "storage-slots"
+	RuleGraphics    = "graphics"
+	RulePower       = "power"
+)
+
+// Incompatibility is a rule a combination of parts breaks.
+type Incompatibility struct {
+	Rule    string   // one of the Rule constants
+	Parts   []string // IDs of the parts involved
+	Message string
+}
+
+func (e *Incompatibility) Error() string {
+	return fmt.Sprintf("incompatible %s: %s", e.Rule, e.Message)
+}
+
+// RequiredWatts returns the smallest power supply the PC should have.
+func (pc PC) RequiredWatts() int {
+	return int(math.Ceil(float64(pc.PowerDraw()) * PowerHeadroom))
+}
+
+// PowerDraw returns the estimated load in watts.
+func (pc PC) PowerDraw() int {
+	w := BaseWatts + pc.CPU.TDP
+	if pc.GPU != nil {
+		w += pc.GPU.TDP
+	}
+	return w
+}
+
+// Check returns an *Incompatibility for every rule the parts break,
+// joined with errors.Join, or nil if they work together. Every part but
+// the GPU must be present.
+func (pc PC) Check() error {
+	var found []error
+	bad := func(rule string, parts []string, format string, args ...any) {
+		found = append(found, &Incompatibility{Rule: rule, Parts: parts, Message: fmt.Sprintf(format, args...)})
+	}
+	cpu, board, mem, psu := pc.CPU, pc.Motherboard, pc.Memory, pc.PSU
+
+	if cpu.Socket != board.Socket {
+		bad(RuleSocket, []string{cpu.ID, board.ID},
+			"CPU %s (%s) doesn't fit motherboard %s (%s)", cpu.ID, cpu.Socket, board.ID, board.Socket)
+	}
+
+	if mem.MemoryType != board.MemoryType {
+		bad(RuleMemoryType, []string{mem.ID, board.ID},
+			"memory %s is %s but motherboard %s takes %s", mem.ID, mem.MemoryType, board.ID, board.MemoryType)
+	} else if !cpu.SupportsMemory(mem.MemoryType) {
+		bad(RuleMemoryType, []string{cpu.ID, mem.ID},
+			"CPU %s doesn't support %s", cpu.ID, mem.MemoryType)
+	}
+
+	if mem.Modules > board.MemorySlots {
+		bad(RuleMemorySlots, []string{mem.ID, board.ID},
+			"memory %s has %d modules but motherboard %s has %d slots", mem.ID, mem.Modules, board.ID, board.MemorySlots)
+	}
+	if mem.CapacityGB() > cpu.MaxMemoryGB {
+		bad(RuleMemorySize, []string{mem.ID, cpu.ID},
+			"memory %s is %d GB but CPU %s supports %d GB", mem.ID, mem.CapacityGB(), cpu.ID, cpu.MaxMemoryGB)
+	}
+	if mem.CapacityGB() > board.MaxMemoryGB {
+		bad(RuleMemorySize, []string{mem.ID, board.ID},
+			"memory %s is %d GB but motherboard %s supports %d GB", mem.ID, mem.CapacityGB(), board.ID, board.MaxMemoryGB)
+	}
+
+	slots := map[string]int{NVMe: board.M2Slots, SATA: board.SATAPorts}
+	drives := map[string][]string{}
+	for _, s := range pc.Storage {
+		drives[s.Interface] = append(drives[s.Interface], s.ID)
+	}
+	for _, iface := range []string{NVMe, SATA} {
+		if n := len(drives[iface]); n > slots[iface] {
+			bad(RuleStorage, append([]string{board.ID}, drives[iface]...),
+				"%d %s drives but motherboard %s has room for %d", n, iface, board.ID, slots[iface])
+		}
+	}
+	for _, iface := range slices.Sorted(maps.Keys(drives)) {
+		if ids := drives[iface]; iface != NVMe && iface != SATA {
+			bad(RuleStorage, ids,
+				"drive %s has unknown interface %q", ids[0], iface)
+		}
+	}
+
+	switch {
+	case pc.GPU == nil && !cpu.IntegratedGraphics:
+		bad(RuleGraphics, []string{cpu.ID},
+			"CPU %s has no integrated graphics, so a graphics card is needed", cpu.ID)
+	case pc.GPU != nil && board.PCIeX16 == 0:
+		bad(RuleGraphics, []string{board.ID, pc.GPU.ID},
+			"motherboard %s has no PCIe x16 slot for GPU %s", board.ID, pc.GPU.ID)
+	}
+
+	if need := pc.RequiredWatts(); psu.Wattage < need {
+		ids := []string{psu.ID, cpu.ID}
+		if pc.GPU != nil {
+			ids = append(ids, pc.GPU.ID)
+		}
+		bad(RulePower, ids,
+			"power supply %s gives %d W but the parts need %d W (%d W draw + %.0f%% headroom)",
+			psu.ID, psu.Wattage, need, pc.PowerDraw(), (PowerHeadroom-1)*100)
+	}
+
+	return errors.Join(found...)
+}
@@ -0,0 +1,162 @@
+package hardware
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"go-practice/errs"
+)
+
+// get looks id up in the default catalogue and returns a copy that a
+// test may change.
+func get[P interface {
+	Component
+	*T
+}, T any](t *testing.T, id string) P {
+	t.Helper()
+	p, err := Lookup[P](DefaultCatalogue(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := *p
+	return &c
+}
+
+// basePC breaks no rule: it uses the CPU's integrated graphics and has
+// room for more of everything.
+func basePC(t *testing.T) PC {
+	return PC{
+		CPU:         get[*CPU](t, "ryzen-5-7600"),
+		Motherboard: get[*Motherboard](t, "b650-plus"),
+		Memory:      get[*Memory](t, "ddr5-32-6000"),
+		Storage:     []*Storage{get[*Storage](t, "nvme-1tb")},
+		PSU:         get[*PSU](t, "psu-550"),
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(t *testing.T, pc *PC)
+		rule   string   // "" for no problem
+		parts  []string // of the one Incompatibility
+	}{
+		{"compatible", func(*testing.T, *PC) {}, "", nil},
+		{
+			name:   "CPU in another socket",
+			change: func(t *testing.T, pc *PC) { pc.CPU = get[*CPU](t, "core-i7-14700k") },
+			rule:   RuleSocket, parts: []string{"core-i7-14700k", "b650-plus"},
+		},
+		{
+			name:   "memory the board doesn't take",
+			change: func(t *testing.T, pc *PC) { pc.Memory = get[*Memory](t, "ddr4-16-3200") },
+			rule:   RuleMemoryType, parts: []string{"ddr4-16-3200", "b650-plus"},
+		},
+		{
+			name:   "memory the CPU doesn't support",
+			change: func(_ *testing.T, pc *PC) { pc.CPU.MemoryTypes = []string{"DDR4"} },
+			rule:   RuleMemoryType, parts: []string{"ryzen-5-7600", "ddr5-32-6000"},
+		},
+		{
+			name: "more modules than slots",
+			change: func(t *testing.T, pc *PC) {
+				pc.Motherboard = get[*Motherboard](t, "a620m")
+				pc.Memory.Modules, pc.Memory.ModuleGB = 4, 8
+			},
+			rule: RuleMemorySlots, parts: []string{"ddr5-32-6000", "a620m"},
+		},
+		{
+			name:   "more memory than the CPU supports",
+			change: func(_ *testing.T, pc *PC) { pc.Memory.ModuleGB = 96 },
+			rule:   RuleMemorySize, parts: []string{"ddr5-32-6000", "ryzen-5-7600"},
+		},
+		{
+			name: "more memory than the board supports",
+			change: func(t *testing.T, pc *PC) {
+				pc.Motherboard = get[*Motherboard](t, "a620m")
+				pc.Memory.ModuleGB = 64
+			},
+			rule: RuleMemorySize, parts: []string{"ddr5-32-6000", "a620m"},
+		},
+		{
+			name: "more drives than M.2 slots",
+			change: func(t *testing.T, pc *PC) {
+				pc.Motherboard = get[*Motherboard](t, "a620m")
+				pc.Storage = append(pc.Storage, get[*Storage](t, "nvme-2tb"))
+			},
+			rule: RuleStorage, parts: []string{"a620m", "nvme-1tb", "nvme-2tb"},
+		},
+		{
+			name:   "drive with an unknown interface",
+			change: func(_ *testing.T, pc *PC) { pc.Storage[0].Interface = "USB" },
+			rule:   RuleStorage, parts: []string{"nvme-1tb"},
+		},
+		{
+			name:   "no graphics at all",
+			change: func(_ *testing.T, pc *PC) { pc.CPU.IntegratedGraphics = false },
+			rule:   RuleGraphics, parts: []string{"ryzen-5-7600"},
+		},
+		{
+			name: "nowhere to put the graphics card",
+			change: func(t *testing.T, pc *PC) {
+				pc.Motherboard.PCIeX16 = 0
+				pc.GPU = get[*GPU](t, "rtx-4060")
+			},
+			rule: RuleGraphics, parts: []string{"b650-plus", "rtx-4060"},
+		},
+		{
+			// 65 + 320 + 75 W, with headroom 575 W
+			name:   "power supply too small",
+			change: func(t *testing.T, pc *PC) { pc.GPU = get[*GPU](t, "rtx-4080-super") },
+			rule:   RulePower, parts: []string{"psu-550", "ryzen-5-7600", "rtx-4080-super"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pc := basePC(t)
+			tt.change(t, &pc)
+			err := pc.Check()
+			if tt.rule == "" {
+				if err != nil {
+					t.Errorf("Check = %v, want nil", err)
+				}
+				return
+			}
+
+			var found []*Incompatibility
+			for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+				var inc *Incompatibility
+				if !errors.As(e, &inc) {
+					t.Fatalf("Check returned %T: %v", e, e)
+				}
+				found = append(found, inc)
+			}
+			if len(found) != 1 || found[0].Rule != tt.rule || !slices.Equal(found[0].Parts, tt.parts) {
+				t.Errorf("Check = %v, want one %s problem with %v", err, tt.rule, tt.parts)
+			}
+		})
+	}
+}
+
+func TestChooseReplacesFailure(t *testing.T) {
+	complete := func(pb *PCBuilder) *PCBuilder {
+		return pb.Motherboard("b650-plus").Memory("ddr5-32-6000").AddStorage("nvme-1tb").PSU("psu-550")
+	}
+	cat := DefaultCatalogue()
+
+	pc, err := complete(cat.NewPCBuilder().CPU("no-such-cpu").CPU("b650-plus").CPU("ryzen-5-7600")).Build()
+	if err != nil {
+		t.Fatalf("a good CPU after two bad ones: %v", err)
+	}
+	if pc.CPU.ID != "ryzen-5-7600" {
+		t.Errorf("CPU = %s", pc.CPU.ID)
+	}
+
+	// a bad pick after a good one is still reported, once
+	_, err = complete(cat.NewPCBuilder().CPU("ryzen-5-7600").CPU("no-such-cpu")).Build()
+	problems := errs.ValidationErrors(err)
+	if len(problems) != 1 || problems[0].Field != "CPU" || !errors.Is(err, ErrUnknownPart) {
+		t.Errorf("Build = %v, want one unknown CPU", err)
+	}
+}
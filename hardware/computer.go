@@ -0,0 +1,76 @@
+// Package hardware holds the Computer type that chapters 7 and 9 each
+// defined, together with the one ComputerBuilder they now share, and a
+// parts catalogue whose PCBuilder checks that the parts fit together.
+package hardware
+
+import "go-practice/builder"
+
+// Computer represents a computer system. RAM is in GB.
+type Computer struct {
+	CPU     string
+	RAM     int
+	Storage string
+	GPU     string
+}
+
+// Defaults for the fields a build may leave out.
+const (
+	DefaultStorage = "512GB SSD"
+	DefaultGPU     = "Integrated graphics"
+)
+
+var computerSpec = builder.NewSpec[Computer]("computer")
+
+var (
+	cpuField = builder.Required(computerSpec, "CPU",
+		func(c *Computer) *string { return &c.CPU }, builder.NotEmpty)
+	ramField = builder.Required(computerSpec, "RAM",
+		func(c *Computer) *int { return &c.RAM }, builder.Between(4, 1024))
+	storageField = builder.Optional(computerSpec, "Storage",
+		func(c *Computer) *string { return &c.Storage }, DefaultStorage, builder.NotEmpty)
+	gpuField = builder.Optional(computerSpec, "GPU",
+		func(c *Computer) *string { return &c.GPU }, DefaultGPU, builder.NotEmpty)
+)
+
+// ComputerBuilder assembles a Computer step by step. CPU and RAM (4 to
+// 1024 GB) are required; Storage and GPU default to DefaultStorage and
+// DefaultGPU.
+type ComputerBuilder struct {
+	b *builder.Builder[Computer]
+}
+
+func NewComputerBuilder() *ComputerBuilder {
+	return &ComputerBuilder{b: computerSpec.New()}
+}
+
+func (cb *ComputerBuilder) SetCPU(cpu string) *ComputerBuilder {
+	cb.b.Set(cpuField.To(cpu))
+	return cb
+}
+
+func (cb *ComputerBuilder) SetRAM(ram int) *ComputerBuilder {
+	cb.b.Set(ramField.To(ram))
+	return cb
+}
+
+func (cb *ComputerBuilder) SetStorage(storage string) *ComputerBuilder {
+	cb.b.Set(storageField.To(storage))
+	return cb
+}
+
+func (cb *ComputerBuilder) SetGPU(gpu string) *ComputerBuilder {
+	cb.b.Set(gpuField.To(gpu))
+	return cb
+}
+
+// Clone returns an independent copy, for building variants of one base
+// configuration.
+func (cb *ComputerBuilder) Clone() *ComputerBuilder {
+	return &ComputerBuilder{b: cb.b.Clone()}
+}
+
+// Build returns the Computer, or an *errs.AggregatedError listing every
+// missing or invalid field.
+func (cb *ComputerBuilder) Build() (Computer, error) {
+	return cb.b.Build()
+}
@@ -0,0 +1,85 @@
+package hardware_test
+
+import (
+	"errors"
+	"fmt"
+
+	"go-practice/errs"
+	"go-practice/hardware"
+)
+
+func ExampleComputerBuilder() {
+	computer, err := hardware.NewComputerBuilder().
+		SetCPU("Intel i9").
+		SetRAM(32).
+		SetGPU("RTX 4080").
+		Build()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Printf("%+v\n", computer)
+
+	// Build reports every missing or invalid field
+	_, err = hardware.NewComputerBuilder().SetRAM(0).Build()
+	for _, problem := range errs.ValidationErrors(err) {
+		fmt.Println(problem)
+	}
+	// Output:
+	// {CPU:Intel i9 RAM:32 Storage:512GB SSD GPU:RTX 4080}
+	// validation failed for CPU: is required
+	// validation failed for RAM: must be between 4 and 1024 (value: 0)
+}
+
+func ExamplePCBuilder() {
+	catalogue := hardware.DefaultCatalogue()
+	pc, err := catalogue.NewPCBuilder().
+		CPU("ryzen-5-7600").
+		Motherboard("b650-plus").
+		Memory("ddr5-32-6000").
+		AddStorage("nvme-1tb").
+		GPU("rtx-4060").
+		PSU("psu-550").
+		Build()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(pc)
+	fmt.Println("Gaming score:", pc.Score(hardware.Gaming))
+	// Output:
+	// cpu          AMD Ryzen 5 7600             $  199.00
+	// motherboard  B650 Plus ATX                $  179.00
+	// memory       32GB (2x16GB) DDR5-6000      $  109.00
+	// storage      1TB NVMe SSD                 $   79.00
+	// gpu          GeForce RTX 4060             $  299.00
+	// psu          550W 80+ Bronze              $   69.00
+	// total                                     $  934.00
+	// Gaming score: $934.00, 30334 points, 3248 per $100
+}
+
+// Every broken rule comes back as its own *Incompatibility, naming the
+// parts involved.
+func ExampleIncompatibility() {
+	_, err := hardware.DefaultCatalogue().NewPCBuilder().
+		CPU("core-i7-14700k").
+		Motherboard("b650-plus").
+		Memory("ddr4-16-3200").
+		AddStorage("nvme-1tb").
+		GPU("rtx-4080-super").
+		PSU("psu-550").
+		Build()
+	var agg *errs.AggregatedError
+	if errors.As(err, &agg) {
+		for _, problem := range agg.Errors {
+			var inc *hardware.Incompatibility
+			if errors.As(problem, &inc) {
+				fmt.Printf("[%s] %v\n", inc.Rule, inc.Parts)
+			}
+		}
+	}
+	// Output:
+	// [socket] [core-i7-14700k b650-plus]
+	// [memory-type] [ddr4-16-3200 b650-plus]
+	// [power] [psu-550 core-i7-14700k rtx-4080-super]
+}
@@ -0,0 +1,166 @@
+package hardware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"slices"
+)
+
+// Part is what every component has in common.
+type Part struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Price Cents  `json:"price"`
+}
+
+// Cents is an amount of US money in whole cents, so that prices add up
+// exactly. In JSON it is written in dollars, such as 199 or 74.99.
+type Cents int64
+
+// String formats c in dollars, such as "$74.99".
+func (c Cents) String() string {
+	if c < 0 {
+		return "-$" + (-c).dollars()
+	}
+	return "$" + c.dollars()
+}
+
+// dollars formats c without the dollar sign.
+func (c Cents) dollars() string {
+	sign := ""
+	if c < 0 {
+		sign, c = "-", -c
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, c/100, c%100)
+}
+
+func (c Cents) MarshalJSON() ([]byte, error) {
+	return []byte(c.dollars()), nil
+}
+
+// UnmarshalJSON reads a number of dollars, which must come to a whole
+// number of cents.
+func (c *Cents) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	var v any
+	if err := d.Decode(&v); err != nil {
+		return err
+	}
+	n, ok := v.(json.Number)
+	if !ok {
+		return fmt.Errorf("hardware: price %s is not a number", data)
+	}
+	r, _ := new(big.Rat).SetString(n.String())
+	r.Mul(r, big.NewRat(100, 1))
+	if !r.IsInt() || !r.Num().IsInt64() {
+		return fmt.Errorf("hardware: price %s is not a whole number of cents", n)
+	}
+	*c = Cents(r.Num().Int64())
+	return nil
+}
+
+func (p Part) part() Part { return p }
+
+// Component is any catalogue entry: *CPU, *Motherboard, *Memory,
+// *Storage, *GPU or *PSU.
+type Component interface {
+	part() Part
+}
+
+// Info returns the common fields of any component.
+func Info(c Component) Part { return c.part() }
+
+// CPU is a processor. TDP is its sustained power draw in watts, and Score
+// a benchmark result on a common scale with GPU and Storage scores.
+type CPU struct {
+	Part
+	Socket             string   `json:"socket"`
+	Cores              int      `json:"cores"`
+	TDP                int      `json:"tdp"`
+	MemoryTypes        []string `json:"memory_types"`
+	MaxMemoryGB        int      `json:"max_memory_gb"`
+	IntegratedGraphics bool     `json:"integrated_graphics"`
+	Score              int      `json:"score"`
+}
+
+// SupportsMemory reports whether the CPU's memory controller handles t.
+func (c *CPU) SupportsMemory(t string) bool {
+	return slices.Contains(c.MemoryTypes, t)
+}
+
+// Motherboard connects everything else. PCIeX16 counts the full-length
+// slots a graphics card can use.
+type Motherboard struct {
+	Part
+	Socket      string `json:"socket"`
+	MemoryType  string `json:"memory_type"`
+	MemorySlots int    `json:"memory_slots"`
+	MaxMemoryGB int    `json:"max_memory_gb"`
+	M2Slots     int    `json:"m2_slots"`
+	SATAPorts   int    `json:"sata_ports"`
+	PCIeX16     int    `json:"pcie_x16_slots"`
+}
+
+// Memory is a kit of identical modules.
+type Memory struct {
+	Part
+	MemoryType string `json:"memory_type"`
+	Modules    int    `json:"modules"`
+	ModuleGB   int    `json:"module_gb"`
+	SpeedMTs   int    `json:"speed_mts"`
+}
+
+// CapacityGB returns the kit's total size.
+func (m *Memory) CapacityGB() int { return m.Modules * m.ModuleGB }
+
+// Drive interfaces.
+const (
+	NVMe = "NVMe" // uses an M.2 slot
+	SATA = "SATA" // uses a SATA port
+)
+
+// Storage is an SSD or hard drive.
+type Storage struct {
+	Part
+	Interface  string `json:"interface"`
+	CapacityGB int    `json:"capacity_gb"`
+	Score      int    `json:"score"`
+}
+
+// GPU is a graphics card, which needs a PCIe x16 slot.
+type GPU struct {
+	Part
+	TDP   int `json:"tdp"`
+	Score int `json:"score"`
+}
+
+// PSU is a power supply.
+type PSU struct {
+	Part
+	Wattage int `json:"wattage"`
+}
+
+// kindOf names a component's type the way the catalogue file does.
+func kindOf(c Component) string {
+	switch c.(type) {
+	case *CPU:
+		return "cpu"
+	case *Motherboard:
+		return "motherboard"
+	case *Memory:
+		return "memory"
+	case *Storage:
+		return "storage"
+	case *GPU:
+		return "gpu"
+	case *PSU:
+		return "psu"
+	}
+	return fmt.Sprintf("%T", c)
+}
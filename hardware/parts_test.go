@@ -0,0 +1,58 @@
+package hardware
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCentsJSON(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Cents
+		ok   bool
+	}{
+		{"199", 19900, true},
+		{"74.99", 7499, true},
+		{"0.1", 10, true},
+		{"1.5e2", 15000, true},
+		{"-3.25", -325, true},
+		{"0.999", 0, false},
+		{"1e30", 0, false},
+		{`"5"`, 0, false},
+	}
+	for _, tt := range tests {
+		var c Cents
+		err := json.Unmarshal([]byte(tt.in), &c)
+		if (err == nil) != tt.ok || c != tt.want {
+			t.Errorf("Unmarshal(%s) = %d, %v; want %d", tt.in, c, err, tt.want)
+		}
+		if !tt.ok {
+			continue
+		}
+		out, _ := json.Marshal(c)
+		var back Cents
+		if err := json.Unmarshal(out, &back); err != nil || back != c {
+			t.Errorf("%d came back from %s as %d, %v", c, out, back, err)
+		}
+	}
+}
+
+func TestCentsString(t *testing.T) {
+	for c, want := range map[Cents]string{0: "$0.00", 5: "$0.05", 7499: "$74.99", 102300: "$1023.00", -325: "-$3.25"} {
+		if got := c.String(); got != want {
+			t.Errorf("Cents(%d).String() = %q, want %q", int64(c), got, want)
+		}
+	}
+}
+
+// TestPriceAddsUp sums prices that float64 dollars can't hold exactly.
+func TestPriceAddsUp(t *testing.T) {
+	pc := basePC(t)
+	for _, p := range []*Part{&pc.CPU.Part, &pc.Motherboard.Part, &pc.Memory.Part, &pc.Storage[0].Part, &pc.PSU.Part} {
+		p.Price = 10 // ten cents each
+	}
+	pc.Storage[0].Price = 20
+	if got := pc.Price(); got != 60 {
+		t.Errorf("Price = %v, want $0.60", got)
+	}
+}
@@ -0,0 +1,204 @@
+package hardware
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"go-practice/builder"
+	"go-practice/errs"
+)
+
+// PC is a complete build from catalogue parts. GPU is nil when the CPU's
+// integrated graphics are used.
+type PC struct {
+	CPU         *CPU
+	Motherboard *Motherboard
+	Memory      *Memory
+	Storage     []*Storage
+	GPU         *GPU
+	PSU         *PSU
+}
+
+// Parts returns every component in the build.
+func (pc PC) Parts() []Component {
+	parts := []Component{pc.CPU, pc.Motherboard, pc.Memory}
+	for _, s := range pc.Storage {
+		parts = append(parts, s)
+	}
+	if pc.GPU != nil {
+		parts = append(parts, pc.GPU)
+	}
+	return append(parts, pc.PSU)
+}
+
+// Price returns the total cost of the parts.
+func (pc PC) Price() Cents {
+	var total Cents
+	for _, p := range pc.Parts() {
+		total += p.part().Price
+	}
+	return total
+}
+
+// Computer summarizes the build in the free-text form of chapters 7
+// and 9.
+func (pc PC) Computer() Computer {
+	names := make([]string, len(pc.Storage))
+	for i, s := range pc.Storage {
+		names[i] = s.Name
+	}
+	gpu := DefaultGPU
+	if pc.GPU != nil {
+		gpu = pc.GPU.Name
+	}
+	return Computer{
+		CPU:     pc.CPU.Name,
+		RAM:     pc.Memory.CapacityGB(),
+		Storage: strings.Join(names, " + "),
+		GPU:     gpu,
+	}
+}
+
+var pcSpec = builder.NewSpec[PC]("pc")
+
+var (
+	pcCPU = builder.Required(pcSpec, "CPU",
+		func(pc *PC) **CPU { return &pc.CPU })
+	pcMotherboard = builder.Required(pcSpec, "Motherboard",
+		func(pc *PC) **Motherboard { return &pc.Motherboard })
+	pcMemory = builder.Required(pcSpec, "Memory",
+		func(pc *PC) **Memory { return &pc.Memory })
+	pcStorage = builder.Required(pcSpec, "Storage",
+		func(pc *PC) *[]*Storage { return &pc.Storage }, atLeastOneDrive)
+	pcGPU = builder.Optional(pcSpec, "GPU",
+		func(pc *PC) **GPU { return &pc.GPU }, nil)
+	pcPSU = builder.Required(pcSpec, "PSU",
+		func(pc *PC) **PSU { return &pc.PSU })
+)
+
+func init() {
+	pcSpec.Check(PC.Check)
+}
+
+func atLeastOneDrive(s []*Storage) error {
+	if len(s) == 0 {
+		return errors.New("needs at least one drive")
+	}
+	return nil
+}
+
+// PCBuilder assembles a PC from catalogue IDs. Build reports unknown
+// IDs, parts of the wrong kind, missing parts and every compatibility
+// problem together.
+type PCBuilder struct {
+	cat      *Catalogue
+	b        *builder.Builder[PC]
+	storage  []*Storage
+	problems []error // lookup failures, by field
+}
+
+// NewPCBuilder returns an empty builder choosing parts from c.
+func (c *Catalogue) NewPCBuilder() *PCBuilder {
+	return &PCBuilder{cat: c, b: pcSpec.New()}
+}
+
+// choose looks id up as a P and sets field to it, or records why it can't.
+func choose[P Component](pb *PCBuilder, field builder.Field[PC, P], id string) *PCBuilder {
+	p, err := Lookup[P](pb.cat, id)
+	if err != nil {
+		pb.problems = append(pb.problems, &errs.ValidationError{
+			Field: field.Name(), Err: err,
+		})
+		return pb
+	}
+	// a later good choice replaces an earlier bad one
+	pb.problems = slices.DeleteFunc(pb.problems, func(err error) bool {
+		ve, ok := err.(*errs.ValidationError)
+		return ok && ve.Field == field.Name()
+	})
+	pb.b.Set(field.To(p))
+	return pb
+}
+
+// CPU chooses the processor by catalogue ID. Motherboard, Memory, GPU and
+// PSU choose the other parts the same way.
+func (pb *PCBuilder) CPU(id string) *PCBuilder { return choose(pb, pcCPU, id) }
+
+func (pb *PCBuilder) Motherboard(id string) *PCBuilder { return choose(pb, pcMotherboard, id) }
+
+func (pb *PCBuilder) Memory(id string) *PCBuilder { return choose(pb, pcMemory, id) }
+
+func (pb *PCBuilder) GPU(id string) *PCBuilder { return choose(pb, pcGPU, id) }
+
+func (pb *PCBuilder) PSU(id string) *PCBuilder { return choose(pb, pcPSU, id) }
+
+// AddStorage adds a drive; a PC may have several.
+func (pb *PCBuilder) AddStorage(id string) *PCBuilder {
+	s, err := Lookup[*Storage](pb.cat, id)
+	if err != nil {
+		pb.problems = append(pb.problems, &errs.ValidationError{
+			Field: pcStorage.Name(), Err: err,
+		})
+		return pb
+	}
+	pb.storage = append(pb.storage, s)
+	pb.b.Set(pcStorage.To(slices.Clone(pb.storage)))
+	return pb
+}
+
+// Clone returns an independent copy, for trying variants of a build.
+func (pb *PCBuilder) Clone() *PCBuilder {
+	return &PCBuilder{
+		cat:      pb.cat,
+		b:        pb.b.Clone(),
+		storage:  slices.Clone(pb.storage),
+		problems: slices.Clone(pb.problems),
+	}
+}
+
+// Build returns the PC, or an *errs.AggregatedError holding an
+// *errs.ValidationError for each unusable or missing part and an
+// *Incompatibility for each broken compatibility rule. Compatibility is
+// only checked once every part is present.
+func (pb *PCBuilder) Build() (PC, error) {
+	pc, err := pb.b.Build()
+	if err == nil && len(pb.problems) == 0 {
+		return pc, nil
+	}
+
+	problems := slices.Clone(pb.problems)
+	var agg *errs.AggregatedError
+	switch {
+	case errors.As(err, &agg):
+		for _, e := range agg.Errors {
+			// a part that failed to look up is already reported
+			if ve, ok := e.(*errs.ValidationError); ok && pb.failed(ve.Field) {
+				continue
+			}
+			problems = append(problems, e)
+		}
+	case err != nil:
+		problems = append(problems, err)
+	}
+	return PC{}, errs.Aggregate("build pc", problems...)
+}
+
+func (pb *PCBuilder) failed(field string) bool {
+	return slices.ContainsFunc(pb.problems, func(err error) bool {
+		ve, ok := err.(*errs.ValidationError)
+		return ok && ve.Field == field
+	})
+}
+
+// String lists the parts, one per line, with prices.
+func (pc PC) String() string {
+	var b strings.Builder
+	for _, p := range pc.Parts() {
+		info := p.part()
+		fmt.Fprintf(&b, "%-12s %-28s $%8s\n", kindOf(p), info.Name, info.Price.dollars())
+	}
+	fmt.Fprintf(&b, "%-12s %-28s $%8s", "total", "", pc.Price().dollars())
+	return b.String()
+}
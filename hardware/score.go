@@ -0,0 +1,60 @@
+package hardware
+
+import "fmt"
+
+// IntegratedGPUScore rates a CPU's built-in graphics on the GPU scale.
+const IntegratedGPUScore = 1500
+
+// Weights sets how much each kind of part counts towards a build's
+// performance, so the same build can be judged for different uses.
+type Weights struct {
+	CPU, GPU, Memory, Storage float64
+}
+
+// Presets for common uses.
+var (
+	Gaming      = Weights{CPU: 0.5, GPU: 1.5, Memory: 0.2, Storage: 0.1}
+	Office      = Weights{CPU: 1, GPU: 0.1, Memory: 0.3, Storage: 0.3}
+	Workstation = Weights{CPU: 1.5, GPU: 0.5, Memory: 0.5, Storage: 0.3}
+)
+
+// Score rates a build. PerDollar is performance points per $100, the
+// number to compare when choosing between builds.
+type Score struct {
+	Price       Cents
+	Performance float64
+	PerDollar   float64
+}
+
+func (s Score) String() string {
+	return fmt.Sprintf("%v, %.0f points, %.0f per $100", s.Price, s.Performance, s.PerDollar)
+}
+
+// Score rates the build with w. Memory scores capacity times speed, and
+// storage scores the fastest drive only.
+func (pc PC) Score(w Weights) Score {
+	gpu := 0
+	switch {
+	case pc.GPU != nil:
+		gpu = pc.GPU.Score
+	case pc.CPU.IntegratedGraphics:
+		gpu = IntegratedGPUScore
+	}
+	storage := 0
+	for _, s := range pc.Storage {
+		storage = max(storage, s.Score)
+	}
+	memory := pc.Memory.CapacityGB() * pc.Memory.SpeedMTs / 100
+
+	s := Score{
+		Price: pc.Price(),
+		Performance: w.CPU*float64(pc.CPU.Score) +
+			w.GPU*float64(gpu) +
+			w.Memory*float64(memory) +
+			w.Storage*float64(storage),
+	}
+	if s.Price > 0 {
+		s.PerDollar = s.Performance / (float64(s.Price) / 100) * 100
+	}
+	return s
+}
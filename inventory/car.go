@@ -0,0 +1,104 @@
+// Package inventory keeps a dealer's stock of the chapter 7 Car. Each
+// vehicle is keyed by its VIN, which must pass the check-digit test, and
+// carries an asking price with its full history and an optional hold
+// that expires on its own. The stock is saved through a Database, the
+// chapter 8 interface, and served to people by cmd/inventory and to
+// programs by Handler.
+package inventory
+
+import (
+	"time"
+
+	"go-practice/builder"
+)
+
+// Car represents a car.
+type Car struct {
+	Brand string `json:"brand"`
+	Model string `json:"model"`
+	Year  int    `json:"year"`
+	Color string `json:"color"`
+}
+
+// DefaultColor is the color of a car built without one.
+const DefaultColor = "White"
+
+// FirstCarYear is the year of the first production car, the earliest
+// Year a Car may have.
+const FirstCarYear = 1886
+
+var carSpec = builder.NewSpec[Car]("car")
+
+// carClock supplies the current year for carYear; tests replace it.
+var carClock = time.Now
+
+// modelYear accepts years from FirstCarYear to next year's models. Next
+// year is worked out on every call, so a long-running server accepts new
+// models after New Year without a restart.
+func modelYear(year int) error {
+	return builder.Between(FirstCarYear, carClock().Year()+1)(year)
+}
+
+var (
+	carBrand = builder.Required(carSpec, "Brand",
+		func(c *Car) *string { return &c.Brand }, builder.NotEmpty)
+	carModel = builder.Required(carSpec, "Model",
+		func(c *Car) *string { return &c.Model }, builder.NotEmpty)
+	carYear = builder.Required(carSpec, "Year",
+		func(c *Car) *int { return &c.Year }, modelYear)
+	carColor = builder.Optional(carSpec, "Color",
+		func(c *Car) *string { return &c.Color }, DefaultColor, builder.NotEmpty)
+)
+
+// CarBuilder assembles a Car step by step. Brand, Model and Year (from
+// FirstCarYear to next year's models) are required; Color defaults to
+// DefaultColor.
+type CarBuilder struct {
+	b *builder.Builder[Car]
+}
+
+func NewCarBuilder() *CarBuilder {
+	return &CarBuilder{b: carSpec.New()}
+}
+
+func (cb *CarBuilder) SetBrand(brand string) *CarBuilder {
+	cb.b.Set(carBrand.To(brand))
+	return cb
+}
+
+func (cb *CarBuilder) SetModel(model string) *CarBuilder {
+	cb.b.Set(carModel.To(model))
+	return cb
+}
+
+func (cb *CarBuilder) SetYear(year int) *CarBuilder {
+	cb.b.Set(carYear.To(year))
+	return cb
+}
+
+func (cb *CarBuilder) SetColor(color string) *CarBuilder {
+	cb.b.Set(carColor.To(color))
+	return cb
+}
+
+// Clone returns an independent copy, for building variants of one base
+// configuration.
+func (cb *CarBuilder) Clone() *CarBuilder {
+	return &CarBuilder{b: cb.b.Clone()}
+}
+
+// Build returns the Car, or an *errs.AggregatedError listing every
+// missing or invalid field.
+func (cb *CarBuilder) Build() (Car, error) {
+	return cb.b.Build()
+}
+
+// normalize runs c through a CarBuilder, so a Car that didn't come from
+// one gets the same checks and default color.
+func (c Car) normalize() (Car, error) {
+	cb := NewCarBuilder().SetBrand(c.Brand).SetModel(c.Model).SetYear(c.Year)
+	if c.Color != "" {
+		cb.SetColor(c.Color)
+	}
+	return cb.Build()
+}
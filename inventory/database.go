@@ -0,0 +1,200 @@
+package inventory
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Database is the chapter 8 database interface. The inventory speaks a
+// small key-value language to it, one command per Query:
+//
+//	GET <key>           the value stored under key
+//	PUT <key> <value>   store value, which may contain spaces
+//	DELETE <key>        remove key
+//	KEYS <prefix>       every key starting with prefix, sorted, one per line
+//
+// Keys never contain whitespace. MemoryDatabase and FileDatabase
+// implement it; anything else that understands these commands can be
+// used in their place.
+type Database interface {
+	Connect() error
+	Query(query string) (string, error)
+	Close()
+	GetType() string
+}
+
+var (
+	// ErrNotConnected is returned by a query before Connect or after
+	// Close.
+	ErrNotConnected = errors.New("database not connected")
+
+	// ErrNoKey is returned by GET and DELETE for a missing key.
+	ErrNoKey = errors.New("no such key")
+
+	// ErrBadQuery is returned for a query that isn't one of the commands.
+	ErrBadQuery = errors.New("bad query")
+)
+
+// execute runs query against data and reports whether it changed data.
+func execute(data map[string]string, query string) (result string, changed bool, err error) {
+	cmd, rest, _ := strings.Cut(query, " ")
+	switch cmd {
+	case "GET":
+		v, ok := data[rest]
+		if !ok {
+			return "", false, fmt.Errorf("%w: %s", ErrNoKey, rest)
+		}
+		return v, false, nil
+	case "PUT":
+		key, value, ok := strings.Cut(rest, " ")
+		if !ok || key == "" {
+			return "", false, fmt.Errorf("%w: PUT needs a key and a value", ErrBadQuery)
+		}
+		data[key] = value
+		return "", true, nil
+	case "DELETE":
+		if _, ok := data[rest]; !ok {
+			return "", false, fmt.Errorf("%w: %s", ErrNoKey, rest)
+		}
+		delete(data, rest)
+		return "", true, nil
+	case "KEYS":
+		var keys []string
+		for _, k := range slices.Sorted(maps.Keys(data)) {
+			if strings.HasPrefix(k, rest) {
+				keys = append(keys, k)
+			}
+		}
+		return strings.Join(keys, "\n"), false, nil
+	}
+	return "", false, fmt.Errorf("%w: unknown command %q", ErrBadQuery, cmd)
+}
+
+// MemoryDatabase keeps everything in memory, for demos and for tools
+// that don't need the stock to outlive them. The data survives Close,
+// so a closed database can be connected again. It is safe for
+// concurrent use.
+type MemoryDatabase struct {
+	mu        sync.Mutex
+	data      map[string]string
+	connected bool
+}
+
+func (m *MemoryDatabase) Connect() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.data == nil {
+		m.data = make(map[string]string)
+	}
+	m.connected = true
+	return nil
+}
+
+func (m *MemoryDatabase) Query(query string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.connected {
+		return "", ErrNotConnected
+	}
+	result, _, err := execute(m.data, query)
+	return result, err
+}
+
+func (m *MemoryDatabase) Close() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.connected = false
+}
+
+func (m *MemoryDatabase) GetType() string { return "memory" }
+
+// FileDatabase keeps its data in a JSON file at Path. Connect reads the
+// file, or starts empty if there is none, and every change rewrites it
+// through a temporary file, so a crash leaves the old or the new
+// contents and never half of each. It is safe for concurrent use within
+// one process.
+type FileDatabase struct {
+	Path string
+
+	mu   sync.Mutex
+	data map[string]string // nil when not connected
+}
+
+func (f *FileDatabase) Connect() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	data := make(map[string]string)
+	raw, err := os.ReadFile(f.Path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return err
+	default:
+		if err := json.Unmarshal(raw, &data); err != nil {
+			return fmt.Errorf("%s: %w", f.Path, err)
+		}
+	}
+	f.data = data
+	return nil
+}
+
+func (f *FileDatabase) Query(query string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.data == nil {
+		return "", ErrNotConnected
+	}
+
+	// Only PUT and DELETE need a copy to undo a failed save, and they
+	// rewrite the whole file anyway. GET, which Open runs for every
+	// vehicle, copies nothing.
+	var before map[string]string
+	if cmd, _, _ := strings.Cut(query, " "); cmd == "PUT" || cmd == "DELETE" {
+		before = maps.Clone(f.data)
+	}
+	result, changed, err := execute(f.data, query)
+	if err != nil || !changed {
+		return result, err
+	}
+	if err := f.save(); err != nil {
+		f.data = before
+		return "", err
+	}
+	return result, nil
+}
+
+func (f *FileDatabase) save() error {
+	raw, err := json.MarshalIndent(f.data, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(f.Path), filepath.Base(f.Path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // fails harmlessly after the rename
+	if _, err := tmp.Write(append(raw, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), f.Path)
+}
+
+func (f *FileDatabase) Close() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.data = nil
+}
+
+func (f *FileDatabase) GetType() string { return "file" }
@@ -0,0 +1,89 @@
+package inventory
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileDatabase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stock.json")
+	db := &FileDatabase{Path: path}
+	if _, err := db.Query("GET a"); !errors.Is(err, ErrNotConnected) {
+		t.Fatalf("query before Connect: %v", err)
+	}
+	if err := db.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	for _, q := range []string{"PUT a 1", "PUT b two words", "DELETE a"} {
+		if _, err := db.Query(q); err != nil {
+			t.Fatalf("%s: %v", q, err)
+		}
+	}
+	db.Close()
+
+	// A second database reads what the first one saved.
+	again := &FileDatabase{Path: path}
+	if err := again.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := again.Query("GET b"); err != nil || got != "two words" {
+		t.Errorf("GET b = %q, %v", got, err)
+	}
+	if _, err := again.Query("GET a"); !errors.Is(err, ErrNoKey) {
+		t.Errorf("GET a after DELETE: %v", err)
+	}
+}
+
+// TestFileDatabaseFailedSave checks that a change the file couldn't take
+// is undone in memory too.
+func TestFileDatabaseFailedSave(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "gone")
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	db := &FileDatabase{Path: filepath.Join(dir, "stock.json")}
+	if err := db.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Query("PUT a 1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, q := range []string{"PUT a 2", "PUT b 3", "DELETE a"} {
+		if _, err := db.Query(q); err == nil {
+			t.Fatalf("%s saved into a missing directory", q)
+		}
+	}
+	if got, err := db.Query("KEYS "); err != nil || got != "a" {
+		t.Errorf("keys after failed saves = %q, %v", got, err)
+	}
+	if got, err := db.Query("GET a"); err != nil || got != "1" {
+		t.Errorf("GET a = %q, %v; want the value from before the failures", got, err)
+	}
+}
+
+// TestFileDatabaseReadsDontCopy guards Open, which runs a GET for every
+// vehicle: copying the data for each would make it quadratic.
+func TestFileDatabaseReadsDontCopy(t *testing.T) {
+	db := &FileDatabase{Path: filepath.Join(t.TempDir(), "stock.json")}
+	if err := db.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	for i := range 1000 {
+		db.data[fmt.Sprintf("k%d", i)] = "v"
+	}
+	allocs := testing.AllocsPerRun(100, func() {
+		if _, err := db.Query("GET k500"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("GET made %v allocations, want 0", allocs)
+	}
+}
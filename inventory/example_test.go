@@ -0,0 +1,103 @@
+package inventory_test
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go-practice/errs"
+	"go-practice/inventory"
+)
+
+func ExampleCarBuilder() {
+	car, err := inventory.NewCarBuilder().
+		SetBrand("Tesla").
+		SetModel("Model 3").
+		SetYear(2024).
+		SetColor("Red").
+		Build()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Printf("%+v\n", car)
+
+	// Build checks every field and reports all the problems at once
+	_, err = inventory.NewCarBuilder().SetModel("Model T").SetYear(1850).Build()
+	for _, problem := range errs.ValidationErrors(err) {
+		fmt.Println("invalid:", problem.Field)
+	}
+	// Output:
+	// {Brand:Tesla Model:Model 3 Year:2024 Color:Red}
+	// invalid: Brand
+	// invalid: Year
+}
+
+// Clone copies a configured builder, so one base can make several variants.
+func ExampleCarBuilder_Clone() {
+	base := inventory.NewCarBuilder().SetBrand("Tesla").SetModel("Model Y").SetYear(2025)
+	for _, color := range []string{"Blue", "Black"} {
+		variant, err := base.Clone().SetColor(color).Build()
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		fmt.Printf("%+v\n", variant)
+	}
+	defaultColor, err := base.Build()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Printf("%+v\n", defaultColor)
+	// Output:
+	// {Brand:Tesla Model:Model Y Year:2025 Color:Blue}
+	// {Brand:Tesla Model:Model Y Year:2025 Color:Black}
+	// {Brand:Tesla Model:Model Y Year:2025 Color:White}
+}
+
+func ExampleInventory() {
+	stock, err := inventory.Open(&inventory.MemoryDatabase{})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer stock.Close()
+
+	for _, v := range []struct {
+		vin   string
+		car   inventory.Car
+		price int
+	}{
+		{"5YJ3E1EA9PF000001", inventory.Car{Brand: "Tesla", Model: "Model 3", Year: 2024, Color: "Red"}, 38000},
+		{"5YJYGDEE4SF000002", inventory.Car{Brand: "Tesla", Model: "Model Y", Year: 2025, Color: "Blue"}, 45000},
+		{"1FA6P8TH6R5100003", inventory.Car{Brand: "Ford", Model: "Mustang", Year: 2024, Color: "Red"}, 41000},
+	} {
+		if _, err := stock.Add(v.vin, v.car, v.price); err != nil {
+			fmt.Println(err)
+		}
+	}
+	if _, err := stock.SetPrice("5YJ3E1EA9PF000001", 35500); err != nil {
+		fmt.Println(err)
+	}
+
+	// A hold blocks other customers until it expires
+	if _, err := stock.Reserve("5YJYGDEE4SF000002", "Alice", 24*time.Hour); err != nil {
+		fmt.Println(err)
+	}
+	_, err = stock.Reserve("5YJYGDEE4SF000002", "Bob", 0)
+	fmt.Println("Bob blocked:", errors.Is(err, inventory.ErrOnHold))
+
+	for _, v := range stock.Search(inventory.Make("tesla"), inventory.Available()) {
+		fmt.Printf("Available: %s %d %s %s, $%d (was $%d)\n",
+			v.VIN, v.Car.Year, v.Car.Brand, v.Car.Model, v.Price, v.History[0].Price)
+	}
+
+	// The VIN's check digit catches most typos
+	_, err = stock.Add("5YJ3E1EA0PF000001", inventory.Car{Brand: "Tesla", Model: "Model 3", Year: 2024}, 38000)
+	fmt.Println(err)
+	// Output:
+	// Bob blocked: true
+	// Available: 5YJ3E1EA9PF000001 2024 Tesla Model 3, $35500 (was $38000)
+	// add vehicle: 1 problem: validation failed for VIN: check digit is 0 but should be 9 (value: 5YJ3E1EA0PF000001)
+}
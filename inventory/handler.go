@@ -0,0 +1,283 @@
+package inventory
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-practice/errs"
+)
+
+// Handler serves an Inventory as JSON. It is a chapter 8 HTTPHandler,
+// taking a method and flat string parameters, and also an http.Handler
+// with these routes under its endpoint:
+//
+//	GET    /vehicles?make=&model=&color=&year_min=&year_max=&price_min=&price_max=&available=
+//	POST   /vehicles              vin, brand, model, year, color, price
+//	GET    /vehicles/{vin}
+//	DELETE /vehicles/{vin}        customer, if the vehicle is held
+//	PUT    /vehicles/{vin}/price  price
+//	POST   /vehicles/{vin}/hold   customer, for (a duration such as 72h)
+//	DELETE /vehicles/{vin}/hold   customer
+//
+// Parameters come from the query string and from a form or JSON object
+// body. Through Handle, the path's VIN and last element are the "vin"
+// and "action" parameters, and neither can be overridden by the query
+// string or body.
+type Handler struct {
+	inv      *Inventory
+	endpoint string
+}
+
+// NewHandler serves inv under endpoint, such as "/vehicles".
+func NewHandler(inv *Inventory, endpoint string) *Handler {
+	return &Handler{inv: inv, endpoint: strings.TrimSuffix(endpoint, "/")}
+}
+
+func (h *Handler) GetEndpoint() string { return h.endpoint }
+
+// Handle runs one request and returns the JSON response. Failures are
+// {"error": ..., "status": ...} with the HTTP status ServeHTTP would use.
+func (h *Handler) Handle(method string, params map[string]string) string {
+	status, body := h.route(method, params)
+	if status >= 400 {
+		body = map[string]any{"error": body, "status": status}
+	}
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Sprintf(`{"error":%q,"status":500}`, err)
+	}
+	return string(raw)
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	params, err := requestParams(r, h.endpoint)
+	var status int
+	var body any
+	switch {
+	case errors.Is(err, errNoRoute):
+		status, body = http.StatusNotFound, err.Error()
+	case err != nil:
+		status, body = http.StatusBadRequest, err.Error()
+	default:
+		status, body = h.route(r.Method, params)
+	}
+	if status >= 400 {
+		body = map[string]any{"error": body}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(body)
+}
+
+var errNoRoute = errors.New("no such route")
+
+// requestParams flattens the path, query string and body of r into
+// Handle's parameters. The path decides the route, so "action" comes only
+// from the path, and the path's VIN wins over a "vin" in the query string
+// or body; a "vin" there is used only by POST to the bare endpoint.
+func requestParams(r *http.Request, endpoint string) (map[string]string, error) {
+	rest, ok := strings.CutPrefix(r.URL.Path, endpoint)
+	if !ok || (rest != "" && rest[0] != '/') {
+		return nil, errNoRoute
+	}
+	var vin, action string
+	switch parts := strings.Split(strings.Trim(rest, "/"), "/"); len(parts) {
+	case 2:
+		action = parts[1]
+		fallthrough
+	case 1:
+		vin = parts[0]
+	default:
+		return nil, errNoRoute
+	}
+
+	params, err := queryAndBody(r)
+	if err != nil {
+		return nil, err
+	}
+	delete(params, "action")
+	if action != "" {
+		params["action"] = action
+	}
+	if vin != "" {
+		params["vin"] = vin
+	}
+	return params, nil
+}
+
+// queryAndBody flattens the query string and the form or JSON object body
+// of r, the body winning.
+func queryAndBody(r *http.Request) (map[string]string, error) {
+	params := make(map[string]string)
+	for k, v := range r.URL.Query() {
+		params[k] = v[0]
+	}
+	if r.Body == nil || r.ContentLength == 0 {
+		return params, nil
+	}
+	if mt, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mt == "application/json" {
+		dec := json.NewDecoder(r.Body)
+		dec.UseNumber()
+		var fields map[string]any
+		if err := dec.Decode(&fields); err != nil {
+			return nil, fmt.Errorf("request body: %w", err)
+		}
+		for k, v := range fields {
+			params[k] = fmt.Sprint(v)
+		}
+		return params, nil
+	}
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+	for k, v := range r.PostForm {
+		params[k] = v[0]
+	}
+	return params, nil
+}
+
+// route runs one request and returns its HTTP status and the value to
+// send, or the error message for a failure.
+func (h *Handler) route(method string, params map[string]string) (int, any) {
+	p := paramReader{params: params}
+	vin := params["vin"]
+	var (
+		result any
+		err    error
+		status = http.StatusOK
+	)
+	switch action := params["action"]; {
+	case method == http.MethodGet && vin == "" && action == "":
+		filters := h.filters(&p)
+		if err = p.err(); err == nil {
+			found := h.inv.Search(filters...)
+			if found == nil {
+				found = []Vehicle{} // [] rather than null
+			}
+			result = found
+		}
+	case method == http.MethodPost && vin != "" && action == "":
+		car := Car{Brand: params["brand"], Model: params["model"], Color: params["color"], Year: p.int("year")}
+		price := p.int("price")
+		if err = p.err(); err == nil {
+			result, err = h.inv.Add(vin, car, price)
+			status = http.StatusCreated
+		}
+	case method == http.MethodGet && vin != "" && action == "":
+		result, err = h.inv.Get(vin)
+	case method == http.MethodDelete && vin != "" && action == "":
+		err = h.inv.Remove(vin, params["customer"])
+		result = map[string]string{"removed": vin}
+	case method == http.MethodPut && vin != "" && action == "price":
+		price := p.int("price")
+		if err = p.err(); err == nil {
+			result, err = h.inv.SetPrice(vin, price)
+		}
+	case method == http.MethodPost && vin != "" && action == "hold":
+		d := p.duration("for")
+		if err = p.err(); err == nil {
+			result, err = h.inv.Reserve(vin, params["customer"], d)
+		}
+	case method == http.MethodDelete && vin != "" && action == "hold":
+		err = h.inv.Release(vin, params["customer"])
+		result = map[string]string{"released": vin}
+	default:
+		return http.StatusNotFound, fmt.Sprintf("no route for %s with vin %q and action %q", method, vin, params["action"])
+	}
+	if err != nil {
+		return statusOf(err), err.Error()
+	}
+	return status, result
+}
+
+func (h *Handler) filters(p *paramReader) []Filter {
+	var filters []Filter
+	if v := p.params["make"]; v != "" {
+		filters = append(filters, Make(v))
+	}
+	if v := p.params["model"]; v != "" {
+		filters = append(filters, Model(v))
+	}
+	if v := p.params["color"]; v != "" {
+		filters = append(filters, Color(v))
+	}
+	if min, max := p.int("year_min"), p.int("year_max"); min != 0 || max != 0 {
+		filters = append(filters, YearBetween(min, max))
+	}
+	if min, max := p.int("price_min"), p.int("price_max"); min != 0 || max != 0 {
+		filters = append(filters, PriceBetween(min, max))
+	}
+	if p.bool("available") {
+		filters = append(filters, Available())
+	}
+	return filters
+}
+
+// statusOf picks the HTTP status for an inventory error.
+func statusOf(err error) int {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrDuplicate), errors.Is(err, ErrOnHold):
+		return http.StatusConflict
+	case errs.IsValidationError(err):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// paramReader converts parameters, collecting a ValidationError for each
+// one that doesn't parse. Missing parameters read as zero.
+type paramReader struct {
+	params   map[string]string
+	problems []error
+}
+
+func (p *paramReader) int(name string) int {
+	s := p.params[name]
+	if s == "" {
+		return 0
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		p.problems = append(p.problems, &errs.ValidationError{Field: name, Message: "must be a whole number", Value: s})
+	}
+	return n
+}
+
+func (p *paramReader) bool(name string) bool {
+	s := p.params[name]
+	if s == "" {
+		return false
+	}
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		p.problems = append(p.problems, &errs.ValidationError{Field: name, Message: "must be true or false", Value: s})
+	}
+	return b
+}
+
+func (p *paramReader) duration(name string) time.Duration {
+	s := p.params[name]
+	if s == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		p.problems = append(p.problems, &errs.ValidationError{Field: name, Message: "must be a duration such as 72h", Value: s})
+	}
+	return d
+}
+
+func (p *paramReader) err() error {
+	return errs.Aggregate("bad parameters", p.problems...)
+}
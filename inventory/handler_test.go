@@ -0,0 +1,58 @@
+package inventory
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestHandlerPathWins checks that the query string and body can't change
+// the VIN or action the path picked.
+func TestHandlerPathWins(t *testing.T) {
+	inv, err := Open(&MemoryDatabase{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := NewHandler(inv, "/vehicles")
+	const red, blue = "5YJ3E1EA9PF000001", "5YJYGDEE4SF000002"
+
+	steps := []struct {
+		name, method, target, body string
+		want                       int
+	}{
+		{"vin from the body of a bare POST", "POST", "/vehicles",
+			`{"vin": "` + red + `", "brand": "Tesla", "model": "Model 3", "year": 2024, "price": 38000}`, http.StatusCreated},
+		{"vin in the path", "POST", "/vehicles/" + blue,
+			`{"brand": "Tesla", "model": "Model Y", "year": 2025, "price": 45000}`, http.StatusCreated},
+		{"body vin doesn't redirect a price change", "PUT", "/vehicles/" + red + "/price",
+			`{"vin": "` + blue + `", "price": 35500}`, http.StatusOK},
+		{"query vin doesn't redirect a price change", "PUT", "/vehicles/" + red + "/price?vin=" + blue,
+			`{"price": 35000}`, http.StatusOK},
+		{"hold", "POST", "/vehicles/" + red + "/hold", `{"customer": "Alice", "for": "24h"}`, http.StatusOK},
+		{"query action doesn't turn an add into a hold", "POST", "/vehicles/" + blue + "?action=hold",
+			`{"customer": "Bob"}`, http.StatusBadRequest}, // an add with no car
+		{"query action doesn't turn a removal into a release", "DELETE", "/vehicles/" + red + "?action=hold&customer=Alice",
+			"", http.StatusOK},
+		{"removed", "GET", "/vehicles/" + red, "", http.StatusNotFound},
+	}
+	for _, s := range steps {
+		r := httptest.NewRequest(s.method, s.target, strings.NewReader(s.body))
+		if s.body != "" {
+			r.Header.Set("Content-Type", "application/json")
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		if w.Code != s.want {
+			t.Fatalf("%s: %s %s = %d %s, want %d", s.name, s.method, s.target, w.Code, w.Body, s.want)
+		}
+	}
+
+	v, err := inv.Get(blue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Price != 45000 || v.Hold != nil {
+		t.Errorf("the other vehicle changed: price %d, hold %+v", v.Price, v.Hold)
+	}
+}
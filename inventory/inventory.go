@@ -0,0 +1,346 @@
+package inventory
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"go-practice/errs"
+)
+
+var (
+	// ErrNotFound is returned for a VIN that isn't in stock.
+	ErrNotFound = errors.New("vehicle not found")
+
+	// ErrDuplicate is returned by Add for a VIN already in stock.
+	ErrDuplicate = errors.New("vehicle already in stock")
+
+	// ErrOnHold is returned when another customer holds the vehicle.
+	ErrOnHold = errors.New("vehicle is on hold")
+)
+
+// DefaultHold is how long a reservation lasts when no duration is given.
+const DefaultHold = 48 * time.Hour
+
+// PricePoint is one asking price and when it was set.
+type PricePoint struct {
+	Price int       `json:"price"`
+	Set   time.Time `json:"set"`
+}
+
+// Hold reserves a vehicle for a customer until Expires. An expired hold
+// is simply ignored, so nothing has to run to clear it.
+type Hold struct {
+	Customer string    `json:"customer"`
+	Expires  time.Time `json:"expires"`
+}
+
+// Vehicle is one car in stock. Prices are whole dollars. History holds
+// every asking price, oldest first, so its last entry is Price.
+type Vehicle struct {
+	VIN     string       `json:"vin"`
+	Car     Car          `json:"car"`
+	Price   int          `json:"price"`
+	History []PricePoint `json:"history"`
+	Hold    *Hold        `json:"hold,omitempty"`
+}
+
+// OnHold reports whether the vehicle is reserved at time t.
+func (v Vehicle) OnHold(t time.Time) bool {
+	return v.Hold != nil && t.Before(v.Hold.Expires)
+}
+
+const keyPrefix = "vehicle/"
+
+// Inventory is the stock, held in memory and written through to a
+// Database on every change. It is safe for concurrent use.
+type Inventory struct {
+	// Now returns the current time, for setting prices and holds. It
+	// defaults to time.Now; set it before using the inventory.
+	Now func() time.Time
+
+	mu       sync.Mutex
+	db       Database
+	vehicles map[string]Vehicle
+}
+
+// Open connects to db and loads the stock saved in it.
+func Open(db Database) (*Inventory, error) {
+	if err := db.Connect(); err != nil {
+		return nil, fmt.Errorf("inventory: %w", err)
+	}
+	inv := &Inventory{Now: time.Now, db: db, vehicles: make(map[string]Vehicle)}
+
+	keys, err := db.Query("KEYS " + keyPrefix)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("inventory: %w", err)
+	}
+	for key := range strings.FieldsSeq(keys) {
+		raw, err := db.Query("GET " + key)
+		if err != nil {
+			db.Close()
+			return nil, fmt.Errorf("inventory: %w", err)
+		}
+		var v Vehicle
+		if err := json.Unmarshal([]byte(raw), &v); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("inventory: %s: %w", key, err)
+		}
+		inv.vehicles[v.VIN] = v
+	}
+	return inv, nil
+}
+
+// Close closes the database.
+func (inv *Inventory) Close() {
+	inv.db.Close()
+}
+
+// save writes v to the database and then to memory, so a failed write
+// leaves both unchanged. The caller holds inv.mu.
+func (inv *Inventory) save(v Vehicle) error {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if _, err := inv.db.Query("PUT " + keyPrefix + v.VIN + " " + string(raw)); err != nil {
+		return fmt.Errorf("inventory: save %s: %w", v.VIN, err)
+	}
+	inv.vehicles[v.VIN] = v
+	return nil
+}
+
+// lookup returns the stored vehicle with the given VIN. The caller holds
+// inv.mu.
+func (inv *Inventory) lookup(vin string) (Vehicle, error) {
+	vin, err := ParseVIN(vin)
+	if err != nil {
+		return Vehicle{}, err
+	}
+	v, ok := inv.vehicles[vin]
+	if !ok {
+		return Vehicle{}, fmt.Errorf("%w: %s", ErrNotFound, vin)
+	}
+	return v, nil
+}
+
+// current returns a copy of v safe to hand out, without an expired hold.
+func (inv *Inventory) current(v Vehicle) Vehicle {
+	v.History = slices.Clone(v.History)
+	if v.Hold != nil {
+		if v.OnHold(inv.Now()) {
+			hold := *v.Hold
+			v.Hold = &hold
+		} else {
+			v.Hold = nil
+		}
+	}
+	return v
+}
+
+// Add puts a car into stock at the given price. The car gets the same
+// checks as CarBuilder.Build, and the VIN those of ParseVIN.
+func (inv *Inventory) Add(vin string, car Car, price int) (Vehicle, error) {
+	var problems []error
+	vin, err := ParseVIN(vin)
+	if err != nil {
+		problems = append(problems, err)
+	}
+	car, err = car.normalize()
+	if err != nil {
+		problems = append(problems, err)
+	}
+	if price <= 0 {
+		problems = append(problems, &errs.ValidationError{Field: "Price", Message: "must be positive", Value: price})
+	}
+	if len(problems) > 0 {
+		return Vehicle{}, errs.Aggregate("add vehicle", problems...)
+	}
+
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+	if _, ok := inv.vehicles[vin]; ok {
+		return Vehicle{}, fmt.Errorf("%w: %s", ErrDuplicate, vin)
+	}
+	v := Vehicle{
+		VIN:     vin,
+		Car:     car,
+		Price:   price,
+		History: []PricePoint{{Price: price, Set: inv.Now()}},
+	}
+	if err := inv.save(v); err != nil {
+		return Vehicle{}, err
+	}
+	return inv.current(v), nil
+}
+
+// Get returns the vehicle with the given VIN.
+func (inv *Inventory) Get(vin string) (Vehicle, error) {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+	v, err := inv.lookup(vin)
+	if err != nil {
+		return Vehicle{}, err
+	}
+	return inv.current(v), nil
+}
+
+// Remove takes a vehicle out of stock, when it is sold. A vehicle held
+// by someone other than customer can't be removed.
+func (inv *Inventory) Remove(vin, customer string) error {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+	v, err := inv.lookup(vin)
+	if err != nil {
+		return err
+	}
+	if v.OnHold(inv.Now()) && v.Hold.Customer != customer {
+		return fmt.Errorf("%w by %s", ErrOnHold, v.Hold.Customer)
+	}
+	if _, err := inv.db.Query("DELETE " + keyPrefix + v.VIN); err != nil {
+		return fmt.Errorf("inventory: remove %s: %w", v.VIN, err)
+	}
+	delete(inv.vehicles, v.VIN)
+	return nil
+}
+
+// SetPrice changes the asking price and records it in the history.
+func (inv *Inventory) SetPrice(vin string, price int) (Vehicle, error) {
+	if price <= 0 {
+		return Vehicle{}, &errs.ValidationError{Field: "Price", Message: "must be positive", Value: price}
+	}
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+	v, err := inv.lookup(vin)
+	if err != nil {
+		return Vehicle{}, err
+	}
+	if price == v.Price {
+		return inv.current(v), nil
+	}
+	v.Price = price
+	v.History = append(slices.Clone(v.History), PricePoint{Price: price, Set: inv.Now()})
+	if err := inv.save(v); err != nil {
+		return Vehicle{}, err
+	}
+	return inv.current(v), nil
+}
+
+// Reserve holds a vehicle for customer for d, or DefaultHold if d is 0.
+// The customer who holds it may extend the hold; anyone else gets
+// ErrOnHold until it expires.
+func (inv *Inventory) Reserve(vin, customer string, d time.Duration) (Hold, error) {
+	if customer == "" {
+		return Hold{}, &errs.ValidationError{Field: "Customer", Message: "is required"}
+	}
+	if d < 0 {
+		return Hold{}, &errs.ValidationError{Field: "Duration", Message: "can't be negative", Value: d}
+	}
+	if d == 0 {
+		d = DefaultHold
+	}
+
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+	v, err := inv.lookup(vin)
+	if err != nil {
+		return Hold{}, err
+	}
+	now := inv.Now()
+	if v.OnHold(now) && v.Hold.Customer != customer {
+		return Hold{}, fmt.Errorf("%w by %s until %s", ErrOnHold, v.Hold.Customer, v.Hold.Expires.Format(time.RFC3339))
+	}
+	v.Hold = &Hold{Customer: customer, Expires: now.Add(d)}
+	if err := inv.save(v); err != nil {
+		return Hold{}, err
+	}
+	return *v.Hold, nil
+}
+
+// Release ends customer's hold on a vehicle. Releasing a vehicle that
+// isn't held, or whose hold has expired, does nothing.
+func (inv *Inventory) Release(vin, customer string) error {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+	v, err := inv.lookup(vin)
+	if err != nil {
+		return err
+	}
+	if !v.OnHold(inv.Now()) {
+		return nil
+	}
+	if v.Hold.Customer != customer {
+		return fmt.Errorf("%w by %s", ErrOnHold, v.Hold.Customer)
+	}
+	v.Hold = nil
+	return inv.save(v)
+}
+
+// Filter selects vehicles in a search.
+type Filter func(v Vehicle, now time.Time) bool
+
+// Make matches a brand, ignoring case.
+func Make(brand string) Filter {
+	return func(v Vehicle, _ time.Time) bool { return strings.EqualFold(v.Car.Brand, brand) }
+}
+
+// Model matches a model, ignoring case.
+func Model(model string) Filter {
+	return func(v Vehicle, _ time.Time) bool { return strings.EqualFold(v.Car.Model, model) }
+}
+
+// Color matches a color, ignoring case.
+func Color(color string) Filter {
+	return func(v Vehicle, _ time.Time) bool { return strings.EqualFold(v.Car.Color, color) }
+}
+
+// YearBetween matches model years from min to max inclusive. A zero
+// bound is open.
+func YearBetween(min, max int) Filter {
+	return func(v Vehicle, _ time.Time) bool {
+		return (min == 0 || v.Car.Year >= min) && (max == 0 || v.Car.Year <= max)
+	}
+}
+
+// PriceBetween matches asking prices from min to max inclusive. A zero
+// bound is open.
+func PriceBetween(min, max int) Filter {
+	return func(v Vehicle, _ time.Time) bool {
+		return (min == 0 || v.Price >= min) && (max == 0 || v.Price <= max)
+	}
+}
+
+// Available matches vehicles nobody holds.
+func Available() Filter {
+	return func(v Vehicle, now time.Time) bool { return !v.OnHold(now) }
+}
+
+// Search returns the vehicles matching every filter, ordered by brand,
+// model, year and VIN.
+func (inv *Inventory) Search(filters ...Filter) []Vehicle {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+
+	now := inv.Now()
+	var found []Vehicle
+	for _, v := range inv.vehicles {
+		if !slices.ContainsFunc(filters, func(f Filter) bool { return !f(v, now) }) {
+			found = append(found, inv.current(v))
+		}
+	}
+	slices.SortFunc(found, func(a, b Vehicle) int {
+		return cmp.Or(
+			cmp.Compare(a.Car.Brand, b.Car.Brand),
+			cmp.Compare(a.Car.Model, b.Car.Model),
+			cmp.Compare(a.Car.Year, b.Car.Year),
+			cmp.Compare(a.VIN, b.VIN),
+		)
+	})
+	return found
+}
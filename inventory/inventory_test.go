@@ -0,0 +1,274 @@
+package inventory
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"go-practice/errs"
+)
+
+// testClock is a settable Inventory.Now.
+type testClock struct{ now time.Time }
+
+func (c *testClock) Now() time.Time          { return c.now }
+func (c *testClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+var start = time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+// at returns the time d after start.
+func at(d time.Duration) time.Time { return start.Add(d) }
+
+// newTestInventory returns an empty inventory over db whose clock reads
+// start until the test moves it.
+func newTestInventory(t *testing.T, db Database) (*Inventory, *testClock) {
+	t.Helper()
+	inv, err := Open(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(inv.Close)
+	clock := &testClock{now: start}
+	inv.Now = clock.Now
+	return inv, clock
+}
+
+const (
+	malibu20 = "1G1ZD5ST8LF000001"
+	malibu19 = "1G1ZD5STXLF000002"
+	camaro   = "1G1ZD5ST1LF000003"
+	f150a    = "1FTFW1E51NFA00001"
+	f150b    = "1FTFW1E53NFA00002"
+	model3   = "5YJ3E1EAXPF000010"
+)
+
+func addStock(t *testing.T, inv *Inventory) {
+	t.Helper()
+	for _, s := range []struct {
+		vin   string
+		car   Car
+		price int
+	}{
+		{f150b, Car{Brand: "Ford", Model: "F-150", Year: 2022, Color: "Black"}, 41000},
+		{malibu20, Car{Brand: "Chevrolet", Model: "Malibu", Year: 2020, Color: "Red"}, 22000},
+		{model3, Car{Brand: "Tesla", Model: "Model 3", Year: 2023, Color: "Red"}, 38000},
+		{camaro, Car{Brand: "Chevrolet", Model: "Camaro", Year: 2021, Color: "Red"}, 30000},
+		{f150a, Car{Brand: "Ford", Model: "F-150", Year: 2022}, 40000},
+		{malibu19, Car{Brand: "Chevrolet", Model: "Malibu", Year: 2019, Color: "Blue"}, 18000},
+	} {
+		if _, err := inv.Add(s.vin, s.car, s.price); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestCarYearFollowsTheClock(t *testing.T) {
+	t.Cleanup(func() { carClock = time.Now })
+	build := func(year int) error {
+		_, err := NewCarBuilder().SetBrand("Tesla").SetModel("Model Y").SetYear(year).Build()
+		return err
+	}
+
+	carClock = func() time.Time { return time.Date(2025, 12, 31, 23, 59, 0, 0, time.UTC) }
+	if err := build(2026); err != nil {
+		t.Errorf("2026 on the last day of 2025: %v", err)
+	}
+	if err := build(2027); err == nil {
+		t.Error("2027 on the last day of 2025: expected an error")
+	}
+
+	carClock = func() time.Time { return time.Date(2026, 1, 1, 0, 1, 0, 0, time.UTC) }
+	if err := build(2027); err != nil {
+		t.Errorf("2027 on the first day of 2026: %v", err)
+	}
+	if err := build(FirstCarYear - 1); err == nil {
+		t.Errorf("%d: expected an error", FirstCarYear-1)
+	}
+}
+
+func TestParseVIN(t *testing.T) {
+	tests := []struct {
+		in, want string
+		problem  string // the ValidationError's Message, when invalid
+	}{
+		{in: "1HGCM82633A004352", want: "1HGCM82633A004352"},
+		{in: " 1hgcm82633a004352\n", want: "1HGCM82633A004352"},
+		{in: "1M8GDM9AXKP042788", want: "1M8GDM9AXKP042788"}, // check digit X
+		{in: "1m8gdm9axkp042788", want: "1M8GDM9AXKP042788"},
+		{in: "1M8GDM9A0KP042788", problem: "check digit is 0 but should be X"},
+		{in: "1HGCM826X3A004352", problem: "check digit is X but should be 3"},
+		{in: "1HGCM82643A004352", problem: "check digit is 4 but should be 3"},
+		{in: "1HGCM82633A00435", problem: "must be 17 characters, not 16"},
+		{in: "", problem: "must be 17 characters, not 0"},
+		{in: "1HGCM82633A0O4352", problem: `character 13, 'O', isn't allowed`},
+		{in: "IHGCM82633A004352", problem: `character 1, 'I', isn't allowed`},
+		{in: "1HGCM82633Q004352", problem: `character 11, 'Q', isn't allowed`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseVIN(tt.in)
+			if tt.problem == "" {
+				if err != nil || got != tt.want {
+					t.Errorf("ParseVIN = %q, %v; want %q", got, err, tt.want)
+				}
+				return
+			}
+			var v *errs.ValidationError
+			if !errors.As(err, &v) || !errors.Is(err, ErrInvalidVIN) {
+				t.Fatalf("ParseVIN error = %v, want a ValidationError wrapping ErrInvalidVIN", err)
+			}
+			if v.Field != "VIN" || v.Message != tt.problem || v.Value != tt.in {
+				t.Errorf("got %s %q (value %q), want VIN %q (value %q)", v.Field, v.Message, v.Value, tt.problem, tt.in)
+			}
+		})
+	}
+}
+
+func TestHolds(t *testing.T) {
+	inv, clock := newTestInventory(t, &MemoryDatabase{})
+	addStock(t, inv)
+
+	steps := []struct {
+		at       time.Duration // since start
+		name     string
+		do       func() error
+		wantErr  error
+		expires  time.Duration // of model3's hold afterwards, 0 for none
+		customer string
+	}{
+		{0, "alice reserves", func() error { _, err := inv.Reserve(model3, "alice", 24*time.Hour); return err },
+			nil, 24 * time.Hour, "alice"},
+		{time.Hour, "bob can't reserve", func() error { _, err := inv.Reserve(model3, "bob", 0); return err },
+			ErrOnHold, 24 * time.Hour, "alice"},
+		{12 * time.Hour, "alice extends", func() error { _, err := inv.Reserve(model3, "alice", 24*time.Hour); return err },
+			nil, 36 * time.Hour, "alice"},
+		{30 * time.Hour, "bob still can't reserve", func() error { _, err := inv.Reserve(model3, "bob", 0); return err },
+			ErrOnHold, 36 * time.Hour, "alice"},
+		{30 * time.Hour, "bob can't release", func() error { return inv.Release(model3, "bob") },
+			ErrOnHold, 36 * time.Hour, "alice"},
+		{30 * time.Hour, "bob can't buy", func() error { return inv.Remove(model3, "bob") },
+			ErrOnHold, 36 * time.Hour, "alice"},
+		{36 * time.Hour, "expired at the deadline", func() error { return nil },
+			nil, 0, ""},
+		{36 * time.Hour, "releasing an expired hold does nothing", func() error { return inv.Release(model3, "bob") },
+			nil, 0, ""},
+		{37 * time.Hour, "bob reserves for the default time", func() error { _, err := inv.Reserve(model3, "bob", 0); return err },
+			nil, 37*time.Hour + DefaultHold, "bob"},
+		{38 * time.Hour, "bob releases", func() error { return inv.Release(model3, "bob") },
+			nil, 0, ""},
+	}
+	for _, s := range steps {
+		clock.now = at(s.at)
+		if err := s.do(); !errors.Is(err, s.wantErr) {
+			t.Fatalf("%s: %v, want %v", s.name, err, s.wantErr)
+		}
+		v, err := inv.Get(model3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		switch {
+		case s.expires == 0 && v.Hold != nil:
+			t.Errorf("%s: held %+v, want no hold", s.name, *v.Hold)
+		case s.expires != 0 && (v.Hold == nil || !v.Hold.Expires.Equal(at(s.expires)) || v.Hold.Customer != s.customer):
+			t.Errorf("%s: held %+v, want by %s until %v", s.name, v.Hold, s.customer, at(s.expires))
+		}
+	}
+
+	// bad reservations change nothing
+	if _, err := inv.Reserve(malibu20, "", time.Hour); !errs.IsValidationError(err) {
+		t.Errorf("reserving without a customer: %v", err)
+	}
+	if _, err := inv.Reserve(malibu20, "alice", -time.Hour); !errs.IsValidationError(err) {
+		t.Errorf("reserving for a negative time: %v", err)
+	}
+	if got := inv.Search(Available()); len(got) != 6 {
+		t.Errorf("%d vehicles available, want 6", len(got))
+	}
+}
+
+func TestPriceHistory(t *testing.T) {
+	db := &MemoryDatabase{}
+	inv, clock := newTestInventory(t, db)
+	addStock(t, inv)
+
+	clock.Advance(24 * time.Hour)
+	if _, err := inv.SetPrice(model3, 36000); err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(time.Hour)
+	if _, err := inv.SetPrice(model3, 36000); err != nil { // unchanged, not recorded
+		t.Fatal(err)
+	}
+	clock.Advance(24 * time.Hour)
+	v, err := inv.SetPrice(model3, 35000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := inv.SetPrice(model3, 0); !errs.IsValidationError(err) {
+		t.Errorf("a zero price: %v", err)
+	}
+
+	want := []PricePoint{
+		{Price: 38000, Set: at(0)},
+		{Price: 36000, Set: at(24 * time.Hour)},
+		{Price: 35000, Set: at(49 * time.Hour)},
+	}
+	if v.Price != 35000 || !slices.EqualFunc(v.History, want, samePoint) {
+		t.Errorf("price %d, history %v; want 35000, %v", v.Price, v.History, want)
+	}
+
+	// the history handed out is a copy
+	v.History[0].Price = 1
+	if again, _ := inv.Get(model3); again.History[0].Price != 38000 {
+		t.Errorf("changing a returned history changed the stock: %v", again.History)
+	}
+
+	// and it is saved
+	inv.Close()
+	reopened, _ := newTestInventory(t, db)
+	if again, err := reopened.Get(model3); err != nil || !slices.EqualFunc(again.History, want, samePoint) {
+		t.Errorf("after reopening: %v, %v; want %v", again.History, err, want)
+	}
+}
+
+func samePoint(a, b PricePoint) bool { return a.Price == b.Price && a.Set.Equal(b.Set) }
+
+func TestSearch(t *testing.T) {
+	inv, clock := newTestInventory(t, &MemoryDatabase{})
+	addStock(t, inv)
+	if _, err := inv.Reserve(camaro, "alice", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		at      time.Duration
+		filters []Filter
+		want    []string
+	}{
+		{"everything by brand, model, year and VIN", 0, nil,
+			[]string{camaro, malibu19, malibu20, f150a, f150b, model3}},
+		{"make ignores case", 0, []Filter{Make("chevrolet")}, []string{camaro, malibu19, malibu20}},
+		{"model", 0, []Filter{Model("F-150")}, []string{f150a, f150b}},
+		{"default color", 0, []Filter{Color("white")}, []string{f150a}},
+		{"color and price", 0, []Filter{Color("Red"), PriceBetween(25000, 0)}, []string{camaro, model3}},
+		{"years", 0, []Filter{YearBetween(2020, 2022)}, []string{camaro, malibu20, f150a, f150b}},
+		{"open price bound", 0, []Filter{PriceBetween(0, 22000)}, []string{malibu19, malibu20}},
+		{"held isn't available", 0, []Filter{Available(), Make("Chevrolet")}, []string{malibu19, malibu20}},
+		{"available after the hold", time.Hour, []Filter{Available(), Make("Chevrolet")}, []string{camaro, malibu19, malibu20}},
+		{"nothing", 0, []Filter{Make("Saab")}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock.now = at(tt.at)
+			var got []string
+			for _, v := range inv.Search(tt.filters...) {
+				got = append(got, v.VIN)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Search = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
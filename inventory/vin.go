@@ -0,0 +1,75 @@
+package inventory
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"go-practice/errs"
+)
+
+// ErrInvalidVIN is wrapped by every VIN validation failure.
+var ErrInvalidVIN = errors.New("invalid VIN")
+
+// vinWeights are the position weights of the check-digit sum. Position
+// 9, the check digit itself, has weight 0.
+var vinWeights = [17]int{8, 7, 6, 5, 4, 3, 2, 10, 0, 9, 8, 7, 6, 5, 4, 3, 2}
+
+// vinValue transliterates one VIN character to its number for the
+// check-digit sum, or returns -1. I, O and Q are never used, being too
+// easy to mistake for 1 and 0.
+func vinValue(c byte) int {
+	switch {
+	case c >= '0' && c <= '9':
+		return int(c - '0')
+	case c >= 'A' && c <= 'H':
+		return int(c-'A') + 1
+	case c >= 'J' && c <= 'N':
+		return int(c-'J') + 1
+	case c == 'P':
+		return 7
+	case c == 'R':
+		return 9
+	case c >= 'S' && c <= 'Z':
+		return int(c-'S') + 2
+	}
+	return -1
+}
+
+// checkDigit returns the character that belongs in position 9 of vin.
+func checkDigit(vin string) byte {
+	sum := 0
+	for i := range len(vin) {
+		sum += vinValue(vin[i]) * vinWeights[i]
+	}
+	if sum%11 == 10 {
+		return 'X'
+	}
+	return byte('0' + sum%11)
+}
+
+// ParseVIN normalizes s to upper case and checks that it is a 17
+// character VIN with a correct check digit in position 9, as every North
+// American VIN has. Failures are *errs.ValidationError values wrapping
+// ErrInvalidVIN.
+func ParseVIN(s string) (string, error) {
+	vin := strings.ToUpper(strings.TrimSpace(s))
+	invalid := func(format string, args ...any) error {
+		return &errs.ValidationError{
+			Field: "VIN", Message: fmt.Sprintf(format, args...), Value: s, Err: ErrInvalidVIN,
+		}
+	}
+
+	if len(vin) != 17 {
+		return "", invalid("must be 17 characters, not %d", len(vin))
+	}
+	for i := range len(vin) {
+		if vinValue(vin[i]) < 0 {
+			return "", invalid("character %d, %q, isn't allowed", i+1, vin[i])
+		}
+	}
+	if want := checkDigit(vin); vin[8] != want {
+		return "", invalid("check digit is %c but should be %c", vin[8], want)
+	}
+	return vin, nil
+}
@@ -0,0 +1,139 @@
+// Package lifecycle keeps the chapter 7 Person as a history of events
+// rather than a struct that is changed in place. Every change is an Event
+// appended to a Log; a person's current state is whatever replaying
+// their events gives, so the log doubles as an audit trail and any
+// earlier state can be rebuilt. Snapshots save replaying long histories
+// from the start.
+package lifecycle
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go-practice/codec"
+)
+
+// Person is the chapter 7 Person.
+type Person struct {
+	Name     string
+	Age      int
+	Email    string
+	IsActive bool
+}
+
+// Event is one change to a person. Apply returns p with the change made;
+// it never fails, because events record what already happened and were
+// checked before they were appended.
+type Event interface {
+	EventType() string
+	Apply(p Person) Person
+}
+
+// Registered starts every person's history.
+type Registered struct {
+	Name  string `json:"name"`
+	Age   int    `json:"age"`
+	Email string `json:"email"`
+}
+
+func (Registered) EventType() string { return "Registered" }
+
+func (e Registered) Apply(Person) Person {
+	return Person{Name: e.Name, Age: e.Age, Email: e.Email, IsActive: true}
+}
+
+// BirthdayCelebrated records the age reached, so replaying it twice by
+// mistake can't age anyone two years.
+type BirthdayCelebrated struct {
+	Age int `json:"age"`
+}
+
+func (BirthdayCelebrated) EventType() string { return "BirthdayCelebrated" }
+
+func (e BirthdayCelebrated) Apply(p Person) Person {
+	p.Age = e.Age
+	return p
+}
+
+// EmailChanged keeps the old address as well as the new one for the
+// audit trail.
+type EmailChanged struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+func (EmailChanged) EventType() string { return "EmailChanged" }
+
+func (e EmailChanged) Apply(p Person) Person {
+	p.Email = e.To
+	return p
+}
+
+// Deactivated ends a person's history; no events may follow it.
+type Deactivated struct {
+	Reason string `json:"reason,omitempty"`
+}
+
+func (Deactivated) EventType() string { return "Deactivated" }
+
+func (e Deactivated) Apply(p Person) Person {
+	p.IsActive = false
+	return p
+}
+
+// events maps each event type's name to its Go type for the log's JSON.
+var events = codec.NewRegistry()
+
+func init() {
+	for _, e := range []Event{Registered{}, BirthdayCelebrated{}, EmailChanged{}, Deactivated{}} {
+		events.MustRegister(e.EventType(), e)
+	}
+}
+
+// Record is an event as stored in the log. Version numbers a person's
+// events from 1 with no gaps.
+type Record struct {
+	PersonID string
+	Version  int
+	Time     time.Time
+	Event    Event
+}
+
+type recordJSON struct {
+	PersonID string          `json:"person"`
+	Version  int             `json:"version"`
+	Time     time.Time       `json:"time"`
+	Event    json.RawMessage `json:"event"`
+}
+
+// MarshalJSON writes the record with its event's type name, such as
+// {"person":"p1","version":2,...,"event":{"type":"EmailChanged",...}}.
+func (r Record) MarshalJSON() ([]byte, error) {
+	if r.Event == nil {
+		return nil, fmt.Errorf("lifecycle: record %s/%d has no event", r.PersonID, r.Version)
+	}
+	event, err := events.MarshalValue(r.Event)
+	if err != nil {
+		return nil, fmt.Errorf("lifecycle: %w", err)
+	}
+	return json.Marshal(recordJSON{
+		PersonID: r.PersonID,
+		Version:  r.Version,
+		Time:     r.Time,
+		Event:    event,
+	})
+}
+
+func (r *Record) UnmarshalJSON(data []byte) error {
+	var j recordJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	event, err := codec.UnmarshalValue[Event](events, j.Event)
+	if err != nil {
+		return fmt.Errorf("lifecycle: %w", err)
+	}
+	*r = Record{PersonID: j.PersonID, Version: j.Version, Time: j.Time, Event: event}
+	return nil
+}
@@ -0,0 +1,62 @@
+package lifecycle_test
+
+import (
+	"errors"
+	"fmt"
+
+	"go-practice/lifecycle"
+)
+
+func Example() {
+	people := lifecycle.New(&lifecycle.MemoryLog{})
+	alice := lifecycle.Person{Name: "Alice", Age: 26, Email: "alice@example.com", IsActive: true}
+	if _, err := people.Register("alice", alice); err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	// Each change appends an event instead of overwriting fields
+	changes := []func() (lifecycle.Snapshot, error){
+		func() (lifecycle.Snapshot, error) { return people.CelebrateBirthday("alice") },
+		func() (lifecycle.Snapshot, error) { return people.ChangeEmail("alice", "alice@work.example.com") },
+		func() (lifecycle.Snapshot, error) { return people.Deactivate("alice", "moved away") },
+	}
+	var now lifecycle.Snapshot
+	for _, change := range changes {
+		var err error
+		if now, err = change(); err != nil {
+			fmt.Println(err)
+			return
+		}
+	}
+	fmt.Printf("Now, version %d: %+v\n", now.Version, now.Person)
+
+	// Replaying only the first events shows the person as they were
+	then, err := people.AsOf("alice", 1)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Printf("Version 1: %+v\n", then.Person)
+
+	history, err := people.History("alice")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for _, r := range history {
+		fmt.Printf("  %d. %s %+v\n", r.Version, r.Event.EventType(), r.Event)
+	}
+
+	// A deactivated person can't change again
+	_, err = people.CelebrateBirthday("alice")
+	fmt.Println(err, errors.Is(err, lifecycle.ErrInactive))
+	// Output:
+	// Now, version 4: {Name:Alice Age:27 Email:alice@work.example.com IsActive:false}
+	// Version 1: {Name:Alice Age:26 Email:alice@example.com IsActive:true}
+	//   1. Registered {Name:Alice Age:26 Email:alice@example.com}
+	//   2. BirthdayCelebrated {Age:27}
+	//   3. EmailChanged {From:alice@example.com To:alice@work.example.com}
+	//   4. Deactivated {Reason:moved away}
+	// person "alice": deactivated true
+}
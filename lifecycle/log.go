@@ -0,0 +1,248 @@
+package lifecycle
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sync"
+)
+
+// ErrVersionConflict is returned by Append when the records don't follow
+// on from the person's last version, usually because someone else
+// appended first.
+var ErrVersionConflict = errors.New("version conflict")
+
+// Log is an append-only store of records. Nothing is ever changed or
+// removed once appended. MemoryLog and FileLog implement it.
+type Log interface {
+	// Append adds records, all for one person, whose versions must carry
+	// on from that person's last without gaps. Either all are added or,
+	// with an error, none.
+	Append(records ...Record) error
+
+	// Read returns a person's records with versions above after, in
+	// order.
+	Read(personID string, after int) ([]Record, error)
+}
+
+// checkAppend checks that records continue from version last of one
+// person.
+func checkAppend(records []Record, last int) error {
+	for i, r := range records {
+		if r.PersonID != records[0].PersonID {
+			return fmt.Errorf("lifecycle: append mixes people %q and %q", records[0].PersonID, r.PersonID)
+		}
+		if r.Version != last+i+1 {
+			return fmt.Errorf("%w: %s: version %d can't follow %d", ErrVersionConflict, r.PersonID, r.Version, last+i)
+		}
+	}
+	return nil
+}
+
+// MemoryLog keeps records in memory, for demos and tests of code that
+// uses a Log. It is safe for concurrent use.
+type MemoryLog struct {
+	mu      sync.Mutex
+	streams map[string][]Record
+}
+
+func (m *MemoryLog) Append(records ...Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	id := records[0].PersonID
+	if err := checkAppend(records, len(m.streams[id])); err != nil {
+		return err
+	}
+	if m.streams == nil {
+		m.streams = make(map[string][]Record)
+	}
+	m.streams[id] = append(m.streams[id], records...)
+	return nil
+}
+
+func (m *MemoryLog) Read(personID string, after int) ([]Record, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stream := m.streams[personID]
+	if after >= len(stream) {
+		return nil, nil
+	}
+	return slices.Clone(stream[max(after, 0):]), nil
+}
+
+// FileLog keeps records in a file at Path, one JSON object per line,
+// only ever appending to it. A failed append is taken back, but a crash
+// part-way through one can leave some of its records; a last line left
+// half-written is cut off the next time the file is read, so it can't
+// corrupt the records that follow.
+//
+// The file is read through once, to index where each record starts.
+// After that, Read seeks to the records it wants, so reading the events
+// since a snapshot costs only those events. FileLog is safe for
+// concurrent use within one process, but it doesn't see records that
+// another process appends.
+type FileLog struct {
+	Path string
+
+	mu      sync.Mutex
+	offsets map[string][]int64 // where each person's records start, by version-1; nil until the file is read
+}
+
+// load indexes every record in the file, trimming a half-written last
+// line.
+func (f *FileLog) load() error {
+	if f.offsets != nil {
+		return nil
+	}
+	offsets := make(map[string][]int64)
+	good, err := f.scan(func(r Record, offset int64) {
+		offsets[r.PersonID] = append(offsets[r.PersonID], offset)
+	})
+	if errors.Is(err, os.ErrNotExist) {
+		f.offsets = offsets
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info, err := os.Stat(f.Path); err == nil && info.Size() > good {
+		if err := os.Truncate(f.Path, good); err != nil {
+			return err
+		}
+	}
+	f.offsets = offsets
+	return nil
+}
+
+// scan calls fn for each record in the file with the offset its line
+// starts at, and returns the length of the file up to the end of the
+// last complete line.
+func (f *FileLog) scan(fn func(r Record, offset int64)) (int64, error) {
+	file, err := os.Open(f.Path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	var good int64
+	r := bufio.NewReader(file)
+	for line := 1; ; line++ {
+		text, err := r.ReadBytes('\n')
+		if err == io.EOF {
+			// a line with no newline was cut short by a crash
+			return good, nil
+		}
+		if err != nil {
+			return good, err
+		}
+		offset := good
+		good += int64(len(text))
+		if len(bytes.TrimSpace(text)) == 0 {
+			continue
+		}
+		var rec Record
+		if err := json.Unmarshal(text, &rec); err != nil {
+			return good, fmt.Errorf("%s:%d: %w", f.Path, line, err)
+		}
+		fn(rec, offset)
+	}
+}
+
+func (f *FileLog) Append(records ...Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.load(); err != nil {
+		return err
+	}
+	id := records[0].PersonID
+	if err := checkAppend(records, len(f.offsets[id])); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	starts := make([]int64, len(records)) // within buf
+	enc := json.NewEncoder(&buf)
+	for i, r := range records {
+		starts[i] = int64(buf.Len())
+		if err := enc.Encode(r); err != nil {
+			return err
+		}
+	}
+	file, err := os.OpenFile(f.Path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	_, err = file.Write(buf.Bytes())
+	if err == nil {
+		err = file.Sync()
+	}
+	if err != nil {
+		// take back whatever part of the records was written
+		file.Truncate(info.Size())
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	for _, start := range starts {
+		f.offsets[id] = append(f.offsets[id], info.Size()+start)
+	}
+	return nil
+}
+
+func (f *FileLog) Read(personID string, after int) ([]Record, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.load(); err != nil {
+		return nil, err
+	}
+	offsets := f.offsets[personID]
+	if after >= len(offsets) {
+		return nil, nil
+	}
+	file, err := os.Open(f.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	found := make([]Record, 0, len(offsets)-max(after, 0))
+	var r *bufio.Reader
+	for _, offset := range offsets[max(after, 0):] {
+		if _, err := file.Seek(offset, io.SeekStart); err != nil {
+			return nil, err
+		}
+		if r == nil {
+			r = bufio.NewReader(file)
+		} else {
+			r.Reset(file)
+		}
+		text, err := r.ReadBytes('\n')
+		if err != nil {
+			return nil, fmt.Errorf("%s: record at offset %d: %w", f.Path, offset, err)
+		}
+		var rec Record
+		if err := json.Unmarshal(text, &rec); err != nil {
+			return nil, fmt.Errorf("%s: record at offset %d: %w", f.Path, offset, err)
+		}
+		found = append(found, rec)
+	}
+	return found, nil
+}
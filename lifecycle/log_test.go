@@ -0,0 +1,178 @@
+package lifecycle
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go-practice/codec"
+)
+
+func birthdays(id string, from, to int) []Record {
+	var records []Record
+	for v := from; v <= to; v++ {
+		records = append(records, Record{PersonID: id, Version: v, Time: time.Unix(int64(v), 0).UTC(), Event: BirthdayCelebrated{Age: 20 + v}})
+	}
+	return records
+}
+
+func TestRecordJSON(t *testing.T) {
+	at := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	for _, e := range []Event{
+		Registered{Name: "Alice", Age: 30, Email: "alice@example.com"},
+		BirthdayCelebrated{Age: 31},
+		EmailChanged{From: "alice@example.com", To: "alice@work.example"},
+		Deactivated{Reason: "left"},
+	} {
+		r := Record{PersonID: "p1", Version: 2, Time: at, Event: e}
+		data, err := json.Marshal(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got Record
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatalf("%s: %v", data, err)
+		}
+		if got != r {
+			t.Errorf("%s: round trip gave %+v", data, got)
+		}
+	}
+
+	want := `{"person":"p1","version":1,"time":"2025-03-01T09:00:00Z","event":{"age":31,"type":"BirthdayCelebrated"}}`
+	if data, err := json.Marshal(Record{PersonID: "p1", Version: 1, Time: at, Event: BirthdayCelebrated{Age: 31}}); err != nil || string(data) != want {
+		t.Errorf("Marshal = %s, %v, want %s", data, err, want)
+	}
+
+	var r Record
+	err := json.Unmarshal([]byte(`{"person":"p1","version":1,"event":{"type":"Renamed","name":"Bob"}}`), &r)
+	var unknown *codec.UnknownTypeError
+	if !errors.As(err, &unknown) || unknown.Name != "Renamed" {
+		t.Errorf("unknown event type: got error %v, want a *codec.UnknownTypeError", err)
+	}
+}
+
+func TestFileLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	log := &FileLog{Path: path}
+	for _, batch := range [][]Record{birthdays("alice", 1, 3), birthdays("bob", 1, 2), birthdays("alice", 4, 5)} {
+		if err := log.Append(batch...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := log.Append(birthdays("bob", 2, 2)...); !errors.Is(err, ErrVersionConflict) {
+		t.Errorf("appending version 2 again: %v", err)
+	}
+
+	// A second FileLog indexes the file the first one wrote.
+	for name, l := range map[string]*FileLog{"same": log, "reopened": {Path: path}} {
+		got, err := l.Read("alice", 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var versions []int
+		for _, r := range got {
+			versions = append(versions, r.Version)
+		}
+		if len(versions) != 3 || versions[0] != 3 || versions[2] != 5 || got[2].Event != (BirthdayCelebrated{Age: 25}) {
+			t.Errorf("%s: alice after 2 = %+v", name, got)
+		}
+		if got, err := l.Read("bob", 2); err != nil || got != nil {
+			t.Errorf("%s: bob after his last version = %v, %v", name, got, err)
+		}
+		if got, err := l.Read("carol", 0); err != nil || got != nil {
+			t.Errorf("%s: nobody = %v, %v", name, got, err)
+		}
+	}
+}
+
+// TestFileLogReadSeeks checks that Read reads only the records it
+// returns: a record before them can be damaged without Read noticing.
+func TestFileLogReadSeeks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	log := &FileLog{Path: path}
+	if err := log.Append(birthdays("alice", 1, 50)...); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := log.Read("alice", 0); err != nil {
+		t.Fatal(err)
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := bytes.IndexByte(raw, '\n')
+	copy(raw, bytes.Repeat([]byte("x"), first))
+	if err := os.WriteFile(path, raw, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := log.Read("alice", 48)
+	if err != nil || len(got) != 2 || got[0].Version != 49 {
+		t.Errorf("after 48 = %v, %v", got, err)
+	}
+	if _, err := log.Read("alice", 0); err == nil {
+		t.Error("reading the damaged record gave no error")
+	}
+}
+
+func TestFileLogTrimsHalfWrittenLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	if err := (&FileLog{Path: path}).Append(birthdays("alice", 1, 2)...); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString(`{"person_id":"alice","vers`); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	log := &FileLog{Path: path}
+	if err := log.Append(birthdays("alice", 3, 3)...); err != nil {
+		t.Fatal(err)
+	}
+	got, err := log.Read("alice", 0)
+	if err != nil || len(got) != 3 {
+		t.Fatalf("got %d records, %v", len(got), err)
+	}
+	if _, err := (&FileLog{Path: path}).Read("alice", 0); err != nil {
+		t.Errorf("the file didn't read back cleanly: %v", err)
+	}
+}
+
+// TestPeopleWithFileLog checks that lookups from a snapshot rebuild the
+// same state as replaying everything.
+func TestPeopleWithFileLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	people := New(&FileLog{Path: path})
+	people.SnapshotEvery = 4
+	if _, err := people.Register("alice", Person{Name: "Alice", Age: 20, Email: "alice@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	for range 9 {
+		if _, err := people.CelebrateBirthday("alice"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	now, err := people.Get("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fresh, err := New(&FileLog{Path: path}).Get("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if now.Version != 10 || now.Person != fresh.Person || now.Person.Age != 29 {
+		t.Errorf("with snapshots %+v, without %+v", now, fresh)
+	}
+	if then, err := people.AsOf("alice", 6); err != nil || then.Person.Age != 25 {
+		t.Errorf("AsOf 6 = %+v, %v", then, err)
+	}
+}
@@ -0,0 +1,270 @@
+package lifecycle
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"go-practice/errs"
+)
+
+var (
+	// ErrNotFound is returned for a person with no history.
+	ErrNotFound = errors.New("not found")
+
+	// ErrExists is returned when registering an ID that has a history.
+	ErrExists = errors.New("already registered")
+
+	// ErrInactive is returned for a change to a deactivated person.
+	ErrInactive = errors.New("deactivated")
+
+	// ErrNoVersion is returned by AsOf for a version the person hasn't
+	// reached.
+	ErrNoVersion = errors.New("no such version")
+)
+
+// DefaultSnapshotEvery is how many events People lets pass between
+// snapshots of a person when SnapshotEvery is 0.
+const DefaultSnapshotEvery = 50
+
+// Snapshot is a person's state as of Version, the number of events
+// applied, and the time of the last of them.
+type Snapshot struct {
+	PersonID string
+	Version  int
+	Updated  time.Time
+	Person   Person
+}
+
+// Apply returns s with r applied.
+func (s Snapshot) Apply(r Record) Snapshot {
+	return Snapshot{PersonID: r.PersonID, Version: r.Version, Updated: r.Time, Person: r.Event.Apply(s.Person)}
+}
+
+// Replay applies records in order to s, which is the zero Snapshot to
+// start from nothing.
+func Replay(s Snapshot, records []Record) Snapshot {
+	for _, r := range records {
+		s = s.Apply(r)
+	}
+	return s
+}
+
+// People records changes to people as events in a Log and answers
+// questions about them by replaying the events. Every change is checked
+// against the current state before its event is appended, so the log
+// holds only changes that were allowed when they were made.
+//
+// Snapshots are kept in memory only: they save replaying whole
+// histories, and are rebuilt as people change after a restart. People is
+// safe for concurrent use.
+type People struct {
+	// Now stamps new events; nil means time.Now.
+	Now func() time.Time
+
+	// SnapshotEvery is how many events pass between automatic snapshots
+	// of a person: 0 means DefaultSnapshotEvery, and a negative number
+	// turns them off. TakeSnapshot works either way.
+	SnapshotEvery int
+
+	mu        sync.Mutex
+	log       Log
+	snapshots map[string][]Snapshot // by person, in version order
+}
+
+// New returns People keeping their histories in log.
+func New(log Log) *People {
+	return &People{log: log, snapshots: make(map[string][]Snapshot)}
+}
+
+func notFound(id string) error {
+	return fmt.Errorf("person %q: %w", id, ErrNotFound)
+}
+
+func validEmail(email string) bool {
+	local, domain, ok := strings.Cut(email, "@")
+	return ok && local != "" && strings.Contains(domain, ".")
+}
+
+// Register starts the history of the person with the given ID. The
+// person starts active, whatever p.IsActive says.
+func (ps *People) Register(id string, p Person) (Snapshot, error) {
+	var problems []error
+	if strings.TrimSpace(id) == "" {
+		problems = append(problems, &errs.ValidationError{Field: "ID", Message: "is required"})
+	}
+	if strings.TrimSpace(p.Name) == "" {
+		problems = append(problems, &errs.ValidationError{Field: "Name", Message: "is required"})
+	}
+	if p.Age < 0 {
+		problems = append(problems, &errs.ValidationError{Field: "Age", Message: "can't be negative", Value: p.Age})
+	}
+	if !validEmail(p.Email) {
+		problems = append(problems, &errs.ValidationError{Field: "Email", Message: "is not an email address", Value: p.Email})
+	}
+	if err := errs.Aggregate("register", problems...); err != nil {
+		return Snapshot{}, err
+	}
+
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	s, err := ps.state(id, 0)
+	switch {
+	case err == nil:
+		return s, fmt.Errorf("person %q: %w", id, ErrExists)
+	case !errors.Is(err, ErrNotFound):
+		return s, err
+	}
+	return ps.append(Snapshot{PersonID: id}, Registered{Name: p.Name, Age: p.Age, Email: p.Email})
+}
+
+// CelebrateBirthday makes the person a year older.
+func (ps *People) CelebrateBirthday(id string) (Snapshot, error) {
+	return ps.change(id, func(p Person) (Event, error) {
+		return BirthdayCelebrated{Age: p.Age + 1}, nil
+	})
+}
+
+// ChangeEmail sets the person's email address. Setting the address they
+// already have records nothing.
+func (ps *People) ChangeEmail(id, email string) (Snapshot, error) {
+	return ps.change(id, func(p Person) (Event, error) {
+		switch {
+		case !validEmail(email):
+			return nil, &errs.ValidationError{Field: "Email", Message: "is not an email address", Value: email}
+		case email == p.Email:
+			return nil, nil
+		}
+		return EmailChanged{From: p.Email, To: email}, nil
+	})
+}
+
+// Deactivate ends the person's history, giving a reason for the record.
+func (ps *People) Deactivate(id, reason string) (Snapshot, error) {
+	return ps.change(id, func(Person) (Event, error) {
+		return Deactivated{Reason: reason}, nil
+	})
+}
+
+// change appends the event decide returns for an active person's
+// current state, if any.
+func (ps *People) change(id string, decide func(p Person) (Event, error)) (Snapshot, error) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	s, err := ps.state(id, 0)
+	if err != nil {
+		return s, err
+	}
+	if !s.Person.IsActive {
+		return s, fmt.Errorf("person %q: %w", id, ErrInactive)
+	}
+	event, err := decide(s.Person)
+	if err != nil || event == nil {
+		return s, err
+	}
+	return ps.append(s, event)
+}
+
+// append records events following s and returns the state after them,
+// taking a snapshot if one is due.
+func (ps *People) append(s Snapshot, events ...Event) (Snapshot, error) {
+	now := time.Now
+	if ps.Now != nil {
+		now = ps.Now
+	}
+	t := now().Round(0) // as it will read back from the log
+	records := make([]Record, len(events))
+	for i, e := range events {
+		records[i] = Record{PersonID: s.PersonID, Version: s.Version + i + 1, Time: t, Event: e}
+	}
+	if err := ps.log.Append(records...); err != nil {
+		return s, err
+	}
+
+	before := s.Version
+	s = Replay(s, records)
+	every := ps.SnapshotEvery
+	if every == 0 {
+		every = DefaultSnapshotEvery
+	}
+	if every > 0 && s.Version/every > before/every {
+		ps.snapshots[s.PersonID] = append(ps.snapshots[s.PersonID], s)
+	}
+	return s, nil
+}
+
+// state replays the person's history up to version upTo, or all of it
+// for 0, starting from the latest snapshot that doesn't pass it.
+func (ps *People) state(id string, upTo int) (Snapshot, error) {
+	s := Snapshot{PersonID: id}
+	snaps := ps.snapshots[id]
+	for i := len(snaps) - 1; i >= 0; i-- {
+		if upTo == 0 || snaps[i].Version <= upTo {
+			s = snaps[i]
+			break
+		}
+	}
+
+	records, err := ps.log.Read(id, s.Version)
+	if err != nil {
+		return s, err
+	}
+	for _, r := range records {
+		if upTo > 0 && r.Version > upTo {
+			break
+		}
+		s = s.Apply(r)
+	}
+	switch {
+	case s.Version == 0:
+		return s, notFound(id)
+	case upTo > 0 && s.Version < upTo:
+		return s, fmt.Errorf("person %q: version %d: %w (latest is %d)", id, upTo, ErrNoVersion, s.Version)
+	}
+	return s, nil
+}
+
+// Get returns the person's current state.
+func (ps *People) Get(id string) (Snapshot, error) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	return ps.state(id, 0)
+}
+
+// AsOf returns the person as they were after their first version
+// events, so AsOf(id, 1) is the person as registered.
+func (ps *People) AsOf(id string, version int) (Snapshot, error) {
+	if version < 1 {
+		return Snapshot{}, fmt.Errorf("person %q: version %d: %w", id, version, ErrNoVersion)
+	}
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	return ps.state(id, version)
+}
+
+// History returns every event recorded for the person, oldest first.
+func (ps *People) History(id string) ([]Record, error) {
+	records, err := ps.log.Read(id, 0)
+	if err == nil && len(records) == 0 {
+		err = notFound(id)
+	}
+	return records, err
+}
+
+// TakeSnapshot saves the person's current state, so later lookups
+// replay only the events after it.
+func (ps *People) TakeSnapshot(id string) (Snapshot, error) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	s, err := ps.state(id, 0)
+	if err != nil {
+		return s, err
+	}
+	snaps := ps.snapshots[id]
+	if len(snaps) == 0 || snaps[len(snaps)-1].Version < s.Version {
+		ps.snapshots[id] = append(snaps, s)
+	}
+	return s, nil
+}
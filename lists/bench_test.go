@@ -0,0 +1,181 @@
+package lists_test
+
+import (
+	"container/list"
+	"fmt"
+	"slices"
+	"testing"
+
+	"go-practice/lists"
+)
+
+// The benchmarks compare List and Deque with plain slices and the
+// standard library's container/list:
+//
+//	go test ./lists -run '^$' -bench . -benchmem
+
+var sizes = []int{100, 10_000, 100_000}
+
+// maxQuadratic caps the sizes at which O(n²) contenders run; past it they
+// take minutes and say nothing new.
+const maxQuadratic = 20_000
+
+type contender struct {
+	name      string
+	quadratic bool
+	run       func(n int)
+}
+
+var sink int
+
+func run(b *testing.B, contenders []contender) {
+	for _, n := range sizes {
+		for _, c := range contenders {
+			if c.quadratic && n > maxQuadratic {
+				continue
+			}
+			b.Run(fmt.Sprintf("%d/%s", n, c.name), func(b *testing.B) {
+				b.ReportAllocs()
+				for b.Loop() {
+					c.run(n)
+				}
+			})
+		}
+	}
+}
+
+// BenchmarkQueue pushes n values at the back and pops them from the front.
+func BenchmarkQueue(b *testing.B) {
+	run(b, []contender{
+		{"slice", false, func(n int) {
+			var s []int
+			for i := range n {
+				s = append(s, i)
+			}
+			for len(s) > 0 {
+				sink += s[0]
+				s = s[1:]
+			}
+		}},
+		{"lists.List", false, func(n int) {
+			var l lists.List[int]
+			for i := range n {
+				l.PushBack(i)
+			}
+			for l.Len() > 0 {
+				v, _ := l.PopFront()
+				sink += v
+			}
+		}},
+		{"lists.Deque", false, func(n int) {
+			var d lists.Deque[int]
+			for i := range n {
+				d.PushBack(i)
+			}
+			for d.Len() > 0 {
+				v, _ := d.PopFront()
+				sink += v
+			}
+		}},
+		{"container/list", false, func(n int) {
+			l := list.New()
+			for i := range n {
+				l.PushBack(i)
+			}
+			for l.Len() > 0 {
+				sink += l.Remove(l.Front()).(int)
+			}
+		}},
+	})
+}
+
+// BenchmarkStack pushes n values at the back and pops them from the back.
+func BenchmarkStack(b *testing.B) {
+	run(b, []contender{
+		{"slice", false, func(n int) {
+			var s []int
+			for i := range n {
+				s = append(s, i)
+			}
+			for len(s) > 0 {
+				sink += s[len(s)-1]
+				s = s[:len(s)-1]
+			}
+		}},
+		{"lists.List", false, func(n int) {
+			var l lists.List[int]
+			for i := range n {
+				l.PushBack(i)
+			}
+			for l.Len() > 0 {
+				v, _ := l.PopBack()
+				sink += v
+			}
+		}},
+		{"lists.Deque", false, func(n int) {
+			var d lists.Deque[int]
+			for i := range n {
+				d.PushBack(i)
+			}
+			for d.Len() > 0 {
+				v, _ := d.PopBack()
+				sink += v
+			}
+		}},
+	})
+}
+
+// BenchmarkPushFront pushes n values at the front, which a slice can only
+// do by shifting everything along.
+func BenchmarkPushFront(b *testing.B) {
+	run(b, []contender{
+		{"slice", true, func(n int) {
+			var s []int
+			for i := range n {
+				s = slices.Insert(s, 0, i)
+			}
+			sink += len(s)
+		}},
+		{"lists.List", false, func(n int) {
+			var l lists.List[int]
+			for i := range n {
+				l.PushFront(i)
+			}
+			sink += l.Len()
+		}},
+		{"lists.Deque", false, func(n int) {
+			var d lists.Deque[int]
+			for i := range n {
+				d.PushFront(i)
+			}
+			sink += d.Len()
+		}},
+	})
+}
+
+// BenchmarkFillIterate builds a container of n values and ranges over it.
+func BenchmarkFillIterate(b *testing.B) {
+	run(b, []contender{
+		{"slice", false, func(n int) {
+			s := make([]int, n)
+			for _, v := range s {
+				sink += v
+			}
+		}},
+		{"lists.List", false, func(n int) {
+			l := lists.New(make([]int, n)...)
+			for v := range l.All() {
+				sink += v
+			}
+		}},
+		{"lists.Deque", false, func(n int) {
+			d := lists.NewDeque[int](n)
+			for range n {
+				d.PushBack(0)
+			}
+			for v := range d.All() {
+				sink += v
+			}
+		}},
+	})
+}
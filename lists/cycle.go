@@ -0,0 +1,42 @@
+package lists
+
+// FindCycle checks a singly linked structure, such as chapter 9's
+// LinkedList, for a loop back to an earlier node, which would make a
+// "for n != nil { n = n.Next }" walk run forever. It uses Floyd's
+// tortoise-and-hare algorithm: O(n) time and O(1) memory.
+//
+// If there is a cycle, FindCycle returns the first node on it and the
+// number of nodes in it; otherwise it returns nil and 0.
+func FindCycle[N any](head *N, next func(*N) *N) (start *N, length int) {
+	slow, fast := head, head
+	for fast != nil {
+		if fast = next(fast); fast == nil {
+			return nil, 0
+		}
+		fast = next(fast)
+		slow = next(slow)
+		if slow == fast {
+			break
+		}
+	}
+	if fast == nil {
+		return nil, 0
+	}
+
+	// Restarting one pointer from head, both meet at the cycle's start.
+	slow = head
+	for slow != fast {
+		slow, fast = next(slow), next(fast)
+	}
+	length = 1
+	for n := next(slow); n != slow; n = next(n) {
+		length++
+	}
+	return slow, length
+}
+
+// HasCycle reports whether following next from head loops forever.
+func HasCycle[N any](head *N, next func(*N) *N) bool {
+	start, _ := FindCycle(head, next)
+	return start != nil
+}
@@ -0,0 +1,64 @@
+package lists
+
+import "testing"
+
+// node is chapter 9's LinkedList node.
+type node struct {
+	Value int
+	Next  *node
+}
+
+func next(n *node) *node { return n.Next }
+
+// chain links n nodes numbered 0 to n-1 and, if loopTo is at least 0,
+// points the last one back at node loopTo.
+func chain(n, loopTo int) *node {
+	nodes := make([]*node, n)
+	for i := range nodes {
+		nodes[i] = &node{Value: i}
+		if i > 0 {
+			nodes[i-1].Next = nodes[i]
+		}
+	}
+	if n == 0 {
+		return nil
+	}
+	if loopTo >= 0 {
+		nodes[n-1].Next = nodes[loopTo]
+	}
+	return nodes[0]
+}
+
+func TestFindCycle(t *testing.T) {
+	tests := []struct {
+		name       string
+		n, loopTo  int
+		wantStart  int // -1 for no cycle
+		wantLength int
+	}{
+		{"empty", 0, -1, -1, 0},
+		{"one node", 1, -1, -1, 0},
+		{"odd length", 5, -1, -1, 0},
+		{"even length", 6, -1, -1, 0},
+		{"node pointing at itself", 1, 0, 0, 1},
+		{"whole list", 5, 0, 0, 5},
+		{"tail loops to the middle", 10, 4, 4, 6},
+		{"last node loops to itself", 7, 6, 6, 1},
+		{"two nodes", 2, 0, 0, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, length := FindCycle(chain(tt.n, tt.loopTo), next)
+			if tt.wantStart < 0 {
+				if start != nil || length != 0 {
+					t.Errorf("FindCycle = node %d, %d; want no cycle", start.Value, length)
+				}
+			} else if start == nil || start.Value != tt.wantStart || length != tt.wantLength {
+				t.Errorf("FindCycle = %v, %d; want node %d, %d", start, length, tt.wantStart, tt.wantLength)
+			}
+			if got := HasCycle(chain(tt.n, tt.loopTo), next); got != (tt.wantStart >= 0) {
+				t.Errorf("HasCycle = %v", got)
+			}
+		})
+	}
+}
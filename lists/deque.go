@@ -0,0 +1,182 @@
+package lists
+
+import (
+	"fmt"
+	"iter"
+)
+
+// Deque is a double-ended queue backed by a ring buffer: pushes and pops
+// at either end are amortized O(1), and At is O(1). The buffer doubles
+// when full and never shrinks on its own; call Clip to release spare
+// capacity. The zero value is an empty deque ready to use. A Deque is not
+// safe for concurrent use.
+type Deque[T any] struct {
+	buf  []T // len(buf) is zero or a power of two
+	head int // index of the front value
+	len  int
+}
+
+// NewDeque returns an empty deque with room for at least capacity values
+// before it has to grow.
+func NewDeque[T any](capacity int) *Deque[T] {
+	d := new(Deque[T])
+	if capacity > 0 {
+		d.buf = make([]T, ceilPow2(capacity))
+	}
+	return d
+}
+
+func ceilPow2(n int) int {
+	p := 1
+	for p < n {
+		p <<= 1
+	}
+	return p
+}
+
+// Len returns the number of values in the deque.
+func (d *Deque[T]) Len() int {
+	return d.len
+}
+
+// Cap returns how many values fit before the buffer grows.
+func (d *Deque[T]) Cap() int {
+	return len(d.buf)
+}
+
+// index maps a position from the front to a buffer index. The mask works
+// because len(buf) is a power of two.
+func (d *Deque[T]) index(i int) int {
+	return (d.head + i) & (len(d.buf) - 1)
+}
+
+func (d *Deque[T]) grow() {
+	if d.len < len(d.buf) {
+		return
+	}
+	d.resize(max(2*len(d.buf), 8))
+}
+
+func (d *Deque[T]) resize(size int) {
+	buf := make([]T, size)
+	if d.len > 0 {
+		// copy the two runs of the ring so the front lands at index 0
+		n := copy(buf, d.buf[d.head:min(d.head+d.len, len(d.buf))])
+		copy(buf[n:], d.buf[:d.len-n])
+	}
+	d.buf, d.head = buf, 0
+}
+
+// PushBack adds v at the back.
+func (d *Deque[T]) PushBack(v T) {
+	d.grow()
+	d.buf[d.index(d.len)] = v
+	d.len++
+}
+
+// PushFront adds v at the front.
+func (d *Deque[T]) PushFront(v T) {
+	d.grow()
+	d.head = (d.head - 1) & (len(d.buf) - 1)
+	d.buf[d.head] = v
+	d.len++
+}
+
+// PopFront removes and returns the front value; ok is false if the deque
+// is empty.
+func (d *Deque[T]) PopFront() (v T, ok bool) {
+	if d.len == 0 {
+		return v, false
+	}
+	var zero T
+	v, d.buf[d.head] = d.buf[d.head], zero // don't keep popped pointers alive
+	d.head = d.index(1)
+	d.len--
+	return v, true
+}
+
+// PopBack removes and returns the back value; ok is false if the deque is
+// empty.
+func (d *Deque[T]) PopBack() (v T, ok bool) {
+	if d.len == 0 {
+		return v, false
+	}
+	var zero T
+	i := d.index(d.len - 1)
+	v, d.buf[i] = d.buf[i], zero
+	d.len--
+	return v, true
+}
+
+// Front returns the front value without removing it.
+func (d *Deque[T]) Front() (v T, ok bool) {
+	if d.len == 0 {
+		return v, false
+	}
+	return d.buf[d.head], true
+}
+
+// Back returns the back value without removing it.
+func (d *Deque[T]) Back() (v T, ok bool) {
+	if d.len == 0 {
+		return v, false
+	}
+	return d.buf[d.index(d.len-1)], true
+}
+
+// At returns the i-th value from the front. It panics if i is out of
+// range, like indexing a slice.
+func (d *Deque[T]) At(i int) T {
+	if i < 0 || i >= d.len {
+		panic(fmt.Sprintf("lists: deque index %d out of range [0:%d]", i, d.len))
+	}
+	return d.buf[d.index(i)]
+}
+
+// Set replaces the i-th value from the front. It panics if i is out of
+// range.
+func (d *Deque[T]) Set(i int, v T) {
+	if i < 0 || i >= d.len {
+		panic(fmt.Sprintf("lists: deque index %d out of range [0:%d]", i, d.len))
+	}
+	d.buf[d.index(i)] = v
+}
+
+// Clear removes every value but keeps the buffer for reuse.
+func (d *Deque[T]) Clear() {
+	clear(d.buf)
+	d.head, d.len = 0, 0
+}
+
+// Clip shrinks the buffer to the smallest power of two that holds the
+// current values.
+func (d *Deque[T]) Clip() {
+	if d.len == 0 {
+		d.buf, d.head = nil, 0
+		return
+	}
+	if size := ceilPow2(d.len); size < len(d.buf) {
+		d.resize(size)
+	}
+}
+
+// All iterates over the values from front to back. The deque must not be
+// modified during iteration.
+func (d *Deque[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := 0; i < d.len; i++ {
+			if !yield(d.buf[d.index(i)]) {
+				return
+			}
+		}
+	}
+}
+
+// Values returns the values from front to back as a new slice.
+func (d *Deque[T]) Values() []T {
+	values := make([]T, d.len)
+	for i := range values {
+		values[i] = d.buf[d.index(i)]
+	}
+	return values
+}
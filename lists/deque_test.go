@@ -0,0 +1,145 @@
+package lists
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDequeEnds(t *testing.T) {
+	var d Deque[int]
+	if _, ok := d.PopFront(); ok {
+		t.Error("PopFront of an empty deque succeeded")
+	}
+	if _, ok := d.Back(); ok {
+		t.Error("Back of an empty deque succeeded")
+	}
+
+	d.PushBack(2)
+	d.PushFront(1)
+	d.PushBack(3)
+	d.PushFront(0)
+	if got := d.Values(); !slices.Equal(got, []int{0, 1, 2, 3}) {
+		t.Fatalf("Values = %v", got)
+	}
+	if v, _ := d.Front(); v != 0 {
+		t.Errorf("Front = %d, want 0", v)
+	}
+	if v, _ := d.Back(); v != 3 {
+		t.Errorf("Back = %d, want 3", v)
+	}
+	if v, ok := d.PopBack(); v != 3 || !ok {
+		t.Errorf("PopBack = %d, %v", v, ok)
+	}
+	if v, ok := d.PopFront(); v != 0 || !ok {
+		t.Errorf("PopFront = %d, %v", v, ok)
+	}
+	d.Set(1, 20)
+	if d.At(0) != 1 || d.At(1) != 20 || d.Len() != 2 {
+		t.Errorf("after Set: %v", d.Values())
+	}
+}
+
+// TestDequeGrowWrapped fills a deque whose front has wrapped around to
+// the end of the buffer, so growing has to unroll two runs.
+func TestDequeGrowWrapped(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		front int // values pushed at the front before filling
+	}{
+		{"not wrapped", 0},
+		{"wrapped by one", 1},
+		{"wrapped by half", 4},
+		{"wrapped by all but one", 7},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewDeque[int](8)
+			var want []int
+			for i := range tt.front {
+				d.PushFront(-i - 1)
+				want = slices.Insert(want, 0, -i-1)
+			}
+			for i := 0; d.Len() < d.Cap(); i++ {
+				d.PushBack(i)
+				want = append(want, i)
+			}
+			if d.Cap() != 8 {
+				t.Fatalf("Cap = %d before growing, want 8", d.Cap())
+			}
+
+			d.PushBack(100)
+			d.PushFront(-100)
+			want = append([]int{-100}, append(want, 100)...)
+			if d.Cap() != 16 {
+				t.Errorf("Cap = %d after growing, want 16", d.Cap())
+			}
+			if got := d.Values(); !slices.Equal(got, want) {
+				t.Errorf("Values = %v, want %v", got, want)
+			}
+			for i, w := range want {
+				if d.At(i) != w {
+					t.Errorf("At(%d) = %d, want %d", i, d.At(i), w)
+				}
+			}
+			if got := slices.Collect(d.All()); !slices.Equal(got, want) {
+				t.Errorf("All = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestDequeQueueWraps(t *testing.T) {
+	// used as a queue, the front chases the back round the buffer
+	d := NewDeque[int](4)
+	next := 0
+	for i := range 100 {
+		d.PushBack(i)
+		if d.Len() == 3 {
+			if v, _ := d.PopFront(); v != next {
+				t.Fatalf("PopFront = %d, want %d", v, next)
+			}
+			next++
+		}
+	}
+	if d.Cap() != 4 {
+		t.Errorf("Cap = %d, want 4: a queue that never fills shouldn't grow", d.Cap())
+	}
+}
+
+func TestDequeClip(t *testing.T) {
+	d := NewDeque[string](3)
+	if d.Cap() != 4 {
+		t.Errorf("NewDeque(3).Cap = %d, want 4", d.Cap())
+	}
+	for _, s := range []string{"a", "b", "c", "d", "e"} {
+		d.PushFront(s)
+	}
+	d.PopBack()
+	d.PopBack()
+	d.Clip()
+	if d.Cap() != 4 || !slices.Equal(d.Values(), []string{"e", "d", "c"}) {
+		t.Errorf("after Clip: Cap %d, %v", d.Cap(), d.Values())
+	}
+	d.Clear()
+	if d.Len() != 0 || d.Cap() != 4 {
+		t.Errorf("after Clear: Len %d, Cap %d", d.Len(), d.Cap())
+	}
+	d.Clip()
+	if d.Cap() != 0 {
+		t.Errorf("Clip of an empty deque kept %d", d.Cap())
+	}
+}
+
+func TestDequeAtPanics(t *testing.T) {
+	d := NewDeque[int](4)
+	d.PushBack(1)
+	for _, i := range []int{-1, 1, 4} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("At(%d) didn't panic", i)
+				}
+			}()
+			d.At(i)
+		}()
+	}
+}
@@ -0,0 +1,153 @@
+package lists
+
+import (
+	"slices"
+	"testing"
+)
+
+// check verifies l's links and length and returns its values.
+func check[T any](t *testing.T, l *List[T]) []T {
+	t.Helper()
+	forward := l.Values()
+	backward := slices.Collect(l.Backward())
+	slices.Reverse(backward)
+	if len(forward) != l.Len() || len(backward) != l.Len() {
+		t.Fatalf("Len = %d, but %d values forward and %d backward", l.Len(), len(forward), len(backward))
+	}
+	for e := l.Front(); e != nil; e = e.Next() {
+		if e.list != l || (e.Next() != nil && e.Next().Prev() != e) {
+			t.Fatalf("element %v is badly linked", e.Value)
+		}
+	}
+	return forward
+}
+
+func TestList(t *testing.T) {
+	tests := []struct {
+		name string
+		do   func(l *List[int])
+		want []int
+	}{
+		{"push front", func(l *List[int]) { l.PushFront(0) }, []int{0, 1, 2, 3}},
+		{"push back", func(l *List[int]) { l.PushBack(4) }, []int{1, 2, 3, 4}},
+		{"pop front", func(l *List[int]) { l.PopFront() }, []int{2, 3}},
+		{"pop back", func(l *List[int]) { l.PopBack() }, []int{1, 2}},
+		{"remove front", func(l *List[int]) { l.Remove(l.Front()) }, []int{2, 3}},
+		{"remove back", func(l *List[int]) { l.Remove(l.Back()) }, []int{1, 2}},
+		{"remove middle", func(l *List[int]) { l.Remove(l.Front().Next()) }, []int{1, 3}},
+		{"insert before front", func(l *List[int]) { l.InsertBefore(0, l.Front()) }, []int{0, 1, 2, 3}},
+		{"insert after back", func(l *List[int]) { l.InsertAfter(4, l.Back()) }, []int{1, 2, 3, 4}},
+		{"insert after front", func(l *List[int]) { l.InsertAfter(9, l.Front()) }, []int{1, 9, 2, 3}},
+		{"back to front", func(l *List[int]) { l.MoveToFront(l.Back()) }, []int{3, 1, 2}},
+		{"front to front", func(l *List[int]) { l.MoveToFront(l.Front()) }, []int{1, 2, 3}},
+		{"middle to front", func(l *List[int]) { l.MoveToFront(l.Front().Next()) }, []int{2, 1, 3}},
+		{"front to back", func(l *List[int]) { l.MoveToBack(l.Front()) }, []int{2, 3, 1}},
+		{"reverse", func(l *List[int]) { l.Reverse() }, []int{3, 2, 1}},
+		{"splice at back", func(l *List[int]) { l.Splice(nil, New(4, 5)) }, []int{1, 2, 3, 4, 5}},
+		{"splice after front", func(l *List[int]) { l.Splice(l.Front(), New(8, 9)) }, []int{1, 8, 9, 2, 3}},
+		{"splice nothing", func(l *List[int]) { l.Splice(l.Back(), New[int]()) }, []int{1, 2, 3}},
+		{"empty it", func(l *List[int]) {
+			for l.Len() > 0 {
+				l.PopBack()
+			}
+		}, []int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := New(1, 2, 3)
+			tt.do(l)
+			if got := check(t, l); !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListZeroValue(t *testing.T) {
+	var l List[string]
+	if l.Front() != nil || l.Back() != nil {
+		t.Error("an empty list has ends")
+	}
+	if _, ok := l.PopFront(); ok {
+		t.Error("PopFront of an empty list succeeded")
+	}
+	if _, ok := l.PopBack(); ok {
+		t.Error("PopBack of an empty list succeeded")
+	}
+	l.PushFront("b")
+	l.PushBack("c")
+	l.PushFront("a")
+	if got := check(t, &l); !slices.Equal(got, []string{"a", "b", "c"}) {
+		t.Errorf("got %v", got)
+	}
+	if v, ok := l.PopFront(); v != "a" || !ok {
+		t.Errorf("PopFront = %q, %v", v, ok)
+	}
+	if v, ok := l.PopBack(); v != "c" || !ok {
+		t.Errorf("PopBack = %q, %v", v, ok)
+	}
+	if e := l.Front(); e != l.Back() || e.Next() != nil || e.Prev() != nil {
+		t.Error("a one-element list isn't its own front and back")
+	}
+}
+
+func TestListRemoveDuringIteration(t *testing.T) {
+	l := New(1, 2, 3, 4, 5, 6)
+	for e := l.Front(); e != nil; {
+		next := e.Next()
+		if e.Value%2 == 0 {
+			l.Remove(e)
+		}
+		e = next
+	}
+	if got := check(t, l); !slices.Equal(got, []int{1, 3, 5}) {
+		t.Errorf("got %v", got)
+	}
+}
+
+func TestSpliceMovesElements(t *testing.T) {
+	l, other := New(1), New(2, 3)
+	e := other.Front()
+	l.Splice(nil, other)
+	if other.Len() != 0 || len(check(t, other)) != 0 {
+		t.Errorf("other still has %v", other.Values())
+	}
+	l.MoveToFront(e.Next()) // e now belongs to l
+	if got := check(t, l); !slices.Equal(got, []int{3, 1, 2}) {
+		t.Errorf("got %v", got)
+	}
+	other.PushBack(4)
+	if got := check(t, other); !slices.Equal(got, []int{4}) {
+		t.Errorf("other after reuse: %v", got)
+	}
+}
+
+func TestListPanics(t *testing.T) {
+	tests := []struct {
+		name string
+		do   func()
+	}{
+		{"remove twice", func() {
+			l := New(1)
+			e := l.Front()
+			l.Remove(e)
+			l.Remove(e)
+		}},
+		{"another list's element", func() { New(1).MoveToFront(New(2).Front()) }},
+		{"nil mark", func() { New(1).InsertAfter(2, nil) }},
+		{"splice into itself", func() {
+			l := New(1)
+			l.Splice(nil, l)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("no panic")
+				}
+			}()
+			tt.do()
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-practice/errs"
+)
+
+// Err returns an attribute for err under the key "error", the key the
+// handler's error fields appear under in the book's programs.
+func Err(err error) slog.Attr { return slog.Any("error", err) }
+
+// extractors each pull one attribute out of the first error in a chain
+// with the right method. The chapter 10 error types and the errs package
+// have these methods; any other error type can add them to be logged the
+// same way.
+var extractors = []func(err error) (slog.Attr, bool){
+	extract("field", func(e interface{ GetField() string }) any { return e.GetField() }),
+	extract("value", func(e interface{ GetValue() interface{} }) any { return e.GetValue() }),
+	extract("line", func(e interface{ GetLine() int }) any { return e.GetLine() }),
+	extract("operation", func(e interface{ GetOperation() string }) any { return e.GetOperation() }),
+	extract("table", func(e interface{ GetTable() string }) any { return e.GetTable() }),
+	extract("code", func(e interface{ GetCode() int }) any { return e.GetCode() }),
+	extract("url", func(e interface{ GetURL() string }) any { return e.GetURL() }),
+	extract("timeout", func(e interface{ GetTimeout() time.Duration }) any { return e.GetTimeout() }),
+	extract("retryable", func(e interface{ IsRetryable() bool }) any { return e.IsRetryable() }),
+}
+
+func extract[I any](key string, get func(I) any) func(error) (slog.Attr, bool) {
+	return func(err error) (slog.Attr, bool) {
+		var target I
+		if !errors.As(err, &target) {
+			return slog.Attr{}, false
+		}
+		return slog.Any(key, get(target)), true
+	}
+}
+
+// errorAttrs returns the attributes err is logged with: its message, the
+// type of the innermost error it wraps, whatever fields the extractors
+// find, and for an error joining several, how many. When err holds more
+// than one *errs.ValidationError, each also gets a group under
+// "problems", numbered from 1, with its field, value and line. The value
+// of every field that is a redacted key is hidden, and so is the
+// " (value: ...)" the error types put it in the message with; the rest of
+// the message is left alone, however short the value.
+func (h *handler) errorAttrs(err error) []slog.Attr {
+	inner := err
+	for next := errors.Unwrap(inner); next != nil; next = errors.Unwrap(inner) {
+		inner = next
+	}
+	msg := err.Error()
+	hide := func(value any) {
+		if value != nil {
+			msg = strings.ReplaceAll(msg, fmt.Sprintf(" (value: %v)", value), " (value: "+Redacted+")")
+		}
+	}
+	attrs := []slog.Attr{{}, slog.String("type", fmt.Sprintf("%T", inner))}
+
+	var field string
+	valueAt := -1
+	for _, extract := range extractors {
+		a, ok := extract(err)
+		if !ok {
+			continue
+		}
+		switch a.Key {
+		case "field":
+			field = a.Value.String()
+		case "value":
+			valueAt = len(attrs)
+		}
+		attrs = append(attrs, a)
+	}
+	if valueAt >= 0 && h.redacts(field) {
+		hide(attrs[valueAt].Value.Any())
+		attrs[valueAt] = slog.String("value", Redacted)
+	}
+
+	problems := errs.ValidationErrors(err)
+	var groups []slog.Attr
+	for i, p := range problems {
+		group := []slog.Attr{slog.String("field", p.Field)}
+		if p.Value != nil {
+			value := slog.Any("value", p.Value)
+			if h.redacts(p.Field) {
+				hide(p.Value)
+				value = slog.String("value", Redacted)
+			}
+			group = append(group, value)
+		}
+		if p.Line > 0 {
+			group = append(group, slog.Int("line", p.Line))
+		}
+		groups = append(groups, slog.Attr{Key: strconv.Itoa(i + 1), Value: slog.GroupValue(group...)})
+	}
+	if len(problems) > 1 {
+		attrs = append(attrs, slog.Attr{Key: "problems", Value: slog.GroupValue(groups...)})
+	}
+	attrs[0] = slog.String("msg", msg)
+
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		attrs = append(attrs, slog.Int("count", len(joined.Unwrap())))
+	}
+	return attrs
+}
@@ -0,0 +1,130 @@
+package logging_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"go-practice/errs"
+	"go-practice/logging"
+	"go-practice/org"
+)
+
+func TestErrorAttrs(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   logging.Options
+		err    error
+		want   map[string]any // attributes under "error."
+		absent []string       // keys that must not be there
+		hidden string         // text that must not appear in error.msg
+	}{
+		{
+			name: "one problem",
+			err:  &errs.ValidationError{Field: "Age", Message: "can't be negative", Value: -1, Line: 3},
+			want: map[string]any{
+				"msg":   "line 3: validation failed for Age: can't be negative (value: -1)",
+				"type":  "*errs.ValidationError",
+				"field": "Age", "value": int64(-1), "line": int64(3),
+			},
+			absent: []string{"problems.1.field", "count"},
+		},
+		{
+			name:   "one redacted problem",
+			err:    &errs.ValidationError{Field: "Email", Message: "is not an email address", Value: "alice@nowhere"},
+			want:   map[string]any{"field": "Email", "value": logging.Redacted},
+			hidden: "alice@nowhere",
+		},
+		{
+			name: "redacted problem after another",
+			err: errs.Aggregate("register",
+				&errs.ValidationError{Field: "Age", Message: "can't be negative", Value: -1},
+				&errs.ValidationError{Field: "Password", Message: "is too short", Value: "hunter2", Line: 4},
+			),
+			want: map[string]any{
+				"field": "Age", "value": int64(-1), "count": int64(2),
+				"problems.1.field": "Age", "problems.1.value": int64(-1),
+				"problems.2.field": "Password", "problems.2.value": logging.Redacted, "problems.2.line": int64(4),
+			},
+			absent: []string{"problems.1.line"},
+			hidden: "hunter2",
+		},
+		{
+			name: "every redacted value",
+			err: errors.Join(
+				&errs.ValidationError{Field: "email", Message: "is taken", Value: "bob@example.com"},
+				errs.Aggregate("inner", &errs.ValidationError{Field: "Token", Message: "expired", Value: "abc123"}),
+			),
+			want: map[string]any{
+				"value": logging.Redacted, "problems.1.value": logging.Redacted, "problems.2.value": logging.Redacted,
+			},
+			hidden: "bob@example.com",
+		},
+		{
+			name: "short redacted value",
+			opts: logging.Options{Redact: []string{"pin"}},
+			err: errs.Aggregate("unlock",
+				&errs.ValidationError{Field: "Attempts", Message: "must be below 1", Value: 3, Line: 1},
+				&errs.ValidationError{Field: "PIN", Message: "is too short", Value: 1, Line: 12},
+			),
+			want: map[string]any{
+				"msg": "unlock: 2 problems: line 1: validation failed for Attempts: must be below 1 (value: 3); " +
+					"line 12: validation failed for PIN: is too short (value: [REDACTED])",
+				"problems.2.value": logging.Redacted,
+			},
+		},
+		{
+			name: "redaction off",
+			opts: logging.Options{Redact: []string{}},
+			err: errs.Aggregate("register",
+				&errs.ValidationError{Field: "Name", Message: "is required"},
+				&errs.ValidationError{Field: "Email", Message: "is not an email address", Value: "x"},
+			),
+			want:   map[string]any{"problems.2.value": "x"},
+			absent: []string{"problems.1.value"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, rec := logging.NewTest(tt.opts)
+			logger.Error("failed", logging.Err(tt.err))
+			e, ok := rec.Find("failed")
+			if !ok {
+				t.Fatal("nothing logged")
+			}
+			for key, want := range tt.want {
+				if got := e.Attrs["error."+key]; got != want {
+					t.Errorf("error.%s = %#v, want %#v", key, got, want)
+				}
+			}
+			for _, key := range tt.absent {
+				if got, ok := e.Attrs["error."+key]; ok {
+					t.Errorf("error.%s = %#v, want none", key, got)
+				}
+			}
+			if msg, _ := e.Attrs["error.msg"].(string); tt.hidden != "" && strings.Contains(msg, tt.hidden) {
+				t.Errorf("error.msg shows %q: %s", tt.hidden, msg)
+			}
+		})
+	}
+}
+
+// TestHireErrorRedacted logs a hire that fails twice over, with the
+// email second, so the first problem's field isn't the redacted one.
+func TestHireErrorRedacted(t *testing.T) {
+	const email = "carol@nowhere"
+	err := org.New().Hire(org.Employee{ID: "carol", Person: org.Person{Email: email}})
+	if err == nil {
+		t.Fatal("Hire accepted an employee with no name")
+	}
+
+	logger, rec := logging.NewTest(logging.Options{})
+	logger.Error("hire failed", logging.Err(err))
+	e, _ := rec.Find("hire failed")
+	if strings.Contains(e.String(), email) {
+		t.Errorf("the email was logged: %s", e)
+	}
+	if e.Attrs["error.problems.1.field"] != "Name" || e.Attrs["error.problems.2.field"] != "Email" {
+		t.Errorf("problems logged as %s", e)
+	}
+}
@@ -0,0 +1,144 @@
+// Package logging sets up structured, leveled logs on log/slog for the
+// book's programs, in place of log.Printf. Its handler adds three things
+// to any slog handler: errors logged as attributes are expanded into
+// their fields (the field of a ValidationError, the table of a
+// DatabaseError, the URL of a NetworkError, and so on), attributes such
+// as email addresses are redacted, and repeated messages can be sampled
+// so a hot loop can't flood the log. Recorder keeps records in memory so
+// tests can check what was logged.
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+)
+
+// Redacted replaces the value of every redacted attribute.
+const Redacted = "[REDACTED]"
+
+// DefaultRedact is the attribute keys redacted when Options.Redact is
+// nil.
+var DefaultRedact = []string{"email", "password", "token", "secret"}
+
+// Format selects how New writes records.
+type Format int
+
+const (
+	Text Format = iota // key=value pairs, as slog.TextHandler
+	JSON               // one JSON object per line, as slog.JSONHandler
+)
+
+// Options configures New and NewHandler.
+type Options struct {
+	Format    Format
+	Level     slog.Leveler // the lowest level logged; nil means slog.LevelInfo
+	AddSource bool         // add the file and line of the log call
+
+	// Redact lists attribute keys whose values are hidden, ignoring case
+	// and the groups they are in. It applies to error fields too: a
+	// ValidationError for field "Email" has its value hidden. nil means
+	// DefaultRedact; use an empty slice to redact nothing.
+	Redact []string
+
+	// Sampling, if set, thins out repeated low-level records.
+	Sampling *Sampling
+}
+
+// New returns a logger writing to w in the given format, with the error
+// fields, redaction and sampling of opts.
+func New(w io.Writer, opts Options) *slog.Logger {
+	handlerOpts := &slog.HandlerOptions{Level: opts.Level, AddSource: opts.AddSource}
+	var base slog.Handler = slog.NewTextHandler(w, handlerOpts)
+	if opts.Format == JSON {
+		base = slog.NewJSONHandler(w, handlerOpts)
+	}
+	return slog.New(NewHandler(base, opts))
+}
+
+// NewHandler adds the error fields, redaction and sampling of opts to
+// next, which may be any handler, such as a Recorder. opts.Level filters
+// records before they reach next; Format and AddSource are for New and
+// are ignored.
+func NewHandler(next slog.Handler, opts Options) slog.Handler {
+	redact := opts.Redact
+	if redact == nil {
+		redact = DefaultRedact
+	}
+	level := opts.Level
+	if level == nil {
+		level = slog.LevelInfo
+	}
+	h := &handler{next: next, level: level}
+	for _, key := range redact {
+		h.redact = append(h.redact, strings.ToLower(key))
+	}
+	if opts.Sampling != nil {
+		return NewSampler(h, *opts.Sampling)
+	}
+	return h
+}
+
+// handler rewrites attributes on their way to next.
+type handler struct {
+	next   slog.Handler
+	level  slog.Leveler
+	redact []string // lower case
+}
+
+func (h *handler) Enabled(ctx context.Context, level slog.Level) bool {
+	if level < h.level.Level() {
+		return false
+	}
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *handler) Handle(ctx context.Context, r slog.Record) error {
+	out := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		out.AddAttrs(h.rewrite(a))
+		return true
+	})
+	return h.next.Handle(ctx, out)
+}
+
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	rewritten := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		rewritten[i] = h.rewrite(a)
+	}
+	return &handler{next: h.next.WithAttrs(rewritten), level: h.level, redact: h.redact}
+}
+
+func (h *handler) WithGroup(name string) slog.Handler {
+	return &handler{next: h.next.WithGroup(name), level: h.level, redact: h.redact}
+}
+
+func (h *handler) redacts(key string) bool {
+	return slices.Contains(h.redact, strings.ToLower(key))
+}
+
+// rewrite redacts a, expands it if it is an error, and does the same
+// inside groups.
+func (h *handler) rewrite(a slog.Attr) slog.Attr {
+	if h.redacts(a.Key) {
+		return slog.String(a.Key, Redacted)
+	}
+	a.Value = a.Value.Resolve()
+	switch a.Value.Kind() {
+	case slog.KindGroup:
+		group := a.Value.Group()
+		rewritten := make([]slog.Attr, len(group))
+		for i, inner := range group {
+			rewritten[i] = h.rewrite(inner)
+		}
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(rewritten...)}
+	case slog.KindAny:
+		if err, ok := a.Value.Any().(error); ok {
+			return slog.Attr{Key: a.Key, Value: slog.GroupValue(h.errorAttrs(err)...)}
+		}
+	}
+	return a
+}
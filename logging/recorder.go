@@ -0,0 +1,150 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Entry is one record as a Recorder received it. Attrs is flat: the
+// attributes of a group have keys such as "error.field". Values are what
+// slog.Value.Any returns, so integers are int64.
+type Entry struct {
+	Time    time.Time
+	Level   slog.Level
+	Message string
+	Attrs   map[string]any
+}
+
+// String formats the entry like a line of text output, with the
+// attributes sorted by key.
+func (e Entry) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s", e.Level, e.Message)
+	for _, key := range slices.Sorted(maps.Keys(e.Attrs)) {
+		fmt.Fprintf(&b, " %s=%v", key, e.Attrs[key])
+	}
+	return b.String()
+}
+
+// Recorder is a handler that keeps every record in memory, so a test can
+// check what was logged. Put it behind NewHandler, or use NewTest, to see
+// records as they would be written, after redaction and sampling.
+// Handlers made from it by WithAttrs and WithGroup record into the same
+// list. It is safe for concurrent use.
+type Recorder struct {
+	store  *recorderStore
+	prefix string         // groups so far, each followed by "."
+	attrs  map[string]any // from WithAttrs, already flat
+}
+
+type recorderStore struct {
+	mu      sync.Mutex
+	entries []Entry
+}
+
+// NewRecorder returns an empty Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{store: &recorderStore{}, attrs: map[string]any{}}
+}
+
+// NewTest returns a logger with the error fields, redaction and sampling
+// of opts that records into a new Recorder.
+func NewTest(opts Options) (*slog.Logger, *Recorder) {
+	r := NewRecorder()
+	return slog.New(NewHandler(r, opts)), r
+}
+
+// Enabled reports true for every level; a Recorder records everything
+// it is given.
+func (r *Recorder) Enabled(context.Context, slog.Level) bool { return true }
+
+func (r *Recorder) Handle(_ context.Context, rec slog.Record) error {
+	e := Entry{Time: rec.Time, Level: rec.Level, Message: rec.Message, Attrs: maps.Clone(r.attrs)}
+	rec.Attrs(func(a slog.Attr) bool {
+		flatten(e.Attrs, r.prefix, a)
+		return true
+	})
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	r.store.entries = append(r.store.entries, e)
+	return nil
+}
+
+func (r *Recorder) WithAttrs(attrs []slog.Attr) slog.Handler {
+	flat := maps.Clone(r.attrs)
+	for _, a := range attrs {
+		flatten(flat, r.prefix, a)
+	}
+	return &Recorder{store: r.store, prefix: r.prefix, attrs: flat}
+}
+
+func (r *Recorder) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return r
+	}
+	return &Recorder{store: r.store, prefix: r.prefix + name + ".", attrs: r.attrs}
+}
+
+// flatten adds a to m under prefix, following slog's rules: attributes
+// with no key are dropped, and so are empty groups, while a group with
+// no key is inlined.
+func flatten(m map[string]any, prefix string, a slog.Attr) {
+	v := a.Value.Resolve()
+	if v.Kind() != slog.KindGroup {
+		if a.Key != "" {
+			m[prefix+a.Key] = v.Any()
+		}
+		return
+	}
+	if a.Key != "" {
+		prefix += a.Key + "."
+	}
+	for _, inner := range v.Group() {
+		flatten(m, prefix, inner)
+	}
+}
+
+// Entries returns every record so far, oldest first.
+func (r *Recorder) Entries() []Entry {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	return slices.Clone(r.store.entries)
+}
+
+// Find returns the first record with the given message.
+func (r *Recorder) Find(message string) (Entry, bool) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	for _, e := range r.store.entries {
+		if e.Message == message {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
+
+// Count returns how many records were at the given level.
+func (r *Recorder) Count(level slog.Level) int {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	n := 0
+	for _, e := range r.store.entries {
+		if e.Level == level {
+			n++
+		}
+	}
+	return n
+}
+
+// Reset forgets every record.
+func (r *Recorder) Reset() {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	r.store.entries = nil
+}
@@ -0,0 +1,104 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Sampling thins out records that repeat. In each Interval, the first
+// First records with the same level and message are logged, and after
+// that every Thereafter-th. Warnings and errors, or whatever is at Level
+// or above, are always logged.
+type Sampling struct {
+	Interval   time.Duration // 0 means one second
+	First      int
+	Thereafter int          // 0 drops everything after the first First
+	Level      slog.Leveler // nil means slog.LevelWarn
+
+	// Now reads the clock; nil means time.Now.
+	Now func() time.Time
+}
+
+// Sampler is a handler that samples records on their way to another
+// handler, as set up by Sampling. Handlers made from it by WithAttrs and
+// WithGroup share its counts. It is safe for concurrent use.
+type Sampler struct {
+	next  slog.Handler
+	cfg   Sampling
+	state *sampleState
+}
+
+type sampleKey struct {
+	level   slog.Level
+	message string
+}
+
+type sampleState struct {
+	mu      sync.Mutex
+	window  time.Time // start of the current interval
+	counts  map[sampleKey]int
+	dropped uint64
+}
+
+// NewSampler returns a Sampler sending the records it keeps to next.
+func NewSampler(next slog.Handler, s Sampling) *Sampler {
+	if s.Interval <= 0 {
+		s.Interval = time.Second
+	}
+	if s.Level == nil {
+		s.Level = slog.LevelWarn
+	}
+	if s.Now == nil {
+		s.Now = time.Now
+	}
+	return &Sampler{next: next, cfg: s, state: &sampleState{counts: make(map[sampleKey]int)}}
+}
+
+func (s *Sampler) Enabled(ctx context.Context, level slog.Level) bool {
+	return s.next.Enabled(ctx, level)
+}
+
+func (s *Sampler) Handle(ctx context.Context, r slog.Record) error {
+	if !s.keep(r) {
+		return nil
+	}
+	return s.next.Handle(ctx, r)
+}
+
+func (s *Sampler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &Sampler{next: s.next.WithAttrs(attrs), cfg: s.cfg, state: s.state}
+}
+
+func (s *Sampler) WithGroup(name string) slog.Handler {
+	return &Sampler{next: s.next.WithGroup(name), cfg: s.cfg, state: s.state}
+}
+
+// Dropped returns how many records have been sampled out.
+func (s *Sampler) Dropped() uint64 {
+	s.state.mu.Lock()
+	defer s.state.mu.Unlock()
+	return s.state.dropped
+}
+
+func (s *Sampler) keep(r slog.Record) bool {
+	if r.Level >= s.cfg.Level.Level() {
+		return true
+	}
+	st := s.state
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if now := s.cfg.Now(); now.Sub(st.window) >= s.cfg.Interval {
+		st.window = now
+		clear(st.counts)
+	}
+	key := sampleKey{r.Level, r.Message}
+	st.counts[key]++
+	n := st.counts[key]
+	if n <= s.cfg.First || (s.cfg.Thereafter > 0 && (n-s.cfg.First)%s.cfg.Thereafter == 0) {
+		return true
+	}
+	st.dropped++
+	return false
+}
@@ -0,0 +1,123 @@
+package logging_test
+
+import (
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	"go-practice/logging"
+)
+
+// sampled logs "tick" n times at info, with i from 0, and returns the
+// i of every record kept.
+func sampled(logger *slog.Logger, rec *logging.Recorder, n int) []int64 {
+	before := len(rec.Entries())
+	for i := range n {
+		logger.Info("tick", "i", i)
+	}
+	var kept []int64
+	for _, e := range rec.Entries()[before:] {
+		kept = append(kept, e.Attrs["i"].(int64))
+	}
+	return kept
+}
+
+func TestSampler(t *testing.T) {
+	tests := []struct {
+		name        string
+		first, then int
+		want        []int64
+	}{
+		{"first only", 3, 0, []int64{0, 1, 2}},
+		{"first then every third", 2, 3, []int64{0, 1, 4, 7}},
+		{"every other from the start", 0, 2, []int64{1, 3, 5, 7}},
+		{"nothing", 0, 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := logging.NewRecorder()
+			s := logging.NewSampler(rec, logging.Sampling{
+				First: tt.first, Thereafter: tt.then,
+				Now: func() time.Time { return time.Unix(0, 0) },
+			})
+			got := sampled(slog.New(s), rec, 9)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("kept %v, want %v", got, tt.want)
+			}
+			if s.Dropped() != uint64(9-len(tt.want)) {
+				t.Errorf("Dropped = %d, want %d", s.Dropped(), 9-len(tt.want))
+			}
+		})
+	}
+}
+
+func TestSamplerWindow(t *testing.T) {
+	now := time.Unix(1000, 0)
+	rec := logging.NewRecorder()
+	s := logging.NewSampler(rec, logging.Sampling{
+		Interval: time.Minute, First: 2,
+		Now: func() time.Time { return now },
+	})
+	logger := slog.New(s)
+
+	if got := sampled(logger, rec, 3); !slices.Equal(got, []int64{0, 1}) {
+		t.Errorf("first window kept %v", got)
+	}
+	now = now.Add(59 * time.Second)
+	if got := sampled(logger, rec, 1); got != nil {
+		t.Errorf("a second before the window ends kept %v", got)
+	}
+	now = now.Add(time.Second)
+	if got := sampled(logger, rec, 3); !slices.Equal(got, []int64{0, 1}) {
+		t.Errorf("next window kept %v", got)
+	}
+	if s.Dropped() != 3 {
+		t.Errorf("Dropped = %d, want 3", s.Dropped())
+	}
+}
+
+func TestSamplerKeys(t *testing.T) {
+	rec := logging.NewRecorder()
+	s := logging.NewSampler(rec, logging.Sampling{
+		First: 1, Level: slog.LevelError,
+		Now: func() time.Time { return time.Unix(0, 0) },
+	})
+	logger := slog.New(s)
+	for range 3 {
+		logger.Info("a")
+		logger.Info("b")
+		logger.Warn("a") // another level, counted apart
+		logger.Error("a")
+		logger.With("k", "v").WithGroup("g").Info("a") // shares the counts
+	}
+
+	counts := map[slog.Level]int{}
+	for _, e := range rec.Entries() {
+		counts[e.Level]++
+	}
+	want := map[slog.Level]int{slog.LevelInfo: 2, slog.LevelWarn: 1, slog.LevelError: 3}
+	for level, n := range want {
+		if counts[level] != n {
+			t.Errorf("%s records kept = %d, want %d", level, counts[level], n)
+		}
+	}
+	if s.Dropped() != 9 {
+		t.Errorf("Dropped = %d, want 9", s.Dropped())
+	}
+}
+
+func TestSamplingOption(t *testing.T) {
+	logger, rec := logging.NewTest(logging.Options{
+		Level:    slog.LevelDebug,
+		Sampling: &logging.Sampling{First: 1, Now: func() time.Time { return time.Unix(0, 0) }},
+	})
+	for range 5 {
+		logger.Debug("poll")
+		logger.Warn("slow")
+	}
+	if rec.Count(slog.LevelDebug) != 1 || rec.Count(slog.LevelWarn) != 5 {
+		t.Errorf("kept %d debug and %d warn records, want 1 and 5 (warnings are never sampled by default)",
+			rec.Count(slog.LevelDebug), rec.Count(slog.LevelWarn))
+	}
+}
@@ -0,0 +1,61 @@
+package org
+
+// ManagerChain returns id's manager, their manager and so on up to the
+// head of the organization. The head's chain is empty.
+func (o *Org) ManagerChain(id string) ([]Employee, error) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	e, ok := o.employees[id]
+	if !ok {
+		return nil, notFound("employee", id)
+	}
+	var chain []Employee
+	for m := e.ManagerID; m != ""; m = o.employees[m].ManagerID {
+		chain = append(chain, o.employees[m])
+	}
+	return chain, nil
+}
+
+// DirectReports returns the employees who report to id, ordered by ID.
+func (o *Org) DirectReports(id string) ([]Employee, error) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	if _, ok := o.employees[id]; !ok {
+		return nil, notFound("employee", id)
+	}
+	reports := make([]Employee, len(o.reports[id]))
+	for i, r := range o.reports[id] {
+		reports[i] = o.employees[r]
+	}
+	return reports, nil
+}
+
+// Span describes a manager's span of control: Direct reports, everyone
+// below them in Total, and the Depth of the deepest chain beneath them
+// (0 for someone with no reports).
+type Span struct {
+	Direct int
+	Total  int
+	Depth  int
+}
+
+// SpanOfControl measures the part of the hierarchy under id.
+func (o *Org) SpanOfControl(id string) (Span, error) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	if _, ok := o.employees[id]; !ok {
+		return Span{}, notFound("employee", id)
+	}
+	return o.span(id), nil
+}
+
+func (o *Org) span(id string) Span {
+	s := Span{Direct: len(o.reports[id])}
+	s.Total = s.Direct
+	for _, r := range o.reports[id] {
+		sub := o.span(r)
+		s.Total += sub.Total
+		s.Depth = max(s.Depth, sub.Depth+1)
+	}
+	return s
+}
@@ -0,0 +1,86 @@
+package org
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// dotEscaper escapes a DOT string; strconv.Quote won't do, as DOT has no
+// \u escapes for non-ASCII names.
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func quote(s string) string { return `"` + dotEscaper.Replace(s) + `"` }
+
+// WriteChartDOT writes the reporting hierarchy as a Graphviz digraph,
+// one box per employee with an edge from each manager to each report:
+//
+//	dot -Tsvg chart.dot > chart.svg
+func (o *Org) WriteChartDOT(w io.Writer) error {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "digraph org {")
+	fmt.Fprintln(bw, "  rankdir=TB;")
+	fmt.Fprintln(bw, "  node [shape=box];")
+	var walk func(id string)
+	walk = func(id string) {
+		e := o.employees[id]
+		fmt.Fprintf(bw, "  %s [label=%s];\n", quote(id), quote(e.Name+"\n"+e.Role))
+		for _, r := range o.reports[id] {
+			fmt.Fprintf(bw, "  %s -> %s;\n", quote(id), quote(r))
+			walk(r)
+		}
+	}
+	if head, ok := o.head(); ok {
+		walk(head.ID)
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+// WriteTeamsDOT writes the teams as nested Graphviz clusters, each
+// holding its members. Someone in several teams appears in each, and the
+// lead is drawn in bold.
+func (o *Org) WriteTeamsDOT(w io.Writer) error {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "digraph teams {")
+	fmt.Fprintln(bw, "  node [shape=box];")
+	var walk func(t *team, indent string)
+	walk = func(t *team, indent string) {
+		fmt.Fprintf(bw, "%ssubgraph %s {\n", indent, quote("cluster_"+t.name))
+		fmt.Fprintf(bw, "%s  label=%s;\n", indent, quote(t.name))
+		for _, m := range o.memberships(t) {
+			style := ""
+			switch m.Role {
+			case Lead:
+				style = ", style=bold"
+			case Guest:
+				style = ", style=dashed"
+			}
+			// node IDs are per team, as a node can sit in only one cluster
+			fmt.Fprintf(bw, "%s  %s [label=%s%s];\n", indent,
+				quote(t.name+"/"+m.Employee.ID), quote(m.Employee.Name), style)
+		}
+		if len(t.members) == 0 && len(t.children) == 0 {
+			// an empty cluster isn't drawn at all
+			fmt.Fprintf(bw, "%s  %s [label=\"\", style=invis];\n", indent, quote(t.name+"/"))
+		}
+		for _, c := range t.children {
+			walk(o.teams[c], indent+"  ")
+		}
+		fmt.Fprintf(bw, "%s}\n", indent)
+	}
+	for _, name := range o.teamNames() {
+		if t := o.teams[name]; t.parent == "" {
+			walk(t, "  ")
+		}
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
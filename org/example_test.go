@@ -0,0 +1,94 @@
+package org_test
+
+import (
+	"fmt"
+	"os"
+
+	"go-practice/org"
+)
+
+func Example() {
+	company := org.New()
+	hires := []org.Employee{
+		{ID: "grace", Person: org.Person{Name: "Grace", Age: 45, Email: "grace@example.com", IsActive: true}, Role: "CTO"},
+		{ID: "alice", ManagerID: "grace", Person: org.Person{Name: "Alice", Age: 25, Email: "alice@example.com", IsActive: true}, Role: "Engineering Manager"},
+		{ID: "bob", ManagerID: "alice", Person: org.Person{Name: "Bob", Age: 30, Email: "bob@example.com", IsActive: true}, Role: "Engineer"},
+		{ID: "charlie", ManagerID: "alice", Person: org.Person{Name: "Charlie", Age: 28, Email: "charlie@example.com"}, Role: "Engineer"},
+		{ID: "diana", ManagerID: "bob", Person: org.Person{Name: "Diana", Age: 27, Email: "diana@example.com", IsActive: true}, Role: "Junior Engineer"},
+		// Emails are compared without case, so this one is taken
+		{ID: "bobby", ManagerID: "alice", Person: org.Person{Name: "Bobby", Email: "BOB@example.com"}, Role: "Engineer"},
+	}
+	for _, e := range hires {
+		if err := company.Hire(e); err != nil {
+			fmt.Println("Hire failed:", err)
+		}
+	}
+
+	for _, step := range []error{
+		company.CreateTeam("Engineering", ""),
+		company.CreateTeam("Platform", "Engineering"),
+		company.AddMember("Engineering", "alice", org.Lead),
+		company.AddMember("Platform", "bob", org.Lead),
+		company.AddMember("Platform", "diana", org.Member),
+	} {
+		if step != nil {
+			fmt.Println(step)
+			return
+		}
+	}
+	if err := company.AddMember("Platform", "charlie", org.Lead); err != nil {
+		fmt.Println("Second lead:", err)
+	}
+
+	chain, err := company.ManagerChain("diana")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Print("Diana reports to:")
+	for _, m := range chain {
+		fmt.Printf(" %s (%s)", m.Name, m.Role)
+	}
+	fmt.Println()
+	span, err := company.SpanOfControl("alice")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Printf("Alice's span of control: %d direct, %d total\n", span.Direct, span.Total)
+
+	// Bob leaves: Diana moves up to Alice and out of Bob's teams
+	if err := company.Remove("bob"); err != nil {
+		fmt.Println(err)
+		return
+	}
+	reports, err := company.DirectReports("alice")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Printf("After Bob leaves, Alice has %d reports\n", len(reports))
+
+	// Graphviz turns this into a picture: dot -Tsvg chart.dot > chart.svg
+	if err := company.WriteChartDOT(os.Stdout); err != nil {
+		fmt.Println(err)
+		return
+	}
+	// Output:
+	// Hire failed: hire "bobby": email already in use by "bob"
+	// Second lead: team "Platform": team already has a lead (bob)
+	// Diana reports to: Bob (Engineer) Alice (Engineering Manager) Grace (CTO)
+	// Alice's span of control: 2 direct, 3 total
+	// After Bob leaves, Alice has 2 reports
+	// digraph org {
+	//   rankdir=TB;
+	//   node [shape=box];
+	//   "grace" [label="Grace\nCTO"];
+	//   "grace" -> "alice";
+	//   "alice" [label="Alice\nEngineering Manager"];
+	//   "alice" -> "charlie";
+	//   "charlie" [label="Charlie\nEngineer"];
+	//   "alice" -> "diana";
+	//   "diana" [label="Diana\nJunior Engineer"];
+	// }
+}
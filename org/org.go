@@ -0,0 +1,247 @@
+// Package org models an organization built from the chapter 7 Person and
+// Employee: a reporting hierarchy in which every employee but the top has
+// a manager, and a tree of teams and sub-teams whose members each hold a
+// role. Email addresses are unique across the organization, so the same
+// person can't be hired twice under two IDs. Org answers org-chart
+// questions such as an employee's chain of managers and span of control,
+// and exports the hierarchy and the teams as Graphviz DOT.
+package org
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"go-practice/errs"
+)
+
+var (
+	// ErrNotFound is returned for an unknown employee ID or team name.
+	ErrNotFound = errors.New("not found")
+
+	// ErrDuplicate is returned when an employee ID or team name is taken.
+	ErrDuplicate = errors.New("already exists")
+
+	// ErrDuplicateEmail is returned when hiring someone whose email
+	// address, ignoring case, belongs to another employee.
+	ErrDuplicateEmail = errors.New("email already in use")
+
+	// ErrCycle is returned for a change that would make someone their own
+	// manager, or a team its own sub-team.
+	ErrCycle = errors.New("would create a cycle")
+)
+
+// Person is the chapter 7 Person.
+type Person struct {
+	Name     string
+	Age      int
+	Email    string
+	IsActive bool
+}
+
+// Employee is the chapter 7 Employee placed in the hierarchy: ID is its
+// key and ManagerID its manager's ID, empty only at the top. Role is the
+// job title; a team role is separate, see Role.
+type Employee struct {
+	Person
+	ID        string
+	ManagerID string
+	Salary    int
+	Role      string
+}
+
+// Org is an organization. It is safe for concurrent use.
+type Org struct {
+	mu        sync.RWMutex
+	employees map[string]Employee
+	reports   map[string][]string // manager ID to direct report IDs, sorted
+	emails    map[string]string   // normalized email to employee ID
+	teams     map[string]*team
+}
+
+// New returns an empty organization.
+func New() *Org {
+	return &Org{
+		employees: make(map[string]Employee),
+		reports:   make(map[string][]string),
+		emails:    make(map[string]string),
+		teams:     make(map[string]*team),
+	}
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func notFound(kind, key string) error {
+	return fmt.Errorf("%s %q: %w", kind, key, ErrNotFound)
+}
+
+// validate checks the fields an employee needs, reporting each problem.
+func (e Employee) validate() error {
+	var problems []error
+	if strings.TrimSpace(e.ID) == "" {
+		problems = append(problems, &errs.ValidationError{Field: "ID", Message: "is required"})
+	}
+	if strings.TrimSpace(e.Name) == "" {
+		problems = append(problems, &errs.ValidationError{Field: "Name", Message: "is required"})
+	}
+	if local, domain, ok := strings.Cut(e.Email, "@"); !ok || local == "" || !strings.Contains(domain, ".") {
+		problems = append(problems, &errs.ValidationError{Field: "Email", Message: "is not an email address", Value: e.Email})
+	}
+	if e.Salary < 0 {
+		problems = append(problems, &errs.ValidationError{Field: "Salary", Message: "can't be negative", Value: e.Salary})
+	}
+	return errs.Aggregate("hire", problems...)
+}
+
+// Hire adds an employee reporting to e.ManagerID, who must already be
+// employed. Only the first employee may have no manager.
+func (o *Org) Hire(e Employee) error {
+	if err := e.validate(); err != nil {
+		return err
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if _, ok := o.employees[e.ID]; ok {
+		return fmt.Errorf("employee %q: %w", e.ID, ErrDuplicate)
+	}
+	if other, ok := o.emails[normalizeEmail(e.Email)]; ok {
+		return fmt.Errorf("hire %q: %w by %q", e.ID, ErrDuplicateEmail, other)
+	}
+	switch {
+	case e.ManagerID != "":
+		if _, ok := o.employees[e.ManagerID]; !ok {
+			return fmt.Errorf("hire %q: manager %w", e.ID, notFound("employee", e.ManagerID))
+		}
+	case len(o.employees) > 0:
+		return &errs.ValidationError{Field: "ManagerID", Message: "is required once the organization has a head"}
+	}
+
+	o.employees[e.ID] = e
+	o.emails[normalizeEmail(e.Email)] = e.ID
+	o.addReport(e.ManagerID, e.ID)
+	return nil
+}
+
+func (o *Org) addReport(manager, id string) {
+	if manager == "" {
+		return
+	}
+	reports := o.reports[manager]
+	i, _ := slices.BinarySearch(reports, id)
+	o.reports[manager] = slices.Insert(reports, i, id)
+}
+
+func (o *Org) removeReport(manager, id string) {
+	o.reports[manager] = slices.DeleteFunc(o.reports[manager], func(r string) bool { return r == id })
+	if len(o.reports[manager]) == 0 {
+		delete(o.reports, manager)
+	}
+}
+
+// Remove takes an employee out of the organization and out of every
+// team. Their direct reports move up to their manager. The head of the
+// organization can only be removed once nobody else is left.
+func (o *Org) Remove(id string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	e, ok := o.employees[id]
+	if !ok {
+		return notFound("employee", id)
+	}
+	if e.ManagerID == "" && len(o.employees) > 1 {
+		return fmt.Errorf("remove %q: the head of the organization still has staff", id)
+	}
+
+	for _, r := range o.reports[id] {
+		report := o.employees[r]
+		report.ManagerID = e.ManagerID
+		o.employees[r] = report
+		o.addReport(e.ManagerID, r)
+	}
+	delete(o.reports, id)
+	o.removeReport(e.ManagerID, id)
+	for _, t := range o.teams {
+		delete(t.members, id)
+	}
+	delete(o.emails, normalizeEmail(e.Email))
+	delete(o.employees, id)
+	return nil
+}
+
+// Transfer makes id report to manager, bringing their own reports along.
+func (o *Org) Transfer(id, manager string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	e, ok := o.employees[id]
+	if !ok {
+		return notFound("employee", id)
+	}
+	if _, ok := o.employees[manager]; !ok {
+		return fmt.Errorf("transfer %q: manager %w", id, notFound("employee", manager))
+	}
+	for m := manager; m != ""; m = o.employees[m].ManagerID {
+		if m == id {
+			return fmt.Errorf("transfer %q to %q: %w", id, manager, ErrCycle)
+		}
+	}
+
+	o.removeReport(e.ManagerID, id)
+	e.ManagerID = manager
+	o.employees[id] = e
+	o.addReport(manager, id)
+	return nil
+}
+
+// Employee returns the employee with the given ID.
+func (o *Org) Employee(id string) (Employee, error) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	e, ok := o.employees[id]
+	if !ok {
+		return Employee{}, notFound("employee", id)
+	}
+	return e, nil
+}
+
+// FindByEmail returns the employee with the given email address,
+// ignoring case.
+func (o *Org) FindByEmail(email string) (Employee, bool) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	id, ok := o.emails[normalizeEmail(email)]
+	return o.employees[id], ok
+}
+
+// Employees returns everyone, ordered by ID.
+func (o *Org) Employees() []Employee {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	all := make([]Employee, 0, len(o.employees))
+	for _, e := range o.employees {
+		all = append(all, e)
+	}
+	slices.SortFunc(all, func(a, b Employee) int { return cmp.Compare(a.ID, b.ID) })
+	return all
+}
+
+// Head returns the employee at the top of the hierarchy.
+func (o *Org) Head() (Employee, bool) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.head()
+}
+
+func (o *Org) head() (Employee, bool) {
+	for _, e := range o.employees {
+		if e.ManagerID == "" {
+			return e, true
+		}
+	}
+	return Employee{}, false
+}
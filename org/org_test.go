@@ -0,0 +1,254 @@
+package org
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// newTestOrg returns grace at the top, alice reporting to her, bob and
+// charlie reporting to alice and diana reporting to bob, with teams
+// Engineering > Platform > Storage and Engineering > Web.
+func newTestOrg(t *testing.T) *Org {
+	t.Helper()
+	o := New()
+	for _, e := range []Employee{
+		{ID: "grace", Person: Person{Name: "Grace", Email: "grace@example.com"}},
+		{ID: "alice", ManagerID: "grace", Person: Person{Name: "Alice", Email: "alice@example.com"}},
+		{ID: "bob", ManagerID: "alice", Person: Person{Name: "Bob", Email: "bob@example.com"}},
+		{ID: "charlie", ManagerID: "alice", Person: Person{Name: "Charlie", Email: "charlie@example.com"}},
+		{ID: "diana", ManagerID: "bob", Person: Person{Name: "Diana", Email: "diana@example.com"}},
+	} {
+		if err := o.Hire(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, team := range [][2]string{
+		{"Engineering", ""}, {"Platform", "Engineering"}, {"Storage", "Platform"}, {"Web", "Engineering"},
+	} {
+		if err := o.CreateTeam(team[0], team[1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return o
+}
+
+// tree returns every team's sub-teams, checking that each team is among
+// its parent's.
+func tree(t *testing.T, o *Org) map[string][]string {
+	t.Helper()
+	children := make(map[string][]string)
+	for name, tm := range o.teams {
+		children[name] = tm.children
+		if tm.parent == "" {
+			continue
+		}
+		if !slices.Contains(o.teams[tm.parent].children, name) {
+			t.Errorf("%s is missing from its parent %s's sub-teams %v", name, tm.parent, o.teams[tm.parent].children)
+		}
+	}
+	return children
+}
+
+func TestMoveTeam(t *testing.T) {
+	tests := []struct {
+		name, team, parent string
+		wantErr            error
+		want               map[string][]string // sub-teams after the move
+	}{
+		{
+			name: "to its current parent", team: "Storage", parent: "Platform",
+			want: map[string][]string{"Engineering": {"Platform", "Web"}, "Platform": {"Storage"}},
+		},
+		{
+			name: "to another parent", team: "Storage", parent: "Web",
+			want: map[string][]string{"Engineering": {"Platform", "Web"}, "Web": {"Storage"}},
+		},
+		{
+			name: "with its sub-teams", team: "Platform", parent: "Web",
+			want: map[string][]string{"Engineering": {"Web"}, "Web": {"Platform"}, "Platform": {"Storage"}},
+		},
+		{
+			name: "to the top", team: "Platform", parent: "",
+			want: map[string][]string{"Engineering": {"Web"}, "Platform": {"Storage"}},
+		},
+		{
+			name: "top-level team to the top", team: "Engineering", parent: "",
+			want: map[string][]string{"Engineering": {"Platform", "Web"}, "Platform": {"Storage"}},
+		},
+		{name: "under itself", team: "Platform", parent: "Platform", wantErr: ErrCycle},
+		{name: "under its own sub-team", team: "Engineering", parent: "Storage", wantErr: ErrCycle},
+		{name: "unknown team", team: "Sales", parent: "Engineering", wantErr: ErrNotFound},
+		{name: "unknown parent", team: "Web", parent: "Sales", wantErr: ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := newTestOrg(t)
+			before := tree(t, o)
+			err := o.MoveTeam(tt.team, tt.parent)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("MoveTeam = %v, want %v", err, tt.wantErr)
+				}
+				tt.want = before // a failed move changes nothing
+			} else if err != nil {
+				t.Fatalf("MoveTeam: %v", err)
+			}
+
+			got := tree(t, o)
+			for name, children := range got {
+				if !slices.Equal(children, tt.want[name]) {
+					t.Errorf("%s has sub-teams %v, want %v", name, children, tt.want[name])
+				}
+			}
+			if tt.wantErr == nil && o.teams[tt.team].parent != tt.parent {
+				t.Errorf("%s's parent is %q, want %q", tt.team, o.teams[tt.team].parent, tt.parent)
+			}
+		})
+	}
+}
+
+func TestRemoveTeam(t *testing.T) {
+	o := newTestOrg(t)
+	if err := o.RemoveTeam("Platform"); !errors.Is(err, ErrNotEmpty) {
+		t.Errorf("removing a team with sub-teams = %v, want %v", err, ErrNotEmpty)
+	}
+	if err := o.AddMember("Web", "charlie", Member); err != nil {
+		t.Fatal(err)
+	}
+	if err := o.RemoveTeam("Web"); !errors.Is(err, ErrNotEmpty) {
+		t.Errorf("removing a team with members = %v, want %v", err, ErrNotEmpty)
+	}
+	if err := o.RemoveTeam("Storage"); err != nil {
+		t.Fatalf("RemoveTeam: %v", err)
+	}
+	if subs, _ := o.SubTeams("Platform"); len(subs) != 0 {
+		t.Errorf("Platform's sub-teams after removing Storage = %v, want none", subs)
+	}
+	if err := o.RemoveTeam("Storage"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("removing Storage twice = %v, want %v", err, ErrNotFound)
+	}
+}
+
+func TestTransfer(t *testing.T) {
+	tests := []struct {
+		name, id, manager string
+		wantErr           error
+		reports           map[string][]string // direct reports afterwards
+	}{
+		{
+			name: "to a peer", id: "diana", manager: "charlie",
+			reports: map[string][]string{"alice": {"bob", "charlie"}, "bob": nil, "charlie": {"diana"}},
+		},
+		{
+			name: "with their reports", id: "bob", manager: "grace",
+			reports: map[string][]string{"grace": {"alice", "bob"}, "alice": {"charlie"}, "bob": {"diana"}},
+		},
+		{
+			name: "to their current manager", id: "bob", manager: "alice",
+			reports: map[string][]string{"alice": {"bob", "charlie"}, "bob": {"diana"}},
+		},
+		{name: "to themselves", id: "bob", manager: "bob", wantErr: ErrCycle},
+		{name: "under their own report", id: "alice", manager: "diana", wantErr: ErrCycle},
+		{name: "unknown manager", id: "bob", manager: "zoe", wantErr: ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := newTestOrg(t)
+			err := o.Transfer(tt.id, tt.manager)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Transfer = %v, want %v", err, tt.wantErr)
+				}
+				tt.reports = map[string][]string{"alice": {"bob", "charlie"}, "bob": {"diana"}}
+			} else if err != nil {
+				t.Fatalf("Transfer: %v", err)
+			}
+			for manager, want := range tt.reports {
+				if got := o.reports[manager]; !slices.Equal(got, want) {
+					t.Errorf("%s's reports = %v, want %v", manager, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRemove(t *testing.T) {
+	o := newTestOrg(t)
+	if err := o.AddMember("Platform", "bob", Lead); err != nil {
+		t.Fatal(err)
+	}
+	if err := o.Remove("grace"); err == nil {
+		t.Error("removing the head while others remain succeeded")
+	}
+	if err := o.Remove("bob"); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+
+	if got, want := o.reports["alice"], []string{"charlie", "diana"}; !slices.Equal(got, want) {
+		t.Errorf("alice's reports = %v, want %v", got, want)
+	}
+	if d, _ := o.Employee("diana"); d.ManagerID != "alice" {
+		t.Errorf("diana's manager = %q, want alice", d.ManagerID)
+	}
+	if ms := o.TeamsOf("bob"); len(ms) != 0 {
+		t.Errorf("bob is still in %v", ms)
+	}
+	if _, ok := o.FindByEmail("bob@example.com"); ok {
+		t.Error("bob's email is still taken")
+	}
+	if err := o.Hire(Employee{ID: "bob2", ManagerID: "alice", Person: Person{Name: "Bob", Email: "BOB@example.com"}}); err != nil {
+		t.Errorf("rehiring with bob's email: %v", err)
+	}
+}
+
+func TestHeadcount(t *testing.T) {
+	o := newTestOrg(t)
+	for _, m := range []struct {
+		team, id string
+		role     Role
+	}{
+		{"Engineering", "alice", Lead},
+		{"Platform", "bob", Lead},
+		{"Platform", "diana", Member},
+		{"Storage", "diana", Member}, // in two teams, counted once
+		{"Storage", "grace", Guest},  // guests don't count
+		{"Web", "charlie", Lead},
+	} {
+		if err := o.AddMember(m.team, m.id, m.role); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		team string
+		want int
+	}{
+		{"Engineering", 4},
+		{"Platform", 2},
+		{"Storage", 1},
+		{"Web", 1},
+	}
+	for _, tt := range tests {
+		got, err := o.Headcount(tt.team)
+		if err != nil {
+			t.Fatalf("Headcount(%s): %v", tt.team, err)
+		}
+		if got != tt.want {
+			t.Errorf("Headcount(%s) = %d, want %d", tt.team, got, tt.want)
+		}
+	}
+	if _, err := o.Headcount("Sales"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Headcount(Sales) = %v, want %v", err, ErrNotFound)
+	}
+
+	// after a move, the old parent no longer counts the team's members
+	if err := o.MoveTeam("Storage", "Web"); err != nil {
+		t.Fatal(err)
+	}
+	for team, want := range map[string]int{"Engineering": 4, "Platform": 2, "Web": 2} {
+		if got, _ := o.Headcount(team); got != want {
+			t.Errorf("after moving Storage, Headcount(%s) = %d, want %d", team, got, want)
+		}
+	}
+}
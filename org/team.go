@@ -0,0 +1,332 @@
+package org
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+
+	"go-practice/errs"
+)
+
+var (
+	// ErrRoleTaken is returned when a team already has a Lead.
+	ErrRoleTaken = errors.New("team already has a lead")
+
+	// ErrNotEmpty is returned when removing a team that still has
+	// members or sub-teams.
+	ErrNotEmpty = errors.New("team is not empty")
+)
+
+// Role is a member's part in a team. A team has at most one Lead.
+type Role string
+
+const (
+	Lead   Role = "lead"
+	Member Role = "member"
+	Guest  Role = "guest" // sits in without counting towards the team's size
+)
+
+var roleOrder = []Role{Lead, Member, Guest}
+
+func (r Role) valid() bool { return slices.Contains(roleOrder, r) }
+
+type team struct {
+	name     string
+	parent   string   // "" for a top-level team
+	children []string // sorted
+	members  map[string]Role
+}
+
+// Membership is an employee's place in one team.
+type Membership struct {
+	Team     string
+	Employee Employee
+	Role     Role
+}
+
+// team returns the named team. The caller holds o.mu.
+func (o *Org) team(name string) (*team, error) {
+	t, ok := o.teams[name]
+	if !ok {
+		return nil, notFound("team", name)
+	}
+	return t, nil
+}
+
+// CreateTeam adds a team, as a sub-team of parent unless parent is "".
+func (o *Org) CreateTeam(name, parent string) error {
+	if name == "" {
+		return &errs.ValidationError{Field: "Team", Message: "is required"}
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if _, ok := o.teams[name]; ok {
+		return fmt.Errorf("team %q: %w", name, ErrDuplicate)
+	}
+	if parent != "" {
+		p, err := o.team(parent)
+		if err != nil {
+			return err
+		}
+		p.addChild(name)
+	}
+	o.teams[name] = &team{name: name, parent: parent, members: make(map[string]Role)}
+	return nil
+}
+
+func (t *team) addChild(name string) {
+	i, _ := slices.BinarySearch(t.children, name)
+	t.children = slices.Insert(t.children, i, name)
+}
+
+func (t *team) removeChild(name string) {
+	t.children = slices.DeleteFunc(t.children, func(c string) bool { return c == name })
+}
+
+// MoveTeam makes name a sub-team of parent, or a top-level team if
+// parent is "", bringing its own sub-teams along.
+func (o *Org) MoveTeam(name, parent string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	t, err := o.team(name)
+	if err != nil {
+		return err
+	}
+	var p *team
+	if parent != "" {
+		if p, err = o.team(parent); err != nil {
+			return err
+		}
+		for a := p; a != nil; a = o.teams[a.parent] {
+			if a == t {
+				return fmt.Errorf("move team %q under %q: %w", name, parent, ErrCycle)
+			}
+		}
+	}
+	// out of the old parent before into the new one, which may be the same
+	if old, ok := o.teams[t.parent]; ok {
+		old.removeChild(name)
+	}
+	if p != nil {
+		p.addChild(name)
+	}
+	t.parent = parent
+	return nil
+}
+
+// RemoveTeam deletes a team that has no members and no sub-teams.
+func (o *Org) RemoveTeam(name string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	t, err := o.team(name)
+	if err != nil {
+		return err
+	}
+	if len(t.members) > 0 || len(t.children) > 0 {
+		return fmt.Errorf("remove team %q: %w", name, ErrNotEmpty)
+	}
+	if p, ok := o.teams[t.parent]; ok {
+		p.removeChild(name)
+	}
+	delete(o.teams, name)
+	return nil
+}
+
+// checkRole reports whether id may take role in t.
+func (o *Org) checkRole(t *team, id string, role Role) error {
+	if !role.valid() {
+		return &errs.ValidationError{Field: "Role", Message: "must be lead, member or guest", Value: role}
+	}
+	if role != Lead {
+		return nil
+	}
+	for other, r := range t.members {
+		if r == Lead && other != id {
+			return fmt.Errorf("team %q: %w (%s)", t.name, ErrRoleTaken, other)
+		}
+	}
+	return nil
+}
+
+// AddMember puts employee id into a team with the given role.
+func (o *Org) AddMember(teamName, id string, role Role) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	t, err := o.team(teamName)
+	if err != nil {
+		return err
+	}
+	if _, ok := o.employees[id]; !ok {
+		return notFound("employee", id)
+	}
+	if _, ok := t.members[id]; ok {
+		return fmt.Errorf("team %q member %q: %w", teamName, id, ErrDuplicate)
+	}
+	if err := o.checkRole(t, id, role); err != nil {
+		return err
+	}
+	t.members[id] = role
+	return nil
+}
+
+// SetRole changes a member's role in a team.
+func (o *Org) SetRole(teamName, id string, role Role) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	t, err := o.team(teamName)
+	if err != nil {
+		return err
+	}
+	if _, ok := t.members[id]; !ok {
+		return fmt.Errorf("team %q: member %w", teamName, notFound("employee", id))
+	}
+	if err := o.checkRole(t, id, role); err != nil {
+		return err
+	}
+	t.members[id] = role
+	return nil
+}
+
+// RemoveMember takes employee id out of a team.
+func (o *Org) RemoveMember(teamName, id string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	t, err := o.team(teamName)
+	if err != nil {
+		return err
+	}
+	if _, ok := t.members[id]; !ok {
+		return fmt.Errorf("team %q: member %w", teamName, notFound("employee", id))
+	}
+	delete(t.members, id)
+	return nil
+}
+
+// MoveMember moves employee id from one team to another, keeping their
+// role. Nothing changes if the move fails.
+func (o *Org) MoveMember(id, from, to string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	src, err := o.team(from)
+	if err != nil {
+		return err
+	}
+	dst, err := o.team(to)
+	if err != nil {
+		return err
+	}
+	role, ok := src.members[id]
+	if !ok {
+		return fmt.Errorf("team %q: member %w", from, notFound("employee", id))
+	}
+	if _, ok := dst.members[id]; ok {
+		return fmt.Errorf("team %q member %q: %w", to, id, ErrDuplicate)
+	}
+	if err := o.checkRole(dst, id, role); err != nil {
+		return err
+	}
+	delete(src.members, id)
+	dst.members[id] = role
+	return nil
+}
+
+// memberships lists t's members by role and then name. The caller holds
+// o.mu.
+func (o *Org) memberships(t *team) []Membership {
+	ms := make([]Membership, 0, len(t.members))
+	for id, role := range t.members {
+		ms = append(ms, Membership{Team: t.name, Employee: o.employees[id], Role: role})
+	}
+	slices.SortFunc(ms, func(a, b Membership) int {
+		return cmp.Or(
+			cmp.Compare(slices.Index(roleOrder, a.Role), slices.Index(roleOrder, b.Role)),
+			cmp.Compare(a.Employee.Name, b.Employee.Name),
+			cmp.Compare(a.Employee.ID, b.Employee.ID),
+		)
+	})
+	return ms
+}
+
+// Members returns a team's own members, lead first, then members and
+// guests, each by name.
+func (o *Org) Members(teamName string) ([]Membership, error) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	t, err := o.team(teamName)
+	if err != nil {
+		return nil, err
+	}
+	return o.memberships(t), nil
+}
+
+// AllMembers returns the members of a team and of all its sub-teams,
+// the team's own first and then each sub-team's depth first.
+func (o *Org) AllMembers(teamName string) ([]Membership, error) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	t, err := o.team(teamName)
+	if err != nil {
+		return nil, err
+	}
+	var all []Membership
+	var walk func(t *team)
+	walk = func(t *team) {
+		all = append(all, o.memberships(t)...)
+		for _, c := range t.children {
+			walk(o.teams[c])
+		}
+	}
+	walk(t)
+	return all, nil
+}
+
+// Headcount counts the leads and members, but not guests, of a team and
+// its sub-teams. Someone in several of them counts once.
+func (o *Org) Headcount(teamName string) (int, error) {
+	all, err := o.AllMembers(teamName)
+	if err != nil {
+		return 0, err
+	}
+	seen := make(map[string]bool)
+	for _, m := range all {
+		if m.Role != Guest {
+			seen[m.Employee.ID] = true
+		}
+	}
+	return len(seen), nil
+}
+
+// SubTeams returns the names of a team's direct sub-teams, sorted.
+func (o *Org) SubTeams(teamName string) ([]string, error) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	t, err := o.team(teamName)
+	if err != nil {
+		return nil, err
+	}
+	return slices.Clone(t.children), nil
+}
+
+// TeamsOf returns every team employee id belongs to, by team name.
+func (o *Org) TeamsOf(id string) []Membership {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	var ms []Membership
+	for _, name := range o.teamNames() {
+		if role, ok := o.teams[name].members[id]; ok {
+			ms = append(ms, Membership{Team: name, Employee: o.employees[id], Role: role})
+		}
+	}
+	return ms
+}
+
+// teamNames returns every team name, sorted. The caller holds o.mu.
+func (o *Org) teamNames() []string {
+	names := make([]string, 0, len(o.teams))
+	for name := range o.teams {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
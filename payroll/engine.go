@@ -0,0 +1,193 @@
+package payroll
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-practice/errs"
+)
+
+// ErrNotEmployed is returned for a period the employee didn't work any
+// of.
+var ErrNotEmployed = errors.New("not employed during the period")
+
+// Employee is what payroll needs to know about the chapter 7 Employee.
+// Salary is annual. Start and End are the first and last days employed;
+// a zero End means still employed.
+type Employee struct {
+	ID     string
+	Name   string
+	Salary Money
+	Start  time.Time
+	End    time.Time
+}
+
+func (e Employee) validate() error {
+	var problems []error
+	if strings.TrimSpace(e.ID) == "" {
+		problems = append(problems, &errs.ValidationError{Field: "ID", Message: "is required"})
+	}
+	if e.Salary.Currency() == "" {
+		problems = append(problems, &errs.ValidationError{Field: "Salary", Message: "needs a currency"})
+	}
+	if e.Salary.IsNegative() {
+		problems = append(problems, &errs.ValidationError{Field: "Salary", Message: "can't be negative", Value: e.Salary})
+	}
+	if e.Start.IsZero() {
+		problems = append(problems, &errs.ValidationError{Field: "Start", Message: "is required"})
+	}
+	if !e.End.IsZero() && e.End.Before(e.Start) {
+		problems = append(problems, &errs.ValidationError{
+			Field: "End", Message: "is before Start", Value: e.End.Format(time.DateOnly),
+		})
+	}
+	return errs.Aggregate(fmt.Sprintf("employee %q", e.ID), problems...)
+}
+
+// Engine runs payroll on a schedule with a set of rules.
+type Engine struct {
+	Schedule Schedule
+	Rules    []Rule
+
+	// ProRate measures partial periods; nil means WorkingDays.
+	ProRate ProRation
+}
+
+// Validate checks every rule that has a Validate method, such as
+// BracketTax, so a mistake in the rules shows up before anyone is paid.
+func (en *Engine) Validate() error {
+	var problems []error
+	for _, r := range en.Rules {
+		if v, ok := r.(interface{ Validate() error }); ok {
+			problems = append(problems, v.Validate())
+		}
+	}
+	return errs.Aggregate("payroll rules", problems...)
+}
+
+// Run computes one employee's pay for p. A rule that gives an amount in
+// another currency than the salary fails with ErrCurrencyMismatch.
+func (en *Engine) Run(e Employee, p Period) (Payslip, error) {
+	if err := e.validate(); err != nil {
+		return Payslip{}, err
+	}
+	start, end := day(e.Start), p.End
+	if !e.End.IsZero() {
+		end = day(e.End)
+	}
+	start = later(start, p.Start)
+	end = earlier(end, p.End)
+	if end.Before(start) {
+		return Payslip{}, fmt.Errorf("payroll: %s, %v: %w", e.ID, p, ErrNotEmployed)
+	}
+
+	proRate := en.ProRate
+	if proRate == nil {
+		proRate = WorkingDays
+	}
+	worked, total := proRate(p, start, end)
+	if total <= 0 {
+		// a period with no working days at all pays in full or not at all
+		worked, total = 1, 1
+	}
+	perYear := en.Schedule.Frequency.PerYear()
+	// one division, so there is one rounding
+	gross := e.Salary.MulDiv(worked, perYear*total)
+
+	c := Context{
+		Employee: e,
+		Period:   p,
+		PerYear:  perYear,
+		Worked:   worked,
+		Total:    total,
+		Gross:    gross,
+		Taxable:  gross,
+	}
+	c.Remaining = gross
+	slip := Payslip{
+		EmployeeID: e.ID,
+		Name:       e.Name,
+		Period:     p,
+		Worked:     worked,
+		Days:       total,
+		Gross:      gross,
+	}
+	for _, r := range byStage(en.Rules) {
+		amount, err := apply(r, c)
+		if err != nil {
+			return Payslip{}, err
+		}
+		if amount.IsNegative() {
+			return Payslip{}, fmt.Errorf("payroll: rule %q gave a negative amount, %v", r.Name(), amount)
+		}
+		if cur := amount.Currency(); cur != "" && cur != e.Salary.Currency() {
+			return Payslip{}, fmt.Errorf("payroll: rule %q gave %v for %s paid in %s: %w",
+				r.Name(), amount, e.ID, e.Salary.Currency(), ErrCurrencyMismatch)
+		}
+		amount = amount.Min(c.Remaining)
+		if amount.Currency() == "" {
+			// a zero Money has no currency, and a payslip line needs one
+			// to be read back
+			amount.currency = e.Salary.Currency()
+		}
+		c.Remaining = c.Remaining.Sub(amount)
+		if r.Stage() == PreTax {
+			c.Taxable = c.Taxable.Sub(amount)
+		}
+		slip.Lines = append(slip.Lines, Line{Name: r.Name(), Stage: r.Stage(), Amount: amount})
+	}
+	slip.Taxable = c.Taxable
+	slip.Net = c.Remaining
+	return slip, nil
+}
+
+// apply runs r, turning the panic from combining amounts in two
+// currencies inside it, such as tax brackets in another currency than
+// the salary, into an error.
+func apply(r Rule, c Context) (amount Money, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			e, ok := p.(error)
+			if !ok || !errors.Is(e, ErrCurrencyMismatch) {
+				panic(p)
+			}
+			err = fmt.Errorf("payroll: rule %q: %w", r.Name(), e)
+		}
+	}()
+	return r.Apply(c), nil
+}
+
+// RunAll computes pay for everyone employed during p, in the order
+// given. People not employed during p are skipped. Every other problem
+// is collected into one *errs.AggregatedError.
+func (en *Engine) RunAll(employees []Employee, p Period) ([]Payslip, error) {
+	var slips []Payslip
+	var problems []error
+	for _, e := range employees {
+		slip, err := en.Run(e, p)
+		switch {
+		case errors.Is(err, ErrNotEmployed):
+		case err != nil:
+			problems = append(problems, err)
+		default:
+			slips = append(slips, slip)
+		}
+	}
+	return slips, errs.Aggregate("payroll "+p.String(), problems...)
+}
+
+func later(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func earlier(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
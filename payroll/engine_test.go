@@ -0,0 +1,109 @@
+package payroll
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"go-practice/errs"
+)
+
+func TestRunProRates(t *testing.T) {
+	engine := &Engine{Schedule: Schedule{Frequency: Monthly}}
+	march := engine.Schedule.PeriodFor(Date(2025, 3, 1))
+	tests := []struct {
+		name          string
+		start, end    time.Time
+		gross         Money
+		worked, total int64
+	}{
+		{"whole month", Date(2024, 6, 1), time.Time{}, usd("6250"), 21, 21},
+		{"joined on the 17th", Date(2025, 3, 17), time.Time{}, usd("3273.81"), 11, 21},
+		{"left on the 14th", Date(2024, 6, 1), Date(2025, 3, 14), usd("2976.19"), 10, 21},
+		{"joined and left", Date(2025, 3, 10), Date(2025, 3, 14), usd("1488.10"), 5, 21},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := Employee{ID: "E1", Salary: usd("75000"), Start: tt.start, End: tt.end}
+			slip, err := engine.Run(e, march)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if slip.Gross != tt.gross || slip.Worked != tt.worked || slip.Days != tt.total {
+				t.Errorf("got %v for %d of %d days, want %v for %d of %d",
+					slip.Gross, slip.Worked, slip.Days, tt.gross, tt.worked, tt.total)
+			}
+		})
+	}
+
+	_, err := engine.Run(Employee{ID: "E1", Salary: usd("75000"), Start: Date(2025, 4, 1)}, march)
+	if !errors.Is(err, ErrNotEmployed) {
+		t.Errorf("someone who joined later: %v", err)
+	}
+	_, err = engine.Run(Employee{Salary: usd("-1")}, march)
+	if got := len(errs.ValidationErrors(err)); got != 3 {
+		t.Errorf("an employee with no ID, a negative salary and no start gave %d problems: %v", got, err)
+	}
+}
+
+// TestPayslipJSON reads a payslip back, including a tax line that came to
+// nothing.
+func TestPayslipJSON(t *testing.T) {
+	engine := &Engine{
+		Schedule: Schedule{Frequency: Monthly},
+		Rules: []Rule{
+			PercentDeduction{Label: "Pension", Rate: Percent(5), When: PreTax},
+			RuleFunc{Label: "Bonus tax", When: Tax, Func: func(Context) Money { return Money{} }},
+			FixedDeduction{Label: "Union dues", Amount: usd("12.50"), When: PostTax},
+		},
+	}
+	e := Employee{ID: "E1", Name: "Alice", Salary: usd("30000"), Start: Date(2025, 3, 17)}
+	slip, err := engine.Run(e, engine.Schedule.PeriodFor(Date(2025, 3, 1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tax := slip.Lines[1]; !tax.Amount.IsZero() || tax.Amount.Currency() != "USD" {
+		t.Errorf("the untaxed line is %#v, want a zero in USD", tax.Amount)
+	}
+
+	var buf bytes.Buffer
+	if err := slip.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var back Payslip
+	if err := json.Unmarshal(buf.Bytes(), &back); err != nil {
+		t.Fatalf("%v\n%s", err, buf.Bytes())
+	}
+	if !reflect.DeepEqual(back, slip) {
+		t.Errorf("read back\n%+v\nwant\n%+v", back, slip)
+	}
+}
+
+func TestRunRejectsOtherCurrencies(t *testing.T) {
+	eur := func(s string) Money { return MustParse(s, "EUR") }
+	tests := []struct {
+		name string
+		rule Rule
+	}{
+		{"fixed deduction", FixedDeduction{Label: "Gym", Amount: eur("30"), When: PostTax}},
+		{"pro-rated deduction", FixedDeduction{Label: "Gym", Amount: eur("30"), When: PreTax, ProRated: true}},
+		{"function", RuleFunc{Label: "Levy", When: Tax, Func: func(Context) Money { return eur("1") }}},
+		{"brackets", BracketTax{Label: "Income tax", Brackets: []Bracket{
+			{UpTo: eur("10000"), Rate: Percent(0)},
+			{Rate: Percent(20)},
+		}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := &Engine{Schedule: Schedule{Frequency: Monthly}, Rules: []Rule{tt.rule}}
+			e := Employee{ID: "E1", Salary: usd("75000"), Start: Date(2024, 6, 1)}
+			_, err := engine.Run(e, engine.Schedule.PeriodFor(Date(2025, 3, 1)))
+			if !errors.Is(err, ErrCurrencyMismatch) {
+				t.Errorf("Run = %v, want %v", err, ErrCurrencyMismatch)
+			}
+		})
+	}
+}
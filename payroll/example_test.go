@@ -0,0 +1,50 @@
+package payroll_test
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"go-practice/payroll"
+)
+
+func ExampleEngine_Run() {
+	engine := &payroll.Engine{
+		Schedule: payroll.Schedule{Frequency: payroll.Monthly},
+		Rules: []payroll.Rule{
+			payroll.PercentDeduction{Label: "Pension", Rate: payroll.Percent(5), When: payroll.PreTax},
+			payroll.FlatTax{Label: "Income tax", Rate: payroll.Percent(20)},
+		},
+	}
+
+	// Joining on March 17th pays 11 of March's 21 working days
+	worker := payroll.Employee{ID: "E001", Name: "Alice", Salary: payroll.MustParse("75000", "USD"), Start: payroll.Date(2025, 3, 17)}
+	for _, month := range []time.Month{time.March, time.April} {
+		slip, err := engine.Run(worker, engine.Schedule.PeriodFor(payroll.Date(2025, month, 1)))
+		if err != nil {
+			fmt.Println("Error:", err)
+			continue
+		}
+		if err := slip.WriteText(os.Stdout); err != nil {
+			fmt.Println("Error:", err)
+		}
+		fmt.Println()
+	}
+	// Output:
+	// Payslip: Alice (E001)
+	// Period: 2025-03-01 to 2025-03-31
+	// Pro-rated: 11 of 21 days
+	// Gross pay                 $3,273.81
+	//   Pension (pre-tax)        -$163.69
+	// Taxable pay               $3,110.12
+	//   Income tax (tax)         -$622.02
+	// Net pay                   $2,488.10
+	//
+	// Payslip: Alice (E001)
+	// Period: 2025-04-01 to 2025-04-30
+	// Gross pay                 $6,250.00
+	//   Pension (pre-tax)        -$312.50
+	// Taxable pay               $5,937.50
+	//   Income tax (tax)       -$1,187.50
+	// Net pay                   $4,750.00
+}
@@ -0,0 +1,297 @@
+// Package payroll computes pay for the chapter 7 Employee, whose Salary is
+// a bare int of dollars. Amounts here are Money: whole minor units (cents)
+// of a known currency, with every division rounded exactly once and
+// half to even, so a payslip comes out to the cent the same on every run
+// and every machine. An Engine turns an annual salary into pay for a
+// period of a Schedule, pro-rates it for someone who joins or leaves part
+// way through, and applies Rules for pre-tax deductions, taxes and
+// post-tax deductions in that order. The result is a Payslip, printable
+// as text or JSON.
+package payroll
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrUnknownCurrency is returned for a currency code with no entry in
+	// Currencies.
+	ErrUnknownCurrency = errors.New("unknown currency")
+
+	// ErrCurrencyMismatch is the panic value, wrapped, when amounts in two
+	// currencies are combined.
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+)
+
+// Currency describes how amounts in one currency are written. Digits is
+// the number of minor-unit digits: 2 for cents, 0 for yen.
+type Currency struct {
+	Code   string
+	Symbol string
+	Digits int
+}
+
+// Currencies holds the currencies Money can use, by ISO 4217 code.
+var Currencies = map[string]Currency{
+	"USD": {"USD", "$", 2},
+	"EUR": {"EUR", "€", 2},
+	"GBP": {"GBP", "£", 2},
+	"CAD": {"CAD", "CA$", 2},
+	"JPY": {"JPY", "¥", 0},
+	"KWD": {"KWD", "KD", 3},
+}
+
+func currency(code string) (Currency, error) {
+	c, ok := Currencies[code]
+	if !ok {
+		return Currency{}, fmt.Errorf("%w %q", ErrUnknownCurrency, code)
+	}
+	return c, nil
+}
+
+// scale returns 10^digits, the minor units in one major unit.
+func (c Currency) scale() int64 {
+	n := int64(1)
+	for range c.Digits {
+		n *= 10
+	}
+	return n
+}
+
+// Money is an amount in minor units of a currency. The zero value has no
+// currency and adds to anything.
+type Money struct {
+	minor    int64
+	currency string
+}
+
+// Minor returns an amount of minor units, such as cents.
+func Minor(units int64, code string) (Money, error) {
+	if _, err := currency(code); err != nil {
+		return Money{}, err
+	}
+	return Money{minor: units, currency: code}, nil
+}
+
+// Major returns a whole number of major units, such as dollars.
+func Major(units int64, code string) (Money, error) {
+	c, err := currency(code)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{minor: units * c.scale(), currency: code}, nil
+}
+
+// Parse reads an amount such as "1234.56" in the given currency. It
+// rejects more decimal places than the currency has rather than round.
+func Parse(s, code string) (Money, error) {
+	c, err := currency(code)
+	if err != nil {
+		return Money{}, err
+	}
+	bad := func(why string) error { return fmt.Errorf("payroll: amount %q: %s", s, why) }
+
+	s = strings.ReplaceAll(strings.TrimSpace(s), ",", "")
+	neg := strings.HasPrefix(s, "-")
+	whole, frac, _ := strings.Cut(strings.TrimPrefix(s, "-"), ".")
+	if len(frac) > c.Digits {
+		return Money{}, bad(fmt.Sprintf("%s has %d decimal places", code, c.Digits))
+	}
+	if whole == "" || strings.ContainsAny(whole+frac, "+-") {
+		return Money{}, bad("not a number")
+	}
+	frac += strings.Repeat("0", c.Digits-len(frac))
+	n, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil {
+		return Money{}, bad("not a number")
+	}
+	if neg {
+		n = -n
+	}
+	return Money{minor: n, currency: code}, nil
+}
+
+// MustParse is Parse for amounts known to be valid, such as constants.
+func MustParse(s, code string) Money {
+	m, err := Parse(s, code)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// Units returns the amount in minor units.
+func (m Money) Units() int64 { return m.minor }
+
+// Currency returns the currency code, "" for the zero value.
+func (m Money) Currency() string { return m.currency }
+
+func (m Money) IsZero() bool { return m.minor == 0 }
+
+func (m Money) IsNegative() bool { return m.minor < 0 }
+
+// join returns the currency of a result combining m and o.
+func (m Money) join(o Money) string {
+	switch {
+	case m.currency == o.currency || o.currency == "":
+		return m.currency
+	case m.currency == "":
+		return o.currency
+	}
+	panic(fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.currency, o.currency))
+}
+
+// Add returns m+o. It panics if both have a currency and they differ.
+func (m Money) Add(o Money) Money { return Money{m.minor + o.minor, m.join(o)} }
+
+// Sub returns m-o. It panics if both have a currency and they differ.
+func (m Money) Sub(o Money) Money { return Money{m.minor - o.minor, m.join(o)} }
+
+// Neg returns -m.
+func (m Money) Neg() Money { return Money{-m.minor, m.currency} }
+
+// Cmp compares m and o like cmp.Compare.
+func (m Money) Cmp(o Money) int {
+	m.join(o)
+	switch {
+	case m.minor < o.minor:
+		return -1
+	case m.minor > o.minor:
+		return 1
+	}
+	return 0
+}
+
+// Min returns the smaller of m and o.
+func (m Money) Min(o Money) Money {
+	if m.Cmp(o) <= 0 {
+		return m
+	}
+	return o
+}
+
+// MulDiv returns m*num/den rounded half to even, computed without
+// overflow or floating point. It panics if den is 0.
+func (m Money) MulDiv(num, den int64) Money {
+	x := new(big.Int).Mul(big.NewInt(m.minor), big.NewInt(num))
+	return Money{minor: divRound(x, big.NewInt(den)), currency: m.currency}
+}
+
+// divRound divides rounding half to even, so repeated rounding doesn't
+// drift in one direction.
+func divRound(x, y *big.Int) int64 {
+	if y.Sign() < 0 {
+		x, y = new(big.Int).Neg(x), new(big.Int).Neg(y)
+	}
+	q, r := new(big.Int).QuoRem(x, y, new(big.Int))
+	twice := new(big.Int).Abs(r)
+	twice.Lsh(twice, 1)
+	switch c := twice.Cmp(y); {
+	case c > 0, c == 0 && q.Bit(0) == 1:
+		if r.Sign() < 0 {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	return q.Int64()
+}
+
+// Rate is a percentage in basis points, hundredths of a percent, so
+// Percent(7.65) is Rate(765). Integer basis points keep rates exact.
+type Rate int64
+
+// Percent converts a percentage, rounding to the nearest basis point.
+func Percent(p float64) Rate {
+	if p < 0 {
+		return Rate(p*100 - 0.5)
+	}
+	return Rate(p*100 + 0.5)
+}
+
+func (r Rate) String() string {
+	s := strconv.FormatFloat(float64(r)/100, 'f', -1, 64)
+	return s + "%"
+}
+
+// Apply returns r percent of m.
+func (m Money) Apply(r Rate) Money { return m.MulDiv(int64(r), 10000) }
+
+// Amount returns the amount in major units, such as "-1234.56", with no
+// symbol or grouping.
+func (m Money) Amount() string {
+	c := Currencies[m.currency]
+	neg, n := m.minor < 0, m.minor
+	if neg {
+		n = -n
+	}
+	s := strconv.FormatInt(n, 10)
+	if c.Digits > 0 {
+		if len(s) <= c.Digits {
+			s = strings.Repeat("0", c.Digits-len(s)+1) + s
+		}
+		s = s[:len(s)-c.Digits] + "." + s[len(s)-c.Digits:]
+	}
+	if neg {
+		s = "-" + s
+	}
+	return s
+}
+
+// String formats m for people, as in "$1,234.56" or "-¥500".
+func (m Money) String() string {
+	amount := strings.TrimPrefix(m.Amount(), "-")
+	whole, frac, hasFrac := strings.Cut(amount, ".")
+	var b strings.Builder
+	if m.minor < 0 {
+		b.WriteByte('-')
+	}
+	if c, ok := Currencies[m.currency]; ok {
+		b.WriteString(c.Symbol)
+	}
+	for i, d := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(d)
+	}
+	if hasFrac {
+		b.WriteString("." + frac)
+	}
+	return b.String()
+}
+
+type moneyJSON struct {
+	Amount   string `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// MarshalJSON writes {"amount": "1234.56", "currency": "USD"}. The amount
+// is a string, as a JSON number would be read back as a float.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(moneyJSON{Amount: m.Amount(), Currency: m.currency})
+}
+
+// UnmarshalJSON reads what MarshalJSON writes, including the zero value's
+// {"amount": "0", "currency": ""}.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var j moneyJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	if j.Currency == "" && j.Amount == "0" {
+		*m = Money{}
+		return nil
+	}
+	parsed, err := Parse(j.Amount, j.Currency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
@@ -0,0 +1,132 @@
+package payroll
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMulDiv(t *testing.T) {
+	tests := []struct {
+		minor, num, den, want int64
+	}{
+		{100, 1, 3, 33},
+		{200, 1, 3, 67},
+		{5, 1, 2, 2},    // 2.5 rounds to the even 2
+		{15, 1, 2, 8},   // 7.5 rounds to the even 8
+		{25, 1, 10, 2},  // 2.5
+		{35, 1, 10, 4},  // 3.5
+		{-5, 1, 2, -2},  // -2.5
+		{-15, 1, 2, -8}, // -7.5
+		{5, -1, 2, -2},
+		{5, 1, -2, -2},
+		{251, 1, 10, 25}, // 25.1 isn't a tie
+		{259, 1, 10, 26},
+		{7500000, 11, 12 * 21, 327381}, // the March payslip's gross
+		{1 << 62, 4, 8, 1 << 61},       // the product overflows int64
+	}
+	for _, tt := range tests {
+		got := Money{minor: tt.minor, currency: "USD"}.MulDiv(tt.num, tt.den)
+		if got.Units() != tt.want || got.Currency() != "USD" {
+			t.Errorf("%d*%d/%d = %d %s, want %d", tt.minor, tt.num, tt.den, got.Units(), got.Currency(), tt.want)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in, code string
+		want     int64
+		err      string // a substring of the error, if one is expected
+	}{
+		{"1234.56", "USD", 123456, ""},
+		{"1,234.5", "USD", 123450, ""},
+		{" 12 ", "USD", 1200, ""},
+		{"-0.07", "USD", -7, ""},
+		{".5", "USD", 0, "not a number"},
+		{"500", "JPY", 500, ""},
+		{"1.5", "JPY", 0, "JPY has 0 decimal places"},
+		{"1.234", "KWD", 1234, ""},
+		{"1.005", "USD", 0, "USD has 2 decimal places"},
+		{"", "USD", 0, "not a number"},
+		{"1-2", "USD", 0, "not a number"},
+		{"--1", "USD", 0, "not a number"},
+		{"+1", "USD", 0, "not a number"},
+		{"1e3", "USD", 0, "not a number"},
+		{"99999999999999999999", "USD", 0, "not a number"},
+		{"1", "XYZ", 0, "unknown currency"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in+" "+tt.code, func(t *testing.T) {
+			got, err := Parse(tt.in, tt.code)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("err = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Units() != tt.want || got.Currency() != tt.code {
+				t.Errorf("got %d %s, want %d %s", got.Units(), got.Currency(), tt.want, tt.code)
+			}
+		})
+	}
+}
+
+func TestMoneyString(t *testing.T) {
+	tests := []struct {
+		m    Money
+		want string
+	}{
+		{MustParse("1234567.89", "USD"), "$1,234,567.89"},
+		{MustParse("-0.05", "USD"), "-$0.05"},
+		{MustParse("-500", "JPY"), "-¥500"},
+		{MustParse("1.5", "KWD"), "KD1.500"},
+		{Money{}, "0"},
+	}
+	for _, tt := range tests {
+		if got := tt.m.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestMoneyCurrencies(t *testing.T) {
+	usd := MustParse("1", "USD")
+	if got := usd.Add(Money{}); got != usd {
+		t.Errorf("adding the zero value gave %v", got)
+	}
+	if got := (Money{}).Sub(usd); got.Currency() != "USD" || got.Units() != -100 {
+		t.Errorf("0 - $1 = %v", got)
+	}
+	defer func() {
+		err, _ := recover().(error)
+		if !errors.Is(err, ErrCurrencyMismatch) {
+			t.Errorf("adding USD to EUR panicked with %v", err)
+		}
+	}()
+	usd.Add(MustParse("1", "EUR"))
+}
+
+func TestMoneyJSON(t *testing.T) {
+	for _, m := range []Money{MustParse("-1234.5", "USD"), MustParse("7", "JPY"), MustParse("0", "EUR"), {}} {
+		raw, err := json.Marshal(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var back Money
+		if err := json.Unmarshal(raw, &back); err != nil {
+			t.Fatalf("%s: %v", raw, err)
+		}
+		if back != m {
+			t.Errorf("%s read back as %#v", raw, back)
+		}
+	}
+	var m Money
+	if err := json.Unmarshal([]byte(`{"amount": "1", "currency": ""}`), &m); err == nil {
+		t.Error("a non-zero amount with no currency was accepted")
+	}
+}
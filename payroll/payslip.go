@@ -0,0 +1,75 @@
+package payroll
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// Line is one tax or deduction on a payslip.
+type Line struct {
+	Name   string `json:"name"`
+	Stage  Stage  `json:"stage"`
+	Amount Money  `json:"amount"`
+}
+
+// Payslip is one employee's pay for one period. Worked of Days days
+// counted towards pay, by the engine's ProRation.
+type Payslip struct {
+	EmployeeID string `json:"employee_id"`
+	Name       string `json:"name"`
+	Period     Period `json:"period"`
+	Worked     int64  `json:"worked_days"`
+	Days       int64  `json:"period_days"`
+	Gross      Money  `json:"gross"`
+	Lines      []Line `json:"lines"`
+	Taxable    Money  `json:"taxable"`
+	Net        Money  `json:"net"`
+}
+
+// ProRated reports whether the employee worked only part of the period.
+func (s Payslip) ProRated() bool { return s.Worked < s.Days }
+
+// Total returns the sum of the lines at one stage.
+func (s Payslip) Total(stage Stage) Money {
+	var total Money
+	for _, l := range s.Lines {
+		if l.Stage == stage {
+			total = total.Add(l.Amount)
+		}
+	}
+	return total
+}
+
+// WriteJSON writes the payslip as indented JSON.
+func (s Payslip) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(s)
+}
+
+// WriteText writes the payslip as a table for printing.
+func (s Payslip) WriteText(w io.Writer) error {
+	fmt.Fprintf(w, "Payslip: %s (%s)\n", s.Name, s.EmployeeID)
+	fmt.Fprintf(w, "Period: %s\n", s.Period)
+	if s.ProRated() {
+		fmt.Fprintf(w, "Pro-rated: %d of %d days\n", s.Worked, s.Days)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	row := func(label, amount string) { fmt.Fprintf(tw, "%s\t%14s\n", label, amount) }
+	row("Gross pay", s.Gross.String())
+	for _, stage := range []Stage{PreTax, Tax, PostTax} {
+		for _, l := range s.Lines {
+			if l.Stage == stage {
+				row(fmt.Sprintf("  %s (%s)", l.Name, l.Stage), "-"+l.Amount.String())
+			}
+		}
+		if stage == PreTax {
+			row("Taxable pay", s.Taxable.String())
+		}
+	}
+	row("Net pay", s.Net.String())
+	return tw.Flush()
+}
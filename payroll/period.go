@@ -0,0 +1,173 @@
+package payroll
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Date returns midnight UTC on the given day. Payroll works in whole
+// days, and UTC midnights keep day arithmetic free of time zone shifts.
+func Date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func day(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return Date(y, m, d)
+}
+
+// unixDay numbers t's day, counting from 1 January 1970. Unlike
+// time.Time.Sub, which stops at about 292 years, it counts days between
+// any two dates.
+func unixDay(t time.Time) int64 {
+	return day(t).Unix() / (24 * 60 * 60)
+}
+
+// Period is a pay period from Start to End, both days included.
+type Period struct {
+	Start time.Time
+	End   time.Time
+}
+
+func (p Period) String() string {
+	return p.Start.Format(time.DateOnly) + " to " + p.End.Format(time.DateOnly)
+}
+
+// MarshalJSON writes the days as dates, such as "2025-03-01".
+func (p Period) MarshalJSON() ([]byte, error) {
+	return json.Marshal(periodJSON{
+		Start: p.Start.Format(time.DateOnly),
+		End:   p.End.Format(time.DateOnly),
+	})
+}
+
+type periodJSON struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+func (p *Period) UnmarshalJSON(data []byte) error {
+	var j periodJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	start, err := time.Parse(time.DateOnly, j.Start)
+	if err != nil {
+		return err
+	}
+	end, err := time.Parse(time.DateOnly, j.End)
+	if err != nil {
+		return err
+	}
+	*p = Period{Start: start, End: end}
+	return nil
+}
+
+// Contains reports whether t falls on a day of the period.
+func (p Period) Contains(t time.Time) bool {
+	t = day(t)
+	return !t.Before(p.Start) && !t.After(p.End)
+}
+
+// Frequency is how often people are paid.
+type Frequency int
+
+const (
+	Weekly      Frequency = iota // every 7 days from the schedule's anchor
+	Biweekly                     // every 14 days from the schedule's anchor
+	Semimonthly                  // the 1st to the 15th, and the 16th to month end
+	Monthly                      // calendar months
+)
+
+// PerYear returns the number of periods in a year, which divides an
+// annual salary into each period's pay.
+func (f Frequency) PerYear() int64 {
+	switch f {
+	case Weekly:
+		return 52
+	case Biweekly:
+		return 26
+	case Semimonthly:
+		return 24
+	}
+	return 12
+}
+
+func (f Frequency) String() string {
+	switch f {
+	case Weekly:
+		return "weekly"
+	case Biweekly:
+		return "biweekly"
+	case Semimonthly:
+		return "semimonthly"
+	case Monthly:
+		return "monthly"
+	}
+	return fmt.Sprintf("Frequency(%d)", int(f))
+}
+
+// Schedule fixes the pay periods. Anchor is the first day of any one
+// period, and is only used for weekly and biweekly pay. A zero Anchor is
+// 1 January of year 1, a Monday, so periods then start on Mondays.
+type Schedule struct {
+	Frequency Frequency
+	Anchor    time.Time
+}
+
+// PeriodFor returns the period containing t.
+func (s Schedule) PeriodFor(t time.Time) Period {
+	t = day(t)
+	y, m, d := t.Date()
+	switch s.Frequency {
+	case Weekly, Biweekly:
+		days := 7
+		if s.Frequency == Biweekly {
+			days = 14
+		}
+		offset := (unixDay(t) - unixDay(s.Anchor)) % int64(days)
+		if offset < 0 {
+			offset += int64(days)
+		}
+		start := t.AddDate(0, 0, -int(offset))
+		return Period{Start: start, End: start.AddDate(0, 0, days-1)}
+	case Semimonthly:
+		if d <= 15 {
+			return Period{Start: Date(y, m, 1), End: Date(y, m, 15)}
+		}
+		return Period{Start: Date(y, m, 16), End: Date(y, m+1, 0)}
+	}
+	return Period{Start: Date(y, m, 1), End: Date(y, m+1, 0)}
+}
+
+// Next returns the period after p.
+func (s Schedule) Next(p Period) Period {
+	return s.PeriodFor(p.End.AddDate(0, 0, 1))
+}
+
+// ProRation measures how much of a period counts towards pay: worked out
+// of total, for someone employed from start to end inclusive.
+type ProRation func(p Period, start, end time.Time) (worked, total int64)
+
+// CalendarDays pro-rates by days, weekends included.
+func CalendarDays(p Period, start, end time.Time) (worked, total int64) {
+	return countDays(start, end, nil), countDays(p.Start, p.End, nil)
+}
+
+// WorkingDays pro-rates by weekdays, so joining on a Saturday or a Monday
+// makes no difference.
+func WorkingDays(p Period, start, end time.Time) (worked, total int64) {
+	weekday := func(t time.Time) bool { return t.Weekday() != time.Saturday && t.Weekday() != time.Sunday }
+	return countDays(start, end, weekday), countDays(p.Start, p.End, weekday)
+}
+
+func countDays(from, to time.Time, keep func(time.Time) bool) int64 {
+	var n int64
+	for t := from; !t.After(to); t = t.AddDate(0, 0, 1) {
+		if keep == nil || keep(t) {
+			n++
+		}
+	}
+	return n
+}
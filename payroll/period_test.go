@@ -0,0 +1,86 @@
+package payroll
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPeriodFor(t *testing.T) {
+	monday := Date(2025, 1, 6)
+	tests := []struct {
+		name       string
+		s          Schedule
+		t          time.Time
+		start, end time.Time
+	}{
+		{"monthly", Schedule{Frequency: Monthly}, Date(2025, 2, 14), Date(2025, 2, 1), Date(2025, 2, 28)},
+		{"monthly, leap year", Schedule{Frequency: Monthly}, Date(2024, 2, 29), Date(2024, 2, 1), Date(2024, 2, 29)},
+		{"semimonthly, first half", Schedule{Frequency: Semimonthly}, Date(2025, 4, 15), Date(2025, 4, 1), Date(2025, 4, 15)},
+		{"semimonthly, second half", Schedule{Frequency: Semimonthly}, Date(2025, 4, 16), Date(2025, 4, 16), Date(2025, 4, 30)},
+		{"weekly, on the anchor", Schedule{Weekly, monday}, monday, monday, Date(2025, 1, 12)},
+		{"weekly, after", Schedule{Weekly, monday}, Date(2025, 3, 20), Date(2025, 3, 17), Date(2025, 3, 23)},
+		{"weekly, before the anchor", Schedule{Weekly, monday}, Date(2024, 12, 31), Date(2024, 12, 30), Date(2025, 1, 5)},
+		{"weekly, time of day ignored", Schedule{Weekly, monday.Add(20 * time.Hour)}, Date(2025, 1, 19).Add(23 * time.Hour), Date(2025, 1, 13), Date(2025, 1, 19)},
+		{"biweekly", Schedule{Biweekly, monday}, Date(2025, 1, 21), Date(2025, 1, 20), Date(2025, 2, 2)},
+		{"biweekly, centuries on", Schedule{Biweekly, monday}, Date(2525, 1, 5), Date(2525, 1, 1), Date(2525, 1, 14)},
+		{"weekly, zero anchor", Schedule{Frequency: Weekly}, Date(2025, 3, 20), Date(2025, 3, 17), Date(2025, 3, 23)},
+		{"biweekly, zero anchor", Schedule{Frequency: Biweekly}, Date(2025, 3, 20), Date(2025, 3, 17), Date(2025, 3, 30)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.s.PeriodFor(tt.t)
+			if !p.Start.Equal(tt.start) || !p.End.Equal(tt.end) {
+				t.Errorf("PeriodFor(%s) = %v, want %s to %s", tt.t.Format(time.DateOnly), p,
+					tt.start.Format(time.DateOnly), tt.end.Format(time.DateOnly))
+			}
+		})
+	}
+}
+
+// TestNextTilesTheYear checks that consecutive periods neither overlap nor
+// leave gaps, for every frequency, with and without an anchor.
+func TestNextTilesTheYear(t *testing.T) {
+	for _, s := range []Schedule{
+		{Frequency: Weekly}, {Frequency: Biweekly}, {Weekly, Date(2025, 1, 8)}, {Biweekly, Date(2025, 1, 8)},
+		{Frequency: Semimonthly}, {Frequency: Monthly},
+	} {
+		p := s.PeriodFor(Date(2025, 1, 1))
+		if !p.Contains(Date(2025, 1, 1)) {
+			t.Errorf("%v: %v doesn't contain its day", s, p)
+		}
+		for range 60 {
+			next := s.Next(p)
+			if !next.Start.Equal(p.End.AddDate(0, 0, 1)) || next.End.Before(next.Start) {
+				t.Fatalf("%v: %v is followed by %v", s, p, next)
+			}
+			p = next
+		}
+	}
+}
+
+func TestProRation(t *testing.T) {
+	march := Period{Start: Date(2025, 3, 1), End: Date(2025, 3, 31)}
+	tests := []struct {
+		name          string
+		rate          ProRation
+		start, end    time.Time
+		worked, total int64
+	}{
+		{"calendar, whole month", CalendarDays, march.Start, march.End, 31, 31},
+		{"calendar, from the 17th", CalendarDays, Date(2025, 3, 17), march.End, 15, 31},
+		{"calendar, one day", CalendarDays, Date(2025, 3, 8), Date(2025, 3, 8), 1, 31},
+		{"working, whole month", WorkingDays, march.Start, march.End, 21, 21},
+		{"working, from Monday the 17th", WorkingDays, Date(2025, 3, 17), march.End, 11, 21},
+		{"working, from Saturday the 15th", WorkingDays, Date(2025, 3, 15), march.End, 11, 21},
+		{"working, a weekend only", WorkingDays, Date(2025, 3, 8), Date(2025, 3, 9), 0, 21},
+		{"working, left on the 4th", WorkingDays, march.Start, Date(2025, 3, 4), 2, 21},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			worked, total := tt.rate(march, tt.start, tt.end)
+			if worked != tt.worked || total != tt.total {
+				t.Errorf("got %d of %d days, want %d of %d", worked, total, tt.worked, tt.total)
+			}
+		})
+	}
+}
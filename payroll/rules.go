@@ -0,0 +1,175 @@
+package payroll
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Stage is when a rule runs. Pre-tax deductions lower the income taxes
+// are computed on; post-tax deductions come out of what is left.
+type Stage int
+
+const (
+	PreTax Stage = iota
+	Tax
+	PostTax
+)
+
+func (s Stage) MarshalText() ([]byte, error) { return []byte(s.String()), nil }
+
+// UnmarshalText reads the names MarshalText writes, such as "pre-tax".
+func (s *Stage) UnmarshalText(text []byte) error {
+	for _, stage := range []Stage{PreTax, Tax, PostTax} {
+		if string(text) == stage.String() {
+			*s = stage
+			return nil
+		}
+	}
+	return fmt.Errorf("payroll: unknown stage %q", text)
+}
+
+func (s Stage) String() string {
+	switch s {
+	case PreTax:
+		return "pre-tax"
+	case Tax:
+		return "tax"
+	case PostTax:
+		return "post-tax"
+	}
+	return fmt.Sprintf("Stage(%d)", int(s))
+}
+
+// Context is what a rule sees. Worked of Total days of the period count
+// towards pay. Taxable is gross pay less the pre-tax deductions so far,
+// and Remaining is what the employee would take home if no more rules
+// ran.
+type Context struct {
+	Employee      Employee
+	Period        Period
+	PerYear       int64 // periods in a year, to annualize with
+	Worked, Total int64
+	Gross         Money
+	Taxable       Money
+	Remaining     Money
+}
+
+// Rule is a tax or deduction. Apply returns the amount to take for one
+// period; the engine caps it at what remains, so pay never goes negative.
+type Rule interface {
+	Name() string
+	Stage() Stage
+	Apply(c Context) Money
+}
+
+// FlatTax takes a fixed percentage of taxable pay.
+type FlatTax struct {
+	Label string
+	Rate  Rate
+}
+
+func (t FlatTax) Name() string          { return t.Label }
+func (t FlatTax) Stage() Stage          { return Tax }
+func (t FlatTax) Apply(c Context) Money { return c.Taxable.Apply(t.Rate) }
+
+// Bracket taxes annual income above the previous bracket's limit and up
+// to UpTo at Rate. The last bracket has a zero UpTo, meaning no limit.
+type Bracket struct {
+	UpTo Money
+	Rate Rate
+}
+
+// BracketTax is a progressive tax with annual brackets, in increasing
+// order. Each period's taxable pay is annualized, taxed, and the tax
+// divided back over the year, so it comes to the same total as taxing
+// the annual salary once.
+type BracketTax struct {
+	Label    string
+	Brackets []Bracket
+}
+
+func (t BracketTax) Name() string { return t.Label }
+func (t BracketTax) Stage() Stage { return Tax }
+
+func (t BracketTax) Apply(c Context) Money {
+	annual := c.Taxable.MulDiv(c.PerYear, 1)
+	var tax, below Money
+	for _, b := range t.Brackets {
+		top := annual
+		if !b.UpTo.IsZero() {
+			top = annual.Min(b.UpTo)
+		}
+		if top.Cmp(below) <= 0 {
+			break
+		}
+		tax = tax.Add(top.Sub(below).Apply(b.Rate))
+		below = b.UpTo
+		if b.UpTo.IsZero() {
+			break
+		}
+	}
+	return tax.MulDiv(1, c.PerYear)
+}
+
+// Validate checks that the limits increase and only the last is open.
+func (t BracketTax) Validate() error {
+	for i, b := range t.Brackets {
+		last := i == len(t.Brackets)-1
+		switch {
+		case b.UpTo.IsZero() && !last:
+			return fmt.Errorf("payroll: %s: only the last bracket may have no limit", t.Label)
+		case i > 0 && !b.UpTo.IsZero() && b.UpTo.Cmp(t.Brackets[i-1].UpTo) <= 0:
+			return fmt.Errorf("payroll: %s: bracket %d's limit %v isn't above %v", t.Label, i+1, b.UpTo, t.Brackets[i-1].UpTo)
+		}
+	}
+	return nil
+}
+
+// PercentDeduction takes a percentage of gross pay, such as a pension
+// contribution.
+type PercentDeduction struct {
+	Label string
+	Rate  Rate
+	When  Stage // PreTax or PostTax
+}
+
+func (d PercentDeduction) Name() string          { return d.Label }
+func (d PercentDeduction) Stage() Stage          { return d.When }
+func (d PercentDeduction) Apply(c Context) Money { return c.Gross.Apply(d.Rate) }
+
+// FixedDeduction takes the same amount every period, such as a health
+// plan premium, pro-rated when the employee worked part of the period.
+type FixedDeduction struct {
+	Label    string
+	Amount   Money
+	When     Stage // PreTax or PostTax
+	ProRated bool
+}
+
+func (d FixedDeduction) Name() string { return d.Label }
+func (d FixedDeduction) Stage() Stage { return d.When }
+
+func (d FixedDeduction) Apply(c Context) Money {
+	if !d.ProRated {
+		return d.Amount
+	}
+	return d.Amount.MulDiv(c.Worked, c.Total)
+}
+
+// RuleFunc adapts a function to a Rule, for one-off rules.
+type RuleFunc struct {
+	Label string
+	When  Stage
+	Func  func(c Context) Money
+}
+
+func (r RuleFunc) Name() string          { return r.Label }
+func (r RuleFunc) Stage() Stage          { return r.When }
+func (r RuleFunc) Apply(c Context) Money { return r.Func(c) }
+
+// byStage orders rules by stage, keeping their order within a stage.
+func byStage(rules []Rule) []Rule {
+	sorted := slices.Clone(rules)
+	slices.SortStableFunc(sorted, func(a, b Rule) int { return int(a.Stage()) - int(b.Stage()) })
+	return sorted
+}
@@ -0,0 +1,84 @@
+package payroll
+
+import (
+	"strings"
+	"testing"
+)
+
+func usd(s string) Money { return MustParse(s, "USD") }
+
+func TestBracketTax(t *testing.T) {
+	tax := BracketTax{Label: "Income tax", Brackets: []Bracket{
+		{UpTo: usd("10000"), Rate: Percent(10)},
+		{UpTo: usd("40000"), Rate: Percent(20)},
+		{Rate: Percent(30)},
+	}}
+	tests := []struct {
+		name    string
+		taxable Money
+		perYear int64
+		want    Money
+	}{
+		{"nothing to tax", usd("0"), 12, usd("0")},
+		{"first bracket", usd("500"), 12, usd("50")},
+		{"exactly the first limit", usd("10000"), 1, usd("1000")},
+		{"second bracket", usd("2500"), 12, usd("416.67")},
+		{"exactly the second limit", usd("40000"), 1, usd("7000")},
+		{"open bracket", usd("5000"), 12, usd("1083.33")},
+		{"weekly", usd("1000"), 52, usd("203.85")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tax.Apply(Context{Taxable: tt.taxable, PerYear: tt.perYear})
+			if got.Units() != tt.want.Units() {
+				t.Errorf("tax on %v = %v, want %v", tt.taxable, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBracketTaxValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		brackets []Bracket
+		err      string // a substring of the error, if one is expected
+	}{
+		{"none", nil, ""},
+		{"open only", []Bracket{{Rate: Percent(20)}}, ""},
+		{"closed last", []Bracket{{UpTo: usd("10"), Rate: Percent(10)}, {UpTo: usd("20"), Rate: Percent(20)}}, ""},
+		{"open in the middle", []Bracket{{Rate: Percent(10)}, {UpTo: usd("20"), Rate: Percent(20)}}, "only the last bracket may have no limit"},
+		{"limits out of order", []Bracket{{UpTo: usd("20"), Rate: Percent(10)}, {UpTo: usd("10"), Rate: Percent(20)}}, "bracket 2's limit $10.00 isn't above $20.00"},
+		{"repeated limit", []Bracket{{UpTo: usd("20"), Rate: Percent(10)}, {UpTo: usd("20"), Rate: Percent(20)}}, "isn't above"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := BracketTax{Label: "tax", Brackets: tt.brackets}.Validate()
+			if tt.err == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("err = %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestStageText(t *testing.T) {
+	for _, s := range []Stage{PreTax, Tax, PostTax} {
+		text, err := s.MarshalText()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var back Stage
+		if err := back.UnmarshalText(text); err != nil || back != s {
+			t.Errorf("%s read back as %v, %v", text, back, err)
+		}
+	}
+	var s Stage
+	if err := s.UnmarshalText([]byte("Stage(7)")); err == nil {
+		t.Error("an unknown stage was accepted")
+	}
+}
@@ -0,0 +1,208 @@
+package persistent_test
+
+import (
+	"fmt"
+	"maps"
+	"strconv"
+	"testing"
+
+	"go-practice/persistent"
+)
+
+// The benchmarks compare "copy then modify" using persistent values with
+// the deep copy of chapter 9's Person.DeepCopy, which copies a whole
+// slice or map before every change:
+//
+//	go test ./persistent -run '^$' -bench . -benchmem
+
+var sizes = []int{10, 1000, 100_000}
+
+// batch is the number of edits in BenchmarkBatchEdits.
+const batch = 100
+
+var (
+	sinkSlice []string
+	sinkMap   map[string]string
+	sinkVec   persistent.Vector[string]
+	sinkPMap  persistent.Map[string, string]
+	sinkInt   int
+)
+
+func hobbies(n int) []string {
+	s := make([]string, n)
+	for i := range s {
+		s[i] = "hobby-" + strconv.Itoa(i)
+	}
+	return s
+}
+
+func metadata(n int) map[string]string {
+	m := make(map[string]string, n)
+	for i := range n {
+		m["key-"+strconv.Itoa(i)] = "value-" + strconv.Itoa(i)
+	}
+	return m
+}
+
+// deepCopySlice and deepCopyMap do what Person.DeepCopy does per field.
+func deepCopySlice(s []string) []string {
+	c := make([]string, len(s))
+	copy(c, s)
+	return c
+}
+
+func deepCopyMap(m map[string]string) map[string]string {
+	c := make(map[string]string, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
+
+type approach struct {
+	name string
+	run  func(b *testing.B, n int)
+}
+
+func run(b *testing.B, approaches []approach) {
+	for _, n := range sizes {
+		for _, a := range approaches {
+			b.Run(fmt.Sprintf("%d/%s", n, a.name), func(b *testing.B) {
+				b.ReportAllocs()
+				a.run(b, n)
+			})
+		}
+	}
+}
+
+// BenchmarkSetHobby copies a list of hobbies with one changed.
+func BenchmarkSetHobby(b *testing.B) {
+	run(b, []approach{
+		{"deep copy", func(b *testing.B, n int) {
+			s := hobbies(n)
+			for b.Loop() {
+				c := deepCopySlice(s)
+				c[n/2] = "coding"
+				sinkSlice = c
+			}
+		}},
+		{"persistent.Vector", func(b *testing.B, n int) {
+			v := persistent.VectorOf(hobbies(n)...)
+			for b.Loop() {
+				sinkVec = v.Set(n/2, "coding")
+			}
+		}},
+	})
+}
+
+// BenchmarkAppendHobby copies a list of hobbies with one added.
+func BenchmarkAppendHobby(b *testing.B) {
+	run(b, []approach{
+		{"deep copy", func(b *testing.B, n int) {
+			s := hobbies(n)
+			for b.Loop() {
+				c := make([]string, len(s), len(s)+1)
+				copy(c, s)
+				sinkSlice = append(c, "coding")
+			}
+		}},
+		{"persistent.Vector", func(b *testing.B, n int) {
+			v := persistent.VectorOf(hobbies(n)...)
+			for b.Loop() {
+				sinkVec = v.Append("coding")
+			}
+		}},
+	})
+}
+
+// BenchmarkSetMetadata copies a metadata map with one entry changed.
+func BenchmarkSetMetadata(b *testing.B) {
+	run(b, []approach{
+		{"deep copy", func(b *testing.B, n int) {
+			m := metadata(n)
+			for b.Loop() {
+				c := deepCopyMap(m)
+				c["city"] = "Boston"
+				sinkMap = c
+			}
+		}},
+		{"maps.Clone", func(b *testing.B, n int) {
+			m := metadata(n)
+			for b.Loop() {
+				c := maps.Clone(m)
+				c["city"] = "Boston"
+				sinkMap = c
+			}
+		}},
+		{"persistent.Map", func(b *testing.B, n int) {
+			m := persistent.MapOf(metadata(n))
+			for b.Loop() {
+				sinkPMap = m.Set("city", "Boston")
+			}
+		}},
+	})
+}
+
+// BenchmarkBatchEdits copies a metadata map with batch entries changed,
+// where a transient saves copying the same nodes over and over.
+func BenchmarkBatchEdits(b *testing.B) {
+	run(b, []approach{
+		{"deep copy", func(b *testing.B, n int) {
+			m := metadata(n)
+			for b.Loop() {
+				c := deepCopyMap(m)
+				for i := range batch {
+					c["key-"+strconv.Itoa(i%n)] = "edited"
+				}
+				sinkMap = c
+			}
+		}},
+		{"persistent.Map", func(b *testing.B, n int) {
+			m := persistent.MapOf(metadata(n))
+			for b.Loop() {
+				c := m
+				for i := range batch {
+					c = c.Set("key-"+strconv.Itoa(i%n), "edited")
+				}
+				sinkPMap = c
+			}
+		}},
+		{"transient", func(b *testing.B, n int) {
+			m := persistent.MapOf(metadata(n))
+			for b.Loop() {
+				t := m.Transient()
+				for i := range batch {
+					t.Set("key-"+strconv.Itoa(i%n), "edited")
+				}
+				sinkPMap = t.Persistent()
+			}
+		}},
+	})
+}
+
+// BenchmarkReadHobbies ranges over every hobby, the price a Vector pays
+// for cheap copies.
+func BenchmarkReadHobbies(b *testing.B) {
+	run(b, []approach{
+		{"slice", func(b *testing.B, n int) {
+			s := hobbies(n)
+			for b.Loop() {
+				total := 0
+				for _, h := range s {
+					total += len(h)
+				}
+				sinkInt = total
+			}
+		}},
+		{"persistent.Vector", func(b *testing.B, n int) {
+			v := persistent.VectorOf(hobbies(n)...)
+			for b.Loop() {
+				total := 0
+				for _, h := range v.All() {
+					total += len(h)
+				}
+				sinkInt = total
+			}
+		}},
+	})
+}
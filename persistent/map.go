@@ -0,0 +1,403 @@
+package persistent
+
+import (
+	"cmp"
+	"fmt"
+	"hash/maphash"
+	"iter"
+	"math/bits"
+	"slices"
+	"strings"
+)
+
+// seed is shared by every Map so that versions of a map can share nodes.
+// Iteration order depends on it, and so changes between runs.
+var seed = maphash.MakeSeed()
+
+// hashBits is the width of a key hash; below this depth, keys whose hashes
+// are fully equal share a collision node.
+const hashBits = 64
+
+// entry is a slot of an hnode: either a key/value pair or a child node.
+type entry[K comparable, V any] struct {
+	hash  uint64
+	key   K
+	value V
+	child *hnode[K, V]
+}
+
+// hnode is a hash array mapped trie node. bitmap has one bit per 5-bit
+// hash fragment in use at this depth, and entries holds those slots in
+// fragment order. A collision node ignores bitmap and holds every pair in
+// entries.
+type hnode[K comparable, V any] struct {
+	edit      *owner
+	bitmap    uint32
+	entries   []entry[K, V]
+	collision bool
+}
+
+// Map is an immutable hash map: a hash array mapped trie with 32-way
+// branching, so Get, Set and Delete are O(log32 n). Keys can be any
+// comparable type. The zero value is an empty map.
+type Map[K comparable, V any] struct {
+	root *hnode[K, V]
+	len  int
+}
+
+// MapOf builds a Map holding the entries of m.
+func MapOf[K comparable, V any](m map[K]V) Map[K, V] {
+	t := Map[K, V]{}.Transient()
+	for k, v := range m {
+		t.Set(k, v)
+	}
+	return t.Persistent()
+}
+
+// Len returns the number of entries.
+func (m Map[K, V]) Len() int {
+	return m.len
+}
+
+func hashOf[K comparable](k K) uint64 {
+	return maphash.Comparable(seed, k)
+}
+
+func fragment(h uint64, shift uint) uint32 {
+	return 1 << ((h >> shift) & mask)
+}
+
+// slot returns the position of bit's entry and whether it is present.
+func (n *hnode[K, V]) slot(bit uint32) (int, bool) {
+	return bits.OnesCount32(n.bitmap & (bit - 1)), n.bitmap&bit != 0
+}
+
+// Get returns the value stored for k.
+func (m Map[K, V]) Get(k K) (V, bool) {
+	return m.root.get(hashOf(k), k)
+}
+
+// get finds k, whose hash is h, below n.
+func (n *hnode[K, V]) get(h uint64, k K) (V, bool) {
+	for shift := uint(0); n != nil; shift += levelBits {
+		if n.collision {
+			for _, e := range n.entries {
+				if e.key == k {
+					return e.value, true
+				}
+			}
+			break
+		}
+		i, ok := n.slot(fragment(h, shift))
+		if !ok {
+			break
+		}
+		e := &n.entries[i]
+		if e.child == nil {
+			if e.hash == h && e.key == k {
+				return e.value, true
+			}
+			break
+		}
+		n = e.child
+	}
+	var zero V
+	return zero, false
+}
+
+// Has reports whether k is present.
+func (m Map[K, V]) Has(k K) bool {
+	_, ok := m.Get(k)
+	return ok
+}
+
+// Set returns a map with k mapped to v.
+func (m Map[K, V]) Set(k K, v V) Map[K, V] {
+	root := m.root
+	if root == nil {
+		root = &hnode[K, V]{}
+	}
+	var added bool
+	m.root, added = root.set(nil, 0, entry[K, V]{hash: hashOf(k), key: k, value: v})
+	if added {
+		m.len++
+	}
+	return m
+}
+
+// Delete returns a map without k. Deleting a missing key returns m
+// unchanged.
+func (m Map[K, V]) Delete(k K) Map[K, V] {
+	if m.root == nil {
+		return m
+	}
+	root, removed := m.root.delete(nil, 0, hashOf(k), k)
+	if !removed {
+		return m
+	}
+	m.root = root
+	m.len--
+	if m.len == 0 {
+		m.root = nil
+	}
+	return m
+}
+
+// All iterates over the entries in an unspecified order that is stable
+// for a given map value within one run of the program.
+func (m Map[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		m.root.each(yield)
+	}
+}
+
+// Keys returns the keys in an unspecified order.
+func (m Map[K, V]) Keys() []K {
+	keys := make([]K, 0, m.len)
+	for k := range m.All() {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// ToMap copies the entries into a new Go map.
+func (m Map[K, V]) ToMap() map[K]V {
+	out := make(map[K]V, m.len)
+	for k, v := range m.All() {
+		out[k] = v
+	}
+	return out
+}
+
+// String formats the map like fmt formats a Go map, with sorted keys.
+func (m Map[K, V]) String() string {
+	type pair struct{ k, v string }
+	pairs := make([]pair, 0, m.len)
+	for k, v := range m.All() {
+		pairs = append(pairs, pair{fmt.Sprint(k), fmt.Sprint(v)})
+	}
+	slices.SortFunc(pairs, func(a, b pair) int { return cmp.Compare(a.k, b.k) })
+
+	var b strings.Builder
+	b.WriteString("map[")
+	for i, p := range pairs {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(p.k + ":" + p.v)
+	}
+	b.WriteString("]")
+	return b.String()
+}
+
+func (n *hnode[K, V]) each(yield func(K, V) bool) bool {
+	if n == nil {
+		return true
+	}
+	for _, e := range n.entries {
+		if e.child != nil {
+			if !e.child.each(yield) {
+				return false
+			}
+		} else if !yield(e.key, e.value) {
+			return false
+		}
+	}
+	return true
+}
+
+// editable returns n if the transient identified by edit owns it, and a
+// copy owned by edit otherwise.
+func (n *hnode[K, V]) editable(edit *owner) *hnode[K, V] {
+	if edit != nil && n.edit == edit {
+		return n
+	}
+	c := *n
+	c.edit = edit
+	c.entries = slices.Clone(n.entries)
+	return &c
+}
+
+// set stores e below n, returning the (possibly copied) node and whether
+// the key is new.
+func (n *hnode[K, V]) set(edit *owner, shift uint, e entry[K, V]) (*hnode[K, V], bool) {
+	if n.collision {
+		for i := range n.entries {
+			if n.entries[i].key == e.key {
+				c := n.editable(edit)
+				c.entries[i].value = e.value
+				return c, false
+			}
+		}
+		c := n.editable(edit)
+		c.entries = append(c.entries, e)
+		return c, true
+	}
+
+	bit := fragment(e.hash, shift)
+	i, ok := n.slot(bit)
+	if !ok {
+		c := n.editable(edit)
+		c.bitmap |= bit
+		c.entries = slices.Insert(c.entries, i, e)
+		return c, true
+	}
+
+	old := n.entries[i]
+	switch {
+	case old.child != nil:
+		child, added := old.child.set(edit, shift+levelBits, e)
+		if child == old.child {
+			return n, added // edited in place by a transient
+		}
+		c := n.editable(edit)
+		c.entries[i].child = child
+		return c, added
+	case old.key == e.key:
+		c := n.editable(edit)
+		c.entries[i].value = e.value
+		return c, false
+	default:
+		c := n.editable(edit)
+		c.entries[i] = entry[K, V]{child: pairNode(edit, shift+levelBits, old, e)}
+		return c, true
+	}
+}
+
+// pairNode builds the smallest subtree holding two distinct keys.
+func pairNode[K comparable, V any](edit *owner, shift uint, a, b entry[K, V]) *hnode[K, V] {
+	if shift >= hashBits {
+		return &hnode[K, V]{edit: edit, collision: true, entries: []entry[K, V]{a, b}}
+	}
+	bitA, bitB := fragment(a.hash, shift), fragment(b.hash, shift)
+	if bitA == bitB {
+		return &hnode[K, V]{edit: edit, bitmap: bitA, entries: []entry[K, V]{{child: pairNode(edit, shift+levelBits, a, b)}}}
+	}
+	if bitA > bitB {
+		a, b = b, a
+	}
+	return &hnode[K, V]{edit: edit, bitmap: bitA | bitB, entries: []entry[K, V]{a, b}}
+}
+
+// delete removes k below n. It returns nil when n is left empty, and
+// reports whether k was present. A subtree left holding a single pair is
+// collapsed into its parent so lookups stay short.
+func (n *hnode[K, V]) delete(edit *owner, shift uint, h uint64, k K) (*hnode[K, V], bool) {
+	if n.collision {
+		for i := range n.entries {
+			if n.entries[i].key == k {
+				if len(n.entries) == 1 {
+					return nil, true
+				}
+				c := n.editable(edit)
+				c.entries = slices.Delete(c.entries, i, i+1)
+				return c, true
+			}
+		}
+		return n, false
+	}
+
+	bit := fragment(h, shift)
+	i, ok := n.slot(bit)
+	if !ok {
+		return n, false
+	}
+
+	e := n.entries[i]
+	if e.child == nil {
+		if e.key != k {
+			return n, false
+		}
+		if len(n.entries) == 1 {
+			return nil, true
+		}
+		c := n.editable(edit)
+		c.bitmap &^= bit
+		c.entries = slices.Delete(c.entries, i, i+1)
+		return c, true
+	}
+
+	child, removed := e.child.delete(edit, shift+levelBits, h, k)
+	if !removed {
+		return n, false
+	}
+	if child == nil {
+		if len(n.entries) == 1 {
+			return nil, true
+		}
+		c := n.editable(edit)
+		c.bitmap &^= bit
+		c.entries = slices.Delete(c.entries, i, i+1)
+		return c, true
+	}
+	c := n.editable(edit)
+	if len(child.entries) == 1 && child.entries[0].child == nil {
+		c.entries[i] = child.entries[0] // pull the lone pair up
+	} else {
+		c.entries[i].child = child
+	}
+	return c, true
+}
+
+// TransientMap is a mutable builder for a Map, the counterpart of
+// TransientVector. Call Persistent when done.
+type TransientMap[K comparable, V any] struct {
+	m    Map[K, V]
+	edit *owner
+}
+
+// Transient returns a builder starting from m.
+func (m Map[K, V]) Transient() *TransientMap[K, V] {
+	return &TransientMap[K, V]{m: m, edit: new(owner)}
+}
+
+func (t *TransientMap[K, V]) ensure() {
+	if t.edit == nil {
+		panic("persistent: transient used after Persistent")
+	}
+}
+
+// Len returns the number of entries.
+func (t *TransientMap[K, V]) Len() int {
+	t.ensure()
+	return t.m.len
+}
+
+// Get returns the value stored for k.
+func (t *TransientMap[K, V]) Get(k K) (V, bool) {
+	t.ensure()
+	return t.m.Get(k)
+}
+
+// Set maps k to v.
+func (t *TransientMap[K, V]) Set(k K, v V) {
+	t.ensure()
+	if t.m.root == nil {
+		t.m.root = &hnode[K, V]{edit: t.edit}
+	}
+	var added bool
+	t.m.root, added = t.m.root.set(t.edit, 0, entry[K, V]{hash: hashOf(k), key: k, value: v})
+	if added {
+		t.m.len++
+	}
+}
+
+// Delete removes k, if present.
+func (t *TransientMap[K, V]) Delete(k K) {
+	t.ensure()
+	if t.m.root == nil {
+		return
+	}
+	root, removed := t.m.root.delete(t.edit, 0, hashOf(k), k)
+	if removed {
+		t.m.root = root
+		t.m.len--
+	}
+}
+
+// Persistent freezes the transient into a Map and ends the transient.
+func (t *TransientMap[K, V]) Persistent() Map[K, V] {
+	t.ensure()
+	t.edit = nil
+	return t.m
+}
@@ -0,0 +1,167 @@
+package persistent
+
+import (
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"testing"
+)
+
+func checkMap(t *testing.T, step string, m Map[int, int], want map[int]int) {
+	t.Helper()
+	if m.Len() != len(want) {
+		t.Fatalf("%s: Len = %d, want %d", step, m.Len(), len(want))
+	}
+	if got := m.ToMap(); !maps.Equal(got, want) {
+		t.Fatalf("%s: entries differ", step)
+	}
+	for k, v := range want {
+		if got, ok := m.Get(k); !ok || got != v {
+			t.Fatalf("%s: Get(%d) = %d, %v; want %d", step, k, got, ok, v)
+		}
+	}
+}
+
+// TestMapMatchesGoMap runs random Set and Delete calls against a Go map,
+// keeping every fiftieth version, which must still be intact at the end.
+func TestMapMatchesGoMap(t *testing.T) {
+	rng := rand.New(rand.NewPCG(11, 11))
+	var m Map[int, int]
+	want := map[int]int{}
+	type version struct {
+		m    Map[int, int]
+		want map[int]int
+	}
+	var kept []version
+
+	for step := range 20_000 {
+		k := rng.IntN(3000)
+		if rng.IntN(3) == 0 {
+			m = m.Delete(k)
+			delete(want, k)
+		} else {
+			m = m.Set(k, step)
+			want[k] = step
+		}
+		if _, present := want[k]; m.Has(k) != present {
+			t.Fatalf("step %d: Has(%d) = %v", step, k, m.Has(k))
+		}
+		if step%50 == 0 {
+			kept = append(kept, version{m, maps.Clone(want)})
+		}
+	}
+	checkMap(t, "final", m, want)
+	for i, k := range kept {
+		checkMap(t, fmt.Sprintf("version %d", i), k.m, k.want)
+	}
+
+	for k := range want {
+		m = m.Delete(k)
+	}
+	if m.Len() != 0 || m.root != nil {
+		t.Errorf("deleting everything left Len %d, root %v", m.Len(), m.root)
+	}
+}
+
+func TestMapDeleteMissing(t *testing.T) {
+	m := MapOf(map[string]int{"a": 1})
+	if got := m.Delete("b"); got != m {
+		t.Error("deleting a missing key didn't return the map unchanged")
+	}
+	var empty Map[string, int]
+	if got := empty.Delete("a"); got.Len() != 0 {
+		t.Error("deleting from the zero map failed")
+	}
+}
+
+func TestTransientMap(t *testing.T) {
+	base := MapOf(map[int]int{1: 1, 2: 2, 3: 3})
+	tr := base.Transient()
+	for k := range 1000 {
+		tr.Set(k, -k)
+	}
+	tr.Delete(2)
+	tr.Delete(5000)
+	result := tr.Persistent()
+
+	checkMap(t, "base", base, map[int]int{1: 1, 2: 2, 3: 3})
+	if result.Len() != 999 || result.Has(2) {
+		t.Fatalf("result has Len %d, Has(2) %v", result.Len(), result.Has(2))
+	}
+
+	// A new transient from the result must copy, not edit, the nodes the
+	// first transient created.
+	snapshot := result.ToMap()
+	tr2 := result.Transient()
+	for k := range 1000 {
+		tr2.Set(k, 0)
+	}
+	tr2.Delete(7)
+	tr2.Persistent()
+	if !maps.Equal(result.ToMap(), snapshot) {
+		t.Fatal("a second transient edited nodes shared with an earlier result")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("using a transient after Persistent didn't panic")
+		}
+	}()
+	tr.Set(1, 1)
+}
+
+// TestMapHashCollisions forces keys with equal hashes, which real keys
+// almost never have, to exercise collision nodes below the last level.
+func TestMapHashCollisions(t *testing.T) {
+	const h = 0xdeadbeef
+	root := &hnode[string, int]{}
+	keys := []string{"a", "b", "c"}
+	for i, k := range keys {
+		var added bool
+		root, added = root.set(nil, 0, entry[string, int]{hash: h, key: k, value: i})
+		if !added {
+			t.Fatalf("Set(%q) reported an existing key", k)
+		}
+	}
+	before := root
+
+	root, added := root.set(nil, 0, entry[string, int]{hash: h, key: "b", value: 10})
+	if added {
+		t.Error("replacing a colliding key reported a new key")
+	}
+	if v, ok := before.get(h, "b"); !ok || v != 1 {
+		t.Errorf("replacing changed the earlier version: %d, %v", v, ok)
+	}
+	if v, ok := root.get(h, "b"); !ok || v != 10 {
+		t.Errorf("get(b) = %d, %v", v, ok)
+	}
+	if _, ok := root.get(h, "z"); ok {
+		t.Error("found a key that was never set")
+	}
+
+	for _, k := range keys {
+		var removed bool
+		root, removed = root.delete(nil, 0, h, k)
+		if !removed {
+			t.Fatalf("delete(%q) found nothing", k)
+		}
+		if _, ok := root.get(h, k); ok {
+			t.Fatalf("%q still present after delete", k)
+		}
+	}
+	if root != nil {
+		t.Errorf("deleting every key left %+v", root)
+	}
+	for i, k := range keys {
+		if v, ok := before.get(h, k); !ok || v != i {
+			t.Errorf("deleting changed the earlier version: %s=%d, %v", k, v, ok)
+		}
+	}
+}
+
+func TestMapString(t *testing.T) {
+	m := MapOf(map[string]int{"b": 2, "a": 1, "c": 3})
+	if got := m.String(); got != "map[a:1 b:2 c:3]" {
+		t.Errorf("got %q", got)
+	}
+}
@@ -0,0 +1,115 @@
+package persistent
+
+import "fmt"
+
+// TransientVector is a mutable builder for a Vector. It copies a node the
+// first time it changes it and edits its own copies in place after that,
+// so a batch of n changes costs far less than n persistent updates. The
+// vector it started from is never affected.
+//
+// Call Persistent when done; the transient must not be used afterwards.
+// A transient is not safe for concurrent use.
+type TransientVector[T any] struct {
+	v    Vector[T]
+	edit *owner
+}
+
+// Transient returns a builder starting from v.
+func (v Vector[T]) Transient() *TransientVector[T] {
+	t := &TransientVector[T]{v: v, edit: new(owner)}
+	t.v.tail = t.ownTail(v.tail)
+	return t
+}
+
+// ownTail copies tail into a buffer the transient may append to.
+func (t *TransientVector[T]) ownTail(tail []T) []T {
+	own := make([]T, len(tail), width)
+	copy(own, tail)
+	return own
+}
+
+func (t *TransientVector[T]) ensure() {
+	if t.edit == nil {
+		panic("persistent: transient used after Persistent")
+	}
+}
+
+// Len returns the number of values.
+func (t *TransientVector[T]) Len() int {
+	t.ensure()
+	return t.v.cnt
+}
+
+// Get returns the value at index i.
+func (t *TransientVector[T]) Get(i int) T {
+	t.ensure()
+	return t.v.Get(i)
+}
+
+// Set replaces the value at index i.
+func (t *TransientVector[T]) Set(i int, value T) {
+	t.ensure()
+	t.v.check(i)
+	if i >= t.v.tailOffset() {
+		t.v.tail[i&mask] = value
+		return
+	}
+	t.v.root = assoc(t.edit, t.v.shift, t.v.root, i, value)
+}
+
+// Append adds value at the end.
+func (t *TransientVector[T]) Append(value T) {
+	t.ensure()
+	v := &t.v
+	if v.cnt-v.tailOffset() < width {
+		v.tail = append(v.tail, value)
+		v.cnt++
+		return
+	}
+	leaf := &vnode[T]{edit: t.edit, vals: v.tail}
+	v.root, v.shift = pushTail(t.edit, v.cnt, v.shift, v.root, leaf)
+	v.tail = make([]T, 1, width)
+	v.tail[0] = value
+	v.cnt++
+}
+
+// Pop removes and returns the last value. It panics if the vector is
+// empty.
+func (t *TransientVector[T]) Pop() T {
+	t.ensure()
+	v := &t.v
+	if v.cnt == 0 {
+		panic("persistent: Pop of an empty vector")
+	}
+	last := v.Get(v.cnt - 1)
+	if v.cnt-v.tailOffset() > 1 || v.cnt == 1 {
+		var zero T
+		v.tail[len(v.tail)-1] = zero
+		v.tail = v.tail[:len(v.tail)-1]
+		v.cnt--
+		if v.cnt == 0 {
+			v.root, v.shift = nil, 0
+		}
+		return last
+	}
+	// The leaf leaving the trie becomes the tail. It may still be shared
+	// with persistent versions, so it is copied rather than adopted.
+	v.tail = t.ownTail(v.leafFor(v.cnt - 2))
+	v.root, v.shift = popTail(t.edit, v.cnt, v.shift, v.root)
+	v.cnt--
+	return last
+}
+
+// Persistent freezes the transient into a Vector and ends the transient.
+func (t *TransientVector[T]) Persistent() Vector[T] {
+	t.ensure()
+	t.edit = nil
+	v := t.v
+	v.tail = v.tail[:len(v.tail):len(v.tail)]
+	return v
+}
+
+// String formats the current values like a slice.
+func (t *TransientVector[T]) String() string {
+	return fmt.Sprint(t.v.Values())
+}
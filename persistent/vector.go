@@ -0,0 +1,280 @@
+// Package persistent provides immutable Vector and Map types that share
+// structure between versions. "Changing" one returns a new value and
+// leaves the old one intact, but only the path from the root to the
+// changed element is copied, so Set, Append and Delete cost O(log n)
+// instead of the O(n) of a deep copy. Copying a value is O(1): it is just
+// a struct assignment, and nothing a copy holder does can affect anyone
+// else's version.
+//
+// This is the alternative to chapter 9's Person.DeepCopy, which has to
+// copy every slice and map up front, and has to be updated by hand every
+// time a field is added.
+//
+// For many changes in a row, Transient returns a mutable builder that
+// edits nodes it has already copied in place, then Persistent freezes the
+// result again.
+package persistent
+
+import (
+	"fmt"
+	"iter"
+)
+
+const (
+	levelBits = 5 // index bits consumed per trie level
+	width     = 1 << levelBits
+	mask      = width - 1
+)
+
+// owner marks the nodes a transient created and may therefore mutate.
+// Each transient gets a new owner, so nodes from a finished transient can
+// never be edited again. The field keeps owner from being zero-sized,
+// since distinct pointers to zero-sized values may compare equal.
+type owner struct{ _ byte }
+
+// vnode is a vector trie node: a branch holds kids, a leaf holds values.
+type vnode[T any] struct {
+	edit *owner
+	kids []*vnode[T]
+	vals []T
+}
+
+// Vector is an immutable indexed sequence: a 32-way trie of leaves plus a
+// tail buffer for the last few values, so Get, Set, Append and Pop are all
+// O(log32 n), which is at most 7 levels for any realistic length. The zero
+// value is an empty vector.
+type Vector[T any] struct {
+	cnt   int
+	shift uint
+	root  *vnode[T]
+	tail  []T
+}
+
+// VectorOf builds a vector holding values.
+func VectorOf[T any](values ...T) Vector[T] {
+	t := Vector[T]{}.Transient()
+	for _, v := range values {
+		t.Append(v)
+	}
+	return t.Persistent()
+}
+
+// Len returns the number of values.
+func (v Vector[T]) Len() int {
+	return v.cnt
+}
+
+func (v Vector[T]) tailOffset() int {
+	if v.cnt < width {
+		return 0
+	}
+	return ((v.cnt - 1) >> levelBits) << levelBits
+}
+
+// leafFor returns the values slice holding index i.
+func (v Vector[T]) leafFor(i int) []T {
+	if i >= v.tailOffset() {
+		return v.tail
+	}
+	n := v.root
+	for level := v.shift; level > 0; level -= levelBits {
+		n = n.kids[(i>>level)&mask]
+	}
+	return n.vals
+}
+
+func (v Vector[T]) check(i int) {
+	if i < 0 || i >= v.cnt {
+		panic(fmt.Sprintf("persistent: vector index %d out of range [0:%d]", i, v.cnt))
+	}
+}
+
+// Get returns the value at index i. It panics if i is out of range, like
+// indexing a slice.
+func (v Vector[T]) Get(i int) T {
+	v.check(i)
+	return v.leafFor(i)[i&mask]
+}
+
+// Set returns a vector with the value at index i replaced. It panics if i
+// is out of range.
+func (v Vector[T]) Set(i int, value T) Vector[T] {
+	v.check(i)
+	if i >= v.tailOffset() {
+		tail := append([]T(nil), v.tail...)
+		tail[i&mask] = value
+		v.tail = tail
+		return v
+	}
+	v.root = assoc(nil, v.shift, v.root, i, value)
+	return v
+}
+
+// Append returns a vector with value added at the end.
+func (v Vector[T]) Append(value T) Vector[T] {
+	if v.cnt-v.tailOffset() < width {
+		tail := make([]T, len(v.tail)+1)
+		copy(tail, v.tail)
+		tail[len(v.tail)] = value
+		v.tail = tail
+		v.cnt++
+		return v
+	}
+	v.root, v.shift = pushTail(nil, v.cnt, v.shift, v.root, &vnode[T]{vals: v.tail})
+	v.tail = []T{value}
+	v.cnt++
+	return v
+}
+
+// Pop returns a vector without its last value, and that value. It panics
+// if the vector is empty.
+func (v Vector[T]) Pop() (Vector[T], T) {
+	if v.cnt == 0 {
+		panic("persistent: Pop of an empty vector")
+	}
+	last := v.Get(v.cnt - 1)
+	if v.cnt == 1 {
+		return Vector[T]{}, last
+	}
+	if v.cnt-v.tailOffset() > 1 {
+		v.tail = v.tail[: len(v.tail)-1 : len(v.tail)-1]
+		v.cnt--
+		return v, last
+	}
+	v.tail = v.leafFor(v.cnt - 2)
+	v.root, v.shift = popTail(nil, v.cnt, v.shift, v.root)
+	v.cnt--
+	return v, last
+}
+
+// All iterates over the index/value pairs in order.
+func (v Vector[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for start := 0; start < v.cnt; start += width {
+			for j, value := range v.leafFor(start) {
+				if !yield(start+j, value) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Values returns the values as a new slice.
+func (v Vector[T]) Values() []T {
+	values := make([]T, 0, v.cnt)
+	for _, value := range v.All() {
+		values = append(values, value)
+	}
+	return values
+}
+
+// String formats the vector like a slice.
+func (v Vector[T]) String() string {
+	return fmt.Sprint(v.Values())
+}
+
+// editable returns n if the transient identified by edit owns it, and a
+// copy owned by edit otherwise. Persistent operations pass a nil edit and
+// so always copy.
+func (n *vnode[T]) editable(edit *owner) *vnode[T] {
+	if edit != nil && n.edit == edit {
+		return n
+	}
+	c := &vnode[T]{edit: edit}
+	if n.kids != nil {
+		c.kids = make([]*vnode[T], len(n.kids), width)
+		copy(c.kids, n.kids)
+	} else {
+		c.vals = make([]T, len(n.vals))
+		copy(c.vals, n.vals)
+	}
+	return c
+}
+
+func assoc[T any](edit *owner, level uint, n *vnode[T], i int, value T) *vnode[T] {
+	c := n.editable(edit)
+	if level == 0 {
+		c.vals[i&mask] = value
+		return c
+	}
+	sub := (i >> level) & mask
+	c.kids[sub] = assoc(edit, level-levelBits, n.kids[sub], i, value)
+	return c
+}
+
+// pushTail moves a full tail leaf into the trie of a vector holding cnt
+// values, adding a level when the root is full.
+func pushTail[T any](edit *owner, cnt int, shift uint, root, leaf *vnode[T]) (*vnode[T], uint) {
+	if root == nil {
+		return &vnode[T]{edit: edit, kids: []*vnode[T]{leaf}}, levelBits
+	}
+	if cnt>>levelBits > 1<<shift {
+		return &vnode[T]{edit: edit, kids: []*vnode[T]{root, newPath(edit, shift, leaf)}}, shift + levelBits
+	}
+	return pushTailAt(edit, cnt, shift, root, leaf), shift
+}
+
+func pushTailAt[T any](edit *owner, cnt int, level uint, parent, leaf *vnode[T]) *vnode[T] {
+	c := parent.editable(edit)
+	sub := ((cnt - 1) >> level) & mask
+	var child *vnode[T]
+	if level == levelBits {
+		child = leaf
+	} else if sub < len(parent.kids) {
+		child = pushTailAt(edit, cnt, level-levelBits, parent.kids[sub], leaf)
+	} else {
+		child = newPath(edit, level-levelBits, leaf)
+	}
+	if sub < len(c.kids) {
+		c.kids[sub] = child
+	} else {
+		c.kids = append(c.kids, child)
+	}
+	return c
+}
+
+func newPath[T any](edit *owner, level uint, leaf *vnode[T]) *vnode[T] {
+	if level == 0 {
+		return leaf
+	}
+	return &vnode[T]{edit: edit, kids: []*vnode[T]{newPath(edit, level-levelBits, leaf)}}
+}
+
+// popTail removes the last leaf from the trie of a vector holding cnt
+// values, dropping a level when the root is left with one child.
+func popTail[T any](edit *owner, cnt int, shift uint, root *vnode[T]) (*vnode[T], uint) {
+	root = popTailAt(edit, cnt, shift, root)
+	if root == nil {
+		return nil, 0
+	}
+	if shift > levelBits && len(root.kids) == 1 {
+		return root.kids[0], shift - levelBits
+	}
+	return root, shift
+}
+
+func popTailAt[T any](edit *owner, cnt int, level uint, n *vnode[T]) *vnode[T] {
+	sub := ((cnt - 2) >> level) & mask
+	if level > levelBits {
+		child := popTailAt(edit, cnt, level-levelBits, n.kids[sub])
+		if child == nil && sub == 0 {
+			return nil
+		}
+		c := n.editable(edit)
+		if child == nil {
+			clear(c.kids[sub:])
+			c.kids = c.kids[:sub]
+		} else {
+			c.kids[sub] = child
+		}
+		return c
+	}
+	if sub == 0 {
+		return nil
+	}
+	c := n.editable(edit)
+	clear(c.kids[sub:])
+	c.kids = c.kids[:sub]
+	return c
+}
@@ -0,0 +1,181 @@
+package persistent
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// version is a vector together with the slice it should equal.
+type version struct {
+	v    Vector[int]
+	want []int
+}
+
+func checkVector(t *testing.T, step string, v Vector[int], want []int) {
+	t.Helper()
+	if v.Len() != len(want) {
+		t.Fatalf("%s: Len = %d, want %d", step, v.Len(), len(want))
+	}
+	if got := v.Values(); !slices.Equal(got, want) {
+		t.Fatalf("%s: Values differ at length %d", step, len(want))
+	}
+	for _, i := range []int{0, len(want) / 2, len(want) - 1} {
+		if i >= 0 && i < len(want) && v.Get(i) != want[i] {
+			t.Fatalf("%s: Get(%d) = %d, want %d", step, i, v.Get(i), want[i])
+		}
+	}
+}
+
+// TestVectorMatchesSlice runs random operations against a slice and keeps
+// every hundredth version, which must still be intact at the end. The
+// sizes cross the tail (32), one-level (1024) and two-level (32768)
+// boundaries in both directions.
+func TestVectorMatchesSlice(t *testing.T) {
+	rng := rand.New(rand.NewPCG(7, 7))
+	var v Vector[int]
+	var want []int
+	var kept []version
+
+	for step := range 120_000 {
+		switch op := rng.IntN(10); {
+		case op < 6 || len(want) == 0 || step < 40_000:
+			v = v.Append(step)
+			want = append(want, step)
+		case op < 8:
+			i := rng.IntN(len(want))
+			v = v.Set(i, -step)
+			want[i] = -step
+		default:
+			var last int
+			v, last = v.Pop()
+			if last != want[len(want)-1] {
+				t.Fatalf("step %d: Pop returned %d, want %d", step, last, want[len(want)-1])
+			}
+			want = want[:len(want)-1]
+		}
+		if step%100 == 0 {
+			kept = append(kept, version{v, slices.Clone(want)})
+		}
+	}
+	for len(want) > 0 {
+		v, _ = v.Pop()
+		want = want[:len(want)-1]
+		if len(want)%997 == 0 {
+			checkVector(t, "popping", v, want)
+		}
+	}
+	checkVector(t, "empty", v, nil)
+
+	for i, k := range kept {
+		checkVector(t, fmt.Sprintf("version %d", i), k.v, k.want)
+	}
+}
+
+func TestVectorVersionsDontShare(t *testing.T) {
+	for _, n := range []int{0, 5, 31, 32, 33, 1056, 1057} {
+		base := VectorOf(make([]int, n)...)
+
+		// Two appends from the same version must not land in one tail.
+		a, b := base.Append(1), base.Append(2)
+		if a.Get(n) != 1 || b.Get(n) != 2 || base.Len() != n {
+			t.Errorf("n=%d: appends from one version interfere: %d %d", n, a.Get(n), b.Get(n))
+		}
+		if n == 0 {
+			continue
+		}
+		set := base.Set(n-1, 9)
+		popped, _ := base.Pop()
+		if base.Get(n-1) != 0 || set.Get(n-1) != 9 || popped.Len() != n-1 || base.Len() != n {
+			t.Errorf("n=%d: Set or Pop changed the version it started from", n)
+		}
+		again := popped.Append(7)
+		if base.Get(n-1) != 0 || again.Get(n-1) != 7 {
+			t.Errorf("n=%d: append after pop wrote into the original", n)
+		}
+	}
+}
+
+func TestTransientVector(t *testing.T) {
+	base := VectorOf(make([]int, 100)...)
+	tr := base.Transient()
+	for i := range 100 {
+		tr.Set(i, i)
+	}
+	for i := range 1000 {
+		tr.Append(i)
+	}
+	for range 500 {
+		tr.Pop()
+	}
+	result := tr.Persistent()
+
+	if base.Len() != 100 || slices.ContainsFunc(base.Values(), func(v int) bool { return v != 0 }) {
+		t.Fatal("the transient changed the vector it started from")
+	}
+	if result.Len() != 600 || result.Get(99) != 99 || result.Get(599) != 499 {
+		t.Fatalf("result has Len %d, Get(99) %d, Get(599) %d", result.Len(), result.Get(99), result.Get(599))
+	}
+
+	// A new transient from the result must copy, not edit, the nodes the
+	// first transient created.
+	snapshot := result.Values()
+	tr2 := result.Transient()
+	for i := range result.Len() {
+		tr2.Set(i, -1)
+	}
+	tr2.Append(-1)
+	tr2.Persistent()
+	if !slices.Equal(result.Values(), snapshot) {
+		t.Fatal("a second transient edited nodes shared with an earlier result")
+	}
+}
+
+func TestTransientVectorPopIntoSharedLeaf(t *testing.T) {
+	base := VectorOf(make([]int, 33)...) // one full leaf in the trie, one value in the tail
+	tr := base.Transient()
+	tr.Pop()
+	tr.Pop() // the leaf comes back out of the trie as the tail
+	tr.Set(0, 5)
+	tr.Append(6)
+	if base.Get(0) != 0 || base.Get(31) != 0 {
+		t.Fatal("the transient wrote into a leaf shared with its source")
+	}
+	if got := tr.Persistent(); got.Len() != 32 || got.Get(0) != 5 || got.Get(31) != 6 {
+		t.Fatalf("got %v", got)
+	}
+}
+
+func TestVectorPanics(t *testing.T) {
+	tr := Vector[int]{}.Transient()
+	tr.Persistent()
+	for name, f := range map[string]func(){
+		"Get out of range":     func() { VectorOf(1, 2).Get(2) },
+		"Get negative":         func() { VectorOf(1, 2).Get(-1) },
+		"Set out of range":     func() { VectorOf(1).Set(1, 0) },
+		"Pop empty":            func() { Vector[int]{}.Pop() },
+		"transient Pop empty":  func() { Vector[int]{}.Transient().Pop() },
+		"transient after done": func() { tr.Append(1) },
+	} {
+		func() {
+			defer func() {
+				r := recover()
+				if msg, ok := r.(string); !ok || !strings.HasPrefix(msg, "persistent: ") {
+					t.Errorf("%s: recovered %v", name, r)
+				}
+			}()
+			f()
+		}()
+	}
+}
+
+func TestVectorString(t *testing.T) {
+	if got := VectorOf("a", "b").String(); got != "[a b]" {
+		t.Errorf("got %q", got)
+	}
+	if got := (Vector[int]{}).String(); got != "[]" {
+		t.Errorf("empty vector gave %q", got)
+	}
+}
@@ -0,0 +1,190 @@
+// Package reflectutil uses reflection to do, for any type, what chapter 9
+// does by hand for one: DeepCopy replaces Person.DeepCopy, which has to be
+// updated every time a slice or map field is added, and Diff and DeepEqual
+// compare two values field by field and say where they differ, and SizeOf
+// replaces estimateStructSize's guesswork with a walk of the real memory.
+package reflectutil
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+// Copier configures DeepCopy. The zero value copies exported fields deeply
+// and uses no hooks. A Copier may be shared by concurrent copies once its
+// hooks are registered.
+type Copier struct {
+	// Unexported deep-copies unexported struct fields too. Without it they
+	// are copied the way struct assignment copies them: slices, maps and
+	// pointers in unexported fields stay shared with the original.
+	Unexported bool
+
+	hooks map[reflect.Type]func(reflect.Value) reflect.Value
+}
+
+// Hook registers f as the way to copy values of type T, overriding the
+// reflective walk for that type wherever it appears, including inside
+// other values. Use it for types whose copy needs care, such as types
+// holding a mutex, a file handle or a cache that should start empty.
+// T may be an interface type; f then sees every value stored in a
+// variable of that type, nil included, and may return nil.
+// Hook returns c so calls can be chained.
+func Hook[T any](c *Copier, f func(T) T) *Copier {
+	if c.hooks == nil {
+		c.hooks = make(map[reflect.Type]func(reflect.Value) reflect.Value)
+	}
+	c.hooks[reflect.TypeFor[T]()] = func(v reflect.Value) reflect.Value {
+		// through pointers, so a nil interface T in or out stays a T
+		var in T
+		reflect.ValueOf(&in).Elem().Set(v)
+		out := f(in)
+		return reflect.ValueOf(&out).Elem()
+	}
+	return c
+}
+
+var defaultCopier Copier
+
+// DeepCopy returns a copy of v that shares no mutable memory with it:
+// every pointer, slice, map and interface value reachable from v is copied
+// as well. Two pointers to the same value, two references to the same map
+// and two identical slices (same array, length and capacity) share one copy,
+// and cycles are reproduced rather than followed forever. Other aliasing is
+// not preserved: slices that overlap without being identical, such as s and
+// s[1:], and pointers into a slice's elements or a struct's fields get
+// copies of their own. Channels, functions and unsafe pointers are copied
+// as is, since they can't be duplicated meaningfully.
+func DeepCopy[T any](v T) T {
+	return CopyWith(&defaultCopier, v)
+}
+
+// CopyWith is DeepCopy with a configured Copier.
+func CopyWith[T any](c *Copier, v T) T {
+	src := reflect.New(reflect.TypeFor[T]()).Elem()
+	src.Set(reflect.ValueOf(&v).Elem())
+
+	dst := reflect.New(src.Type()).Elem()
+	run := copyRun{Copier: c, seen: make(map[seenKey]reflect.Value)}
+	run.copy(dst, src)
+	return *dst.Addr().Interface().(*T)
+}
+
+// seenKey identifies memory that has already been copied. Type is part of
+// the key because a struct and its first field share an address.
+type seenKey struct {
+	typ      reflect.Type
+	ptr      unsafe.Pointer
+	len, cap int
+}
+
+type copyRun struct {
+	*Copier
+	seen map[seenKey]reflect.Value
+}
+
+// copy sets dst, which must be settable, to a deep copy of src.
+func (r *copyRun) copy(dst, src reflect.Value) {
+	if hook, ok := r.hooks[src.Type()]; ok {
+		dst.Set(hook(src))
+		return
+	}
+
+	switch src.Kind() {
+	case reflect.Pointer:
+		if src.IsNil() {
+			return
+		}
+		key := seenKey{typ: src.Type(), ptr: src.UnsafePointer()}
+		if done, ok := r.seen[key]; ok {
+			dst.Set(done)
+			return
+		}
+		p := reflect.New(src.Type().Elem())
+		r.seen[key] = p // before recursing, so cycles find it
+		r.copy(p.Elem(), src.Elem())
+		dst.Set(p)
+
+	case reflect.Interface:
+		if src.IsNil() {
+			return
+		}
+		inner := src.Elem()
+		c := reflect.New(inner.Type()).Elem()
+		r.copy(c, inner)
+		dst.Set(c)
+
+	case reflect.Slice:
+		if src.IsNil() {
+			return
+		}
+		key := seenKey{typ: src.Type(), ptr: src.UnsafePointer(), len: src.Len(), cap: src.Cap()}
+		if done, ok := r.seen[key]; ok {
+			dst.Set(done)
+			return
+		}
+		s := reflect.MakeSlice(src.Type(), src.Len(), src.Cap())
+		r.seen[key] = s
+		for i := range src.Len() {
+			r.copy(s.Index(i), src.Index(i))
+		}
+		dst.Set(s)
+
+	case reflect.Map:
+		if src.IsNil() {
+			return
+		}
+		key := seenKey{typ: src.Type(), ptr: src.UnsafePointer()}
+		if done, ok := r.seen[key]; ok {
+			dst.Set(done)
+			return
+		}
+		m := reflect.MakeMapWithSize(src.Type(), src.Len())
+		r.seen[key] = m
+		kt, vt := src.Type().Key(), src.Type().Elem()
+		for iter := src.MapRange(); iter.Next(); {
+			k := reflect.New(kt).Elem()
+			r.copy(k, iter.Key())
+			v := reflect.New(vt).Elem()
+			r.copy(v, iter.Value())
+			m.SetMapIndex(k, v)
+		}
+		dst.Set(m)
+
+	case reflect.Array:
+		for i := range src.Len() {
+			r.copy(dst.Index(i), src.Index(i))
+		}
+
+	case reflect.Struct:
+		dst.Set(src) // unexported fields start out shallow-copied
+		if r.Unexported && !src.CanAddr() {
+			src = addressable(src)
+		}
+		t := src.Type()
+		for i := range t.NumField() {
+			switch {
+			case t.Field(i).IsExported():
+				r.copy(dst.Field(i), src.Field(i))
+			case r.Unexported:
+				r.copy(unlock(dst.Field(i)), unlock(src.Field(i)))
+			}
+		}
+
+	default:
+		dst.Set(src)
+	}
+}
+
+// addressable returns an addressable copy of v, so its unexported fields
+// can be unlocked.
+func addressable(v reflect.Value) reflect.Value {
+	c := reflect.New(v.Type()).Elem()
+	c.Set(v)
+	return c
+}
+
+// unlock returns a view of an addressable unexported field that reflect
+// allows to be read and set.
+func unlock(field reflect.Value) reflect.Value {
+	return reflect.NewAt(field.Type(), unsafe.Pointer(field.UnsafeAddr())).Elem()
+}
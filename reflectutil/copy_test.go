@@ -0,0 +1,258 @@
+package reflectutil
+
+import (
+	"slices"
+	"sync"
+	"testing"
+)
+
+// person and largeStruct have the shape of chapter 9's Person and
+// LargeStruct, the types DeepCopy replaced hand-written copies for.
+type person struct {
+	Name     string
+	Age      int
+	Email    string
+	IsActive bool
+	Hobbies  []string
+	Metadata map[string]string
+}
+
+type largeStruct struct {
+	ID, FirstName, LastName, Email string
+	Preferences                    map[string]string
+	Tags                           []string
+	Metadata                       map[string]interface{}
+}
+
+func newPerson() person {
+	return person{
+		Name:     "Alice",
+		Age:      30,
+		Email:    "alice@example.com",
+		IsActive: true,
+		Hobbies:  []string{"reading", "swimming"},
+		Metadata: map[string]string{"city": "New York"},
+	}
+}
+
+func newLargeStruct() largeStruct {
+	return largeStruct{
+		ID:          "user-1",
+		FirstName:   "Alice",
+		Preferences: map[string]string{"theme": "dark"},
+		Tags:        []string{"premium", "verified"},
+		Metadata: map[string]interface{}{
+			"source":  "web",
+			"devices": []string{"phone", "laptop"},
+			"limits":  map[string]int{"daily": 10},
+		},
+	}
+}
+
+func TestDeepCopyPerson(t *testing.T) {
+	orig := newPerson()
+	c := DeepCopy(orig)
+	if !DeepEqual(orig, c) {
+		t.Fatalf("copy differs: %v", Diff(orig, c))
+	}
+
+	c.Hobbies[0] = "coding"
+	c.Hobbies = append(c.Hobbies, "chess")
+	c.Metadata["city"] = "Boston"
+	if !DeepEqual(orig, newPerson()) {
+		t.Errorf("changing the copy changed the original: %v", Diff(newPerson(), orig))
+	}
+}
+
+func TestDeepCopyLargeStruct(t *testing.T) {
+	orig := newLargeStruct()
+	c := DeepCopy(orig)
+	if !DeepEqual(orig, c) {
+		t.Fatalf("copy differs: %v", Diff(orig, c))
+	}
+
+	// Values inside interface{} map entries are copied too.
+	c.Metadata["devices"].([]string)[0] = "tablet"
+	c.Metadata["limits"].(map[string]int)["daily"] = 0
+	c.Preferences["theme"] = "light"
+	c.Tags[1] = "unverified"
+	if d := Diff(newLargeStruct(), orig); d != nil {
+		t.Errorf("changing the copy changed the original: %v", d)
+	}
+}
+
+func TestDeepCopyNilAndEmpty(t *testing.T) {
+	c := DeepCopy(person{Hobbies: []string{}, Metadata: nil})
+	if c.Hobbies == nil || c.Metadata != nil {
+		t.Errorf("got Hobbies %#v, Metadata %#v; nil and empty must survive", c.Hobbies, c.Metadata)
+	}
+	if got := DeepCopy[*person](nil); got != nil {
+		t.Errorf("copying a nil pointer gave %v", got)
+	}
+}
+
+type node struct {
+	Value int
+	Next  *node
+}
+
+func TestDeepCopySharing(t *testing.T) {
+	shared := &person{Name: "Bob"}
+	tags := make([]string, 2, 4)
+	meta := map[string]string{"k": "v"}
+	type team struct {
+		Lead, Deputy *person
+		A, B         []string
+		M1, M2       map[string]string
+	}
+	c := DeepCopy(team{shared, shared, tags, tags, meta, meta})
+
+	if c.Lead != c.Deputy || c.Lead == shared {
+		t.Error("two pointers to one value should share one new copy")
+	}
+	if &c.A[0] != &c.B[0] || &c.A[0] == &tags[0] || cap(c.A) != 4 {
+		t.Error("two identical slices should share one new array of the same capacity")
+	}
+	c.M1["k"] = "changed"
+	if c.M2["k"] != "changed" || meta["k"] != "v" {
+		t.Error("two references to one map should share one new map")
+	}
+}
+
+func TestDeepCopyCycle(t *testing.T) {
+	a := &node{Value: 1}
+	b := &node{Value: 2, Next: a}
+	a.Next = b
+
+	c := DeepCopy(a)
+	if c == a || c.Next == b {
+		t.Fatal("the copy reuses original nodes")
+	}
+	if c.Next.Next != c || c.Value != 1 || c.Next.Value != 2 {
+		t.Error("the cycle wasn't reproduced")
+	}
+}
+
+// TestDeepCopyOverlapNotShared pins down the aliasing DeepCopy doesn't
+// preserve, as its doc says.
+func TestDeepCopyOverlapNotShared(t *testing.T) {
+	backing := []int{1, 2, 3}
+	type views struct {
+		All, Tail []int
+		Second    *int
+	}
+	c := DeepCopy(views{backing, backing[1:], &backing[1]})
+
+	if !slices.Equal(c.All, backing) || !slices.Equal(c.Tail, backing[1:]) || *c.Second != 2 {
+		t.Fatalf("got %+v", c)
+	}
+	c.All[1] = 20
+	if c.Tail[0] != 2 || *c.Second != 2 {
+		t.Error("overlapping slices or an element pointer now share memory; update the DeepCopy doc")
+	}
+}
+
+type private struct {
+	Public string
+	tags   []string
+}
+
+func TestCopierUnexported(t *testing.T) {
+	orig := private{"p", []string{"a"}}
+
+	shallow := DeepCopy(orig)
+	if &shallow.tags[0] != &orig.tags[0] {
+		t.Error("unexported fields should be shallow by default")
+	}
+
+	deep := CopyWith(&Copier{Unexported: true}, orig)
+	if &deep.tags[0] == &orig.tags[0] || !slices.Equal(deep.tags, orig.tags) {
+		t.Error("Unexported: true should copy unexported fields deeply")
+	}
+}
+
+type cache struct {
+	mu      sync.Mutex
+	entries map[string]int
+	Name    string
+}
+
+func TestHook(t *testing.T) {
+	c := Hook(&Copier{}, func(c *cache) *cache {
+		if c == nil {
+			return nil
+		}
+		return &cache{entries: map[string]int{}, Name: c.Name}
+	})
+	type service struct{ Primary, Backup *cache }
+	orig := service{Primary: &cache{entries: map[string]int{"a": 1}, Name: "p"}}
+
+	got := CopyWith(c, orig)
+	if got.Primary == orig.Primary || got.Primary.Name != "p" || len(got.Primary.entries) != 0 {
+		t.Errorf("the hook wasn't used for a nested value: %+v", got.Primary)
+	}
+	if got.Backup != nil {
+		t.Errorf("a nil pointer should stay nil, got %+v", got.Backup)
+	}
+}
+
+type shape interface{ Area() float64 }
+
+type square struct{ Side float64 }
+
+func (s *square) Area() float64 { return s.Side * s.Side }
+
+func TestHookInterface(t *testing.T) {
+	type drawing struct {
+		Main   shape
+		Shapes []shape
+	}
+	tests := []struct {
+		name string
+		hook func(shape) shape
+		orig drawing
+		want drawing
+	}{
+		{
+			name: "nil source",
+			hook: func(s shape) shape {
+				if s == nil {
+					return &square{Side: -1}
+				}
+				return s
+			},
+			orig: drawing{Shapes: []shape{nil}},
+			want: drawing{Main: &square{Side: -1}, Shapes: []shape{&square{Side: -1}}},
+		},
+		{
+			name: "nil result",
+			hook: func(shape) shape { return nil },
+			orig: drawing{Main: &square{Side: 2}, Shapes: []shape{&square{Side: 3}, nil}},
+			want: drawing{Shapes: []shape{nil, nil}},
+		},
+		{
+			name: "doubled",
+			hook: func(s shape) shape {
+				if sq, ok := s.(*square); ok {
+					return &square{Side: 2 * sq.Side}
+				}
+				return s
+			},
+			orig: drawing{Main: &square{Side: 2}, Shapes: []shape{nil, &square{Side: 3}}},
+			want: drawing{Main: &square{Side: 4}, Shapes: []shape{nil, &square{Side: 6}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CopyWith(Hook(&Copier{}, tt.hook), tt.orig)
+			if !DeepEqual(got, tt.want) {
+				t.Errorf("CopyWith differs from the want: %v", Diff(tt.want, got))
+			}
+
+			// the hook also applies to a top-level interface value
+			if got := CopyWith(Hook(&Copier{}, tt.hook), tt.orig.Main); !DeepEqual(got, tt.want.Main) {
+				t.Errorf("CopyWith(Main) = %v, want %v", got, tt.want.Main)
+			}
+		})
+	}
+}
@@ -0,0 +1,247 @@
+package reflectutil
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"slices"
+	"unsafe"
+)
+
+// Difference is one place where two values disagree. Path locates it from
+// the root in Go syntax, such as .Hobbies[2] or .Metadata["city"]; it is
+// empty when the values differ at the top.
+type Difference struct {
+	Path string
+	A, B string
+}
+
+func (d Difference) String() string {
+	path := d.Path
+	if path == "" {
+		path = "(value)"
+	}
+	return fmt.Sprintf("%s: %s != %s", path, d.A, d.B)
+}
+
+// DeepEqual reports whether a and b are deeply equal. It follows the rules
+// of Diff, so it stops at the first difference instead of collecting them.
+func DeepEqual(a, b any) bool {
+	w := differ{limit: 1, visited: make(map[visit]bool)}
+	w.walk("", reflect.ValueOf(a), reflect.ValueOf(b))
+	return len(w.diffs) == 0
+}
+
+// Diff returns every difference between a and b, in field order, with map
+// keys visited in sorted order so the output is stable.
+//
+// The rules are those of reflect.DeepEqual with two changes. Types with an
+// Equal(T) bool method, such as time.Time, are compared with it. And nil
+// and empty slices or maps are reported as different, since code that
+// checks for nil can tell them apart. Functions are equal only when both
+// are nil. Unexported fields are compared.
+func Diff(a, b any) []Difference {
+	w := differ{visited: make(map[visit]bool)}
+	w.walk("", reflect.ValueOf(a), reflect.ValueOf(b))
+	return w.diffs
+}
+
+// visit records a pair of references already being compared, which is how
+// Diff terminates on cyclic values.
+type visit struct {
+	a, b unsafe.Pointer
+	typ  reflect.Type
+}
+
+type differ struct {
+	limit   int // stop after this many differences; 0 means no limit
+	diffs   []Difference
+	visited map[visit]bool
+}
+
+func (w *differ) done() bool {
+	return w.limit > 0 && len(w.diffs) >= w.limit
+}
+
+func (w *differ) report(path string, a, b reflect.Value) {
+	w.diffs = append(w.diffs, Difference{Path: path, A: format(a), B: format(b)})
+}
+
+func (w *differ) reportf(path, a, b string) {
+	w.diffs = append(w.diffs, Difference{Path: path, A: a, B: b})
+}
+
+func (w *differ) walk(path string, a, b reflect.Value) {
+	if w.done() {
+		return
+	}
+	if !a.IsValid() || !b.IsValid() {
+		if a.IsValid() != b.IsValid() {
+			w.report(path, a, b)
+		}
+		return
+	}
+	if a.Type() != b.Type() {
+		w.reportf(path, a.Type().String(), b.Type().String())
+		return
+	}
+	if eq, ok := equalMethod(a, b); ok {
+		if !eq {
+			w.report(path, a, b)
+		}
+		return
+	}
+
+	switch a.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice:
+		if a.IsNil() || b.IsNil() {
+			if a.IsNil() != b.IsNil() {
+				w.report(path, a, b)
+			}
+			return
+		}
+		if a.UnsafePointer() == b.UnsafePointer() && (a.Kind() != reflect.Slice || a.Len() == b.Len()) {
+			return
+		}
+		v := visit{a.UnsafePointer(), b.UnsafePointer(), a.Type()}
+		if w.visited[v] {
+			return
+		}
+		w.visited[v] = true
+	}
+
+	switch a.Kind() {
+	case reflect.Pointer:
+		w.walk(path, a.Elem(), b.Elem())
+
+	case reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			if a.IsNil() != b.IsNil() {
+				w.report(path, a, b)
+			}
+			return
+		}
+		w.walk(path, a.Elem(), b.Elem())
+
+	case reflect.Slice, reflect.Array:
+		n := min(a.Len(), b.Len())
+		for i := range n {
+			w.walk(fmt.Sprintf("%s[%d]", path, i), a.Index(i), b.Index(i))
+		}
+		for i := n; i < a.Len() && !w.done(); i++ {
+			w.reportf(fmt.Sprintf("%s[%d]", path, i), format(a.Index(i)), "<missing>")
+		}
+		for i := n; i < b.Len() && !w.done(); i++ {
+			w.reportf(fmt.Sprintf("%s[%d]", path, i), "<missing>", format(b.Index(i)))
+		}
+
+	case reflect.Map:
+		for _, k := range sortedKeys(a, b) {
+			kp := fmt.Sprintf("%s[%s]", path, format(k))
+			av, bv := a.MapIndex(k), b.MapIndex(k)
+			switch {
+			case !av.IsValid():
+				w.reportf(kp, "<missing>", format(bv))
+			case !bv.IsValid():
+				w.reportf(kp, format(av), "<missing>")
+			default:
+				w.walk(kp, av, bv)
+			}
+			if w.done() {
+				return
+			}
+		}
+
+	case reflect.Struct:
+		t := a.Type()
+		for i := range t.NumField() {
+			w.walk(path+"."+t.Field(i).Name, a.Field(i), b.Field(i))
+		}
+
+	case reflect.Func:
+		if !a.IsNil() || !b.IsNil() {
+			w.report(path, a, b)
+		}
+
+	default:
+		if !basicEqual(a, b) {
+			w.report(path, a, b)
+		}
+	}
+}
+
+// basicEqual compares values of the remaining kinds through their typed
+// accessors, which also work on values read from unexported fields.
+func basicEqual(a, b reflect.Value) bool {
+	switch a.Kind() {
+	case reflect.Bool:
+		return a.Bool() == b.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() == b.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return a.Uint() == b.Uint()
+	case reflect.Float32, reflect.Float64:
+		return a.Float() == b.Float()
+	case reflect.Complex64, reflect.Complex128:
+		return a.Complex() == b.Complex()
+	case reflect.String:
+		return a.String() == b.String()
+	case reflect.Chan, reflect.UnsafePointer:
+		return a.UnsafePointer() == b.UnsafePointer()
+	}
+	panic("reflectutil: unexpected kind " + a.Kind().String())
+}
+
+// equalMethod calls a.Equal(b) when the type declares func (T) Equal(T) bool
+// and the values are reachable without going through unexported fields.
+func equalMethod(a, b reflect.Value) (eq, ok bool) {
+	if a.Kind() == reflect.Interface || !a.CanInterface() || !b.CanInterface() {
+		return false, false
+	}
+	m, found := a.Type().MethodByName("Equal")
+	if !found {
+		return false, false
+	}
+	mt := m.Type // includes the receiver
+	if mt.NumIn() != 2 || mt.In(1) != a.Type() || mt.NumOut() != 1 || mt.Out(0).Kind() != reflect.Bool {
+		return false, false
+	}
+	return m.Func.Call([]reflect.Value{a, b})[0].Bool(), true
+}
+
+// sortedKeys returns the union of the keys of two maps, ordered by their
+// formatted form so the diff comes out in the same order every run.
+func sortedKeys(a, b reflect.Value) []reflect.Value {
+	keys := a.MapKeys()
+	for _, k := range b.MapKeys() {
+		if !a.MapIndex(k).IsValid() {
+			keys = append(keys, k)
+		}
+	}
+	slices.SortFunc(keys, func(x, y reflect.Value) int {
+		return cmp.Compare(format(x), format(y))
+	})
+	return keys
+}
+
+// format renders a value for a Difference: strings quoted, nil explicit.
+func format(v reflect.Value) string {
+	if !v.IsValid() {
+		return "<nil>"
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return fmt.Sprintf("%q", v.String())
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Interface, reflect.Func, reflect.Chan:
+		if v.IsNil() {
+			return "nil"
+		}
+	}
+	if v.Kind() == reflect.Interface {
+		return format(v.Elem())
+	}
+	if v.CanInterface() {
+		return fmt.Sprintf("%v", v.Interface())
+	}
+	return fmt.Sprintf("%v", v)
+}
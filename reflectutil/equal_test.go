@@ -0,0 +1,86 @@
+package reflectutil
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestDiff(t *testing.T) {
+	changed := newPerson()
+	changed.Age = 31
+	changed.Hobbies = []string{"reading", "coding", "chess"}
+	changed.Metadata = map[string]string{"city": "Boston", "team": "blue"}
+
+	emptied := newPerson()
+	emptied.Hobbies = []string{}
+
+	large := newLargeStruct()
+	large.Metadata["devices"] = []string{"phone"}
+	large.Metadata["limits"] = map[string]int{"daily": 10, "weekly": 50}
+
+	utc := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		a, b any
+		want []string
+	}{
+		{"equal", newPerson(), newPerson(), nil},
+		{"person", newPerson(), changed, []string{
+			`.Age: 30 != 31`,
+			`.Hobbies[1]: "swimming" != "coding"`,
+			`.Hobbies[2]: <missing> != "chess"`,
+			`.Metadata["city"]: "New York" != "Boston"`,
+			`.Metadata["team"]: <missing> != "blue"`,
+		}},
+		{"nil vs empty", newPerson(), emptied, []string{
+			`.Hobbies[0]: "reading" != <missing>`,
+			`.Hobbies[1]: "swimming" != <missing>`,
+		}},
+		{"nested interface values", newLargeStruct(), large, []string{
+			`.Metadata["devices"][1]: "laptop" != <missing>`,
+			`.Metadata["limits"]["weekly"]: <missing> != 50`,
+		}},
+		{"nil slice", person{}, person{Hobbies: []string{}}, []string{`.Hobbies: nil != []`}},
+		{"types", 1, "1", []string{`(value): int != string`}},
+		{"Equal method", utc, utc.In(time.FixedZone("EST", -5*3600)), nil},
+		{"unexported", private{tags: []string{"a"}}, private{tags: []string{"b"}}, []string{
+			`.tags[0]: "a" != "b"`,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, d := range Diff(tt.a, tt.b) {
+				got = append(got, d.String())
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got  %q\nwant %q", got, tt.want)
+			}
+			if eq := DeepEqual(tt.a, tt.b); eq != (tt.want == nil) {
+				t.Errorf("DeepEqual = %v", eq)
+			}
+		})
+	}
+}
+
+func TestDiffCycles(t *testing.T) {
+	ring := func(values ...int) *node {
+		head := &node{Value: values[0]}
+		cur := head
+		for _, v := range values[1:] {
+			cur.Next = &node{Value: v}
+			cur = cur.Next
+		}
+		cur.Next = head
+		return head
+	}
+	if d := Diff(ring(1, 2, 3), ring(1, 2, 3)); d != nil {
+		t.Errorf("equal rings differ: %v", d)
+	}
+	d := Diff(ring(1, 2, 3), ring(1, 5, 3))
+	if len(d) != 1 || d[0].Path != ".Next.Value" {
+		t.Errorf("got %v", d)
+	}
+}
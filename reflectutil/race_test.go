@@ -0,0 +1,5 @@
+//go:build race
+
+package reflectutil
+
+func init() { raceEnabled = true }
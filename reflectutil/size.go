@@ -0,0 +1,295 @@
+package reflectutil
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"text/tabwriter"
+	"unsafe"
+)
+
+// MemSize describes how much memory a value occupies. Shallow is the
+// value itself, exactly unsafe.Sizeof including alignment padding.
+// Retained is the heap memory reachable from it: string and slice backing
+// arrays, map tables, pointees and boxed interface values, each rounded up
+// to the allocator's size class.
+//
+// Retained is an estimate. The sizer can't tell heap memory from static
+// data, so string literals are counted as if they had been allocated, and
+// map sizes assume the map grew one insert at a time rather than being
+// created with a size hint.
+type MemSize struct {
+	Type     string
+	Shallow  uintptr
+	Retained uintptr
+
+	// Padding is the part of Shallow that is alignment gaps between and
+	// after struct fields. It is zero for values that aren't structs.
+	Padding uintptr
+
+	// Fields breaks a struct value down by field. Memory reachable from
+	// more than one field is charged to the first of them only, so the
+	// field Retained values add up to the total.
+	Fields []FieldSize
+}
+
+// Total returns Shallow + Retained.
+func (s MemSize) Total() uintptr { return s.Shallow + s.Retained }
+
+// FieldSize is one struct field's share of a MemSize.
+type FieldSize struct {
+	Name     string
+	Type     string
+	Offset   uintptr
+	Shallow  uintptr
+	Retained uintptr
+}
+
+// SizeOf measures v. Memory reachable through several paths, such as two
+// pointers to one value or two slices starting at the same element, is
+// counted once.
+func SizeOf[T any](v T) MemSize {
+	rv := reflect.ValueOf(&v).Elem()
+	t := rv.Type()
+	s := MemSize{Type: t.String(), Shallow: t.Size()}
+	sz := sizer{seen: make(map[seenKey]bool)}
+
+	if t.Kind() != reflect.Struct {
+		s.Retained = sz.retained(rv)
+		return s
+	}
+	s.Padding = t.Size()
+	for i := range t.NumField() {
+		f := t.Field(i)
+		fv := unlock(rv.Field(i))
+		fs := FieldSize{
+			Name:     f.Name,
+			Type:     f.Type.String(),
+			Offset:   f.Offset,
+			Shallow:  f.Type.Size(),
+			Retained: sz.retained(fv),
+		}
+		s.Padding -= fs.Shallow
+		s.Retained += fs.Retained
+		s.Fields = append(s.Fields, fs)
+	}
+	return s
+}
+
+// WriteText renders the size as a table with one row per field.
+func (s MemSize) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(tw, "%s\tshallow\tretained\ttotal\t\n", s.Type)
+	for _, f := range s.Fields {
+		fmt.Fprintf(tw, "%s %s\t%d\t%d\t%d\t\n", f.Name, f.Type, f.Shallow, f.Retained, f.Shallow+f.Retained)
+	}
+	if s.Padding > 0 {
+		fmt.Fprintf(tw, "(padding)\t%d\t\t%d\t\n", s.Padding, s.Padding)
+	}
+	fmt.Fprintf(tw, "total\t%d\t%d\t%d\t\n", s.Shallow, s.Retained, s.Total())
+	return tw.Flush()
+}
+
+type sizer struct {
+	seen map[seenKey]bool
+}
+
+// visit reports whether the memory at p has not been counted yet, and
+// marks it counted.
+func (z *sizer) visit(t reflect.Type, p unsafe.Pointer) bool {
+	key := seenKey{typ: t, ptr: p}
+	if z.seen[key] {
+		return false
+	}
+	z.seen[key] = true
+	return true
+}
+
+// retained returns the heap memory reachable from v that hasn't been
+// counted yet. v's own bytes are the caller's business.
+func (z *sizer) retained(v reflect.Value) uintptr {
+	t := v.Type()
+	switch v.Kind() {
+	case reflect.String:
+		if v.Len() == 0 || !z.visit(t, unsafe.Pointer(unsafe.StringData(v.String()))) {
+			return 0
+		}
+		return allocSize(uintptr(v.Len()), false)
+
+	case reflect.Pointer:
+		if v.IsNil() || !z.visit(t, v.UnsafePointer()) {
+			return 0
+		}
+		return allocSize(t.Elem().Size(), hasPointers(t.Elem())) + z.retained(v.Elem())
+
+	case reflect.Slice:
+		if v.Cap() == 0 || !z.visit(t.Elem(), v.UnsafePointer()) {
+			return 0
+		}
+		n := allocSize(uintptr(v.Cap())*t.Elem().Size(), hasPointers(t.Elem()))
+		return n + z.elements(v)
+
+	case reflect.Array:
+		return z.elements(v)
+
+	case reflect.Struct:
+		if !v.CanAddr() {
+			v = addressable(v)
+		}
+		var n uintptr
+		for i := range v.NumField() {
+			n += z.retained(unlock(v.Field(i)))
+		}
+		return n
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return 0
+		}
+		inner := v.Elem()
+		n := z.retained(inner)
+		if !pointerShaped(inner.Type()) && inner.Type().Size() > 0 {
+			// non-pointer values are boxed in their own allocation
+			n += allocSize(inner.Type().Size(), hasPointers(inner.Type()))
+		}
+		return n
+
+	case reflect.Map:
+		if v.IsNil() || !z.visit(t, v.UnsafePointer()) {
+			return 0
+		}
+		n := mapSize(t, v.Len())
+		for iter := v.MapRange(); iter.Next(); {
+			n += z.retained(iter.Key()) + z.retained(iter.Value())
+		}
+		return n
+
+	case reflect.Chan:
+		if v.IsNil() || !z.visit(t, v.UnsafePointer()) {
+			return 0
+		}
+		return allocSize(hchanSize+uintptr(v.Cap())*t.Elem().Size(), hasPointers(t.Elem()))
+	}
+	// Funcs and unsafe pointers: there's nothing reflect can follow.
+	return 0
+}
+
+func (z *sizer) elements(v reflect.Value) uintptr {
+	if !hasPointers(v.Type().Elem()) {
+		return 0
+	}
+	var n uintptr
+	for i := range v.Len() {
+		n += z.retained(v.Index(i))
+	}
+	return n
+}
+
+// pointerShaped reports whether values of t are stored directly in an
+// interface rather than boxed.
+func pointerShaped(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		return true
+	}
+	return false
+}
+
+// hasPointers reports whether values of t contain pointers, which decides
+// whether the allocator adds a header to them.
+func hasPointers(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Chan, reflect.Func, reflect.UnsafePointer,
+		reflect.Interface, reflect.Slice, reflect.String:
+		return true
+	case reflect.Array:
+		return t.Len() > 0 && hasPointers(t.Elem())
+	case reflect.Struct:
+		for i := range t.NumField() {
+			if hasPointers(t.Field(i).Type) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// The runtime's allocation parameters on 64-bit platforms.
+const (
+	maxTinySize         = 16
+	maxSmallSize        = 32768
+	pageSize            = 8192
+	mallocHeaderSize    = 8
+	minSizeForMallocHdr = 512
+	hchanSize           = 96
+
+	mapHeaderSize      = 48 // the Map struct every non-nil map points to
+	mapTableSize       = 32 // one table of a directory
+	mapGroupSlots      = 8
+	mapMaxTableSlots   = 1024
+	mapLoadNumerator   = 7 // tables grow when 7/8 full
+	mapLoadDenominator = 8
+)
+
+// sizeClasses are the object sizes the runtime's small-object allocator
+// hands out; a request is rounded up to the next one.
+var sizeClasses = []uintptr{
+	8, 16, 24, 32, 48, 64, 80, 96, 112, 128, 144, 160, 176, 192, 208, 224, 240, 256,
+	288, 320, 352, 384, 416, 448, 480, 512, 576, 640, 704, 768, 896, 1024, 1152, 1280,
+	1408, 1536, 1792, 2048, 2304, 2688, 3072, 3200, 3456, 4096, 4864, 5376, 6144, 6528,
+	6784, 6912, 8192, 9472, 9728, 10240, 10880, 12288, 13568, 14336, 16384, 18432, 19072,
+	20480, 21760, 24576, 27264, 28672, 32768,
+}
+
+// allocSize returns the bytes the allocator really uses for an n-byte
+// object.
+func allocSize(n uintptr, pointers bool) uintptr {
+	if n == 0 {
+		return 0
+	}
+	if !pointers && n < maxTinySize {
+		// The tiny allocator packs small pointer-free objects into shared
+		// 16-byte blocks; charge each its share of a block.
+		return maxTinySize / (maxTinySize / n)
+	}
+	if pointers && n > minSizeForMallocHdr && n+mallocHeaderSize <= maxSmallSize {
+		n += mallocHeaderSize
+	}
+	if n > maxSmallSize {
+		return (n + pageSize - 1) &^ (pageSize - 1)
+	}
+	i, _ := slices.BinarySearch(sizeClasses, n)
+	return sizeClasses[i]
+}
+
+// mapSize estimates the memory of a Swiss-table map with n entries that
+// grew by insertion: a header, then either a single group of 8 slots or a
+// directory of tables whose capacity doubles whenever they are 7/8 full.
+func mapSize(t reflect.Type, n int) uintptr {
+	k, e := t.Key(), t.Elem()
+	slot := reflect.StructOf([]reflect.StructField{
+		{Name: "K", Type: k},
+		{Name: "E", Type: e},
+	})
+	group := 8 + mapGroupSlots*slot.Size()
+	pointers := hasPointers(slot)
+
+	size := uintptr(mapHeaderSize)
+	if n <= mapGroupSlots {
+		return size + allocSize(group, pointers)
+	}
+
+	slots := 2 * mapGroupSlots
+	for slots*mapLoadNumerator/mapLoadDenominator < n && slots < mapMaxTableSlots {
+		slots *= 2
+	}
+	tables := 1
+	for tables*mapMaxTableSlots*mapLoadNumerator/mapLoadDenominator < n {
+		tables *= 2
+	}
+	perTable := allocSize(mapTableSize, true) +
+		allocSize(uintptr(slots/mapGroupSlots)*group, pointers)
+	dir := allocSize(uintptr(tables)*unsafe.Sizeof(uintptr(0)), true)
+	return size + dir + uintptr(tables)*perTable
+}
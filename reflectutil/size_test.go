@@ -0,0 +1,185 @@
+package reflectutil
+
+import (
+	"runtime"
+	"strconv"
+	"testing"
+	"unsafe"
+)
+
+// profile has the shape of chapter 9's LargeStruct.
+type profile struct {
+	ID          string
+	Name        string
+	Email       string
+	Age         int
+	City        string
+	IsActive    bool
+	IsVerified  bool
+	Preferences map[string]string
+	Tags        []string
+	Metadata    map[string]interface{}
+}
+
+type shared struct {
+	Owner    *profile
+	Watchers [8]*profile // all the same profile, counted once
+}
+
+// Every string is built at run time: literals live in the binary, not on
+// the heap, so MemStats wouldn't see them.
+func newProfile(i int) *profile {
+	id := strconv.Itoa(i)
+	return &profile{
+		ID:          "user-" + id,
+		Name:        "Student " + id,
+		Email:       "student" + id + "@example.com",
+		Age:         20 + i%10,
+		City:        "City " + strconv.Itoa(i%50),
+		IsActive:    i%2 == 0,
+		Preferences: map[string]string{"theme": "dark-" + id, "language": "en-" + id},
+		Tags:        []string{"tag-a-" + id, "tag-b-" + id, "tag-c-" + id},
+		Metadata: map[string]interface{}{
+			"source":  "web-" + id,
+			"devices": []string{"laptop-" + id, "phone-" + id},
+			"visits":  1000 + i,
+		},
+	}
+}
+
+// heapSamples build values whose SizeOf estimate is checked against the
+// runtime. Each build must return a pointer, so nothing is boxed.
+var heapSamples = []struct {
+	name  string
+	build func(i int) any
+}{
+	{"profile", func(i int) any { return newProfile(i) }},
+	{"linked list x100", func(i int) any {
+		type strNode struct {
+			Value string
+			Next  *strNode
+		}
+		var head *strNode
+		for j := range 100 {
+			head = &strNode{Value: strconv.Itoa(i*100 + j), Next: head}
+		}
+		return head
+	}},
+	{"[]int64 x1000", func(i int) any {
+		s := make([]int64, 1000)
+		return &s
+	}},
+	{"map[int]string x1000", func(i int) any {
+		m := make(map[int]string)
+		for j := range 1000 {
+			m[j] = "value-" + strconv.Itoa(i*1000+j)
+		}
+		return &m
+	}},
+	{"map[string]int x5", func(i int) any {
+		m := make(map[string]int)
+		for j := range 5 {
+			m["key-"+strconv.Itoa(i*5+j)] = j
+		}
+		return &m
+	}},
+	{"shared pointers", func(i int) any {
+		p := newProfile(i)
+		s := &shared{Owner: p}
+		for j := range s.Watchers {
+			s.Watchers[j] = p
+		}
+		return s
+	}},
+}
+
+// heapTolerance is how far an estimate may stray from the measured heap
+// growth. Small maps come out furthest, at around 11% under.
+const heapTolerance = 0.15
+
+// raceEnabled is set by race_test.go. The race detector changes how the
+// runtime allocates, so the heap no longer matches the estimates.
+var raceEnabled bool
+
+// TestSizeOfMatchesHeap builds many copies of each sample, measures how
+// much the live heap grew with runtime.MemStats, and compares the average
+// with the average SizeOf(v).Retained.
+func TestSizeOfMatchesHeap(t *testing.T) {
+	if testing.Short() {
+		t.Skip("allocates a few hundred megabytes")
+	}
+	if raceEnabled {
+		t.Skip("the race detector changes allocation sizes")
+	}
+	const n = 2000
+	for _, s := range heapSamples {
+		keep := make([]any, n)
+
+		var before, after runtime.MemStats
+		runtime.GC()
+		runtime.ReadMemStats(&before)
+		for i := range keep {
+			keep[i] = s.build(i)
+		}
+		runtime.GC()
+		runtime.ReadMemStats(&after)
+
+		var total uintptr
+		for _, v := range keep {
+			total += SizeOf(v).Retained
+		}
+		runtime.KeepAlive(keep)
+
+		measured := float64(int64(after.HeapAlloc)-int64(before.HeapAlloc)) / n
+		estimated := float64(total) / n
+		off := (estimated - measured) / measured
+		t.Logf("%-22s estimated %8.0f B, measured %8.0f B, %+5.1f%%", s.name, estimated, measured, 100*off)
+		if off < -heapTolerance || off > heapTolerance {
+			t.Errorf("%s: estimated %.0f B per value, measured %.0f B (%+.1f%%)", s.name, estimated, measured, 100*off)
+		}
+	}
+}
+
+func TestSizeOfStruct(t *testing.T) {
+	type padded struct {
+		A bool
+		B int64
+		C bool
+	}
+	s := SizeOf(padded{})
+	if s.Shallow != unsafe.Sizeof(padded{}) || s.Shallow != 24 {
+		t.Errorf("Shallow = %d", s.Shallow)
+	}
+	if s.Padding != 14 || s.Retained != 0 || len(s.Fields) != 3 {
+		t.Errorf("Padding %d, Retained %d, %d fields", s.Padding, s.Retained, len(s.Fields))
+	}
+	if f := s.Fields[2]; f.Name != "C" || f.Offset != 16 || f.Shallow != 1 {
+		t.Errorf("field C = %+v", f)
+	}
+}
+
+func TestSizeOfCountsSharedMemoryOnce(t *testing.T) {
+	p := newProfile(1)
+	alone := SizeOf(p).Retained
+
+	s := shared{Owner: p}
+	for i := range s.Watchers {
+		s.Watchers[i] = p
+	}
+	size := SizeOf(s)
+	if size.Retained != alone {
+		t.Errorf("Retained = %d, want %d: the shared profile was counted more than once", size.Retained, alone)
+	}
+	var sum uintptr
+	for _, f := range size.Fields {
+		sum += f.Retained
+	}
+	if sum != size.Retained || size.Fields[0].Retained != alone {
+		t.Errorf("fields retain %d, total %d; the owner should carry it all", sum, size.Retained)
+	}
+
+	tags := make([]int64, 100)
+	if a, b := SizeOf(tags).Retained, SizeOf([2][]int64{tags, tags[:10]}).Retained; a != b {
+		t.Errorf("two slices from one element retain %d, one alone %d", b, a)
+	}
+}
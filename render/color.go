@@ -0,0 +1,55 @@
+package render
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+)
+
+// paint is a parsed colour. none means "don't draw" (SVG's fill="none").
+type paint struct {
+	raw  string
+	rgba color.RGBA
+	none bool
+}
+
+// namedColors is the small palette accepted by name in a Style.
+var namedColors = map[string]color.RGBA{
+	"black":  {0, 0, 0, 255},
+	"white":  {255, 255, 255, 255},
+	"gray":   {128, 128, 128, 255},
+	"red":    {255, 0, 0, 255},
+	"green":  {0, 128, 0, 255},
+	"blue":   {0, 0, 255, 255},
+	"yellow": {255, 255, 0, 255},
+	"orange": {255, 165, 0, 255},
+	"purple": {128, 0, 128, 255},
+}
+
+func parsePaint(s string) (paint, error) {
+	value := strings.ToLower(strings.TrimSpace(s))
+	if value == "none" {
+		return paint{raw: value, none: true}, nil
+	}
+	if c, ok := namedColors[value]; ok {
+		return paint{raw: value, rgba: c}, nil
+	}
+	if !strings.HasPrefix(value, "#") {
+		return paint{}, fmt.Errorf("unknown colour %q", s)
+	}
+
+	hex := value[1:]
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) != 6 {
+		return paint{}, fmt.Errorf("invalid hex colour %q", s)
+	}
+	n, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return paint{}, fmt.Errorf("invalid hex colour %q", s)
+	}
+	c := color.RGBA{R: uint8(n >> 16), G: uint8(n >> 8), B: uint8(n), A: 255}
+	return paint{raw: value, rgba: c}, nil
+}
@@ -0,0 +1,210 @@
+// Package render draws collections of shapes from the shapes package as
+// SVG documents or PNG images, so the shapes built in chapters 7 and 8 can
+// be turned into diagrams for reports and docs.
+//
+// Shapes carry no position, so the renderer lays them out in a single row
+// from left to right, top-aligned, with an optional label underneath each
+// one. Circles and any shape implementing shapes.Polygon are supported.
+package render
+
+import (
+	"fmt"
+	"math"
+
+	"go-practice/shapes"
+)
+
+// Style controls how a single shape is drawn. Colours are CSS-style hex
+// values ("#f80", "#ff8800"), a small set of named colours, or "none".
+type Style struct {
+	Fill        string
+	Stroke      string
+	StrokeWidth float64 // in pixels
+	Label       string
+}
+
+// Item pairs a shape with the style used to draw it.
+type Item struct {
+	Shape shapes.Shape
+	Style Style
+}
+
+// Options controls the canvas. Zero values fall back to the defaults
+// below.
+type Options struct {
+	Scale      float64 // pixels per shape unit (default 20)
+	Padding    float64 // pixels between shapes and around the edge (default 10)
+	FontSize   float64 // label font size in pixels (default 12)
+	Background string  // canvas colour (default "white")
+}
+
+// MaxCanvasSize is the largest width or height, in pixels, that layout
+// accepts. It keeps a huge shape or scale from asking Rasterize for an
+// image that cannot be allocated.
+const MaxCanvasSize = 1 << 14
+
+// DefaultStyle is applied to any field left empty in an item's Style.
+var DefaultStyle = Style{
+	Fill:        "#cccccc",
+	Stroke:      "black",
+	StrokeWidth: 1,
+}
+
+// Items wraps plain shapes with DefaultStyle so callers holding a
+// []shapes.Shape don't have to build Items by hand.
+func Items(list []shapes.Shape) []Item {
+	items := make([]Item, len(list))
+	for i, s := range list {
+		items[i] = Item{Shape: s}
+	}
+	return items
+}
+
+// placement is an item resolved to pixel coordinates on the canvas.
+type placement struct {
+	style  Style
+	fill   paint
+	stroke paint
+	x, y   float64 // top-left corner of the bounding box
+	w, h   float64
+	circle bool
+	radius float64
+	points []shapes.Point // absolute pixel coordinates for polygons
+}
+
+// canvas is the result of laying out a list of items.
+type canvas struct {
+	width, height int
+	background    paint
+	fontSize      float64
+	labelY        float64 // baseline shared by every label
+	placements    []placement
+}
+
+func (o Options) withDefaults() Options {
+	if o.Scale <= 0 {
+		o.Scale = 20
+	}
+	if o.Padding <= 0 {
+		o.Padding = 10
+	}
+	if o.FontSize <= 0 {
+		o.FontSize = 12
+	}
+	if o.Background == "" {
+		o.Background = "white"
+	}
+	return o
+}
+
+func (s Style) withDefaults() Style {
+	if s.Fill == "" {
+		s.Fill = DefaultStyle.Fill
+	}
+	if s.Stroke == "" {
+		s.Stroke = DefaultStyle.Stroke
+	}
+	if s.StrokeWidth <= 0 {
+		s.StrokeWidth = DefaultStyle.StrokeWidth
+	}
+	return s
+}
+
+// layout places every item on the canvas and validates styles, so the SVG
+// and PNG backends share the same geometry and the same errors.
+func layout(items []Item, opts Options) (*canvas, error) {
+	opts = opts.withDefaults()
+
+	bg, err := parsePaint(opts.Background)
+	if err != nil {
+		return nil, fmt.Errorf("background: %w", err)
+	}
+
+	c := &canvas{background: bg, fontSize: opts.FontSize}
+	hasLabel := false
+	maxHeight := 0.0
+	x := opts.Padding
+
+	for i, item := range items {
+		if item.Shape == nil {
+			return nil, fmt.Errorf("item %d: shape is nil", i)
+		}
+
+		if w := item.Style.StrokeWidth; math.IsNaN(w) || math.IsInf(w, 0) {
+			return nil, fmt.Errorf("item %d: stroke width must be finite, got %g", i, w)
+		}
+		style := item.Style.withDefaults()
+		fill, err := parsePaint(style.Fill)
+		if err != nil {
+			return nil, fmt.Errorf("item %d: fill: %w", i, err)
+		}
+		stroke, err := parsePaint(style.Stroke)
+		if err != nil {
+			return nil, fmt.Errorf("item %d: stroke: %w", i, err)
+		}
+
+		p := placement{style: style, fill: fill, stroke: stroke, x: x, y: opts.Padding}
+
+		switch s := item.Shape.(type) {
+		case shapes.Circle:
+			if s.Radius <= 0 {
+				return nil, fmt.Errorf("item %d: circle radius must be positive, got %g", i, s.Radius)
+			}
+			p.circle = true
+			p.radius = s.Radius * opts.Scale
+			p.w, p.h = 2*p.radius, 2*p.radius
+		case shapes.Polygon:
+			w, h := polygonBounds(s)
+			if w <= 0 || h <= 0 {
+				return nil, fmt.Errorf("item %d: %T has an empty outline", i, s)
+			}
+			p.w, p.h = w*opts.Scale, h*opts.Scale
+			for _, v := range s.Vertices() {
+				p.points = append(p.points, shapes.Point{X: p.x + v.X*opts.Scale, Y: p.y + v.Y*opts.Scale})
+			}
+		default:
+			return nil, fmt.Errorf("item %d: unsupported shape type %T", i, item.Shape)
+		}
+
+		if style.Label != "" {
+			hasLabel = true
+		}
+		if p.h > maxHeight {
+			maxHeight = p.h
+		}
+		x += p.w + opts.Padding
+		c.placements = append(c.placements, p)
+	}
+
+	height := opts.Padding*2 + maxHeight
+	if hasLabel {
+		c.labelY = opts.Padding + maxHeight + opts.Padding/2 + opts.FontSize
+		height += opts.FontSize + opts.Padding/2
+	}
+	if len(items) == 0 {
+		x += opts.Padding
+	}
+
+	// Written as !(<=) so that NaN and infinite sizes are rejected too.
+	if !(x <= MaxCanvasSize && height <= MaxCanvasSize) {
+		return nil, fmt.Errorf("canvas of %gx%g pixels exceeds the %d pixel limit", x, height, MaxCanvasSize)
+	}
+	c.width = int(x + 0.5)
+	c.height = int(height + 0.5)
+	return c, nil
+}
+
+func polygonBounds(p shapes.Polygon) (width, height float64) {
+	if b, ok := p.(shapes.Bounded); ok {
+		return b.Bounds()
+	}
+	for _, v := range p.Vertices() {
+		if v.X > width {
+			width = v.X
+		}
+		if v.Y > height {
+			height = v.Y
+		}
+	}
+	return width, height
+}
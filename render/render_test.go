@@ -0,0 +1,138 @@
+package render
+
+import (
+	"bytes"
+	"flag"
+	"image"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"go-practice/shapes"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+var goldenCases = []struct {
+	name  string
+	items []Item
+	opts  Options
+}{
+	{
+		name:  "defaults",
+		items: Items([]shapes.Shape{shapes.Circle{Radius: 1}, shapes.Square{Side: 2}, shapes.Triangle{Base: 3, Height: 2}}),
+	},
+	{
+		name: "styled",
+		items: []Item{
+			{Shape: shapes.Circle{Radius: 1.5}, Style: Style{Fill: "#f80", Stroke: "blue", StrokeWidth: 3, Label: "circle"}},
+			{Shape: shapes.Rectangle{Width: 3, Height: 1}, Style: Style{Fill: "none", Stroke: "red", Label: "a < b & c"}},
+			{Shape: shapes.Triangle{Base: 2, Height: 3}, Style: Style{Fill: "green", Stroke: "none"}},
+		},
+		opts: Options{Scale: 16, Padding: 6, Background: "#eeeeee"},
+	},
+	{
+		name:  "empty",
+		items: nil,
+		opts:  Options{Background: "none"},
+	},
+}
+
+// golden compares got with testdata/name, or rewrites the file with -update.
+func golden(t *testing.T, name string, got []byte) []byte {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run go test ./render -update to create it)", err)
+	}
+	return want
+}
+
+func TestSVGGolden(t *testing.T) {
+	for _, tc := range goldenCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := SVG(tc.items, tc.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := golden(t, tc.name+".svg", got); !bytes.Equal(got, want) {
+				t.Errorf("SVG differs from testdata/%s.svg:\ngot:\n%s\nwant:\n%s", tc.name, got, want)
+			}
+		})
+	}
+}
+
+// TestPNGGolden compares decoded pixels rather than encoded bytes, so a
+// change in the image/png encoder doesn't break it.
+func TestPNGGolden(t *testing.T) {
+	for _, tc := range goldenCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WritePNG(&buf, tc.items, tc.opts); err != nil {
+				t.Fatal(err)
+			}
+			got, err := png.Decode(bytes.NewReader(buf.Bytes()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, err := png.Decode(bytes.NewReader(golden(t, tc.name+".png", buf.Bytes())))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !samePixels(got, want) {
+				t.Errorf("image differs from testdata/%s.png; inspect it with -update and git diff", tc.name)
+			}
+		})
+	}
+}
+
+func samePixels(a, b image.Image) bool {
+	if a.Bounds() != b.Bounds() {
+		return false
+	}
+	r := a.Bounds()
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if a.At(x, y) != b.At(x, y) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func TestLayoutErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		items []Item
+		opts  Options
+	}{
+		{"nil shape", []Item{{}}, Options{}},
+		{"zero radius", Items([]shapes.Shape{shapes.Circle{}}), Options{}},
+		{"bad fill", []Item{{Shape: shapes.Square{Side: 1}, Style: Style{Fill: "#12"}}}, Options{}},
+		{"bad background", nil, Options{Background: "chartreuse"}},
+		{"NaN stroke width", []Item{{Shape: shapes.Square{Side: 1}, Style: Style{StrokeWidth: math.NaN()}}}, Options{}},
+		{"infinite stroke width", []Item{{Shape: shapes.Square{Side: 1}, Style: Style{StrokeWidth: math.Inf(1)}}}, Options{}},
+		{"oversized shape", Items([]shapes.Shape{shapes.Circle{Radius: 1e12}}), Options{}},
+		{"too many shapes", Items(slices.Repeat([]shapes.Shape{shapes.Square{Side: 10}}, 100)), Options{}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := SVG(tc.items, tc.opts); err == nil {
+				t.Error("SVG: expected an error")
+			}
+			if _, err := Rasterize(tc.items, tc.opts); err == nil {
+				t.Error("Rasterize: expected an error")
+			}
+		})
+	}
+}
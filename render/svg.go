@@ -0,0 +1,71 @@
+package render
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// SVG renders items as a standalone SVG document.
+func SVG(items []Item, opts Options) ([]byte, error) {
+	c, err := layout(items, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n",
+		c.width, c.height, c.width, c.height)
+	if !c.background.none {
+		fmt.Fprintf(&b, `  <rect width="100%%" height="100%%" fill="%s"/>`+"\n", c.background.raw)
+	}
+
+	for _, p := range c.placements {
+		paintAttrs := fmt.Sprintf(`fill="%s" stroke="%s" stroke-width="%s"`,
+			p.fill.raw, p.stroke.raw, num(p.style.StrokeWidth))
+
+		if p.circle {
+			fmt.Fprintf(&b, `  <circle cx="%s" cy="%s" r="%s" %s/>`+"\n",
+				num(p.x+p.radius), num(p.y+p.radius), num(p.radius), paintAttrs)
+		} else {
+			points := make([]string, len(p.points))
+			for i, pt := range p.points {
+				points[i] = num(pt.X) + "," + num(pt.Y)
+			}
+			fmt.Fprintf(&b, `  <polygon points="%s" %s/>`+"\n", strings.Join(points, " "), paintAttrs)
+		}
+
+		if p.style.Label != "" {
+			fmt.Fprintf(&b, `  <text x="%s" y="%s" text-anchor="middle" font-family="sans-serif" font-size="%s">%s</text>`+"\n",
+				num(p.x+p.w/2), num(c.labelY), num(c.fontSize), escape(p.style.Label))
+		}
+	}
+
+	b.WriteString("</svg>\n")
+	return []byte(b.String()), nil
+}
+
+// WriteSVG renders items as SVG and writes the document to w.
+func WriteSVG(w io.Writer, items []Item, opts Options) error {
+	data, err := SVG(items, opts)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// num formats a coordinate with at most two decimals so output is stable
+// across platforms.
+func num(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
+
+func escape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
@@ -0,0 +1,265 @@
+package roster
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"go-practice/errs"
+)
+
+// Columns lists the student fields in the order exporters write them.
+var Columns = []string{"name", "age", "grade", "city", "active"}
+
+// CSVOptions configures CSV import and export. The zero value reads and
+// writes comma-separated files whose headers are the Columns names.
+type CSVOptions struct {
+	// Comma is the field delimiter; 0 means ','.
+	Comma rune
+
+	// Aliases maps extra header names to columns, e.g. {"Student": "name",
+	// "Enrolled": "active"}. Header matching ignores case and surrounding
+	// spaces.
+	Aliases map[string]string
+
+	// Strict rejects headers with columns that map to no field. By default
+	// such columns are skipped.
+	Strict bool
+}
+
+func (o *CSVOptions) comma() rune {
+	if o == nil || o.Comma == 0 {
+		return ','
+	}
+	return o.Comma
+}
+
+// column resolves a header cell to a field name, or "" if it has none.
+func (o *CSVOptions) column(header string) string {
+	h := strings.ToLower(strings.TrimSpace(header))
+	for _, c := range Columns {
+		if h == c {
+			return c
+		}
+	}
+	if o != nil {
+		for alias, c := range o.Aliases {
+			if strings.ToLower(strings.TrimSpace(alias)) == h {
+				return strings.ToLower(c)
+			}
+		}
+	}
+	return ""
+}
+
+// CSVReader streams students from CSV, one row at a time. The first row
+// must be a header naming the columns; only "name" is required, and
+// missing columns leave the field at its zero value.
+type CSVReader struct {
+	r      *csv.Reader
+	fields []string // field name per column, "" for skipped columns
+	line   int
+}
+
+// NewCSVReader reads the header and returns a reader positioned at the
+// first data row. A header without a name column, or with a repeated or
+// (in strict mode) unknown column, is reported as a *errs.ValidationError.
+func NewCSVReader(r io.Reader, opts *CSVOptions) (*CSVReader, error) {
+	cr := csv.NewReader(r)
+	cr.Comma = opts.comma()
+	cr.FieldsPerRecord = -1 // row length is checked against the header below
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, &errs.ValidationError{Line: 1, Message: "missing header row"}
+	}
+	if err != nil {
+		return nil, csvError(err)
+	}
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\uFEFF") // spreadsheet BOM
+	}
+
+	fields := make([]string, len(header))
+	seen := make(map[string]bool)
+	for i, h := range header {
+		field := opts.column(h)
+		switch {
+		case field == "" && opts != nil && opts.Strict:
+			return nil, &errs.ValidationError{Line: 1, Field: h, Message: "unknown column"}
+		case field == "":
+			continue
+		case seen[field]:
+			return nil, &errs.ValidationError{Line: 1, Field: field, Message: "column appears more than once", Value: h}
+		}
+		seen[field] = true
+		fields[i] = field
+	}
+	if !seen["name"] {
+		return nil, &errs.ValidationError{Line: 1, Field: "name", Message: "missing column"}
+	}
+
+	return &CSVReader{r: cr, fields: fields, line: 1}, nil
+}
+
+// Read returns the next student, or io.EOF after the last row. A row that
+// can't be parsed or doesn't validate yields a *errs.ValidationError, and
+// reading may continue with the next row.
+func (r *CSVReader) Read() (Student, error) {
+	record, err := r.r.Read()
+	if err == io.EOF {
+		return Student{}, io.EOF
+	}
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			r.line = parseErr.StartLine
+		}
+		return Student{}, csvError(err)
+	}
+	r.line, _ = r.r.FieldPos(0)
+
+	if len(record) != len(r.fields) {
+		return Student{}, &errs.ValidationError{
+			Line:    r.line,
+			Message: fmt.Sprintf("row has %d fields, header has %d", len(record), len(r.fields)),
+		}
+	}
+
+	var s Student
+	for i, field := range r.fields {
+		if field == "" {
+			continue
+		}
+		if err := setField(&s, field, record[i]); err != nil {
+			line, _ := r.r.FieldPos(i)
+			return Student{}, &errs.ValidationError{Line: line, Field: field, Value: record[i], Err: err}
+		}
+	}
+	if err := s.Validate(); err != nil {
+		return Student{}, validationError(r.line, err)
+	}
+	return s, nil
+}
+
+// Line returns the line on which the last row read started.
+func (r *CSVReader) Line() int {
+	return r.line
+}
+
+// setField parses a text value into one field. Numbers and booleans are
+// coerced leniently: surrounding spaces are ignored, "20.0" is a valid age
+// and yes/no or active/inactive are valid flags. Blank numbers and flags
+// mean zero and false. Strings are kept exactly as written.
+func setField(s *Student, field, value string) error {
+	switch field {
+	case "name":
+		s.Name = value
+	case "grade":
+		s.Grade = value
+	case "city":
+		s.City = value
+	case "age":
+		age, err := parseAge(value)
+		if err != nil {
+			return err
+		}
+		s.Age = age
+	case "active":
+		active, err := parseActive(value)
+		if err != nil {
+			return err
+		}
+		s.Active = active
+	default:
+		return fmt.Errorf("unknown field %q", field)
+	}
+	return nil
+}
+
+func parseAge(value string) (int, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+	if n, err := strconv.Atoi(value); err == nil {
+		return n, nil
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil || f != math.Trunc(f) || math.Abs(f) > math.MaxInt32 {
+		return 0, errors.New("not a whole number")
+	}
+	return int(f), nil
+}
+
+func parseActive(value string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "false", "f", "0", "no", "n", "inactive":
+		return false, nil
+	case "true", "t", "1", "yes", "y", "active":
+		return true, nil
+	}
+	return false, errors.New("not a yes/no value")
+}
+
+// CSVWriter streams students to CSV. The header is written before the
+// first student.
+type CSVWriter struct {
+	w           *csv.Writer
+	wroteHeader bool
+}
+
+// NewCSVWriter returns a writer using the delimiter from opts. Aliases and
+// Strict only affect reading; exports always use the Columns names.
+func NewCSVWriter(w io.Writer, opts *CSVOptions) *CSVWriter {
+	cw := csv.NewWriter(w)
+	cw.Comma = opts.comma()
+	return &CSVWriter{w: cw}
+}
+
+// Write writes one student, quoting fields as needed.
+func (w *CSVWriter) Write(s Student) error {
+	if err := w.WriteHeader(); err != nil {
+		return err
+	}
+	return w.w.Write([]string{s.Name, strconv.Itoa(s.Age), s.Grade, s.City, strconv.FormatBool(s.Active)})
+}
+
+// WriteHeader writes the header row if it hasn't been written yet, so an
+// export with no students still produces a valid file.
+func (w *CSVWriter) WriteHeader() error {
+	if w.wroteHeader {
+		return nil
+	}
+	w.wroteHeader = true
+	return w.w.Write(Columns)
+}
+
+// Flush writes any buffered rows and reports any write error.
+func (w *CSVWriter) Flush() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+// csvError turns a csv.ParseError into a *errs.ValidationError and passes
+// other errors through.
+func csvError(err error) error {
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return &errs.ValidationError{Line: parseErr.Line, Message: fmt.Sprintf("column %d: %v", parseErr.Column, parseErr.Err), Err: parseErr}
+	}
+	return err
+}
+
+// validationError converts the *FieldError from Student.Validate.
+func validationError(line int, err error) error {
+	var fieldErr *FieldError
+	if errors.As(err, &fieldErr) {
+		return &errs.ValidationError{Line: line, Field: fieldErr.Field, Err: fieldErr.Err}
+	}
+	return &errs.ValidationError{Line: line, Err: err}
+}
@@ -0,0 +1,98 @@
+package roster
+
+import (
+	"errors"
+	"io"
+
+	"go-practice/errs"
+)
+
+// Reader is a stream of students, implemented by CSVReader and
+// JSONLReader.
+type Reader interface {
+	// Read returns the next student or io.EOF. A *errs.ValidationError
+	// rejects one record; any other error ends the stream.
+	Read() (Student, error)
+
+	// Line returns the input line of the last record read.
+	Line() int
+}
+
+// Writer is a sink for students, implemented by CSVWriter and JSONLWriter.
+type Writer interface {
+	Write(Student) error
+	Flush() error
+}
+
+// Import adds every student from r to the store, one record at a time so
+// the input never has to fit in memory. Rejected records don't stop the
+// import: each one, including names already in the store, is reported as a
+// *errs.ValidationError in the errors.Join result, and
+// errs.ValidationErrors lists them. Any other error aborts the import.
+// Import returns the number of students added.
+func (st *Store) Import(r Reader) (int, error) {
+	var rejected []error
+	added := 0
+	for {
+		s, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err == nil {
+			err = st.Add(s)
+			if errors.Is(err, ErrDuplicate) {
+				err = &errs.ValidationError{Line: r.Line(), Field: "name", Value: s.Name, Err: ErrDuplicate}
+			}
+		}
+		if err != nil {
+			if !errs.IsValidationError(err) {
+				return added, errors.Join(append(rejected, err)...)
+			}
+			rejected = append(rejected, err)
+			continue
+		}
+		added++
+	}
+	return added, errors.Join(rejected...)
+}
+
+// ImportCSV is Import over a CSVReader.
+func (st *Store) ImportCSV(r io.Reader, opts *CSVOptions) (int, error) {
+	cr, err := NewCSVReader(r, opts)
+	if err != nil {
+		return 0, err
+	}
+	return st.Import(cr)
+}
+
+// ImportJSONL is Import over a JSONLReader.
+func (st *Store) ImportJSONL(r io.Reader) (int, error) {
+	return st.Import(NewJSONLReader(r))
+}
+
+// Export writes the query's results, in order, to w and flushes it.
+// Offset and Limit apply, so a single page can be exported.
+func (q Query) Export(w Writer) error {
+	if cw, ok := w.(*CSVWriter); ok {
+		if err := cw.WriteHeader(); err != nil {
+			return err
+		}
+	}
+	for _, s := range q.Students() {
+		if err := w.Write(s); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+// ExportCSV writes the query's results as CSV with a header row. Importing
+// the output gives back the same students.
+func (q Query) ExportCSV(w io.Writer, opts *CSVOptions) error {
+	return q.Export(NewCSVWriter(w, opts))
+}
+
+// ExportJSONL writes the query's results as JSON Lines.
+func (q Query) ExportJSONL(w io.Writer) error {
+	return q.Export(NewJSONLWriter(w))
+}
@@ -0,0 +1,192 @@
+package roster
+
+import (
+	"bytes"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+
+	"go-practice/errs"
+)
+
+// awkward has values a careless writer would break: delimiters, quotes,
+// new lines and non-ASCII text.
+var awkward = []Student{
+	{Name: "O'Brien, Pat", Age: 19, Grade: "A", City: "Dublin", Active: true},
+	{Name: `Jo "JJ" Jones`, Age: 0, Grade: "", City: "", Active: false},
+	{Name: "Zoë\nLine", Age: 30, Grade: "B+", City: "São Paulo; SP", Active: true},
+}
+
+func TestRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		export func(Query, io.Writer) error
+		load   func(*Store, io.Reader) (int, error)
+	}{
+		{
+			name:   "csv",
+			export: func(q Query, w io.Writer) error { return q.ExportCSV(w, nil) },
+			load:   func(st *Store, r io.Reader) (int, error) { return st.ImportCSV(r, nil) },
+		},
+		{
+			name:   "csv with semicolons",
+			export: func(q Query, w io.Writer) error { return q.ExportCSV(w, &CSVOptions{Comma: ';'}) },
+			load:   func(st *Store, r io.Reader) (int, error) { return st.ImportCSV(r, &CSVOptions{Comma: ';'}) },
+		},
+		{
+			name:   "jsonl",
+			export: Query.ExportJSONL,
+			load:   (*Store).ImportJSONL,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := newTestStore(t)
+			for _, s := range awkward {
+				if err := src.Add(s); err != nil {
+					t.Fatal(err)
+				}
+			}
+			var buf bytes.Buffer
+			if err := tt.export(src.Query(), &buf); err != nil {
+				t.Fatalf("export: %v", err)
+			}
+
+			dst := NewStore()
+			n, err := tt.load(dst, &buf)
+			if err != nil {
+				t.Fatalf("import: %v", err)
+			}
+			if n != src.Len() {
+				t.Errorf("imported %d students, want %d", n, src.Len())
+			}
+			if got, want := dst.Query().Students(), src.Query().Students(); !slices.Equal(got, want) {
+				t.Errorf("after the round trip:\n got %v\nwant %v", got, want)
+			}
+		})
+	}
+}
+
+func TestRoundTripEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewStore().Query().ExportCSV(&buf, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := buf.String(), "name,age,grade,city,active\n"; got != want {
+		t.Errorf("empty export = %q, want %q", got, want)
+	}
+	n, err := NewStore().ImportCSV(&buf, nil)
+	if n != 0 || err != nil {
+		t.Errorf("importing an empty export = %d, %v; want 0, nil", n, err)
+	}
+}
+
+// problem is the part of a rejected row's *errs.ValidationError a test
+// checks.
+type problem struct {
+	Line  int
+	Field string
+}
+
+func problems(err error) []problem {
+	var ps []problem
+	for _, v := range errs.ValidationErrors(err) {
+		ps = append(ps, problem{v.Line, v.Field})
+	}
+	return ps
+}
+
+func TestImportReportsBadRows(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		load  func(*Store, io.Reader) (int, error)
+		added []string
+		want  []problem
+	}{
+		{
+			name: "csv",
+			input: "name,age,active\n" +
+				"Alice,20,yes\n" +
+				"Bob,twenty,yes\n" + // line 3: age
+				"Carol,21,maybe\n" + // line 4: active
+				",22,no\n" + // line 5: name
+				"Dave,23\n" + // line 6: too few fields
+				"Alice,24,no\n" + // line 7: duplicate
+				"\"Eve\nAdams\",25,yes\n" + // lines 8-9
+				"Frank,-1,no\n", // line 10: age
+			load:  func(st *Store, r io.Reader) (int, error) { return st.ImportCSV(r, nil) },
+			added: []string{"Alice", "Eve\nAdams"},
+			want:  []problem{{3, "age"}, {4, "active"}, {5, "name"}, {6, ""}, {7, "name"}, {10, "age"}},
+		},
+		{
+			name: "csv quote",
+			input: "name,age\n" +
+				"Alice,20\n" +
+				"Bo\"b,21\n", // line 3: bare quote
+			load:  func(st *Store, r io.Reader) (int, error) { return st.ImportCSV(r, nil) },
+			added: []string{"Alice"},
+			want:  []problem{{3, ""}},
+		},
+		{
+			name: "jsonl",
+			input: `{"name":"Alice","age":20}` + "\n" +
+				`{"name":"Bob","age":"20"}` + "\n" + // line 2: age
+				"\n" +
+				`{"name":"Carol","age":21,"school":"x"}` + "\n" + // line 4: unknown
+				`{"name":"","age":22}` + "\n" + // line 5: name
+				`{"name":"Dave"` + "\n" + // line 6: truncated
+				`{"name":"Alice","age":23}` + "\n" + // line 7: duplicate
+				`{"name":"Eve","age":24} {}` + "\n" + // line 8: trailing data
+				`{"name":"Frank","age":25}`,
+			load:  (*Store).ImportJSONL,
+			added: []string{"Alice", "Frank"},
+			want:  []problem{{2, "age"}, {4, "school"}, {5, "name"}, {6, ""}, {7, "name"}, {8, ""}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := NewStore()
+			n, err := tt.load(st, strings.NewReader(tt.input))
+			if n != len(tt.added) {
+				t.Errorf("added %d students, want %d", n, len(tt.added))
+			}
+			if got := st.Names(); !slices.Equal(got, tt.added) {
+				t.Errorf("stored %q, want %q", got, tt.added)
+			}
+			if got := problems(err); !slices.Equal(got, tt.want) {
+				t.Errorf("problems = %v, want %v\nerror: %v", got, tt.want, err)
+			}
+		})
+	}
+}
+
+func TestNewCSVReaderHeader(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		opts   *CSVOptions
+		want   *problem // nil when the header is accepted
+	}{
+		{name: "aliases and BOM", header: "\uFEFFStudent, Enrolled ,extra", opts: &CSVOptions{Aliases: map[string]string{"student": "name", "enrolled": "active"}}},
+		{name: "empty input", header: "", want: &problem{1, ""}},
+		{name: "no name", header: "age,city", want: &problem{1, "name"}},
+		{name: "repeated column", header: "name,Age,age", want: &problem{1, "age"}},
+		{name: "unknown in strict mode", header: "name,extra", opts: &CSVOptions{Strict: true}, want: &problem{1, "extra"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewCSVReader(strings.NewReader(tt.header), tt.opts)
+			if tt.want == nil {
+				if err != nil {
+					t.Errorf("NewCSVReader: %v", err)
+				}
+				return
+			}
+			if got := problems(err); len(got) != 1 || got[0] != *tt.want {
+				t.Errorf("problems = %v, want [%v]\nerror: %v", got, *tt.want, err)
+			}
+		})
+	}
+}
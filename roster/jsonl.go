@@ -0,0 +1,117 @@
+package roster
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"go-practice/errs"
+)
+
+// JSONLReader streams students from JSON Lines: one JSON object per line,
+// with the same field names as Columns. Blank lines are skipped. Unlike
+// CSV, values must already have the right JSON type and unknown fields are
+// rejected.
+type JSONLReader struct {
+	r    *bufio.Reader
+	line int
+	done bool
+}
+
+// NewJSONLReader returns a reader over r.
+func NewJSONLReader(r io.Reader) *JSONLReader {
+	return &JSONLReader{r: bufio.NewReader(r)}
+}
+
+// Read returns the next student, or io.EOF after the last line. A line that
+// isn't a valid student yields a *errs.ValidationError, and reading may
+// continue with the next line.
+func (r *JSONLReader) Read() (Student, error) {
+	for !r.done {
+		data, err := r.r.ReadBytes('\n')
+		if err == io.EOF {
+			r.done = true
+		} else if err != nil {
+			return Student{}, err
+		}
+		if len(data) == 0 {
+			break
+		}
+		r.line++
+
+		data = bytes.TrimSpace(data)
+		if len(data) == 0 {
+			continue
+		}
+		return r.decode(data)
+	}
+	return Student{}, io.EOF
+}
+
+func (r *JSONLReader) decode(data []byte) (Student, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	var s Student
+	if err := dec.Decode(&s); err != nil {
+		return Student{}, jsonError(r.line, err)
+	}
+	if dec.More() {
+		return Student{}, &errs.ValidationError{Line: r.line, Message: "unexpected data after the JSON object"}
+	}
+	if err := s.Validate(); err != nil {
+		return Student{}, validationError(r.line, err)
+	}
+	return s, nil
+}
+
+// Line returns the line number of the last student read.
+func (r *JSONLReader) Line() int {
+	return r.line
+}
+
+func jsonError(line int, err error) error {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return &errs.ValidationError{
+			Line:    line,
+			Field:   typeErr.Field,
+			Message: fmt.Sprintf("want %v, got %s", typeErr.Type, typeErr.Value),
+			Err:     err,
+		}
+	}
+	// DisallowUnknownFields reports `json: unknown field "x"` with no
+	// dedicated error type.
+	if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+		return &errs.ValidationError{Line: line, Field: strings.Trim(field, `"`), Message: "unknown field", Err: err}
+	}
+	return &errs.ValidationError{Line: line, Err: err}
+}
+
+// JSONLWriter streams students as JSON Lines.
+type JSONLWriter struct {
+	w   *bufio.Writer
+	enc *json.Encoder
+}
+
+// NewJSONLWriter returns a buffered writer; call Flush when done.
+func NewJSONLWriter(w io.Writer) *JSONLWriter {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	enc.SetEscapeHTML(false)
+	return &JSONLWriter{w: bw, enc: enc}
+}
+
+// Write writes one student as a single line.
+func (w *JSONLWriter) Write(s Student) error {
+	return w.enc.Encode(s)
+}
+
+// Flush writes any buffered data.
+func (w *JSONLWriter) Flush() error {
+	return w.w.Flush()
+}
@@ -0,0 +1,112 @@
+package roster
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// FromLegacy loads students stored the chapter 6 way, as a map from name
+// to a map of "age", "grade", "city" and "active" fields. Unlike the
+// chapter 6 helpers it doesn't skip values of the wrong type: every bad
+// field is reported as a *FieldError, joined with errors.Join, and only
+// the students without errors are stored. Missing fields take their zero
+// value, as they did before.
+func FromLegacy(legacy map[string]map[string]interface{}) (*Store, error) {
+	names := make([]string, 0, len(legacy))
+	for name := range legacy {
+		names = append(names, name)
+	}
+	slices.Sort(names) // report errors in a stable order
+
+	store := NewStore()
+	var errs []error
+	for _, name := range names {
+		s, err := fromLegacy(name, legacy[name])
+		if err == nil {
+			err = store.Add(s)
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return store, errors.Join(errs...)
+}
+
+func fromLegacy(name string, info map[string]interface{}) (Student, error) {
+	s := Student{Name: name}
+	var errs []error
+	fail := func(field string, v interface{}, want string) {
+		errs = append(errs, &FieldError{Student: name, Field: field, Err: fmt.Errorf("want %s, got %T", want, v)})
+	}
+
+	for field, v := range info {
+		switch field {
+		case "age":
+			age, ok := legacyInt(v)
+			if !ok {
+				fail(field, v, "a whole number")
+			}
+			s.Age = age
+		case "grade", "city":
+			str, ok := v.(string)
+			if !ok {
+				fail(field, v, "a string")
+			}
+			if field == "grade" {
+				s.Grade = str
+			} else {
+				s.City = str
+			}
+		case "active":
+			active, ok := v.(bool)
+			if !ok {
+				fail(field, v, "a bool")
+			}
+			s.Active = active
+		default:
+			errs = append(errs, &FieldError{Student: name, Field: field, Err: errors.New("unknown field")})
+		}
+	}
+	return s, errors.Join(errs...)
+}
+
+// legacyInt accepts any integer type, plus whole float64 values so that
+// maps decoded from JSON load too.
+func legacyInt(v interface{}) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, true
+	case int8:
+		return int(n), true
+	case int16:
+		return int(n), true
+	case int32:
+		return int(n), true
+	case int64:
+		return int(n), true
+	case float64:
+		if n == math.Trunc(n) && !math.IsInf(n, 0) {
+			return int(n), true
+		}
+	}
+	return 0, false
+}
+
+// ToLegacy converts the store back to the chapter 6 nested-map shape.
+func (st *Store) ToLegacy() map[string]map[string]interface{} {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	legacy := make(map[string]map[string]interface{}, len(st.students))
+	for name, s := range st.students {
+		legacy[name] = map[string]interface{}{
+			"age":    s.Age,
+			"grade":  s.Grade,
+			"city":   s.City,
+			"active": s.Active,
+		}
+	}
+	return legacy
+}
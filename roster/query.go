@@ -0,0 +1,218 @@
+package roster
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+
+	"go-practice/sorting"
+)
+
+// Filter selects students. Filters on grade, city and active status are
+// answered from the store's indexes; the rest are checked one student at
+// a time. The zero Filter, like Where(nil), matches every student.
+type Filter struct {
+	field string // indexed field, or "" for a plain predicate
+	key   string
+	match func(Student) bool // nil matches everyone
+}
+
+// HasGrade matches students with exactly the given grade.
+func HasGrade(grade string) Filter {
+	return Filter{field: "grade", key: grade, match: func(s Student) bool { return s.Grade == grade }}
+}
+
+// InCity matches students living in city.
+func InCity(city string) Filter {
+	return Filter{field: "city", key: city, match: func(s Student) bool { return s.City == city }}
+}
+
+// IsActive matches students whose active flag equals active.
+func IsActive(active bool) Filter {
+	return Filter{field: "active", key: strconv.FormatBool(active), match: func(s Student) bool { return s.Active == active }}
+}
+
+// AgeBetween matches students aged min to max inclusive.
+func AgeBetween(min, max int) Filter {
+	return Where(func(s Student) bool { return s.Age >= min && s.Age <= max })
+}
+
+// Where turns any predicate into a filter.
+func Where(match func(Student) bool) Filter {
+	return Filter{match: match}
+}
+
+// Query describes a selection of students. Queries are values: every
+// method returns a new query and leaves the receiver untouched, so a base
+// query can be shared and refined in several directions.
+//
+//	honours := store.Query().Filter(roster.HasGrade("A"), roster.IsActive(true))
+//	page := honours.OrderBy(byAge).Page(1, 10).Students()
+//
+// Results are always deterministic. Students are returned in the query's
+// order, and ties (or all students, without an order) fall back to name.
+type Query struct {
+	store   *Store
+	filters []Filter
+	order   sorting.Comparator[Student]
+	offset  int
+	limit   int // 0 means no limit
+}
+
+// Filter narrows the query to students matching every filter.
+func (q Query) Filter(filters ...Filter) Query {
+	q.filters = append(slices.Clip(q.filters), filters...)
+	return q
+}
+
+// OrderBy sets the result order. Name breaks any remaining ties.
+func (q Query) OrderBy(order sorting.Comparator[Student]) Query {
+	q.order = order
+	return q
+}
+
+// Offset skips the first n results.
+func (q Query) Offset(n int) Query {
+	q.offset = max(n, 0)
+	return q
+}
+
+// Limit caps the number of results; 0 removes the cap.
+func (q Query) Limit(n int) Query {
+	q.limit = max(n, 0)
+	return q
+}
+
+// Page selects the page-th page (starting at 1) of size results.
+func (q Query) Page(page, size int) Query {
+	return q.Offset((max(page, 1) - 1) * size).Limit(size)
+}
+
+// Students runs the query and returns the selected page of results.
+func (q Query) Students() []Student {
+	matched := q.run()
+	if q.offset >= len(matched) {
+		return []Student{}
+	}
+	matched = matched[q.offset:]
+	if q.limit > 0 && q.limit < len(matched) {
+		matched = matched[:q.limit]
+	}
+	return matched
+}
+
+// Names is like Students but returns only the names.
+func (q Query) Names() []string {
+	students := q.Students()
+	names := make([]string, len(students))
+	for i, s := range students {
+		names[i] = s.Name
+	}
+	return names
+}
+
+// Count returns how many students match the filters, ignoring Offset and
+// Limit.
+func (q Query) Count() int {
+	return len(q.run())
+}
+
+// Group is one bucket of a GroupBy result.
+type Group struct {
+	Key      string
+	Students []Student
+}
+
+// GroupBy buckets every matching student by key, e.g. ByCity. Groups are
+// sorted by key and students within a group keep the query's order.
+// Offset and Limit are ignored.
+func (q Query) GroupBy(key func(Student) string) []Group {
+	var groups []Group
+	at := make(map[string]int)
+	for _, s := range q.run() {
+		k := key(s)
+		i, ok := at[k]
+		if !ok {
+			i = len(groups)
+			at[k] = i
+			groups = append(groups, Group{Key: k})
+		}
+		groups[i].Students = append(groups[i].Students, s)
+	}
+	slices.SortFunc(groups, func(a, b Group) int { return cmp.Compare(a.Key, b.Key) })
+	return groups
+}
+
+// CountBy counts the matching students per key, ignoring Offset and Limit.
+func (q Query) CountBy(key func(Student) string) map[string]int {
+	counts := make(map[string]int)
+	for _, s := range q.run() {
+		counts[key(s)]++
+	}
+	return counts
+}
+
+// run returns every match in result order.
+func (q Query) run() []Student {
+	if q.store == nil {
+		return []Student{}
+	}
+
+	st := q.store
+	st.mu.RLock()
+	matched := make([]Student, 0)
+	for _, name := range q.candidates() {
+		s := st.students[name]
+		if q.matches(s) {
+			matched = append(matched, s)
+		}
+	}
+	st.mu.RUnlock()
+
+	byName := sorting.Ascending(func(s Student) string { return s.Name })
+	order := byName
+	if q.order != nil {
+		order = q.order.Then(byName)
+	}
+	sorting.SortFunc(matched, order.Less)
+	return matched
+}
+
+// candidates picks the smallest index set among the indexed filters, or
+// every student when there are none. The caller holds the read lock.
+func (q Query) candidates() []string {
+	st := q.store
+	var best map[string]struct{}
+	indexed := false
+	for _, f := range q.filters {
+		ix := st.indexFor(f.field)
+		if ix == nil {
+			continue
+		}
+		set := ix[f.key]
+		if !indexed || len(set) < len(best) {
+			best, indexed = set, true
+		}
+	}
+
+	names := make([]string, 0, len(st.students))
+	if indexed {
+		for name := range best {
+			names = append(names, name)
+		}
+	} else {
+		for name := range st.students {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func (q Query) matches(s Student) bool {
+	for _, f := range q.filters {
+		if f.match != nil && !f.match(s) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,152 @@
+package roster
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+	"testing"
+
+	"go-practice/sorting"
+)
+
+var testStudents = []Student{
+	{Name: "Alice", Age: 20, Grade: "A", City: "Boston", Active: true},
+	{Name: "Bob", Age: 22, Grade: "B", City: "Austin", Active: true},
+	{Name: "Carol", Age: 20, Grade: "A", City: "Austin", Active: false},
+	{Name: "Dave", Age: 25, Grade: "C", City: "Boston", Active: true},
+	{Name: "Eve", Age: 21, Grade: "A", City: "Chicago", Active: true},
+	{Name: "Frank", Age: 23, Grade: "B", City: "Boston", Active: false},
+}
+
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+	st := NewStore()
+	for _, s := range testStudents {
+		if err := st.Add(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return st
+}
+
+var byAge = sorting.Ascending(func(s Student) int { return s.Age })
+
+func TestQuery(t *testing.T) {
+	st := newTestStore(t)
+	honours := st.Query().Filter(HasGrade("A"))
+	tests := []struct {
+		name  string
+		query Query
+		want  []string
+	}{
+		{"everyone by name", st.Query(), []string{"Alice", "Bob", "Carol", "Dave", "Eve", "Frank"}},
+		{"one index", st.Query().Filter(InCity("Boston")), []string{"Alice", "Dave", "Frank"}},
+		{"two indexes", st.Query().Filter(InCity("Boston"), IsActive(true)), []string{"Alice", "Dave"}},
+		{"index and predicate", honours.Filter(AgeBetween(21, 30)), []string{"Eve"}},
+		{"predicate only", st.Query().Filter(AgeBetween(22, 23)), []string{"Bob", "Frank"}},
+		{"no such key", st.Query().Filter(InCity("Denver")), []string{}},
+		{"zero filter", st.Query().Filter(Filter{}), []string{"Alice", "Bob", "Carol", "Dave", "Eve", "Frank"}},
+		{"nil predicate", honours.Filter(Where(nil)), []string{"Alice", "Carol", "Eve"}},
+		{"base query unchanged", honours, []string{"Alice", "Carol", "Eve"}},
+		{"order with name ties", st.Query().OrderBy(byAge), []string{"Alice", "Carol", "Eve", "Bob", "Frank", "Dave"}},
+		{"reversed order", honours.OrderBy(byAge.Reverse()), []string{"Eve", "Alice", "Carol"}},
+		{"first page", st.Query().OrderBy(byAge).Page(1, 4), []string{"Alice", "Carol", "Eve", "Bob"}},
+		{"last page", st.Query().OrderBy(byAge).Page(2, 4), []string{"Frank", "Dave"}},
+		{"past the end", st.Query().Page(3, 4), []string{}},
+		{"page 0 is page 1", st.Query().Page(0, 2), []string{"Alice", "Bob"}},
+		{"offset without limit", st.Query().Offset(4), []string{"Eve", "Frank"}},
+		{"no store", Query{}.Filter(HasGrade("A")), []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.query.Names(); !slices.Equal(got, tt.want) {
+				t.Errorf("Names = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryCountIgnoresPaging(t *testing.T) {
+	st := newTestStore(t)
+	q := st.Query().Filter(IsActive(true)).Page(2, 3)
+	if got := q.Count(); got != 4 {
+		t.Errorf("Count = %d, want 4", got)
+	}
+	if got := len(q.Students()); got != 1 {
+		t.Errorf("len(Students) = %d, want 1", got)
+	}
+}
+
+func TestCandidatesUseSmallestIndex(t *testing.T) {
+	st := newTestStore(t)
+	q := st.Query().Filter(IsActive(true), HasGrade("C"), AgeBetween(0, 100))
+	got := q.candidates()
+	slices.Sort(got)
+	if want := []string{"Dave"}; !slices.Equal(got, want) {
+		t.Errorf("candidates = %v, want %v", got, want)
+	}
+
+	// the index follows updates
+	if err := st.Put(Student{Name: "Dave", Age: 25, Grade: "B", City: "Denver", Active: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.Delete("Alice"); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		filter Filter
+		want   []string
+	}{
+		{HasGrade("C"), []string{}},
+		{HasGrade("B"), []string{"Bob", "Dave", "Frank"}},
+		{InCity("Boston"), []string{"Frank"}},
+		{InCity("Denver"), []string{"Dave"}},
+	} {
+		if got := st.Query().Filter(tt.filter).Names(); !slices.Equal(got, tt.want) {
+			t.Errorf("%s=%s after updates: %v, want %v", tt.filter.field, tt.filter.key, got, tt.want)
+		}
+	}
+}
+
+func TestGroupBy(t *testing.T) {
+	st := newTestStore(t)
+	groups := st.Query().Filter(IsActive(true)).OrderBy(byAge.Reverse()).GroupBy(ByCity)
+
+	got := make(map[string][]string)
+	var keys []string
+	for _, g := range groups {
+		keys = append(keys, g.Key)
+		for _, s := range g.Students {
+			got[g.Key] = append(got[g.Key], s.Name)
+		}
+	}
+	if want := []string{"Austin", "Boston", "Chicago"}; !slices.Equal(keys, want) {
+		t.Errorf("keys = %v, want %v", keys, want)
+	}
+	want := map[string][]string{"Austin": {"Bob"}, "Boston": {"Dave", "Alice"}, "Chicago": {"Eve"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("groups = %v, want %v", got, want)
+	}
+}
+
+func TestCountBy(t *testing.T) {
+	st := newTestStore(t)
+	tests := []struct {
+		name  string
+		query Query
+		key   func(Student) string
+		want  map[string]int
+	}{
+		{"grade", st.Query(), ByGrade, map[string]int{"A": 3, "B": 2, "C": 1}},
+		{"active", st.Query().Limit(1), ByActive, map[string]int{"active": 4, "inactive": 2}},
+		{"city of grade B", st.Query().Filter(HasGrade("B")), ByCity, map[string]int{"Austin": 1, "Boston": 1}},
+		{"nothing matches", st.Query().Filter(HasGrade("F")), ByCity, map[string]int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.query.CountBy(tt.key); !maps.Equal(got, tt.want) {
+				t.Errorf("CountBy = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package roster
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+)
+
+// index maps one field value to the set of student names holding it.
+type index map[string]map[string]struct{}
+
+func (ix index) add(key, name string) {
+	set, ok := ix[key]
+	if !ok {
+		set = make(map[string]struct{})
+		ix[key] = set
+	}
+	set[name] = struct{}{}
+}
+
+func (ix index) remove(key, name string) {
+	set := ix[key]
+	delete(set, name)
+	if len(set) == 0 {
+		delete(ix, key)
+	}
+}
+
+// Store holds students by name and keeps secondary indexes on grade, city
+// and active status up to date. It is safe for concurrent use.
+type Store struct {
+	mu       sync.RWMutex
+	students map[string]Student
+	grade    index
+	city     index
+	active   index
+}
+
+// NewStore returns an empty store.
+func NewStore() *Store {
+	return &Store{
+		students: make(map[string]Student),
+		grade:    make(index),
+		city:     make(index),
+		active:   make(index),
+	}
+}
+
+// Add stores a new student. It fails with ErrDuplicate if the name is
+// taken, or with a *FieldError if the student doesn't validate.
+func (st *Store) Add(s Student) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if _, exists := st.students[s.Name]; exists {
+		return fmt.Errorf("roster: %q: %w", s.Name, ErrDuplicate)
+	}
+	st.insert(s)
+	return nil
+}
+
+// Put stores s, replacing any student with the same name.
+func (st *Store) Put(s Student) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if old, exists := st.students[s.Name]; exists {
+		st.unindex(old)
+	}
+	st.insert(s)
+	return nil
+}
+
+// Get returns the student with the given name.
+func (st *Store) Get(name string) (Student, bool) {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	s, ok := st.students[name]
+	return s, ok
+}
+
+// Delete removes a student. It returns ErrNotFound if there was nothing to
+// remove.
+func (st *Store) Delete(name string) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	s, exists := st.students[name]
+	if !exists {
+		return fmt.Errorf("roster: %q: %w", name, ErrNotFound)
+	}
+	st.unindex(s)
+	delete(st.students, name)
+	return nil
+}
+
+// Len returns the number of stored students.
+func (st *Store) Len() int {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	return len(st.students)
+}
+
+// Names returns every student name in sorted order.
+func (st *Store) Names() []string {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	names := make([]string, 0, len(st.students))
+	for name := range st.students {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Query starts a query over every student in the store.
+func (st *Store) Query() Query {
+	return Query{store: st}
+}
+
+func (st *Store) insert(s Student) {
+	st.students[s.Name] = s
+	st.grade.add(s.Grade, s.Name)
+	st.city.add(s.City, s.Name)
+	st.active.add(strconv.FormatBool(s.Active), s.Name)
+}
+
+func (st *Store) unindex(s Student) {
+	st.grade.remove(s.Grade, s.Name)
+	st.city.remove(s.City, s.Name)
+	st.active.remove(strconv.FormatBool(s.Active), s.Name)
+}
+
+// indexFor returns the index a filter can use, or nil.
+func (st *Store) indexFor(field string) index {
+	switch field {
+	case "grade":
+		return st.grade
+	case "city":
+		return st.city
+	case "active":
+		return st.active
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+// Package roster keeps the chapter 6 student records in a typed store.
+// Chapter 6 models a student as map[string]interface{}, so every lookup
+// needs a type assertion that quietly yields the zero value when a field
+// is missing or has the wrong type. Here a student is a plain struct, the
+// store maintains secondary indexes on grade, city and active status, and
+// queries compose filters, ordering, grouping and pagination with results
+// in a deterministic order. Rosters can be streamed in and out as CSV or
+// JSON Lines, with rejected rows reported as *errs.ValidationError values.
+package roster
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrDuplicate is returned by Add when a student with the same name is
+	// already stored.
+	ErrDuplicate = errors.New("student already exists")
+
+	// ErrNotFound is returned when no student has the requested name.
+	ErrNotFound = errors.New("student not found")
+)
+
+// Student is one roster entry. Name is the primary key.
+type Student struct {
+	Name   string `json:"name"`
+	Age    int    `json:"age"`
+	Grade  string `json:"grade"`
+	City   string `json:"city"`
+	Active bool   `json:"active"`
+}
+
+// FieldError reports a student field that failed validation.
+type FieldError struct {
+	Student string // the student's name, if known
+	Field   string
+	Err     error
+}
+
+func (e *FieldError) Error() string {
+	if e.Student == "" {
+		return fmt.Sprintf("roster: %s: %v", e.Field, e.Err)
+	}
+	return fmt.Sprintf("roster: student %q: %s: %v", e.Student, e.Field, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// Validate checks the fields the store relies on: a non-blank name and a
+// non-negative age.
+func (s Student) Validate() error {
+	if strings.TrimSpace(s.Name) == "" {
+		return &FieldError{Field: "name", Err: errors.New("is required")}
+	}
+	if s.Age < 0 {
+		return &FieldError{Student: s.Name, Field: "age", Err: fmt.Errorf("must not be negative, got %d", s.Age)}
+	}
+	return nil
+}
+
+// ByGrade, ByCity and ByActive are key functions for Query.GroupBy and
+// Query.CountBy.
+func ByGrade(s Student) string { return s.Grade }
+
+func ByCity(s Student) string { return s.City }
+
+func ByActive(s Student) string {
+	if s.Active {
+		return "active"
+	}
+	return "inactive"
+}
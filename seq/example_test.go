@@ -0,0 +1,111 @@
+package seq_test
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"go-practice/seq"
+)
+
+// The pipeline from the package documentation: the first three passing
+// scores, pulled one at a time. Filter never sees the scores after 88.
+func Example() {
+	scores := []int{45, 92, 78, 51, 88, 96, 67}
+	checked := 0
+	passed := func(s int) bool {
+		checked++
+		return s >= 60
+	}
+
+	for s := range seq.Take(seq.Filter(seq.Slice(scores), passed), 3) {
+		fmt.Println(s)
+	}
+	fmt.Println("checked", checked, "of", len(scores))
+	// Output:
+	// 92
+	// 78
+	// 88
+	// checked 5 of 7
+}
+
+func ExampleMap() {
+	names := seq.Map(seq.Slice([]string{"ann", "bob"}), strings.ToUpper)
+	fmt.Println(slices.Collect(names))
+	// Output: [ANN BOB]
+}
+
+func ExampleCount() {
+	squares := seq.Map(seq.Count(1), func(n int) int { return n * n })
+	fmt.Println(slices.Collect(seq.Take(squares, 5)))
+	// Output: [1 4 9 16 25]
+}
+
+func ExampleZip() {
+	names := seq.Slice([]string{"Ann", "Bob", "Cy"})
+	for rank, name := range seq.Zip(seq.Count(1), names) {
+		fmt.Printf("%d. %s\n", rank, name)
+	}
+	// Output:
+	// 1. Ann
+	// 2. Bob
+	// 3. Cy
+}
+
+func ExampleChain() {
+	all := seq.Chain(seq.Slice([]int{1, 2}), seq.Skip(seq.Slice([]int{0, 3, 4}), 1))
+	fmt.Println(seq.Reduce(all, 0, func(sum, v int) int { return sum + v }))
+	// Output: 10
+}
+
+func ExampleLines() {
+	input := strings.NewReader("first\nsecond\r\nthird")
+	for line, err := range seq.Lines(input) {
+		if err != nil {
+			fmt.Println("error:", err)
+			return
+		}
+		fmt.Printf("%q\n", line)
+	}
+	// Output:
+	// "first"
+	// "second"
+	// "third"
+}
+
+func ExampleLinked() {
+	type node struct {
+		Value string
+		Next  *node
+	}
+	list := &node{"a", &node{"b", &node{"c", nil}}}
+	values := seq.Linked(list,
+		func(n *node) *node { return n.Next },
+		func(n *node) string { return n.Value })
+	fmt.Println(slices.Collect(values))
+	// Output: [a b c]
+}
+
+func ExampleSortedEntries() {
+	stock := map[string]int{"pears": 3, "apples": 5, "figs": 0}
+	for fruit, n := range seq.SortedEntries(stock) {
+		fmt.Println(fruit, n)
+	}
+	fmt.Println(len(maps.Collect(seq.Entries(stock))))
+	// Output:
+	// apples 5
+	// figs 0
+	// pears 3
+	// 3
+}
+
+func ExampleChan() {
+	ch := make(chan int, 3)
+	ch <- 1
+	ch <- 2
+	ch <- 3
+	close(ch)
+	fmt.Println(slices.Collect(seq.Chan(ch)))
+	// Output: [1 2 3]
+}
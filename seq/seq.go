@@ -0,0 +1,114 @@
+// Package seq builds lazy pipelines on the range-over-func iterators from
+// the standard iter package. Where the chapter 5 helpers build a whole new
+// slice at every step, a pipeline such as
+//
+//	for s := range seq.Take(seq.Filter(seq.Slice(scores), passed), 3) {
+//		...
+//	}
+//
+// pulls one value at a time through each stage and stops as soon as the
+// loop does, so nothing in between is allocated and a source larger than
+// memory (a file read line by line, a channel) can be processed. The
+// package example runs this pipeline.
+//
+// Every function returns an iter.Seq or iter.Seq2, so the results work
+// with range loops and with the standard library's slices.Collect,
+// maps.Collect and friends.
+package seq
+
+import "iter"
+
+// Map lazily applies f to every value of s.
+func Map[T, U any](s iter.Seq[T], f func(T) U) iter.Seq[U] {
+	return func(yield func(U) bool) {
+		for v := range s {
+			if !yield(f(v)) {
+				return
+			}
+		}
+	}
+}
+
+// Filter lazily keeps the values of s for which keep returns true.
+func Filter[T any](s iter.Seq[T], keep func(T) bool) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for v := range s {
+			if keep(v) && !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Take yields at most the first n values of s and then stops pulling from
+// it, so it can cut an endless source short.
+func Take[T any](s iter.Seq[T], n int) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if n <= 0 {
+			return
+		}
+		i := 0
+		for v := range s {
+			if !yield(v) {
+				return
+			}
+			i++
+			if i == n {
+				return
+			}
+		}
+	}
+}
+
+// Skip drops the first n values of s and yields the rest.
+func Skip[T any](s iter.Seq[T], n int) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		i := 0
+		for v := range s {
+			if i < n {
+				i++
+				continue
+			}
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Chain yields every value of each sequence in turn.
+func Chain[T any](seqs ...iter.Seq[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, s := range seqs {
+			for v := range s {
+				if !yield(v) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Zip pairs up the values of a and b in step, stopping when either runs
+// out.
+func Zip[A, B any](a iter.Seq[A], b iter.Seq[B]) iter.Seq2[A, B] {
+	return func(yield func(A, B) bool) {
+		nextB, stop := iter.Pull(b)
+		defer stop()
+		for va := range a {
+			vb, ok := nextB()
+			if !ok || !yield(va, vb) {
+				return
+			}
+		}
+	}
+}
+
+// Reduce consumes s, folding it into a single value.
+func Reduce[T, A any](s iter.Seq[T], initial A, f func(A, T) A) A {
+	acc := initial
+	for v := range s {
+		acc = f(acc, v)
+	}
+	return acc
+}
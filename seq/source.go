@@ -0,0 +1,115 @@
+package seq
+
+import (
+	"bufio"
+	"cmp"
+	"io"
+	"iter"
+	"slices"
+)
+
+// Slice yields the elements of s in order. Like ranging over s, it sees
+// writes made to elements it hasn't reached yet.
+func Slice[T any](s []T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, v := range s {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Entries yields the key/value pairs of m in Go's unspecified map order.
+func Entries[K comparable, V any](m map[K]V) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for k, v := range m {
+			if !yield(k, v) {
+				return
+			}
+		}
+	}
+}
+
+// SortedEntries yields the key/value pairs of m in ascending key order, so
+// output built from a map is the same on every run. It has to collect and
+// sort the keys first, which takes O(len(m)) memory.
+func SortedEntries[K cmp.Ordered, V any](m map[K]V) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		keys := make([]K, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		for _, k := range keys {
+			if !yield(k, m[k]) {
+				return
+			}
+		}
+	}
+}
+
+// Chan yields values received from ch until it is closed. Stopping early
+// leaves the remaining values in the channel.
+func Chan[T any](ch <-chan T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for v := range ch {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Lines yields the lines of r without their line endings. A read error is
+// yielded once, with an empty line, as the last pair; lines longer than
+// bufio.MaxScanTokenSize are reported as bufio.ErrTooLong.
+//
+//	for line, err := range seq.Lines(file) {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+func Lines(r io.Reader) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			if !yield(scanner.Text(), nil) {
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			yield("", err)
+		}
+	}
+}
+
+// Linked walks a singly linked list from head, following next until it
+// returns nil, and yields value of each node. It works with any node type,
+// such as chapter 9's LinkedList:
+//
+//	values := seq.Linked(list,
+//		func(n *LinkedList) *LinkedList { return n.Next },
+//		func(n *LinkedList) string { return n.Value })
+//
+// Linked doesn't detect cycles; Take can bound the walk over a list that
+// might have one.
+func Linked[N, T any](head *N, next func(*N) *N, value func(*N) T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for n := head; n != nil; n = next(n) {
+			if !yield(value(n)) {
+				return
+			}
+		}
+	}
+}
+
+// Count yields start, start+1, start+2, ... without end. Combine it with
+// Take or Zip.
+func Count(start int) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for i := start; yield(i); i++ {
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package shapes_test
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"go-practice/shapes"
+)
+
+func ExampleNew() {
+	for _, kind := range []string{"circle", "square", "triangle"} {
+		s, err := shapes.New(kind, 4)
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		fmt.Printf("%s area: %.2f\n", kind, s.Area())
+	}
+
+	// Bad input is reported instead of becoming an "unknown" shape
+	_, err := shapes.New("hexagon", 2)
+	fmt.Println(err)
+	_, err = shapes.New("circle", -1)
+	fmt.Println(errors.Is(err, shapes.ErrInvalidSize))
+	// Output:
+	// circle area: 50.27
+	// square area: 16.00
+	// triangle area: 8.00
+	// unknown shape kind: "hexagon"
+	// true
+}
+
+// Hexagon is a regular hexagon, added without changing the package.
+type Hexagon struct {
+	Side float64 `json:"side"`
+}
+
+func (h Hexagon) Area() float64      { return 3 * math.Sqrt(3) / 2 * h.Side * h.Side }
+func (h Hexagon) Perimeter() float64 { return 6 * h.Side }
+
+func ExampleRegister() {
+	err := shapes.Register("hexagon", Hexagon{}, func(size float64) (shapes.Shape, error) {
+		return Hexagon{Side: size}, nil
+	})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	h, err := shapes.New("hexagon", 2)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Printf("%T area: %.2f\n", h, h.Area())
+	data, err := shapes.Marshal(h)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(string(data))
+	// Output:
+	// shapes_test.Hexagon area: 10.39
+	// {"side":2,"type":"hexagon"}
+}
@@ -0,0 +1,89 @@
+package shapes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"go-practice/codec"
+)
+
+// TypeKey is the JSON field that carries a shape's kind name.
+const TypeKey = codec.TypeKey
+
+// Marshal encodes a shape as a JSON object with its fields plus a "type"
+// discriminator, e.g. {"radius":5,"type":"circle"}.
+func Marshal(s Shape) ([]byte, error) {
+	if _, err := KindOf(s); err != nil {
+		return nil, err
+	}
+	return registry.types.MarshalValue(s)
+}
+
+// Unmarshal decodes a JSON object produced by Marshal back into the
+// concrete shape type registered for its "type" field, which must be
+// spelled as Marshal writes it. Unknown fields are rejected so typos
+// don't silently produce zero-sized shapes, and every float field must
+// be positive, as New requires of its size, so a missing or negative
+// dimension returns ErrInvalidSize.
+func Unmarshal(data []byte) (Shape, error) {
+	s, err := codec.UnmarshalValue[Shape](registry.types, data)
+	var unknown *codec.UnknownTypeError
+	if errors.As(err, &unknown) {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKind, unknown.Name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := KindOf(s)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSizes(name, s); err != nil {
+		return nil, fmt.Errorf("shapes: %w", err)
+	}
+	return s, nil
+}
+
+// List is a slice of shapes that round-trips through JSON as an array of
+// discriminated objects.
+type List []Shape
+
+// MarshalJSON implements json.Marshaler.
+func (l List) MarshalJSON() ([]byte, error) {
+	items := make([]json.RawMessage, len(l))
+	for i, s := range l {
+		data, err := Marshal(s)
+		if err != nil {
+			return nil, fmt.Errorf("shape %d: %w", i, err)
+		}
+		items[i] = data
+	}
+	return json.Marshal(items)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (l *List) UnmarshalJSON(data []byte) error {
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+
+	list := make(List, 0, len(items))
+	var errs []error
+	for i, item := range items {
+		s, err := Unmarshal(item)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("shape %d: %w", i, err))
+			continue
+		}
+		list = append(list, s)
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	*l = list
+	return nil
+}
@@ -0,0 +1,182 @@
+package shapes
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	"go-practice/codec"
+)
+
+var (
+	// ErrUnknownKind is returned when a shape kind has not been registered.
+	ErrUnknownKind = errors.New("unknown shape kind")
+
+	// ErrInvalidSize is returned when a factory is given a size it can't
+	// build a shape from.
+	ErrInvalidSize = errors.New("invalid shape size")
+)
+
+// Factory builds a shape of one kind from a single size, the same input
+// chapter 7's NewShape takes.
+type Factory func(size float64) (Shape, error)
+
+type kind struct {
+	name    string
+	factory Factory
+}
+
+// registry maps kind names to factories for New. The mapping between
+// kind names and concrete types, which the JSON functions use, lives in
+// a codec.Registry.
+var registry = struct {
+	sync.RWMutex
+	byName map[string]kind
+	types  *codec.Registry
+}{
+	byName: make(map[string]kind),
+	types:  codec.NewRegistry(),
+}
+
+func init() {
+	MustRegister("circle", Circle{}, func(size float64) (Shape, error) {
+		return Circle{Radius: size}, nil
+	})
+	MustRegister("rectangle", Rectangle{}, func(size float64) (Shape, error) {
+		return Rectangle{Width: size, Height: size}, nil
+	})
+	MustRegister("square", Square{}, func(size float64) (Shape, error) {
+		return Square{Side: size}, nil
+	})
+	MustRegister("triangle", Triangle{}, func(size float64) (Shape, error) {
+		return Triangle{Base: size, Height: size}, nil
+	})
+}
+
+// Register adds a new shape kind. prototype is any value of the concrete
+// type the factory returns; it is used to map decoded JSON back to the
+// right Go type. Names are case-insensitive and must be unique, as must
+// the concrete type.
+func Register(name string, prototype Shape, factory Factory) error {
+	name = normalize(name)
+	if name == "" {
+		return errors.New("shapes: kind name is required")
+	}
+	if prototype == nil || factory == nil {
+		return fmt.Errorf("shapes: kind %q needs a prototype and a factory", name)
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+
+	if _, exists := registry.byName[name]; exists {
+		return fmt.Errorf("shapes: kind %q is already registered", name)
+	}
+	if err := registry.types.Register(name, prototype); err != nil {
+		return fmt.Errorf("shapes: kind %q: %w", name, err)
+	}
+
+	registry.byName[name] = kind{name: name, factory: factory}
+	return nil
+}
+
+// MustRegister is like Register but panics on error. It is meant for
+// package init functions.
+func MustRegister(name string, prototype Shape, factory Factory) {
+	if err := Register(name, prototype, factory); err != nil {
+		panic(err)
+	}
+}
+
+// New builds a shape of the named kind. Unlike the chapter 7 factory it
+// never hands back a silent "unknown" shape: unregistered kinds return
+// ErrUnknownKind and non-positive sizes return ErrInvalidSize.
+func New(kindName string, size float64) (Shape, error) {
+	k, err := lookup(kindName)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSize(k.name, "size", size); err != nil {
+		return nil, err
+	}
+	return k.factory(size)
+}
+
+// checkSize rejects a size that isn't positive, including NaN.
+func checkSize(kindName, what string, size float64) error {
+	if !(size > 0) {
+		return fmt.Errorf("%w: %s %s must be positive, got %g", ErrInvalidSize, kindName, what, size)
+	}
+	return nil
+}
+
+// checkSizes applies checkSize to every float field of a decoded shape,
+// naming each by its JSON key, so a missing or negative dimension is
+// caught just as New catches a bad size.
+func checkSizes(kindName string, s Shape) error {
+	v := reflect.ValueOf(s)
+	if v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+	for i := range v.NumField() {
+		f := v.Type().Field(i)
+		if !f.IsExported() || (f.Type.Kind() != reflect.Float64 && f.Type.Kind() != reflect.Float32) {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "" {
+			name = f.Name
+		}
+		if err := checkSize(kindName, name, v.Field(i).Float()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Kinds returns the registered kind names in sorted order.
+func Kinds() []string {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	names := make([]string, 0, len(registry.byName))
+	for name := range registry.byName {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// KindOf returns the registered kind name for s's concrete type.
+func KindOf(s Shape) (string, error) {
+	if s == nil {
+		return "", errors.New("shapes: nil shape has no kind")
+	}
+
+	name, err := registry.types.NameOf(s)
+	if err != nil {
+		return "", fmt.Errorf("%w: type %T is not registered", ErrUnknownKind, s)
+	}
+	return name, nil
+}
+
+func lookup(kindName string) (kind, error) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	k, ok := registry.byName[normalize(kindName)]
+	if !ok {
+		return kind{}, fmt.Errorf("%w: %q", ErrUnknownKind, kindName)
+	}
+	return k, nil
+}
+
+func normalize(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
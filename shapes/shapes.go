@@ -0,0 +1,127 @@
+// Package shapes provides the geometric shapes used throughout the book as
+// reusable types. Chapter 8 introduces the Shape interface and chapters 7
+// and 9 build shapes with a NewShape factory; this package gives both a
+// single home that other packages (such as render) can import.
+//
+// Shape kinds live in a registry rather than a switch statement: New
+// builds a shape by kind name and returns an error for unknown kinds, and
+// Register lets other packages add kinds of their own. Each kind is also
+// registered in a codec.Registry, which drives Marshal, Unmarshal and
+// List as they round-trip shapes through JSON using a "type"
+// discriminator field.
+package shapes
+
+import "math"
+
+// Shape is anything with an area and a perimeter.
+type Shape interface {
+	Area() float64
+	Perimeter() float64
+}
+
+// Point is a 2D coordinate. Y grows downwards, matching SVG and image
+// coordinates.
+type Point struct {
+	X, Y float64
+}
+
+// Polygon is implemented by shapes whose outline is a closed sequence of
+// straight edges. Vertices are relative to the top-left corner of the
+// shape's bounding box.
+type Polygon interface {
+	Shape
+	Vertices() []Point
+}
+
+// Bounded is implemented by shapes that know the size of their bounding
+// box.
+type Bounded interface {
+	Bounds() (width, height float64)
+}
+
+// Circle is a circle with the given radius.
+type Circle struct {
+	Radius float64 `json:"radius"`
+}
+
+// Rectangle is an axis-aligned rectangle.
+type Rectangle struct {
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+// Square is a rectangle with equal sides.
+type Square struct {
+	Side float64 `json:"side"`
+}
+
+// Triangle is an isosceles triangle with its apex centred above the base.
+type Triangle struct {
+	Base   float64 `json:"base"`
+	Height float64 `json:"height"`
+}
+
+// Circle methods
+func (c Circle) Area() float64 {
+	return math.Pi * c.Radius * c.Radius
+}
+
+func (c Circle) Perimeter() float64 {
+	return 2 * math.Pi * c.Radius
+}
+
+func (c Circle) Bounds() (width, height float64) {
+	return 2 * c.Radius, 2 * c.Radius
+}
+
+// Rectangle methods
+func (r Rectangle) Area() float64 {
+	return r.Width * r.Height
+}
+
+func (r Rectangle) Perimeter() float64 {
+	return 2 * (r.Width + r.Height)
+}
+
+func (r Rectangle) Bounds() (width, height float64) {
+	return r.Width, r.Height
+}
+
+func (r Rectangle) Vertices() []Point {
+	return []Point{{0, 0}, {r.Width, 0}, {r.Width, r.Height}, {0, r.Height}}
+}
+
+// Square methods
+func (s Square) Area() float64 {
+	return s.Side * s.Side
+}
+
+func (s Square) Perimeter() float64 {
+	return 4 * s.Side
+}
+
+func (s Square) Bounds() (width, height float64) {
+	return s.Side, s.Side
+}
+
+func (s Square) Vertices() []Point {
+	return Rectangle{Width: s.Side, Height: s.Side}.Vertices()
+}
+
+// Triangle methods
+func (t Triangle) Area() float64 {
+	return 0.5 * t.Base * t.Height
+}
+
+func (t Triangle) Perimeter() float64 {
+	side := math.Hypot(t.Base/2, t.Height)
+	return t.Base + 2*side
+}
+
+func (t Triangle) Bounds() (width, height float64) {
+	return t.Base, t.Height
+}
+
+func (t Triangle) Vertices() []Point {
+	return []Point{{0, t.Height}, {t.Base, t.Height}, {t.Base / 2, 0}}
+}
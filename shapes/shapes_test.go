@@ -0,0 +1,116 @@
+package shapes_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go-practice/shapes"
+)
+
+func TestListRoundTrip(t *testing.T) {
+	list := shapes.List{
+		shapes.Circle{Radius: 1.5},
+		shapes.Rectangle{Width: 2, Height: 3},
+		shapes.Square{Side: 4},
+		shapes.Triangle{Base: 5, Height: 6},
+		shapes.Circle{Radius: 7},
+	}
+
+	data, err := json.Marshal(list)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[{"radius":1.5,"type":"circle"},{"height":3,"type":"rectangle","width":2},` +
+		`{"side":4,"type":"square"},{"base":5,"height":6,"type":"triangle"},{"radius":7,"type":"circle"}]`
+	if string(data) != want {
+		t.Errorf("Marshal gave\n%s\nwant\n%s", data, want)
+	}
+
+	var got shapes.List
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, list) {
+		t.Errorf("round trip gave %#v, want %#v", got, list)
+	}
+}
+
+func TestListUnmarshalErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    []string // one line per bad shape
+		unknown bool     // whether the error wraps ErrUnknownKind
+	}{
+		{
+			name:    "unknown type",
+			in:      `[{"type": "circle", "radius": 1}, {"type": "blob", "size": 2}]`,
+			want:    []string{`shape 1: unknown shape kind: "blob"`},
+			unknown: true,
+		},
+		{
+			name: "unknown field",
+			in:   `[{"type": "circle", "raduis": 1}]`,
+			want: []string{`shape 0: codec: circle: json: unknown field "raduis"`},
+		},
+		{
+			name: "field from another kind",
+			in:   `[{"type": "square", "side": 1, "radius": 2}]`,
+			want: []string{`shape 0: codec: square: json: unknown field "radius"`},
+		},
+		{
+			name: "missing type",
+			in:   `[{"radius": 1}]`,
+			want: []string{`shape 0: codec: missing or non-string "type" field`},
+		},
+		{
+			name:    "every bad shape",
+			in:      `[{"type": "blob"}, {"type": "square", "side": 1}, {"type": "triangle", "width": 2}]`,
+			want:    []string{`shape 0: unknown shape kind: "blob"`, `shape 2: codec: triangle: json: unknown field "width"`},
+			unknown: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			list := shapes.List{shapes.Square{Side: 9}}
+			err := json.Unmarshal([]byte(tc.in), &list)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if got := strings.Split(err.Error(), "\n"); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("error = %q, want %q", got, tc.want)
+			}
+			if errors.Is(err, shapes.ErrUnknownKind) != tc.unknown {
+				t.Errorf("errors.Is(err, ErrUnknownKind) = %t, want %t", !tc.unknown, tc.unknown)
+			}
+			if want := (shapes.List{shapes.Square{Side: 9}}); !reflect.DeepEqual(list, want) {
+				t.Errorf("list changed to %#v after a failed decode", list)
+			}
+		})
+	}
+}
+
+func TestUnmarshalRejectsBadSizes(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{`{"type": "circle", "radius": -3}`, "shapes: invalid shape size: circle radius must be positive, got -3"},
+		{`{"type": "circle"}`, "shapes: invalid shape size: circle radius must be positive, got 0"},
+		{`{"type": "circle", "radius": 0}`, "shapes: invalid shape size: circle radius must be positive, got 0"},
+		{`{"type": "rectangle", "width": 2}`, "shapes: invalid shape size: rectangle height must be positive, got 0"},
+		{`{"type": "triangle", "base": -1, "height": -1}`, "shapes: invalid shape size: triangle base must be positive, got -1"},
+	}
+	for _, tc := range tests {
+		s, err := shapes.Unmarshal([]byte(tc.in))
+		if err == nil || err.Error() != tc.want {
+			t.Errorf("Unmarshal(%s) = %v, %v, want error %q", tc.in, s, err, tc.want)
+			continue
+		}
+		if !errors.Is(err, shapes.ErrInvalidSize) {
+			t.Errorf("Unmarshal(%s): %v doesn't wrap ErrInvalidSize", tc.in, err)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package sim
+
+import "math/rand/v2"
+
+// Direction is a single step on the grid.
+type Direction struct {
+	DX, DY int
+}
+
+var (
+	Stay  = Direction{0, 0}
+	North = Direction{0, -1}
+	South = Direction{0, 1}
+	East  = Direction{1, 0}
+	West  = Direction{-1, 0}
+)
+
+// directions lists every move in a fixed order so random choices are
+// reproducible.
+var directions = []Direction{North, East, South, West}
+
+// Behavior decides where a creature goes next. Implementations must only
+// read from the world and draw randomness from rng, so runs stay
+// deterministic.
+type Behavior interface {
+	Name() string
+	Decide(w *World, c *Creature, rng *rand.Rand) Direction
+}
+
+// RandomWalk moves in a random direction every tick.
+type RandomWalk struct{}
+
+func (RandomWalk) Name() string { return "random-walk" }
+
+func (RandomWalk) Decide(w *World, c *Creature, rng *rand.Rand) Direction {
+	return directions[rng.IntN(len(directions))]
+}
+
+// SeekFood heads for the nearest visible food and wanders randomly when
+// none is in sight.
+type SeekFood struct{}
+
+func (SeekFood) Name() string { return "seek-food" }
+
+func (SeekFood) Decide(w *World, c *Creature, rng *rand.Rand) Direction {
+	if w.FoodAt(c.Pos) {
+		return Stay
+	}
+	if target, ok := w.NearestFood(c.Pos, c.Animal.Traits().Sight); ok {
+		return towards(c.Pos, target)
+	}
+	return RandomWalk{}.Decide(w, c, rng)
+}
+
+// Flock heads for the nearest visible member of the same species, which
+// makes breeding more likely, and otherwise behaves like SeekFood.
+type Flock struct{}
+
+func (Flock) Name() string { return "flock" }
+
+func (Flock) Decide(w *World, c *Creature, rng *rand.Rand) Direction {
+	other, ok := w.NearestCreature(c.Pos, c.Animal.Traits().Sight, func(o *Creature) bool {
+		return o.ID != c.ID && o.Animal.Species() == c.Animal.Species()
+	})
+	if ok && other.Pos != c.Pos {
+		// When two flockers are next to each other only the older one
+		// moves, otherwise they would swap places forever.
+		if distance(c.Pos, other.Pos) == 1 && c.ID > other.ID {
+			return Stay
+		}
+		return towards(c.Pos, other.Pos)
+	}
+	return SeekFood{}.Decide(w, c, rng)
+}
+
+// towards returns the step that closes the larger of the two gaps first.
+func towards(from, to Pos) Direction {
+	dx, dy := to.X-from.X, to.Y-from.Y
+	if abs(dx) >= abs(dy) && dx != 0 {
+		return Direction{sign(dx), 0}
+	}
+	if dy != 0 {
+		return Direction{0, sign(dy)}
+	}
+	return Stay
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func sign(n int) int {
+	switch {
+	case n > 0:
+		return 1
+	case n < 0:
+		return -1
+	default:
+		return 0
+	}
+}
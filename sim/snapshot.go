@@ -0,0 +1,95 @@
+package sim
+
+import (
+	"encoding/json"
+	"io"
+	"slices"
+)
+
+// EventKind names what happened in an Event.
+type EventKind string
+
+const (
+	EventEat   EventKind = "eat"
+	EventMeet  EventKind = "meet"
+	EventBirth EventKind = "birth"
+	EventDeath EventKind = "death"
+)
+
+// Event is something notable that happened during a tick.
+type Event struct {
+	Tick      int       `json:"tick"`
+	Kind      EventKind `json:"kind"`
+	Creatures []int     `json:"creatures"`
+	Message   string    `json:"message"`
+}
+
+// CreatureState is the exported view of a creature in a Snapshot.
+type CreatureState struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Species  string `json:"species"`
+	Behavior string `json:"behavior"`
+	Pos      Pos    `json:"pos"`
+	Energy   int    `json:"energy"`
+	Age      int    `json:"age"`
+}
+
+// Snapshot is the full state of the world after a tick. Creatures are in
+// ID order and food cells in row-major order, so identical worlds always
+// encode to identical JSON.
+type Snapshot struct {
+	Tick      int             `json:"tick"`
+	Width     int             `json:"width"`
+	Height    int             `json:"height"`
+	Food      []Pos           `json:"food"`
+	Creatures []CreatureState `json:"creatures"`
+	Events    []Event         `json:"events"`
+}
+
+// Snapshot captures the current state without advancing the world.
+func (w *World) Snapshot() Snapshot {
+	snap := Snapshot{
+		Tick:      w.tick,
+		Width:     w.config.Width,
+		Height:    w.config.Height,
+		Food:      make([]Pos, 0, len(w.food)),
+		Creatures: make([]CreatureState, 0, len(w.creatures)),
+		Events:    slices.Clone(w.events),
+	}
+	if snap.Events == nil {
+		snap.Events = []Event{}
+	}
+
+	for p := range w.food {
+		snap.Food = append(snap.Food, p)
+	}
+	slices.SortFunc(snap.Food, func(a, b Pos) int {
+		if a.Y != b.Y {
+			return a.Y - b.Y
+		}
+		return a.X - b.X
+	})
+
+	for _, c := range w.creatures {
+		snap.Creatures = append(snap.Creatures, CreatureState{
+			ID:       c.ID,
+			Name:     c.Animal.GetName(),
+			Species:  c.Animal.Species(),
+			Behavior: c.Behavior.Name(),
+			Pos:      c.Pos,
+			Energy:   c.Energy,
+			Age:      c.Age,
+		})
+	}
+	return snap
+}
+
+// JSONLines returns an observer for World.Run that writes each snapshot
+// to out as one line of JSON.
+func JSONLines(out io.Writer) func(Snapshot) error {
+	encoder := json.NewEncoder(out)
+	return func(s Snapshot) error {
+		return encoder.Encode(s)
+	}
+}
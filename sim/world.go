@@ -0,0 +1,281 @@
+package sim
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+)
+
+// Pos is a cell on the grid. (0, 0) is the top-left corner.
+type Pos struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// Config describes a world. Zero values for the food settings disable
+// food entirely.
+type Config struct {
+	Width        int
+	Height       int
+	Seed         uint64
+	InitialFood  int     // food cells placed before the first tick
+	FoodEnergy   int     // energy gained by eating one food cell
+	FoodRegrowth float64 // chance per empty cell per tick that food appears
+}
+
+// Creature is an animal placed in the world together with its state.
+type Creature struct {
+	ID       int
+	Animal   Animal
+	Behavior Behavior
+	Pos      Pos
+	Energy   int
+	Age      int
+}
+
+// World is the grid plus everything living on it. It is not safe for
+// concurrent use.
+type World struct {
+	config    Config
+	rng       *rand.Rand
+	tick      int
+	nextID    int
+	food      map[Pos]bool
+	creatures []*Creature // always sorted by ID
+	events    []Event     // events from the most recent tick
+}
+
+// NewWorld creates a world and scatters its initial food.
+func NewWorld(cfg Config) (*World, error) {
+	if cfg.Width <= 0 || cfg.Height <= 0 {
+		return nil, fmt.Errorf("sim: grid must be at least 1x1, got %dx%d", cfg.Width, cfg.Height)
+	}
+	if cfg.FoodRegrowth < 0 || cfg.FoodRegrowth > 1 {
+		return nil, fmt.Errorf("sim: food regrowth must be between 0 and 1, got %g", cfg.FoodRegrowth)
+	}
+	if cfg.InitialFood > cfg.Width*cfg.Height {
+		return nil, fmt.Errorf("sim: %d food cells don't fit on a %dx%d grid", cfg.InitialFood, cfg.Width, cfg.Height)
+	}
+
+	w := &World{
+		config: cfg,
+		rng:    rand.New(rand.NewPCG(cfg.Seed, cfg.Seed^0x9e3779b97f4a7c15)),
+		nextID: 1,
+		food:   make(map[Pos]bool),
+	}
+	for len(w.food) < cfg.InitialFood {
+		w.food[Pos{w.rng.IntN(cfg.Width), w.rng.IntN(cfg.Height)}] = true
+	}
+	return w, nil
+}
+
+// Spawn places an animal at pos with its species' starting energy and
+// returns the new creature's ID.
+func (w *World) Spawn(a Animal, b Behavior, pos Pos) (int, error) {
+	if a == nil {
+		return 0, errors.New("sim: cannot spawn a nil animal")
+	}
+	if b == nil {
+		return 0, fmt.Errorf("sim: %s needs a behavior", describe(a))
+	}
+	if !w.inBounds(pos) {
+		return 0, fmt.Errorf("sim: position %v is outside the %dx%d grid", pos, w.config.Width, w.config.Height)
+	}
+	if a.Traits().StartEnergy <= 0 {
+		return 0, fmt.Errorf("sim: %s has no starting energy", describe(a))
+	}
+	return w.add(a, b, pos).ID, nil
+}
+
+// Tick returns the number of completed ticks.
+func (w *World) Tick() int {
+	return w.tick
+}
+
+// Creatures returns copies of the living creatures in ID order.
+func (w *World) Creatures() []Creature {
+	out := make([]Creature, len(w.creatures))
+	for i, c := range w.creatures {
+		out[i] = *c
+	}
+	return out
+}
+
+// FoodAt reports whether pos holds food.
+func (w *World) FoodAt(pos Pos) bool {
+	return w.food[pos]
+}
+
+// NearestFood finds the closest food within radius (Manhattan distance).
+// Ties are broken by row, then column, so the result is deterministic.
+func (w *World) NearestFood(from Pos, radius int) (Pos, bool) {
+	best, found := Pos{}, false
+	bestDist := radius + 1
+	for y := max(0, from.Y-radius); y <= min(w.config.Height-1, from.Y+radius); y++ {
+		for x := max(0, from.X-radius); x <= min(w.config.Width-1, from.X+radius); x++ {
+			p := Pos{x, y}
+			if d := distance(from, p); w.food[p] && d < bestDist {
+				best, bestDist, found = p, d, true
+			}
+		}
+	}
+	return best, found
+}
+
+// NearestCreature finds the closest creature within radius that matches.
+// Ties go to the lowest ID.
+func (w *World) NearestCreature(from Pos, radius int, match func(*Creature) bool) (*Creature, bool) {
+	var best *Creature
+	bestDist := radius + 1
+	for _, c := range w.creatures {
+		if d := distance(from, c.Pos); d < bestDist && match(c) {
+			best, bestDist = c, d
+		}
+	}
+	return best, best != nil
+}
+
+// Step advances the world by one tick and returns its snapshot.
+//
+// Within a tick every creature alive at the start acts once, in ID order:
+// it moves (or stays), pays the energy cost, eats any food on its new
+// cell and meets whoever is already there. Then same-species pairs that
+// share a cell and have enough energy breed, creatures out of energy die,
+// and food regrows.
+func (w *World) Step() Snapshot {
+	w.tick++
+	w.events = nil
+
+	acting := slices.Clone(w.creatures)
+	for _, c := range acting {
+		w.act(c)
+	}
+	w.breed()
+	w.bury()
+	w.regrow()
+
+	return w.Snapshot()
+}
+
+// Run advances the world by ticks, passing each snapshot to observe. It
+// stops early if observe returns an error or every creature has died.
+func (w *World) Run(ticks int, observe func(Snapshot) error) error {
+	for range ticks {
+		snap := w.Step()
+		if observe != nil {
+			if err := observe(snap); err != nil {
+				return err
+			}
+		}
+		if len(w.creatures) == 0 {
+			return nil
+		}
+	}
+	return nil
+}
+
+func (w *World) act(c *Creature) {
+	traits := c.Animal.Traits()
+	dir := c.Behavior.Decide(w, c, w.rng)
+
+	next := Pos{c.Pos.X + dir.DX, c.Pos.Y + dir.DY}
+	if dir == Stay || !w.inBounds(next) {
+		c.Energy--
+	} else {
+		c.Pos = next
+		c.Energy -= traits.MoveCost
+	}
+	c.Age++
+
+	if w.food[c.Pos] && c.Energy > 0 {
+		delete(w.food, c.Pos)
+		c.Energy = min(c.Energy+w.config.FoodEnergy, traits.MaxEnergy)
+		w.record(EventEat, fmt.Sprintf("%s eats", describe(c.Animal)), c.ID)
+	}
+
+	for _, other := range w.creatures {
+		if other.ID != c.ID && other.Pos == c.Pos && other.Energy > 0 &&
+			other.Animal.Species() != c.Animal.Species() {
+			w.record(EventMeet, fmt.Sprintf("%s says %s to %s, who replies %s",
+				describe(c.Animal), c.Animal.Speak(), describe(other.Animal), other.Animal.Speak()), c.ID, other.ID)
+		}
+	}
+}
+
+func (w *World) breed() {
+	bred := make(map[int]bool)
+	var births []*Creature
+
+	for i, a := range w.creatures {
+		for _, b := range w.creatures[i+1:] {
+			if bred[a.ID] || bred[b.ID] || a.Pos != b.Pos ||
+				a.Animal.Species() != b.Animal.Species() {
+				continue
+			}
+			breeder, ok := a.Animal.(Breeder)
+			need := a.Animal.Traits().BreedEnergy
+			if !ok || need <= 0 || a.Energy < need || b.Energy < need {
+				continue
+			}
+
+			cost := need / 2
+			a.Energy -= cost
+			b.Energy -= cost
+			bred[a.ID], bred[b.ID] = true, true
+
+			name := fmt.Sprintf("%s-%d", a.Animal.GetName(), w.nextID)
+			child := &Creature{ID: w.nextID, Animal: breeder.Offspring(name), Behavior: a.Behavior, Pos: a.Pos}
+			child.Energy = child.Animal.Traits().StartEnergy
+			w.nextID++
+			births = append(births, child)
+			w.record(EventBirth, fmt.Sprintf("%s and %s have %s",
+				describe(a.Animal), describe(b.Animal), describe(child.Animal)), a.ID, b.ID, child.ID)
+		}
+	}
+
+	w.creatures = append(w.creatures, births...)
+}
+
+func (w *World) bury() {
+	w.creatures = slices.DeleteFunc(w.creatures, func(c *Creature) bool {
+		if c.Energy > 0 {
+			return false
+		}
+		w.record(EventDeath, fmt.Sprintf("%s runs out of energy", describe(c.Animal)), c.ID)
+		return true
+	})
+}
+
+func (w *World) regrow() {
+	if w.config.FoodRegrowth == 0 {
+		return
+	}
+	for y := range w.config.Height {
+		for x := range w.config.Width {
+			p := Pos{x, y}
+			if !w.food[p] && w.rng.Float64() < w.config.FoodRegrowth {
+				w.food[p] = true
+			}
+		}
+	}
+}
+
+func (w *World) add(a Animal, b Behavior, pos Pos) *Creature {
+	c := &Creature{ID: w.nextID, Animal: a, Behavior: b, Pos: pos, Energy: a.Traits().StartEnergy}
+	w.nextID++
+	w.creatures = append(w.creatures, c)
+	return c
+}
+
+func (w *World) record(kind EventKind, message string, ids ...int) {
+	w.events = append(w.events, Event{Tick: w.tick, Kind: kind, Creatures: ids, Message: message})
+}
+
+func (w *World) inBounds(p Pos) bool {
+	return p.X >= 0 && p.Y >= 0 && p.X < w.config.Width && p.Y < w.config.Height
+}
+
+func distance(a, b Pos) int {
+	return abs(a.X-b.X) + abs(a.Y-b.Y)
+}
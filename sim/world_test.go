@@ -0,0 +1,115 @@
+package sim
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// newTestWorld returns a small, busy world: plenty of food, pairs that
+// can breed and every kind of behavior.
+func newTestWorld(t *testing.T, seed uint64) *World {
+	t.Helper()
+	w, err := NewWorld(Config{Width: 12, Height: 8, Seed: seed, InitialFood: 20, FoodEnergy: 5, FoodRegrowth: 0.02})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []struct {
+		a   Animal
+		b   Behavior
+		pos Pos
+	}{
+		{Dog{Name: "Rex", Breed: "Collie"}, SeekFood{}, Pos{0, 0}},
+		{Dog{Name: "Fido", Breed: "Beagle"}, Flock{}, Pos{11, 7}},
+		{Cat{Name: "Tom", Color: "Grey"}, RandomWalk{}, Pos{5, 4}},
+		{Rabbit{Name: "Bun"}, SeekFood{}, Pos{6, 4}},
+		{Rabbit{Name: "Hop"}, Flock{}, Pos{6, 5}},
+	} {
+		if _, err := w.Spawn(s.a, s.b, s.pos); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return w
+}
+
+// run runs w for ticks and returns its JSON Lines output.
+func run(t *testing.T, w *World, ticks int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := w.Run(ticks, JSONLines(&buf)); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestSameSeedSameRun(t *testing.T) {
+	first := run(t, newTestWorld(t, 42), 60)
+	second := run(t, newTestWorld(t, 42), 60)
+	if !bytes.Equal(first, second) {
+		t.Fatal("two runs with seed 42 differ")
+	}
+
+	// the run is only a useful check if things happened in it
+	kinds := make(map[EventKind]bool)
+	for line := range bytes.Lines(first) {
+		var s Snapshot
+		if err := json.Unmarshal(line, &s); err != nil {
+			t.Fatal(err)
+		}
+		for _, e := range s.Events {
+			kinds[e.Kind] = true
+		}
+	}
+	for _, k := range []EventKind{EventEat, EventMeet, EventBirth} {
+		if !kinds[k] {
+			t.Errorf("no %s events in 60 ticks", k)
+		}
+	}
+
+	if other := run(t, newTestWorld(t, 43), 60); bytes.Equal(first, other) {
+		t.Error("seeds 42 and 43 gave the same run")
+	}
+}
+
+func TestSnapshotJSON(t *testing.T) {
+	w := newTestWorld(t, 7)
+	var snaps []Snapshot
+	var buf bytes.Buffer
+	write := JSONLines(&buf)
+	err := w.Run(30, func(s Snapshot) error {
+		snaps = append(snaps, s)
+		return write(s)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var i int
+	for line := range bytes.Lines(buf.Bytes()) {
+		var back Snapshot
+		if err := json.Unmarshal(line, &back); err != nil {
+			t.Fatalf("line %d: %v", i+1, err)
+		}
+		if i >= len(snaps) || !reflect.DeepEqual(back, snaps[i]) {
+			t.Fatalf("line %d reads back as\n%+v\nwant\n%+v", i+1, back, snaps[i])
+		}
+		i++
+	}
+	if i != len(snaps) {
+		t.Errorf("%d lines for %d snapshots", i, len(snaps))
+	}
+
+	// an empty world still encodes empty lists, not null
+	empty, err := NewWorld(Config{Width: 1, Height: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := json.Marshal(empty.Snapshot())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"tick":0,"width":1,"height":1,"food":[],"creatures":[],"events":[]}`; string(raw) != want {
+		t.Errorf("empty world = %s, want %s", raw, want)
+	}
+}
@@ -0,0 +1,57 @@
+package sorting
+
+import "cmp"
+
+// Comparator compares two values the way cmp.Compare does: negative when
+// a sorts first, positive when b does and zero when they tie. Comparators
+// chain into multi-key orderings:
+//
+//	byCityThenAge := sorting.Ascending(func(s Student) string { return s.City }).
+//		Then(sorting.Descending(func(s Student) int { return s.Age }))
+//	sorting.StableFunc(students, byCityThenAge.Less)
+type Comparator[T any] func(a, b T) int
+
+// Ascending orders values by key, smallest first.
+func Ascending[T any, K cmp.Ordered](key func(T) K) Comparator[T] {
+	return func(a, b T) int {
+		return cmp.Compare(key(a), key(b))
+	}
+}
+
+// Descending orders values by key, largest first.
+func Descending[T any, K cmp.Ordered](key func(T) K) Comparator[T] {
+	return func(a, b T) int {
+		return cmp.Compare(key(b), key(a))
+	}
+}
+
+// Chain compares by each comparator in turn until one of them breaks the
+// tie.
+func Chain[T any](comparators ...Comparator[T]) Comparator[T] {
+	return func(a, b T) int {
+		for _, c := range comparators {
+			if r := c(a, b); r != 0 {
+				return r
+			}
+		}
+		return 0
+	}
+}
+
+// Then is shorthand for Chain(c, next).
+func (c Comparator[T]) Then(next Comparator[T]) Comparator[T] {
+	return Chain(c, next)
+}
+
+// Reverse flips the order.
+func (c Comparator[T]) Reverse() Comparator[T] {
+	return func(a, b T) int {
+		return c(b, a)
+	}
+}
+
+// Less adapts the comparator to the less functions taken by SortFunc,
+// StableFunc and TopKFunc.
+func (c Comparator[T]) Less(a, b T) bool {
+	return c(a, b) < 0
+}
@@ -0,0 +1,232 @@
+package sorting
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Codec serializes records to and from the temporary run files written by
+// ExternalSorter. Decoders must return io.EOF once a stream is exhausted.
+type Codec[T any] interface {
+	NewEncoder(w io.Writer) func(T) error
+	NewDecoder(r io.Reader) func() (T, error)
+}
+
+// LineCodec stores strings one per line. Strings containing a newline
+// can't be represented and are rejected.
+type LineCodec struct{}
+
+func (LineCodec) NewEncoder(w io.Writer) func(string) error {
+	return func(s string) error {
+		if strings.ContainsRune(s, '\n') {
+			return fmt.Errorf("sorting: LineCodec can't store %q: it contains a newline", s)
+		}
+		_, err := io.WriteString(w, s+"\n")
+		return err
+	}
+}
+
+func (LineCodec) NewDecoder(r io.Reader) func() (string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	return func() (string, error) {
+		if scanner.Scan() {
+			return scanner.Text(), nil
+		}
+		if err := scanner.Err(); err != nil {
+			return "", err
+		}
+		return "", io.EOF
+	}
+}
+
+// JSONCodec stores any JSON-encodable type as JSON Lines.
+type JSONCodec[T any] struct{}
+
+func (JSONCodec[T]) NewEncoder(w io.Writer) func(T) error {
+	encoder := json.NewEncoder(w)
+	return func(v T) error {
+		return encoder.Encode(v)
+	}
+}
+
+func (JSONCodec[T]) NewDecoder(r io.Reader) func() (T, error) {
+	decoder := json.NewDecoder(r)
+	return func() (T, error) {
+		var v T
+		err := decoder.Decode(&v)
+		return v, err
+	}
+}
+
+// ExternalSorter sorts streams too large to hold in memory. It reads up to
+// MaxInMemory records at a time, sorts each batch with StableFunc and
+// writes it to a temporary "run" file, then merges the runs. The result
+// is stable: equal records come out in input order.
+type ExternalSorter[T any] struct {
+	Less        func(a, b T) bool
+	Codec       Codec[T]
+	MaxInMemory int    // records per run; defaults to 100,000
+	TempDir     string // where run files go; defaults to os.TempDir()
+}
+
+// Sort reads records from next until it returns io.EOF and passes them to
+// emit in sorted order. Temporary files are removed before Sort returns.
+func (s *ExternalSorter[T]) Sort(next func() (T, error), emit func(T) error) error {
+	if s.Less == nil || s.Codec == nil {
+		return errors.New("sorting: ExternalSorter needs Less and Codec")
+	}
+	limit := s.MaxInMemory
+	if limit <= 0 {
+		limit = 100_000
+	}
+
+	var runs []string
+	defer func() {
+		for _, name := range runs {
+			os.Remove(name)
+		}
+	}()
+
+	batch := make([]T, 0, min(limit, 1024))
+	for {
+		v, err := next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		batch = append(batch, v)
+		if len(batch) == limit {
+			name, err := s.writeRun(batch)
+			if err != nil {
+				return err
+			}
+			runs = append(runs, name)
+			batch = batch[:0]
+		}
+	}
+
+	// Everything fit in one batch: no need to touch the disk.
+	if len(runs) == 0 {
+		StableFunc(batch, s.Less)
+		for _, v := range batch {
+			if err := emit(v); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if len(batch) > 0 {
+		name, err := s.writeRun(batch)
+		if err != nil {
+			return err
+		}
+		runs = append(runs, name)
+	}
+	return s.mergeRuns(runs, emit)
+}
+
+// writeRun sorts batch and writes it to a new run file.
+func (s *ExternalSorter[T]) writeRun(batch []T) (name string, err error) {
+	StableFunc(batch, s.Less)
+
+	f, err := os.CreateTemp(s.TempDir, "sorting-run-*")
+	if err != nil {
+		return "", err
+	}
+	path := f.Name()
+	defer func() {
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(path)
+			name = ""
+		}
+	}()
+
+	w := bufio.NewWriter(f)
+	encode := s.Codec.NewEncoder(w)
+	for _, v := range batch {
+		if err := encode(v); err != nil {
+			return path, err
+		}
+	}
+	return path, w.Flush()
+}
+
+// runHead is the next unread record of one run file.
+type runHead[T any] struct {
+	value  T
+	run    int
+	decode func() (T, error)
+}
+
+// mergeRuns does a k-way merge with a min-heap of run heads. Ties are
+// broken by run index; runs hold consecutive slices of the input, so that
+// keeps the merge stable.
+func (s *ExternalSorter[T]) mergeRuns(runs []string, emit func(T) error) error {
+	var heads []runHead[T]
+	for i, name := range runs {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		decode := s.Codec.NewDecoder(bufio.NewReader(f))
+		v, err := decode()
+		if err == io.EOF {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("sorting: reading run %d: %w", i, err)
+		}
+		heads = append(heads, runHead[T]{value: v, run: i, decode: decode})
+	}
+
+	less := func(a, b runHead[T]) bool {
+		if s.Less(a.value, b.value) {
+			return true
+		}
+		if s.Less(b.value, a.value) {
+			return false
+		}
+		return a.run < b.run
+	}
+
+	// A min-heap is a max-heap with the comparison flipped.
+	greater := func(a, b runHead[T]) bool { return less(b, a) }
+	for i := len(heads)/2 - 1; i >= 0; i-- {
+		siftDownFunc(heads, greater, i, len(heads))
+	}
+
+	for len(heads) > 0 {
+		if err := emit(heads[0].value); err != nil {
+			return err
+		}
+
+		v, err := heads[0].decode()
+		switch {
+		case err == io.EOF:
+			last := len(heads) - 1
+			heads[0] = heads[last]
+			heads = heads[:last]
+		case err != nil:
+			return fmt.Errorf("sorting: reading run %d: %w", heads[0].run, err)
+		default:
+			heads[0].value = v
+		}
+		siftDownFunc(heads, greater, 0, len(heads))
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package sorting
+
+import (
+	"errors"
+	"io"
+	"math/rand/v2"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// record is a sort key plus the position it had in the input, to check
+// that sorts are stable. Its fields are exported for JSONCodec.
+type record struct {
+	Key, Order int
+}
+
+// from returns a next function for ExternalSorter.Sort that yields s.
+func from[T any](s []T) func() (T, error) {
+	return func() (T, error) {
+		var zero T
+		if len(s) == 0 {
+			return zero, io.EOF
+		}
+		v := s[0]
+		s = s[1:]
+		return v, nil
+	}
+}
+
+// assertNoRuns fails if any run file is left in dir.
+func assertNoRuns(t *testing.T, dir string) {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		t.Errorf("run file left behind: %s", e.Name())
+	}
+}
+
+func TestExternalSorterStable(t *testing.T) {
+	rng := rand.New(rand.NewPCG(3, 3))
+	records := make([]record, 1000)
+	for i := range records {
+		records[i] = record{Key: rng.IntN(20), Order: i}
+	}
+	want := slices.Clone(records)
+	StableFunc(want, func(a, b record) bool { return a.Key < b.Key })
+
+	for _, limit := range []int{1, 7, 100, 1000, 5000} {
+		dir := t.TempDir()
+		sorter := &ExternalSorter[record]{
+			Less:        func(a, b record) bool { return a.Key < b.Key },
+			Codec:       JSONCodec[record]{},
+			MaxInMemory: limit,
+			TempDir:     dir,
+		}
+		var got []record
+		err := sorter.Sort(from(records), func(r record) error {
+			got = append(got, r)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("MaxInMemory %d: %v", limit, err)
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("MaxInMemory %d: output is not the stable order", limit)
+		}
+		assertNoRuns(t, dir)
+	}
+}
+
+func TestExternalSorterCleansUpOnError(t *testing.T) {
+	lines := strings.Fields("pear apple fig kiwi plum date lime")
+	emitErr := errors.New("emit failed")
+	tests := []struct {
+		name  string
+		input []string
+		emit  func(string) error
+		want  string
+	}{
+		{"encode error in first run", append([]string{"bad\nline"}, lines...), nil, "contains a newline"},
+		{"encode error in later run", append(slices.Clone(lines), "bad\nline"), nil, "contains a newline"},
+		{"emit error during merge", lines, func(string) error { return emitErr }, emitErr.Error()},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			sorter := &ExternalSorter[string]{
+				Less:        func(a, b string) bool { return a < b },
+				Codec:       LineCodec{},
+				MaxInMemory: 3,
+				TempDir:     dir,
+			}
+			emit := tc.emit
+			if emit == nil {
+				emit = func(string) error { return nil }
+			}
+			err := sorter.Sort(from(tc.input), emit)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("got error %v, want one containing %q", err, tc.want)
+			}
+			assertNoRuns(t, dir)
+		})
+	}
+}
@@ -0,0 +1,392 @@
+// Package sorting replaces the bubble sorts from chapter 8 (sortInts and
+// sortStrings) with O(n log n) algorithms that work for any element type.
+//
+// There are three ways in:
+//
+//   - Sort and friends take a []T of a cmp.Ordered type and compare with
+//     the < operator directly. This is the fast path.
+//   - SortFunc, StableFunc, PartialSortFunc and TopKFunc take a less
+//     function, which Comparator builds from one or more sort keys.
+//   - SortSortable takes anything implementing the chapter 8 Sortable
+//     interface (Len, Less, Swap).
+//
+// Unstable sorts use introsort: quicksort with median-of-three pivots,
+// falling back to heapsort when recursion gets too deep and to insertion
+// sort for short ranges. Stable sorts use a buffered merge sort. Data that
+// doesn't fit in memory can be sorted with ExternalSorter.
+package sorting
+
+import (
+	"cmp"
+	"math/bits"
+)
+
+// insertionThreshold is the range length below which insertion sort beats
+// partitioning.
+const insertionThreshold = 12
+
+// Sortable is the Less-based interface from chapter 8. It matches the
+// standard library's sort.Interface.
+type Sortable interface {
+	Len() int
+	Less(i, j int) bool
+	Swap(i, j int)
+}
+
+// Sort sorts s in ascending order. NaNs are ordered before other floats,
+// as in cmp.Compare. The sort is not stable.
+func Sort[T cmp.Ordered](s []T) {
+	if presorted(s, lessOrdered[T]) {
+		return
+	}
+	introsortOrdered(s, maxDepth(len(s)))
+}
+
+// SortFunc sorts s so that less(s[i], s[j]) never holds for i > j. The
+// sort is not stable.
+func SortFunc[T any](s []T, less func(a, b T) bool) {
+	if presorted(s, less) {
+		return
+	}
+	introsortFunc(s, less, maxDepth(len(s)))
+}
+
+// SortSortable sorts data with the same introsort used by SortFunc.
+func SortSortable(data Sortable) {
+	n := data.Len()
+	introsortSortable(data, 0, n, maxDepth(n))
+}
+
+// IsSorted reports whether s is in ascending order.
+func IsSorted[T cmp.Ordered](s []T) bool {
+	for i := 1; i < len(s); i++ {
+		if lessOrdered(s[i], s[i-1]) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSortedFunc reports whether s is sorted according to less.
+func IsSortedFunc[T any](s []T, less func(a, b T) bool) bool {
+	for i := 1; i < len(s); i++ {
+		if less(s[i], s[i-1]) {
+			return false
+		}
+	}
+	return true
+}
+
+// presorted handles input that is already ascending, or strictly
+// descending, in a single pass. It reports whether s is now sorted. The
+// scan stops at the first element that fits neither pattern, so random
+// input pays only a couple of comparisons.
+func presorted[T any](s []T, less func(a, b T) bool) bool {
+	if len(s) < 2 {
+		return true
+	}
+
+	if !less(s[1], s[0]) {
+		for i := 2; i < len(s); i++ {
+			if less(s[i], s[i-1]) {
+				return false
+			}
+		}
+		return true
+	}
+
+	for i := 2; i < len(s); i++ {
+		if !less(s[i], s[i-1]) {
+			return false
+		}
+	}
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+	return true
+}
+
+// maxDepth is the recursion budget before introsort switches to heapsort.
+func maxDepth(n int) int {
+	if n == 0 {
+		return 0
+	}
+	return 2 * bits.Len(uint(n))
+}
+
+// lessOrdered is cmp.Less written out so the compiler can inline it.
+func lessOrdered[T cmp.Ordered](a, b T) bool {
+	return a < b || (a != a && b == b)
+}
+
+// ---------------------------------------------------------------------------
+// Ordered fast path
+// ---------------------------------------------------------------------------
+
+func introsortOrdered[T cmp.Ordered](s []T, depth int) {
+	for len(s) > insertionThreshold {
+		if depth == 0 {
+			heapsortOrdered(s)
+			return
+		}
+		depth--
+
+		lt, gt := partitionOrdered(s)
+		// Recurse into the smaller side and loop on the larger one so the
+		// stack stays O(log n).
+		if lt < len(s)-gt {
+			introsortOrdered(s[:lt], depth)
+			s = s[gt:]
+		} else {
+			introsortOrdered(s[gt:], depth)
+			s = s[:lt]
+		}
+	}
+	insertionSortOrdered(s)
+}
+
+// partitionOrdered is a three-way partition around the median of the
+// first, middle and last elements. Afterwards s[:lt] sorts before the
+// pivot, s[lt:gt] equals it and s[gt:] sorts after it, so runs of
+// duplicates don't degrade to quadratic time.
+func partitionOrdered[T cmp.Ordered](s []T) (lt, gt int) {
+	a, b, c := s[0], s[len(s)/2], s[len(s)-1]
+	if lessOrdered(b, a) {
+		a, b = b, a
+	}
+	if lessOrdered(c, b) {
+		b = c
+		if lessOrdered(b, a) {
+			b = a
+		}
+	}
+	pivot := b
+
+	lt, gt = 0, len(s)
+	for i := 0; i < gt; {
+		switch {
+		case lessOrdered(s[i], pivot):
+			s[lt], s[i] = s[i], s[lt]
+			lt++
+			i++
+		case lessOrdered(pivot, s[i]):
+			gt--
+			s[i], s[gt] = s[gt], s[i]
+		default:
+			i++
+		}
+	}
+	return lt, gt
+}
+
+func insertionSortOrdered[T cmp.Ordered](s []T) {
+	for i := 1; i < len(s); i++ {
+		for j := i; j > 0 && lessOrdered(s[j], s[j-1]); j-- {
+			s[j], s[j-1] = s[j-1], s[j]
+		}
+	}
+}
+
+func heapsortOrdered[T cmp.Ordered](s []T) {
+	n := len(s)
+	for i := n/2 - 1; i >= 0; i-- {
+		siftDownOrdered(s, i, n)
+	}
+	for end := n - 1; end > 0; end-- {
+		s[0], s[end] = s[end], s[0]
+		siftDownOrdered(s, 0, end)
+	}
+}
+
+func siftDownOrdered[T cmp.Ordered](s []T, root, n int) {
+	for {
+		child := 2*root + 1
+		if child >= n {
+			return
+		}
+		if child+1 < n && lessOrdered(s[child], s[child+1]) {
+			child++
+		}
+		if !lessOrdered(s[root], s[child]) {
+			return
+		}
+		s[root], s[child] = s[child], s[root]
+		root = child
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Less-function path
+// ---------------------------------------------------------------------------
+
+func introsortFunc[T any](s []T, less func(a, b T) bool, depth int) {
+	for len(s) > insertionThreshold {
+		if depth == 0 {
+			heapsortFunc(s, less)
+			return
+		}
+		depth--
+
+		lt, gt := partitionFunc(s, less)
+		if lt < len(s)-gt {
+			introsortFunc(s[:lt], less, depth)
+			s = s[gt:]
+		} else {
+			introsortFunc(s[gt:], less, depth)
+			s = s[:lt]
+		}
+	}
+	insertionSortFunc(s, less)
+}
+
+// partitionFunc is a three-way partition around the median of the
+// first, middle and last elements. Afterwards s[:lt] sorts before the
+// pivot, s[lt:gt] equals it and s[gt:] sorts after it, so runs of
+// duplicates don't degrade to quadratic time.
+func partitionFunc[T any](s []T, less func(a, b T) bool) (lt, gt int) {
+	a, b, c := s[0], s[len(s)/2], s[len(s)-1]
+	if less(b, a) {
+		a, b = b, a
+	}
+	if less(c, b) {
+		b = c
+		if less(b, a) {
+			b = a
+		}
+	}
+	pivot := b
+
+	lt, gt = 0, len(s)
+	for i := 0; i < gt; {
+		switch {
+		case less(s[i], pivot):
+			s[lt], s[i] = s[i], s[lt]
+			lt++
+			i++
+		case less(pivot, s[i]):
+			gt--
+			s[i], s[gt] = s[gt], s[i]
+		default:
+			i++
+		}
+	}
+	return lt, gt
+}
+
+func insertionSortFunc[T any](s []T, less func(a, b T) bool) {
+	for i := 1; i < len(s); i++ {
+		for j := i; j > 0 && less(s[j], s[j-1]); j-- {
+			s[j], s[j-1] = s[j-1], s[j]
+		}
+	}
+}
+
+func heapsortFunc[T any](s []T, less func(a, b T) bool) {
+	n := len(s)
+	for i := n/2 - 1; i >= 0; i-- {
+		siftDownFunc(s, less, i, n)
+	}
+	for end := n - 1; end > 0; end-- {
+		s[0], s[end] = s[end], s[0]
+		siftDownFunc(s, less, 0, end)
+	}
+}
+
+func siftDownFunc[T any](s []T, less func(a, b T) bool, root, n int) {
+	for {
+		child := 2*root + 1
+		if child >= n {
+			return
+		}
+		if child+1 < n && less(s[child], s[child+1]) {
+			child++
+		}
+		if !less(s[root], s[child]) {
+			return
+		}
+		s[root], s[child] = s[child], s[root]
+		root = child
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Sortable path
+// ---------------------------------------------------------------------------
+
+func introsortSortable(data Sortable, lo, hi, depth int) {
+	for hi-lo > insertionThreshold {
+		if depth == 0 {
+			heapsortSortable(data, lo, hi)
+			return
+		}
+		depth--
+
+		p := partitionSortable(data, lo, hi)
+		if p-lo < hi-p {
+			introsortSortable(data, lo, p, depth)
+			lo = p + 1
+		} else {
+			introsortSortable(data, p+1, hi, depth)
+			hi = p
+		}
+	}
+	for i := lo + 1; i < hi; i++ {
+		for j := i; j > lo && data.Less(j, j-1); j-- {
+			data.Swap(j, j-1)
+		}
+	}
+}
+
+func partitionSortable(data Sortable, lo, hi int) int {
+	last := hi - 1
+	mid := lo + (hi-lo)/2
+
+	if data.Less(mid, lo) {
+		data.Swap(mid, lo)
+	}
+	if data.Less(last, lo) {
+		data.Swap(last, lo)
+	}
+	if data.Less(mid, last) {
+		data.Swap(mid, last)
+	}
+
+	// The pivot stays at last, so compare against that index.
+	i := lo
+	for j := lo; j < last; j++ {
+		if data.Less(j, last) {
+			data.Swap(i, j)
+			i++
+		}
+	}
+	data.Swap(i, last)
+	return i
+}
+
+func heapsortSortable(data Sortable, lo, hi int) {
+	n := hi - lo
+	for i := n/2 - 1; i >= 0; i-- {
+		siftDownSortable(data, lo, i, n)
+	}
+	for end := n - 1; end > 0; end-- {
+		data.Swap(lo, lo+end)
+		siftDownSortable(data, lo, 0, end)
+	}
+}
+
+func siftDownSortable(data Sortable, lo, root, n int) {
+	for {
+		child := 2*root + 1
+		if child >= n {
+			return
+		}
+		if child+1 < n && data.Less(lo+child, lo+child+1) {
+			child++
+		}
+		if !data.Less(lo+root, lo+child) {
+			return
+		}
+		data.Swap(lo+root, lo+child)
+		root = child
+	}
+}
@@ -0,0 +1,236 @@
+package sorting
+
+import (
+	"cmp"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+// inputs are the orderings the sorts are tested and benchmarked on. The
+// sorted and reversed cases take the presorted fast path; few-unique
+// exercises the three-way partition.
+var inputs = []struct {
+	name string
+	make func(n int, rng *rand.Rand) []int
+}{
+	{"random", func(n int, rng *rand.Rand) []int {
+		s := make([]int, n)
+		for i := range s {
+			s[i] = rng.Int()
+		}
+		return s
+	}},
+	{"sorted", func(n int, rng *rand.Rand) []int {
+		s := make([]int, n)
+		for i := range s {
+			s[i] = i
+		}
+		return s
+	}},
+	{"reversed", func(n int, rng *rand.Rand) []int {
+		s := make([]int, n)
+		for i := range s {
+			s[i] = n - i
+		}
+		return s
+	}},
+	{"few-unique", func(n int, rng *rand.Rand) []int {
+		s := make([]int, n)
+		for i := range s {
+			s[i] = rng.IntN(8)
+		}
+		return s
+	}},
+	{"sawtooth", func(n int, rng *rand.Rand) []int {
+		s := make([]int, n)
+		for i := range s {
+			s[i] = i % 50
+		}
+		return s
+	}},
+}
+
+var sorters = []struct {
+	name string
+	sort func([]int)
+}{
+	{"Sort", Sort[int]},
+	{"SortFunc", func(s []int) { SortFunc(s, func(a, b int) bool { return a < b }) }},
+	{"StableFunc", func(s []int) { StableFunc(s, func(a, b int) bool { return a < b }) }},
+	{"SortSortable", func(s []int) { SortSortable(intSlice(s)) }},
+}
+
+type intSlice []int
+
+func (s intSlice) Len() int           { return len(s) }
+func (s intSlice) Less(i, j int) bool { return s[i] < s[j] }
+func (s intSlice) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+func TestSortersMatchSlicesSort(t *testing.T) {
+	for _, in := range inputs {
+		for _, n := range []int{0, 1, 2, 3, 11, 12, 13, 100, 1000} {
+			original := in.make(n, rand.New(rand.NewPCG(1, uint64(n))))
+			want := slices.Clone(original)
+			slices.Sort(want)
+			for _, s := range sorters {
+				got := slices.Clone(original)
+				s.sort(got)
+				if !slices.Equal(got, want) {
+					t.Errorf("%s on %d %s ints: got %v", s.name, n, in.name, got)
+				}
+			}
+		}
+	}
+}
+
+// TestHeapsortFallback starts introsort with no depth budget, and then
+// with a budget of one partition, so each variant falls back to heapsort
+// as it would on input that defeats median-of-three.
+func TestHeapsortFallback(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+	variants := []struct {
+		name string
+		sort func(s []int, depth int)
+	}{
+		{"introsortOrdered", func(s []int, depth int) { introsortOrdered(s, depth) }},
+		{"introsortFunc", func(s []int, depth int) { introsortFunc(s, less, depth) }},
+		{"introsortSortable", func(s []int, depth int) { introsortSortable(intSlice(s), 0, len(s), depth) }},
+	}
+	for _, in := range inputs {
+		for _, n := range []int{insertionThreshold + 1, 100, 1000} {
+			original := in.make(n, rand.New(rand.NewPCG(3, uint64(n))))
+			want := slices.Clone(original)
+			slices.Sort(want)
+			for _, v := range variants {
+				for _, depth := range []int{0, 1} {
+					got := slices.Clone(original)
+					v.sort(got, depth)
+					if !slices.Equal(got, want) {
+						t.Errorf("%s at depth %d on %d %s ints: got %v", v.name, depth, n, in.name, got)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestSortFloatsNaNFirst(t *testing.T) {
+	nan := func() float64 { var zero float64; return zero / zero }()
+	s := []float64{3, nan, 1, nan, 2}
+	Sort(s)
+	if !IsSorted(s) || s[0] == s[0] || s[1] == s[1] || !slices.Equal(s[2:], []float64{1, 2, 3}) {
+		t.Errorf("got %v, want NaNs first then 1 2 3", s)
+	}
+}
+
+func TestStableFuncKeepsTies(t *testing.T) {
+	rng := rand.New(rand.NewPCG(2, 2))
+	records := make([]record, 500)
+	for i := range records {
+		records[i] = record{Key: rng.IntN(10), Order: i}
+	}
+	StableFunc(records, func(a, b record) bool { return a.Key < b.Key })
+	for i := 1; i < len(records); i++ {
+		a, b := records[i-1], records[i]
+		if a.Key > b.Key || (a.Key == b.Key && a.Order > b.Order) {
+			t.Fatalf("records %d and %d out of order: %v %v", i-1, i, a, b)
+		}
+	}
+}
+
+func TestTopK(t *testing.T) {
+	s := []int{5, 1, 4, 1, 3, 9, 2}
+	tests := []struct {
+		k    int
+		want []int
+	}{
+		{-1, nil},
+		{0, nil},
+		{1, []int{1}},
+		{3, []int{1, 1, 2}},
+		{7, []int{1, 1, 2, 3, 4, 5, 9}},
+		{10, []int{1, 1, 2, 3, 4, 5, 9}},
+	}
+	for _, tc := range tests {
+		if got := TopK(s, tc.k); !slices.Equal(got, tc.want) {
+			t.Errorf("TopK(%d) = %v, want %v", tc.k, got, tc.want)
+		}
+	}
+	if !slices.Equal(s, []int{5, 1, 4, 1, 3, 9, 2}) {
+		t.Errorf("TopK modified its input: %v", s)
+	}
+}
+
+func TestPartialSortFunc(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+	for _, in := range inputs {
+		original := in.make(100, rand.New(rand.NewPCG(4, 4)))
+		want := slices.Clone(original)
+		slices.Sort(want)
+		for _, k := range []int{-1, 0, 1, 7, 50, 99, 100, 120} {
+			got := slices.Clone(original)
+			PartialSortFunc(got, k, less)
+
+			n := min(max(k, 0), len(got))
+			if !slices.Equal(got[:n], want[:n]) {
+				t.Errorf("%s, k=%d: first %d = %v, want %v", in.name, k, n, got[:n], want[:n])
+			}
+			if k <= 0 && !slices.Equal(got, original) {
+				t.Errorf("%s, k=%d: changed the slice to %v", in.name, k, got)
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, want) {
+				t.Errorf("%s, k=%d: result is not a permutation of the input", in.name, k)
+			}
+		}
+	}
+}
+
+func TestComparatorChain(t *testing.T) {
+	type student struct {
+		City string
+		Age  int
+	}
+	students := []student{{"Oslo", 20}, {"Bergen", 30}, {"Oslo", 25}, {"Bergen", 22}}
+	byCityThenAge := Ascending(func(s student) string { return s.City }).
+		Then(Descending(func(s student) int { return s.Age }))
+	StableFunc(students, byCityThenAge.Less)
+	want := []student{{"Bergen", 30}, {"Bergen", 22}, {"Oslo", 25}, {"Oslo", 20}}
+	if !slices.Equal(students, want) {
+		t.Errorf("got %v, want %v", students, want)
+	}
+}
+
+// BenchmarkSort compares the package with the standard library on each
+// input ordering and size:
+//
+//	go test ./sorting -run '^$' -bench Sort
+func BenchmarkSort(b *testing.B) {
+	contenders := []struct {
+		name string
+		sort func([]int)
+	}{
+		{"sorting.Sort", Sort[int]},
+		{"slices.Sort", slices.Sort[[]int]},
+		{"sorting.SortFunc", func(s []int) { SortFunc(s, func(a, b int) bool { return a < b }) }},
+		{"slices.SortFunc", func(s []int) { slices.SortFunc(s, cmp.Compare[int]) }},
+		{"sorting.StableFunc", func(s []int) { StableFunc(s, func(a, b int) bool { return a < b }) }},
+		{"slices.SortStableFunc", func(s []int) { slices.SortStableFunc(s, cmp.Compare[int]) }},
+	}
+	for _, in := range inputs {
+		for _, n := range []int{100, 10_000, 1_000_000} {
+			original := in.make(n, rand.New(rand.NewPCG(1, uint64(n))))
+			for _, c := range contenders {
+				b.Run(fmt.Sprintf("%s/%d/%s", in.name, n, c.name), func(b *testing.B) {
+					work := make([]int, n)
+					for b.Loop() {
+						copy(work, original)
+						c.sort(work)
+					}
+				})
+			}
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package sorting
+
+// runLength is the size of the blocks insertion-sorted before merging.
+const runLength = 16
+
+// StableFunc sorts s according to less, keeping equal elements in their
+// original order. It allocates one buffer the size of s.
+func StableFunc[T any](s []T, less func(a, b T) bool) {
+	n := len(s)
+	if n <= runLength {
+		insertionSortFunc(s, less)
+		return
+	}
+
+	for lo := 0; lo < n; lo += runLength {
+		insertionSortFunc(s[lo:min(lo+runLength, n)], less)
+	}
+
+	// Bottom-up merge, ping-ponging between s and buf so each pass is a
+	// straight copy rather than a merge into a temporary and back.
+	buf := make([]T, n)
+	src, dst := s, buf
+	for width := runLength; width < n; width *= 2 {
+		for lo := 0; lo < n; lo += 2 * width {
+			mid := min(lo+width, n)
+			hi := min(lo+2*width, n)
+			merge(dst[lo:hi], src[lo:mid], src[mid:hi], less)
+		}
+		src, dst = dst, src
+	}
+	if &src[0] != &s[0] {
+		copy(s, src)
+	}
+}
+
+// merge writes the stable merge of a and b into dst, taking from a on
+// ties.
+func merge[T any](dst, a, b []T, less func(a, b T) bool) {
+	i, j, k := 0, 0, 0
+	for i < len(a) && j < len(b) {
+		if less(b[j], a[i]) {
+			dst[k] = b[j]
+			j++
+		} else {
+			dst[k] = a[i]
+			i++
+		}
+		k++
+	}
+	k += copy(dst[k:], a[i:])
+	copy(dst[k:], b[j:])
+}
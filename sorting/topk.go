@@ -0,0 +1,72 @@
+package sorting
+
+import "cmp"
+
+// TopK returns the k smallest elements of s in ascending order without
+// modifying s. It runs in O(n log k) time and O(k) space, which beats a
+// full sort when k is much smaller than len(s). If k >= len(s) the whole
+// slice is returned sorted.
+func TopK[T cmp.Ordered](s []T, k int) []T {
+	return TopKFunc(s, k, lessOrdered[T])
+}
+
+// TopKFunc returns the k elements of s that sort first according to less,
+// in sorted order, without modifying s. To get the k largest, pass a less
+// function that compares the other way round.
+func TopKFunc[T any](s []T, k int, less func(a, b T) bool) []T {
+	if k <= 0 {
+		return nil
+	}
+	if k >= len(s) {
+		out := append([]T(nil), s...)
+		SortFunc(out, less)
+		return out
+	}
+
+	// Keep the best k seen so far in a max-heap: the root is the worst of
+	// them and is replaced whenever something better turns up.
+	heap := append(make([]T, 0, k), s[:k]...)
+	for i := k/2 - 1; i >= 0; i-- {
+		siftDownFunc(heap, less, i, k)
+	}
+	for _, v := range s[k:] {
+		if less(v, heap[0]) {
+			heap[0] = v
+			siftDownFunc(heap, less, 0, k)
+		}
+	}
+
+	// Popping the max-heap in place leaves it in ascending order.
+	for end := k - 1; end > 0; end-- {
+		heap[0], heap[end] = heap[end], heap[0]
+		siftDownFunc(heap, less, 0, end)
+	}
+	return heap
+}
+
+// PartialSortFunc rearranges s in place so that s[:k] holds the k
+// smallest elements in sorted order. The order of s[k:] is unspecified.
+func PartialSortFunc[T any](s []T, k int, less func(a, b T) bool) {
+	if k <= 0 {
+		return
+	}
+	if k >= len(s) {
+		SortFunc(s, less)
+		return
+	}
+	// Same idea as TopKFunc, but swapping instead of overwriting so s
+	// remains a permutation of its input.
+	for i := k/2 - 1; i >= 0; i-- {
+		siftDownFunc(s, less, i, k)
+	}
+	for i := k; i < len(s); i++ {
+		if less(s[i], s[0]) {
+			s[0], s[i] = s[i], s[0]
+			siftDownFunc(s, less, 0, k)
+		}
+	}
+	for end := k - 1; end > 0; end-- {
+		s[0], s[end] = s[end], s[0]
+		siftDownFunc(s, less, 0, end)
+	}
+}